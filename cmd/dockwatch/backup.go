@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"dockwatch/internal/backup"
+	"dockwatch/internal/config"
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/domain"
+)
+
+func runBackup(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	to := fs.String("to", "", "directory or s3://bucket/prefix to store the archive in (default backup.destination, else .)")
+	name := fs.String("name", "", "archive name (default VOLUME-TIMESTAMP.tar.gz, plus .age or .enc when encrypting)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: dockwatch backup [-to DEST] [-name NAME] VOLUME")
+	}
+	volume := fs.Arg(0)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	c, err := backup.CipherFrom(cfg)
+	if err != nil {
+		return err
+	}
+	if *name == "" {
+		*name = archiveName(volume, time.Now(), c)
+	}
+	if *to == "" {
+		*to = cfg.BackupDestination()
+	}
+	dest, err := backup.ParseDestination(*to, backup.S3OptionsFrom(cfg))
+	if err != nil {
+		return err
+	}
+
+	// The helper container runs on whatever daemon the docker CLI talks to
+	docker, err := dockercli.NewDockerProvider()
+	if err != nil {
+		return err
+	}
+	defer docker.Close()
+	docker.SetHelper(dockercli.HelperFrom(cfg))
+
+	m, err := backupVolume(context.Background(), docker, volume, dest, *name, c)
+	if err != nil {
+		return err
+	}
+	encrypted := ""
+	if c != nil {
+		encrypted = ", encrypted with " + c.Name()
+	}
+	fmt.Printf("Backed up %s to %s: %d file(s), %s%s, sha256 %s\n", volume, dest.Location(*name), len(m.Files), domain.HumanSize(m.Size), encrypted, m.SHA256)
+	fmt.Printf("Manifest: %s\n", dest.Location(backup.ManifestName(*name)))
+	return nil
+}
+
+// archiveName is the default name of a backup of volume taken at t, with
+// the extension of c when it encrypts.
+func archiveName(volume string, t time.Time, c backup.Cipher) string {
+	if c == nil {
+		return backup.FileName(volume, t)
+	}
+	return backup.FileName(volume, t) + c.Ext()
+}
+
+// backupVolume streams volume out of the daemon into an archive in dest,
+// encrypted with c unless it is nil.
+func backupVolume(ctx context.Context, docker *dockercli.DockerProvider, volume string, dest backup.Destination, name string, c backup.Cipher) (*backup.Manifest, error) {
+	if _, err := docker.GetVolumeDetails(ctx, volume); err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(docker.ExportVolume(ctx, volume, pw))
+	}()
+	m, err := backup.Write(ctx, pr, volume, dest, name, c)
+	// Unblocks the export if the archive failed first
+	pr.CloseWithError(err)
+	return m, err
+}
+
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("usage: dockwatch verify ARCHIVE...  (a path or s3://bucket/key)")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	opts := backup.S3OptionsFrom(cfg)
+	c, err := backup.CipherFrom(cfg)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	bad := 0
+	for _, archive := range fs.Args() {
+		dest, name, err := backup.Locate(archive, opts)
+		if err != nil {
+			return err
+		}
+		m, problems, err := backup.Verify(ctx, dest, name, c)
+		if err != nil {
+			return err
+		}
+		if len(problems) == 0 {
+			fmt.Printf("  %s %s: %s, %d file(s) match the manifest\n", mark(true), archive, m.Volume, len(m.Files))
+			continue
+		}
+		bad++
+		fmt.Printf("  %s %s: %d problem(s)\n", mark(false), archive, len(problems))
+		for _, p := range problems {
+			fmt.Printf("      %s\n", p)
+		}
+	}
+	if bad > 0 {
+		return fmt.Errorf("%d of %d archive(s) failed verification; don't restore from them", bad, fs.NArg())
+	}
+	return nil
+}
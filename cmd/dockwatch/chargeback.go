@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/report"
+)
+
+func runChargeback(args []string) error {
+	fs := flag.NewFlagSet("chargeback", flag.ContinueOnError)
+	label := fs.String("label", "", "label key naming the owner (default: chargeback.label from the config, else team)")
+	format := fs.String("format", "", "table or csv (default: from -o's extension, else table)")
+	out := fs.String("o", "", "output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	key := *label
+	if key == "" {
+		key = cfg.ChargebackLabel()
+	}
+	f := *format
+	if f == "" {
+		f = "table"
+		if strings.ToLower(filepath.Ext(*out)) == ".csv" {
+			f = "csv"
+		}
+	}
+	if f != "table" && f != "csv" {
+		return fmt.Errorf("unknown chargeback format %q (want table or csv)", f)
+	}
+
+	prov, err := openProvider()
+	if err != nil {
+		return err
+	}
+	defer prov.Close()
+
+	r, err := report.Collect(context.Background(), prov)
+	if err != nil {
+		return err
+	}
+	owners := report.Chargeback(r.Volumes, r.Images, r.Containers, key)
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	if f == "csv" {
+		err = report.WriteChargebackCSV(w, owners, key)
+	} else {
+		err = report.WriteChargebackTable(w, owners, key)
+	}
+	if err != nil {
+		return err
+	}
+	if *out != "" {
+		fmt.Printf("Chargeback written to %s\n", *out)
+	}
+	return nil
+}
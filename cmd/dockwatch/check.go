@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/history"
+)
+
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	forecast := fs.Bool("forecast", false, "fail when the data root is forecast to fill up within -within")
+	within := fs.String("within", "14d", "how soon a forecast fill-up counts as a failure")
+	window := fs.String("window", "7d", "how much recent history the growth rate is fitted on")
+	file := fs.String("db", history.Path(), "history database")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !*forecast {
+		return errors.New("usage: dockwatch check --forecast [-within 14d] [-window 7d]")
+	}
+	limit, err := domain.ParseAge(*within)
+	if err != nil {
+		return err
+	}
+	span, err := domain.ParseAge(*window)
+	if err != nil {
+		return err
+	}
+
+	db, err := history.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	f, err := db.Forecast(context.Background(), span, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Println(describeForecast(f))
+	if f.Growing() && f.DaysLeft() < limit.Hours()/24 {
+		return fmt.Errorf("data root forecast to fill within %s", domain.HumanAge(limit))
+	}
+	return nil
+}
+
+// describeForecast summarises a forecast in one line.
+func describeForecast(f history.Forecast) string {
+	line := fmt.Sprintf("%s free of %s", domain.HumanSize(f.Free), domain.HumanSize(f.Total))
+	if !f.Growing() {
+		return line + ", usage not growing"
+	}
+	return fmt.Sprintf("%s, growing %s/day, full in %.1f days (%s)",
+		line, domain.HumanSize(int64(f.PerDay)), f.DaysLeft(), f.FullAt().Local().Format(time.DateOnly))
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"dockwatch/internal/dockercli"
+)
+
+// subcommands are the words a command takes right after its name.
+var subcommands = map[string][]string{
+	"completion": {"bash", "zsh", "fish"},
+	"helper":     {"pull", "check"},
+	"history":    {"record", "prune"},
+	"policy":     {"test", "keygen", "sign"},
+	"safety":     {"export", "import"},
+}
+
+// volumeCommands take volume names as arguments.
+var volumeCommands = []string{"backup"}
+
+// The completion commands list the others, so they can't be in the
+// commands literal itself without an initialization cycle.
+func init() {
+	commands["completion"] = command{"Print a bash, zsh or fish completion script", runCompletion}
+	commands["__complete"] = command{"", runComplete} // for the scripts; not listed
+}
+
+// runCompletion prints a completion script for the shell. Volume and context
+// names are completed by calling back into dockwatch, so they are current.
+func runCompletion(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: dockwatch completion bash|zsh|fish")
+	}
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion())
+	case "zsh":
+		fmt.Print(zshCompletion())
+	case "fish":
+		fmt.Print(fishCompletion())
+	default:
+		return fmt.Errorf("no completion for %q; use bash, zsh or fish", args[0])
+	}
+	return nil
+}
+
+// runComplete lists the volumes or docker contexts for the completion
+// scripts, one per line. It gives up quickly: a shell waiting on a slow
+// daemon is worse than no suggestions.
+func runComplete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: dockwatch __complete volumes|contexts")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var names []string
+	switch args[0] {
+	case "volumes":
+		prov, err := connect()
+		if err != nil {
+			return err
+		}
+		defer prov.Close()
+		vols, err := prov.ListVolumesBasic(ctx)
+		if err != nil {
+			return err
+		}
+		for _, v := range vols {
+			names = append(names, v.Name)
+		}
+	case "contexts":
+		contexts, err := dockercli.ListContexts(ctx)
+		if err != nil {
+			return err
+		}
+		for _, c := range contexts {
+			names = append(names, c.Name)
+		}
+	default:
+		return fmt.Errorf("nothing to complete for %q", args[0])
+	}
+	for _, name := range names {
+		fmt.Fprintln(os.Stdout, name)
+	}
+	return nil
+}
+
+// visibleCommands are the command names usage lists, sorted.
+func visibleCommands() []string {
+	var names []string
+	for name, cmd := range commands {
+		if cmd.summary != "" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func bashCompletion() string {
+	sb := &strings.Builder{}
+	sb.WriteString(`# bash completion for dockwatch; load with: source <(dockwatch completion bash)
+_dockwatch() {
+	local cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]}
+	local i cmd=
+	for ((i = 1; i < COMP_CWORD; i++)); do
+		case ${COMP_WORDS[i]} in
+		--context | -context) ((i++)) ;;
+		-*) ;;
+		*) cmd=${COMP_WORDS[i]} && break ;;
+		esac
+	done
+	case $prev in
+	--context | -context)
+		COMPREPLY=($(compgen -W "$(dockwatch __complete contexts 2>/dev/null)" -- "$cur"))
+		return
+		;;
+	esac
+	case $cmd in
+	"")
+`)
+	fmt.Fprintf(sb, "\t\tCOMPREPLY=($(compgen -W \"--no-color --plain --context %s\" -- \"$cur\"))\n\t\t;;\n", strings.Join(visibleCommands(), " "))
+	for _, name := range slices.Sorted(maps.Keys(subcommands)) {
+		fmt.Fprintf(sb, "\t%s)\n\t\t((i + 1 == COMP_CWORD)) && COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n\t\t;;\n", name, strings.Join(subcommands[name], " "))
+	}
+	fmt.Fprintf(sb, "\t%s)\n", strings.Join(volumeCommands, " | "))
+	sb.WriteString(`		[[ $cur != -* && $prev != -* ]] && COMPREPLY=($(compgen -W "$(dockwatch __complete volumes 2>/dev/null)" -- "$cur"))
+		;;
+	esac
+}
+complete -o default -F _dockwatch dockwatch
+`)
+	return sb.String()
+}
+
+func zshCompletion() string {
+	sb := &strings.Builder{}
+	sb.WriteString(`#compdef dockwatch
+# zsh completion for dockwatch; load with: source <(dockwatch completion zsh)
+_dockwatch() {
+	local -a cmds
+	cmds=(
+`)
+	for _, name := range visibleCommands() {
+		fmt.Fprintf(sb, "\t\t'%s:%s'\n", name, zshQuote(commands[name].summary))
+	}
+	sb.WriteString(`	)
+	local i=2 cmd=
+	while ((i < CURRENT)); do
+		case $words[i] in
+		--context | -context) ((i++)) ;;
+		-*) ;;
+		*) cmd=$words[i] && break ;;
+		esac
+		((i++))
+	done
+	case $words[CURRENT-1] in
+	--context | -context)
+		compadd -- ${(f)"$(dockwatch __complete contexts 2>/dev/null)"}
+		return
+		;;
+	esac
+	case $cmd in
+	'')
+		_describe command cmds
+		compadd -- --no-color --plain --context
+		;;
+`)
+	for _, name := range slices.Sorted(maps.Keys(subcommands)) {
+		fmt.Fprintf(sb, "\t%s)\n\t\tif ((CURRENT == i + 1)); then compadd -- %s; else _files; fi\n\t\t;;\n", name, strings.Join(subcommands[name], " "))
+	}
+	fmt.Fprintf(sb, "\t%s)\n", strings.Join(volumeCommands, " | "))
+	sb.WriteString(`		if [[ $PREFIX != -* && $words[CURRENT-1] != -* ]]; then
+			compadd -- ${(f)"$(dockwatch __complete volumes 2>/dev/null)"}
+		else
+			_files
+		fi
+		;;
+	*) _files ;;
+	esac
+}
+if [[ $funcstack[1] == _dockwatch ]]; then
+	_dockwatch "$@"
+else
+	compdef _dockwatch dockwatch
+fi
+`)
+	return sb.String()
+}
+
+func fishCompletion() string {
+	sb := &strings.Builder{}
+	sb.WriteString(`# fish completion for dockwatch; load with: dockwatch completion fish | source
+# __dockwatch_at tests the command typed so far and, if given, how many
+# words follow it.
+function __dockwatch_at -a want nargs
+	set -l tokens (commandline -opc)
+	set -e tokens[1]
+	set -l cmd ''
+	set -l n 0
+	set -l skip 0
+	for t in $tokens
+		if test $skip = 1
+			set skip 0
+		else if test -n "$cmd"
+			set n (math $n + 1)
+		else if contains -- $t --context -context
+			set skip 1
+		else if not string match -q -- '-*' $t
+			set cmd $t
+		end
+	end
+	test "$cmd" = "$want"; and begin; test -z "$nargs"; or test $n = "$nargs"; end
+end
+complete -c dockwatch -n "__dockwatch_at ''" -f
+complete -c dockwatch -n "__dockwatch_at ''" -l no-color -d 'Turn colors off'
+complete -c dockwatch -n "__dockwatch_at ''" -l plain -d 'Plain output for screen readers'
+complete -c dockwatch -n "__dockwatch_at ''" -l context -x -d 'Docker context' -a '(dockwatch __complete contexts 2>/dev/null)'
+`)
+	for _, name := range visibleCommands() {
+		fmt.Fprintf(sb, "complete -c dockwatch -n \"__dockwatch_at ''\" -a %s -d '%s'\n", name, fishQuote(commands[name].summary))
+	}
+	for _, name := range slices.Sorted(maps.Keys(subcommands)) {
+		fmt.Fprintf(sb, "complete -c dockwatch -n '__dockwatch_at %s 0' -f -a '%s'\n", name, strings.Join(subcommands[name], " "))
+	}
+	for _, name := range volumeCommands {
+		fmt.Fprintf(sb, "complete -c dockwatch -n '__dockwatch_at %s' -a '(dockwatch __complete volumes 2>/dev/null)'\n", name)
+	}
+	return sb.String()
+}
+
+// zshQuote escapes a description for a single-quoted _describe entry.
+func zshQuote(s string) string {
+	return strings.NewReplacer("'", `'\''`, ":", `\:`).Replace(s)
+}
+
+// fishQuote escapes a description for a single-quoted fish string.
+func fishQuote(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"dockwatch/internal/doctor"
+)
+
+func runDoctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	format := fs.String("format", "json", "json or text")
+	strict := fs.Bool("strict", false, "treat warnings as failures")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "json" && *format != "text" {
+		return fmt.Errorf("unknown format %q (want json or text)", *format)
+	}
+
+	r := doctor.Run(context.Background())
+	failed, warned := 0, 0
+	for _, c := range r.Checks {
+		switch c.Status {
+		case doctor.Fail:
+			failed++
+		case doctor.Warn:
+			warned++
+		}
+	}
+	ok := failed == 0 && (!*strict || warned == 0)
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(struct {
+			OK     bool           `json:"ok"`
+			Checks []doctor.Check `json:"checks"`
+		}{ok, r.Checks})
+		if err != nil {
+			return err
+		}
+	} else {
+		arrow := "→"
+		if asciiOutput {
+			arrow = "->"
+		}
+		for _, c := range r.Checks {
+			fmt.Printf("%-5s %-14s %s\n", c.Status, c.Name, c.Detail)
+			if c.Hint != "" {
+				fmt.Printf("%-5s %-14s %s %s\n", "", "", arrow, c.Hint)
+			}
+		}
+	}
+
+	if !ok {
+		return fmt.Errorf("%d check(s) failed, %d warning(s)", failed, warned)
+	}
+	return nil
+}
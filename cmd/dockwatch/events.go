@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// volumeEvent is one line of `dockwatch events`: what happened, plus the
+// volume as dockwatch sees it right after.
+type volumeEvent struct {
+	Time      time.Time   `json:"time"`
+	Event     string      `json:"event"` // volume.created, volume.removed, volume.orphaned, ...
+	Volume    eventVolume `json:"volume"`
+	OldSize   *int64      `json:"old_size_bytes,omitempty"`
+	Threshold int64       `json:"threshold_bytes,omitempty"`
+}
+
+type eventVolume struct {
+	Name      string             `json:"name"`
+	Driver    string             `json:"driver"`
+	Size      int64              `json:"size_bytes"`
+	Project   string             `json:"project,omitempty"`
+	State     domain.OrphanState `json:"state"`
+	Orphan    bool               `json:"orphan"`
+	InUse     bool               `json:"in_use"`
+	Attached  []string           `json:"attached,omitempty"`
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+	LastUsed  *time.Time         `json:"last_used,omitempty"`
+	Labels    map[string]string  `json:"labels,omitempty"`
+	Tags      []string           `json:"tags,omitempty"`
+}
+
+func runEvents(args []string) error {
+	fs := flag.NewFlagSet("events", flag.ContinueOnError)
+	jsonOut := fs.Bool("json", false, "print one JSON object per line")
+	thresholdFlag := fs.String("threshold", "", "also report volumes growing past this size, e.g. 10GB")
+	interval := fs.Duration("interval", time.Minute, "how often to re-check sizes, which the daemon has no events for")
+	var filters stringList
+	fs.Var(&filters, "filter", filterUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var threshold int64
+	if *thresholdFlag != "" {
+		var err error
+		if threshold, err = domain.ParseSize(*thresholdFlag); err != nil {
+			return fmt.Errorf("invalid -threshold: %w", err)
+		}
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	f, err := domain.ParseVolumeFilter(filters)
+	if err != nil {
+		return err
+	}
+
+	prov, err := openProvider()
+	if err != nil {
+		return err
+	}
+	defer prov.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	prev, err := listVolumes(ctx, prov, f)
+	if err != nil {
+		return err
+	}
+
+	// The daemon's events only say that something changed; each relevant one
+	// triggers a fresh listing, which is diffed against the previous one.
+	changed := make(chan struct{}, 1)
+	followErr := make(chan error, 1)
+	go func() {
+		followErr <- prov.Events(ctx, func(ev domain.DaemonEvent) {
+			if !relevantEvent(ev) {
+				return
+			}
+			select {
+			case changed <- struct{}{}:
+			default: // a relisting is already pending
+			}
+		})
+	}()
+
+	tick := time.NewTicker(*interval)
+	defer tick.Stop()
+	enc := json.NewEncoder(os.Stdout)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-followErr:
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err == nil {
+				err = fmt.Errorf("event stream ended")
+			}
+			return err
+		case <-changed:
+		case <-tick.C:
+		}
+
+		cur, err := listVolumes(ctx, prov, f)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "dockwatch events: %v\n", err)
+			continue
+		}
+		now := time.Now().UTC()
+		for _, c := range volumeChanges(prev, cur, threshold) {
+			ev := newVolumeEvent(now, c, threshold)
+			if *jsonOut {
+				err = enc.Encode(ev)
+			} else {
+				_, err = fmt.Println(ev.text())
+			}
+			if err != nil {
+				return err
+			}
+		}
+		prev = cur
+	}
+}
+
+// relevantEvent reports whether ev can create, remove or (de)orphan a
+// volume. Orphan status counts every container, running or not, so only
+// creating and destroying containers matters.
+func relevantEvent(ev domain.DaemonEvent) bool {
+	return (ev.Type == "volume" || ev.Type == "container") && (ev.Action == "create" || ev.Action == "destroy")
+}
+
+func newVolumeEvent(now time.Time, c volumeChange, threshold int64) volumeEvent {
+	v := c.vol
+	ev := volumeEvent{
+		Time:  now,
+		Event: "volume." + c.kind,
+		Volume: eventVolume{
+			Name:     v.Name,
+			Driver:   v.Driver,
+			Size:     v.SizeBytes,
+			Project:  v.Project,
+			State:    v.State,
+			Orphan:   v.Orphan(),
+			InUse:    v.InUse,
+			Attached: v.Attached,
+			Labels:   v.Labels,
+			Tags:     v.Tags,
+		},
+	}
+	if !v.CreatedAt.IsZero() {
+		ev.Volume.CreatedAt = &v.CreatedAt
+	}
+	if !v.LastUsed.IsZero() {
+		ev.Volume.LastUsed = &v.LastUsed
+	}
+	if c.kind == "resized" || c.kind == "threshold" && c.oldSize >= 0 {
+		ev.OldSize = &c.oldSize
+	}
+	if c.kind == "threshold" {
+		ev.Event = "volume.size_threshold"
+		ev.Threshold = threshold
+	}
+	return ev
+}
+
+// text is the event as one human-readable line.
+func (ev volumeEvent) text() string {
+	line := fmt.Sprintf("%s  %-21s %s  %s", ev.Time.Local().Format(time.TimeOnly), ev.Event, ev.Volume.Name, domain.HumanSize(ev.Volume.Size))
+	if ev.OldSize != nil {
+		line += " (was " + domain.HumanSize(*ev.OldSize) + ")"
+	}
+	if len(ev.Volume.Attached) > 0 {
+		line += "  " + strings.Join(ev.Volume.Attached, ",")
+	}
+	return line
+}
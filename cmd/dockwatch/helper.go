@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/dockercli"
+)
+
+// runHelper pulls or checks the helper image. On an air-gapped host, pull
+// it where a registry is reachable, move it over with docker save and
+// docker load, set helper.pull to never and run check.
+func runHelper(args []string) error {
+	if len(args) != 1 || (args[0] != "pull" && args[0] != "check") {
+		return fmt.Errorf("usage: dockwatch helper pull|check")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	// Helper containers always run through the local docker CLI
+	docker, err := dockercli.NewDockerProvider()
+	if err != nil {
+		return err
+	}
+	defer docker.Close()
+	docker.SetHelper(dockercli.HelperFrom(cfg))
+
+	ctx := context.Background()
+	if args[0] == "pull" {
+		if err := docker.PullHelper(ctx); err != nil {
+			return err
+		}
+	}
+	if err := docker.CheckHelper(ctx); err != nil {
+		return err
+	}
+	fmt.Printf("%s is present and has what helper containers need\n", docker.HelperImage())
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/doctor"
+	"dockwatch/internal/domain"
+	"dockwatch/internal/history"
+	"dockwatch/internal/provider"
+)
+
+const historyUsage = "usage: dockwatch history record [-db file] [-every 15m] | prune [-db file] [-older-than 90d]"
+
+func runHistory(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(historyUsage)
+	}
+	switch args[0] {
+	case "record":
+		return runHistoryRecord(args[1:])
+	case "prune":
+		return runHistoryPrune(args[1:])
+	}
+	return fmt.Errorf(historyUsage)
+}
+
+func runHistoryRecord(args []string) error {
+	fs := flag.NewFlagSet("history record", flag.ContinueOnError)
+	file := fs.String("db", history.Path(), "history database")
+	every := fs.Duration("every", 0, "keep recording at this interval instead of once")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	db, err := history.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	prov, err := openProvider()
+	if err != nil {
+		return err
+	}
+	defer prov.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for {
+		if err := recordSample(ctx, db, prov, cfg.HistoryRetention()); err != nil {
+			if *every == 0 {
+				return err
+			}
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "dockwatch history: %v\n", err)
+			}
+		}
+		if *every == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*every):
+		}
+	}
+}
+
+// recordSample stores the current usage and drops samples past retention.
+func recordSample(ctx context.Context, db *history.DB, prov provider.Provider, retention time.Duration) error {
+	s := history.Sample{TakenAt: time.Now()}
+	var err error
+	if s.Volumes, err = prov.ListVolumes(ctx); err != nil {
+		return err
+	}
+	if s.Images, err = prov.ListImages(ctx); err != nil {
+		return err
+	}
+	if s.Containers, err = prov.ListContainers(ctx); err != nil {
+		return err
+	}
+	// The disk readings feed `dockwatch check --forecast`; an agent's data
+	// root is on another machine
+	if os.Getenv("DOCKWATCH_REMOTE") == "" {
+		if du, err := doctor.DataRoot(ctx); err == nil {
+			s.DiskFree, s.DiskTotal = du.Free, du.Total
+		}
+	}
+	if err := db.Record(ctx, s); err != nil {
+		return fmt.Errorf("failed to record sample: %w", err)
+	}
+	if _, err := db.Prune(ctx, s.TakenAt.Add(-retention)); err != nil {
+		return fmt.Errorf("failed to prune history: %w", err)
+	}
+	fmt.Printf("Recorded %d volume(s), %d image(s), %d container(s)\n", len(s.Volumes), len(s.Images), len(s.Containers))
+	return nil
+}
+
+func runHistoryPrune(args []string) error {
+	fs := flag.NewFlagSet("history prune", flag.ContinueOnError)
+	file := fs.String("db", history.Path(), "history database")
+	olderThan := fs.String("older-than", "", "drop samples older than this, e.g. 30d (default: history.retention from the config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	retention := cfg.HistoryRetention()
+	if *olderThan != "" {
+		if retention, err = domain.ParseAge(*olderThan); err != nil {
+			return err
+		}
+	}
+
+	db, err := history.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	n, err := db.Prune(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return err
+	}
+	if err := db.Vacuum(ctx); err != nil {
+		return err
+	}
+	fmt.Printf("Pruned %d sample(s) older than %s from %s\n", n, domain.HumanAge(retention), *file)
+	return nil
+}
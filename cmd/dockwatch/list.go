@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/domain"
+	"dockwatch/internal/notes"
+	"dockwatch/internal/provider"
+)
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(s string) error { *l = append(*l, s); return nil }
+
+func runList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	var filters stringList
+	fs.Var(&filters, "filter", filterUsage)
+	quiet := fs.Bool("q", false, "only print volume names")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	f, err := domain.ParseVolumeFilter(filters)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	prov, err := openProvider()
+	if err != nil {
+		return err
+	}
+	defer prov.Close()
+
+	vols, err := listVolumes(context.Background(), prov, f)
+	if err != nil {
+		return err
+	}
+	if *quiet {
+		for _, v := range vols {
+			fmt.Println(v.Name)
+		}
+		return nil
+	}
+	return printVolumes(os.Stdout, vols, cfg, time.Now())
+}
+
+const filterUsage = "name=, driver=, label=key[=value], dangling=true|false, project= or tag= (repeatable)"
+
+// listVolumes lists the volumes matching f, with local notes and tags.
+func listVolumes(ctx context.Context, prov provider.Provider, f domain.VolumeFilter) ([]domain.Volume, error) {
+	// Tags are local to this machine, so the provider can't filter on them
+	vols, err := prov.ListVolumesFiltered(ctx, f.Without("tag"))
+	if err != nil {
+		return nil, err
+	}
+	store, err := notes.Load()
+	if err != nil {
+		return nil, err
+	}
+	store.Annotate(vols)
+	return slices.DeleteFunc(vols, func(v domain.Volume) bool { return !f.Match(v) }), nil
+}
+
+// printVolumes writes vols as an aligned table with the configured label
+// columns.
+func printVolumes(out io.Writer, vols []domain.Volume, cfg *config.Config, now time.Time) error {
+	header := []string{"NAME", "DRIVER", "SIZE", "AGE", "LAST USED", "ATTACHED", "PROJECT"}
+	for _, lc := range cfg.LabelColumns {
+		header = append(header, strings.ToUpper(lc.Heading()))
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(append(header, "STATUS"), "\t"))
+	for _, v := range vols {
+		attached := "-"
+		if len(v.Attached) > 0 {
+			attached = strings.Join(v.Attached, ",")
+		}
+		status := v.State.String()
+		row := []string{v.Name, v.Driver, v.SizeHuman(), v.AgeHuman(now), v.LastUsedHuman(now), attached, v.Project}
+		for _, lc := range cfg.LabelColumns {
+			row = append(row, v.Labels[lc.Label])
+		}
+		fmt.Fprintln(w, strings.Join(append(row, status), "\t"))
+	}
+	return w.Flush()
+}
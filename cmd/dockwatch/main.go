@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/provider"
+	"dockwatch/internal/remote"
+	"dockwatch/internal/tui"
+)
+
+// command is a CLI subcommand; with no subcommand dockwatch starts the TUI.
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]command{
+	"list":       {"List volumes, optionally filtered like docker volume ls", runList},
+	"policy":     {"Inspect and test cleanup policies", runPolicy},
+	"plan":       {"Write a pinned prune plan file", runPlan},
+	"apply":      {"Execute a plan file, refusing on drift", runApply},
+	"snapshot":   {"Record the current volumes for the Diff pane", runSnapshot},
+	"report":     {"Write a Markdown or HTML disk usage report", runReport},
+	"chargeback": {"Attribute volume and image usage to owners by label, as a table or CSV", runChargeback},
+	"serve":      {"Expose the Docker provider over an authenticated HTTP API", runServe},
+	"check":      {"Exit non-zero when the data root is forecast to fill up soon", runCheck},
+	"doctor":     {"Check that this host is ready for dockwatch", runDoctor},
+	"events":     {"Stream volume changes, optionally as JSON lines", runEvents},
+	"history":    {"Record usage samples to a SQLite database for trend analysis", runHistory},
+	"watch":      {"Print a refreshed volume table or JSON lines of changes", runWatch},
+	"backup":     {"Archive a volume as a tar.gz with a SHA256 manifest", runBackup},
+	"verify":     {"Check backup archives against their manifests", runVerify},
+	"helper":     {"Pull or check the image helper containers run", runHelper},
+	"safety":     {"Export or import the ignore list, protect rules and tags", runSafety},
+}
+
+func main() {
+	args := globalFlags(os.Args[1:])
+	// A broken config is reported by whatever loads it next
+	if cfg, err := config.Load(); err == nil {
+		dockercli.UseContext(cfg.Context)
+		asciiOutput = cfg.ASCII()
+	}
+	endpoint, discovered := dockercli.DiscoverSocket()
+	if len(args) > 0 {
+		name := args[0]
+		if name == "help" || name == "-h" || name == "--help" {
+			usage()
+			return
+		}
+		cmd, ok := commands[name]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "dockwatch: unknown command %q\n\n", name)
+			usage()
+			os.Exit(2)
+		}
+		if discovered {
+			fmt.Fprintf(os.Stderr, "dockwatch: using %s at %s\n", endpoint.Name, endpoint.Host)
+		}
+		if err := cmd.run(args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "dockwatch %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var opts []tea.ProgramOption
+	// Capturing the mouse stops the terminal selecting text, so it can be
+	// turned off
+	if os.Getenv("DOCKWATCH_NO_MOUSE") == "" {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	if _, err := tea.NewProgram(tui.New(), opts...).Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "dockwatch: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// globalFlags applies the flags that go before the command, which the TUI
+// and every command share, and returns the arguments after them.
+func globalFlags(args []string) []string {
+	for len(args) > 0 {
+		switch args[0] {
+		case "--no-color", "-no-color":
+			os.Setenv("NO_COLOR", "1")
+		case "--plain", "-plain":
+			os.Setenv("DOCKWATCH_PLAIN", "1")
+		case "--context", "-context":
+			if len(args) < 2 {
+				return args
+			}
+			// As though DOCKER_CONTEXT were set, so it beats the config
+			os.Setenv("DOCKER_CONTEXT", args[1])
+			args = args[1:]
+		default:
+			return args
+		}
+		args = args[1:]
+	}
+	return args
+}
+
+// asciiOutput is set when the config or locale rules out Unicode.
+var asciiOutput bool
+
+// mark is ✓ or ✗ for a line of command output, a word in plain mode, or a
+// checkbox in ASCII.
+func mark(ok bool) string {
+	switch {
+	case config.PlainRequested() && ok:
+		return "ok"
+	case config.PlainRequested():
+		return "FAILED"
+	case asciiOutput && ok:
+		return "[x]"
+	case asciiOutput:
+		return "[ ]"
+	case ok:
+		return "✓"
+	}
+	return "✗"
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: dockwatch [--no-color] [--plain] [--context NAME] [command] [flags]")
+	fmt.Fprintln(os.Stderr, "\nWith no command, dockwatch starts the interactive TUI. --no-color (or NO_COLOR)")
+	fmt.Fprintln(os.Stderr, "turns colors off; --plain (or DOCKWATCH_PLAIN, or TERM=dumb) also drops box")
+	fmt.Fprintln(os.Stderr, "drawing and marks the selection and statuses with text, for screen readers.")
+	fmt.Fprintln(os.Stderr, "--context (or DOCKER_CONTEXT) picks the docker context.\n\nCommands:")
+	for _, name := range visibleCommands() {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].summary)
+	}
+}
+
+// openProvider connects to the Docker daemon for CLI commands, or to the
+// agent named by $DOCKWATCH_REMOTE. Reads are retried through daemon
+// hiccups. In dry-run mode removals are only logged to stderr; with
+// $DOCKWATCH_TRACE every call is.
+func openProvider() (provider.Provider, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	prov, err := connect()
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := prov.(*dockercli.DockerProvider); ok {
+		d.SetLimits(dockercli.LimitsFrom(cfg))
+		d.SetHelper(dockercli.HelperFrom(cfg))
+	}
+	mws := []provider.Middleware{provider.GuardOrphans(provider.OrphanGuard{
+		MinIdle: cfg.OrphanMinIdle(),
+		Labels:  cfg.Orphans.KeepLabels,
+	})}
+	if provider.DryRunRequested() {
+		mws = append(mws, provider.WithDryRun(dryRunLog))
+	}
+	mws = append(mws, provider.Retry(provider.DefaultRetry, retryLog))
+	if provider.TraceRequested() {
+		mws = append(mws, provider.Logging(traceLog))
+	}
+	return provider.Chain(prov, mws...), nil
+}
+
+func connect() (provider.Provider, error) {
+	if client, err := remote.FromEnv(); client != nil || err != nil {
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+	prov, err := dockercli.NewDockerProvider()
+	if err != nil {
+		return nil, err
+	}
+	return prov, nil
+}
+
+func dryRunLog(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "dry-run: "+format+"\n", args...)
+}
+
+func traceLog(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "trace: "+format+"\n", args...)
+}
+
+func retryLog(st provider.RetryStatus) {
+	if !st.Done {
+		fmt.Fprintf(os.Stderr, "retrying %s (%d/%d): %v\n", st.Op, st.Attempt, st.Of, st.Err)
+	}
+}
+
+// removed is the past-tense verb for removal output, honest in dry-run mode.
+func removed() string {
+	if provider.DryRunRequested() {
+		return "would remove"
+	}
+	return "removed"
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"dockwatch/internal/compose"
+	"dockwatch/internal/config"
+	"dockwatch/internal/domain"
+	"dockwatch/internal/plan"
+	"dockwatch/internal/policy"
+)
+
+func runPlan(args []string) error {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	out := fs.String("out", "plan.dockwatch", "write the plan to this file")
+	policyFile := fs.String("policy", "", "policy file (default: policy.url from the config, else policy.yaml); without one every orphan volume is planned")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	pol, err := loadPolicy(*policyFile, cfg)
+	if err != nil {
+		return err
+	}
+
+	prov, err := openProvider()
+	if err != nil {
+		return err
+	}
+	defer prov.Close()
+
+	vols, err := prov.ListVolumes(context.Background())
+	if err != nil {
+		return err
+	}
+
+	selected, reasons := selectForPrune(vols, cfg, pol)
+	p := plan.New(selected, reasons)
+	if err := p.Write(*out); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, v := range selected {
+		fmt.Fprintf(w, "  - %s\t%s\t%s\n", v.Name, v.SizeHuman(), reasons[v.Name])
+	}
+	w.Flush()
+	if len(cfg.Compose.Dirs) > 0 {
+		refs, err := compose.Scan(cfg.ComposeDirs())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "compose scan: %v\n", err)
+		}
+		for _, v := range selected {
+			for _, r := range refs[v.Name] {
+				fmt.Printf("  ! %s: %s\n", v.Name, r.Warning())
+			}
+		}
+	}
+	fmt.Printf("\nPlan: %d volume(s) to remove, %s to reclaim. Saved to %s\n",
+		len(p.Items), domain.Volume{SizeBytes: p.TotalBytes()}.SizeHuman(), *out)
+	fmt.Printf("Run `dockwatch apply %s` to execute it.\n", *out)
+	return nil
+}
+
+func runApply(args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: dockwatch apply <plan file>")
+	}
+
+	p, err := plan.Read(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	prov, err := openProvider()
+	if err != nil {
+		return err
+	}
+	defer prov.Close()
+
+	ctx := context.Background()
+	vols, err := prov.ListVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Refuse the whole plan on any drift, like terraform does
+	if drift := p.Check(vols); len(drift) > 0 {
+		for _, d := range drift {
+			fmt.Fprintf(os.Stderr, "  ! %s: %s\n", d.Name, d.Reason)
+		}
+		return fmt.Errorf("state drifted since %s; re-run `dockwatch plan`", p.CreatedAt.Local().Format(time.DateTime))
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	batch, delay := cfg.PruneBatch()
+	_, workers, _, _ := cfg.Parallelism()
+
+	failed, skipped := 0, 0
+	for i := 0; i < len(p.Items); {
+		if i > 0 && i%batch == 0 && delay > 0 {
+			time.Sleep(delay)
+		}
+		// Up to workers removals at once, never across a batch boundary
+		group := p.Items[i:min(i+workers, (i/batch+1)*batch, len(p.Items))]
+		errs := make([]error, len(group))
+		var wg sync.WaitGroup
+		for j, it := range group {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[j] = prov.RemoveVolume(ctx, it.Name)
+			}()
+		}
+		wg.Wait()
+		i += len(group)
+
+		for j, it := range group {
+			switch err := errs[j]; {
+			case errors.Is(err, domain.ErrNotFound):
+				fmt.Printf("  - %s: already gone\n", it.Name)
+				skipped++
+			case errors.Is(err, domain.ErrVolumeInUse):
+				// Attached since the drift check; leave it for the next plan
+				fmt.Fprintf(os.Stderr, "  - %s: skipped, now in use\n", it.Name)
+				skipped++
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "  %s %s: %v\n", mark(false), it.Name, err)
+				failed++
+			default:
+				fmt.Printf("  %s %s %s\n", mark(true), removed(), it.Name)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d removal(s) failed", failed, len(p.Items))
+	}
+	fmt.Printf("Applied: %s %d volume(s)", removed(), len(p.Items)-skipped)
+	if skipped > 0 {
+		fmt.Printf(", %d skipped", skipped)
+	}
+	fmt.Println()
+	return nil
+}
+
+// selectForPrune picks the volumes a plan should remove: the policy's prune
+// decisions if a policy is given, otherwise every orphan. Ignored volumes
+// are never selected.
+func selectForPrune(vols []domain.Volume, cfg *config.Config, pol *policy.Policy) ([]domain.Volume, map[string]string) {
+	var selected []domain.Volume
+	reasons := map[string]string{}
+	if pol != nil {
+		for _, d := range pol.Evaluate(vols, time.Now()) {
+			if d.Action != policy.ActionPrune || cfg.IsIgnored(d.Volume.Name) {
+				continue
+			}
+			selected = append(selected, d.Volume)
+			reasons[d.Volume.Name] = "rule " + d.Rule
+		}
+		return selected, reasons
+	}
+	for _, v := range vols {
+		if v.Orphan() && !cfg.IsIgnored(v.Name) {
+			selected = append(selected, v)
+			reasons[v.Name] = "orphan"
+		}
+	}
+	return selected, reasons
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/policy"
+)
+
+func runPolicy(args []string) error {
+	const usage = "usage: dockwatch policy test [-f policy.yaml] | keygen [-o file] | sign -key file policy.yaml"
+	if len(args) == 0 {
+		return fmt.Errorf(usage)
+	}
+	switch args[0] {
+	case "test":
+		return policyTest(args[1:])
+	case "keygen":
+		return policyKeygen(args[1:])
+	case "sign":
+		return policySign(args[1:])
+	}
+	return fmt.Errorf(usage)
+}
+
+func policyTest(args []string) error {
+	fs := flag.NewFlagSet("policy test", flag.ContinueOnError)
+	file := fs.String("f", "", "policy file (default: policy.url from the config, else policy.yaml)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	pol, err := loadPolicy(*file, cfg)
+	if err != nil {
+		return err
+	}
+	if pol == nil {
+		return fmt.Errorf("no policy: %s does not exist", policyName(*file, cfg))
+	}
+
+	prov, err := openProvider()
+	if err != nil {
+		return err
+	}
+	defer prov.Close()
+
+	vols, err := prov.ListVolumes(context.Background())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, rm := range pol.Test(vols, now) {
+		fmt.Fprintf(w, "Rule %q (%s): %d match(es)\n", rm.Rule.Name, rm.Rule.Action, len(rm.Volumes))
+		for _, v := range rm.Volumes {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", v.Name, v.SizeHuman(), ifEmpty(v.Project, "-"), v.State.Label())
+		}
+	}
+	for _, r := range pol.Backups {
+		var matched []string
+		for _, v := range vols {
+			if r.Match.MatchVolume(v, now) {
+				matched = append(matched, v.Name)
+			}
+		}
+		fmt.Fprintf(w, "Backup %q (every %s, keep %d): %d match(es)\n", r.Name, r.Every, r.Keep, len(matched))
+		for _, name := range matched {
+			fmt.Fprintf(w, "  %s\n", name)
+		}
+	}
+	w.Flush()
+
+	counts := map[policy.Action]int{}
+	for _, d := range pol.Evaluate(vols, now) {
+		counts[d.Action]++
+	}
+	fmt.Printf("\nEffective: %d to prune, %d protected, %d notify (of %d volumes)\n",
+		counts[policy.ActionPrune], counts[policy.ActionProtect], counts[policy.ActionNotify], len(vols))
+	return nil
+}
+
+// loadPolicy loads the policy file given on the command line, or without
+// one the policy the config points at; nil when there is none.
+func loadPolicy(file string, cfg *config.Config) (*policy.Policy, error) {
+	if file != "" {
+		return policy.LoadIfExists(file)
+	}
+	return policy.Configured(context.Background(), cfg, func(err error) {
+		fmt.Fprintf(os.Stderr, "dockwatch: %v\n", err)
+	})
+}
+
+// policyName says where loadPolicy reads from.
+func policyName(file string, cfg *config.Config) string {
+	switch {
+	case file != "":
+		return file
+	case cfg.Policy.URL != "":
+		return cfg.Policy.URL
+	}
+	return config.PolicyPath()
+}
+
+// policyKeygen creates the key pair for signing a centrally served policy.
+// The private key stays with whoever publishes the policy; hosts get the
+// public key in their config.
+func policyKeygen(args []string) error {
+	fs := flag.NewFlagSet("policy keygen", flag.ContinueOnError)
+	out := fs.String("o", "policy-signing.key", "file to write the private key to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if _, err := os.Stat(*out); err == nil {
+		return fmt.Errorf("%s already exists", *out)
+	}
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, []byte(base64.StdEncoding.EncodeToString(priv)+"\n"), 0o600); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote the private key to %s; keep it off the hosts.\n\nAdd to each host's config.yaml:\n\n", *out)
+	fmt.Printf("policy:\n  url: https://…/policy.yaml\n  publicKey: %s\n", base64.StdEncoding.EncodeToString(pub))
+	return nil
+}
+
+// policySign writes FILE.sig, the signature hosts check a served policy
+// against. The policy must load first: signing a broken one would only move
+// the error to every host.
+func policySign(args []string) error {
+	fs := flag.NewFlagSet("policy sign", flag.ContinueOnError)
+	keyFile := fs.String("key", "policy-signing.key", "private key from `dockwatch policy keygen`")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: dockwatch policy sign -key file policy.yaml")
+	}
+	file := fs.Arg(0)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if _, err := policy.Parse(data); err != nil {
+		return err
+	}
+	raw, err := os.ReadFile(*keyFile)
+	if err != nil {
+		return err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil || len(key) != ed25519.PrivateKeySize {
+		return fmt.Errorf("%s is not a key from `dockwatch policy keygen`", *keyFile)
+	}
+	sig := ed25519.Sign(ed25519.PrivateKey(key), data)
+	if err := os.WriteFile(file+".sig", []byte(base64.StdEncoding.EncodeToString(sig)+"\n"), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s.sig; publish it next to %s\n", file, file)
+	return nil
+}
+
+func ifEmpty(s, repl string) string {
+	if s == "" {
+		return repl
+	}
+	return s
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/notes"
+	"dockwatch/internal/policy"
+	"dockwatch/internal/provider"
+	"dockwatch/internal/safety"
+)
+
+// runSafety exports the ignore list, protect rules and volume tags to a
+// file, or merges such a file into this machine's, so a team can share one
+// curated set.
+func runSafety(args []string) error {
+	const usage = "usage: dockwatch safety export [-o file] | import [-n] file"
+	if len(args) == 0 {
+		return fmt.Errorf(usage)
+	}
+	switch args[0] {
+	case "export":
+		return safetyExport(args[1:])
+	case "import":
+		return safetyImport(args[1:])
+	}
+	return fmt.Errorf(usage)
+}
+
+func safetyExport(args []string) error {
+	fs := flag.NewFlagSet("safety export", flag.ContinueOnError)
+	out := fs.String("o", "", "file to write (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, pol, store, err := loadSafety(true)
+	if err != nil {
+		return err
+	}
+	b := safety.Export(cfg, pol, store)
+	data, err := b.Marshal()
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d ignore pattern(s), %d protect rule(s) and the tags of %d volume(s) to %s\n",
+		len(b.Ignore), len(b.Protect), len(b.Tags), *out)
+	return nil
+}
+
+func safetyImport(args []string) error {
+	fs := flag.NewFlagSet("safety import", flag.ContinueOnError)
+	dryRun := fs.Bool("n", provider.DryRunRequested(), "show what would change without writing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: dockwatch safety import [-n] file")
+	}
+	b, err := safety.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	cfg, pol, store, err := loadSafety(false)
+	if err != nil {
+		return err
+	}
+
+	ch := b.Merge(cfg, pol, store)
+	if len(ch.Ignore) > 0 {
+		fmt.Printf("Ignore: +%s\n", strings.Join(ch.Ignore, ", +"))
+	}
+	if len(ch.Protect) > 0 {
+		fmt.Printf("Protect rules: +%s\n", strings.Join(ch.Protect, ", +"))
+		if cfg.Policy.URL != "" {
+			fmt.Printf("Note: they go to %s, which policy.url overrides here; add them to the published policy too\n", config.PolicyPath())
+		}
+	}
+	if ch.Tags > 0 {
+		fmt.Printf("Tags: %d added\n", ch.Tags)
+	}
+	for _, name := range ch.Conflict {
+		fmt.Printf("Kept the local rule %q; the imported one has the same name\n", name)
+	}
+	switch {
+	case ch.Empty():
+		fmt.Println("Nothing to import; this machine already has all of it")
+		return nil
+	case *dryRun:
+		fmt.Println("Dry run: nothing written")
+		return nil
+	}
+
+	if len(ch.Ignore) > 0 {
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+	}
+	if err := b.AppendRules(config.PolicyPath(), ch.Protect); err != nil {
+		return err
+	}
+	if ch.Tags > 0 {
+		if err := store.Save(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadSafety loads what a safety file holds on this machine. With
+// effective set the protect rules are those of the policy this host follows,
+// policy.url's when set; otherwise they are the local policy file's, the one
+// an import writes to.
+func loadSafety(effective bool) (*config.Config, *policy.Policy, *notes.Store, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var pol *policy.Policy
+	if effective {
+		pol, err = loadPolicy("", cfg)
+	} else {
+		pol, err = policy.LoadIfExists(config.PolicyPath())
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	store, err := notes.Load()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return cfg, pol, store, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"dockwatch/internal/backup"
+	"dockwatch/internal/config"
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/policy"
+)
+
+// scheduleEvery is how often the agent checks for volumes due a backup.
+const scheduleEvery = time.Minute
+
+// scheduleBackups runs the backup rules of pol until ctx ends, checking for
+// due volumes at start and then every scheduleEvery. A failure is logged and
+// retried on the next check.
+func scheduleBackups(ctx context.Context, pol *policy.Policy, cfg *config.Config, docker *dockercli.DockerProvider, dryRun bool) {
+	tick := time.NewTicker(scheduleEvery)
+	defer tick.Stop()
+	for {
+		runBackupRules(ctx, pol, cfg, docker, time.Now(), dryRun)
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// runBackupRules backs up every matched volume whose newest archive is older
+// than its rule's interval, then prunes past the rule's retention. Volumes
+// are backed up one at a time so a schedule never loads the daemon with
+// several helper containers at once. In a dry run backups are still taken,
+// since they remove nothing, but expired archives are only logged.
+func runBackupRules(ctx context.Context, pol *policy.Policy, cfg *config.Config, docker *dockercli.DockerProvider, now time.Time, dryRun bool) {
+	c, err := backup.CipherFrom(cfg)
+	if err != nil {
+		log.Printf("backups: %v", err)
+		return
+	}
+	vols, err := docker.ListVolumes(ctx)
+	if err != nil {
+		log.Printf("backups: %v", err)
+		return
+	}
+	for _, r := range pol.Backups {
+		to := r.To
+		if to == "" {
+			to = cfg.BackupDestination()
+		}
+		dest, err := backup.ParseDestination(to, backup.S3OptionsFrom(cfg))
+		if err != nil {
+			log.Printf("backup %q: %v", r.Name, err)
+			continue
+		}
+		for _, v := range vols {
+			if ctx.Err() != nil {
+				return
+			}
+			if !r.Match.MatchVolume(v, now) {
+				continue
+			}
+			archives, err := backup.Archives(ctx, dest, v.Name)
+			if err != nil {
+				log.Printf("backup %q: %v", r.Name, err)
+				continue
+			}
+			if n := len(archives); n > 0 && now.Sub(archives[n-1].Taken) < r.Interval() {
+				continue
+			}
+			name := archiveName(v.Name, now, c)
+			if _, err := backupVolume(ctx, docker, v.Name, dest, name, c); err != nil {
+				log.Printf("backup %q: %s: %v", r.Name, v.Name, err)
+				continue
+			}
+			log.Printf("backup %q: %s -> %s", r.Name, v.Name, dest.Location(name))
+			if dryRun {
+				expired, err := backup.Expired(ctx, dest, v.Name, r.Keep)
+				for _, old := range expired {
+					log.Printf("backup %q: would remove %s past keep %d", r.Name, dest.Location(old.Name), r.Keep)
+				}
+				if err != nil {
+					log.Printf("backup %q: %v", r.Name, err)
+				}
+				continue
+			}
+			removed, err := backup.Prune(ctx, dest, v.Name, r.Keep)
+			for _, old := range removed {
+				log.Printf("backup %q: removed %s past keep %d", r.Name, dest.Location(old), r.Keep)
+			}
+			if err != nil {
+				log.Printf("backup %q: %v", r.Name, err)
+			}
+		}
+	}
+}
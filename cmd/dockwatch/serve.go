@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/provider"
+	"dockwatch/internal/remote"
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := fs.String("listen", "127.0.0.1:8080", "address to listen on")
+	token := fs.String("token", os.Getenv("DOCKWATCH_TOKEN"), "bearer token clients must send (default: $DOCKWATCH_TOKEN, else generated)")
+	perm := fs.String("permission", string(provider.PermPruneAny), "what -token may do: view, prune-orphans or prune-any")
+	tokensFile := fs.String("tokens", "", "YAML file of named tokens with per-token permissions (replaces -token)")
+	cert := fs.String("tls-cert", "", "TLS certificate file")
+	key := fs.String("tls-key", "", "TLS key file")
+	insecure := fs.Bool("insecure", false, "serve plain HTTP on a non-loopback -listen address, sending tokens in the clear")
+	policyFile := fs.String("policy", "", "policy file whose backup rules the agent runs (default: policy.url from the config, else policy.yaml)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*cert == "") != (*key == "") {
+		return errors.New("-tls-cert and -tls-key go together")
+	}
+	if *cert == "" && !loopback(*listen) {
+		if !*insecure {
+			return fmt.Errorf("refusing to serve plain HTTP on %s: tokens would cross the network in the clear; pass -tls-cert and -tls-key, or -insecure", *listen)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: serving plain HTTP on %s; tokens and volume data cross the network unencrypted\n", *listen)
+	}
+
+	tokens, err := serveTokens(*tokensFile, *token, *perm)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	// Always the local daemon: an agent proxying another agent is a loop
+	docker, err := dockercli.NewDockerProvider()
+	if err != nil {
+		return err
+	}
+	docker.SetLimits(dockercli.LimitsFrom(cfg))
+	docker.SetHelper(dockercli.HelperFrom(cfg))
+	defer docker.Close()
+
+	pol, err := loadPolicy(*policyFile, cfg)
+	if err != nil {
+		return err
+	}
+	if pol != nil && len(pol.Backups) > 0 {
+		fmt.Printf("Running %d backup rule(s) from %s\n", len(pol.Backups), policyName(*policyFile, cfg))
+		go scheduleBackups(context.Background(), pol, cfg, docker, provider.DryRunRequested())
+	}
+
+	metrics := &provider.Metrics{}
+	mws := []provider.Middleware{metrics.Middleware()}
+	if provider.DryRunRequested() {
+		mws = append(mws, provider.WithDryRun(log.Printf))
+		fmt.Println("Dry run: removals are logged, never executed")
+	}
+	if provider.TraceRequested() {
+		mws = append(mws, provider.Logging(log.Printf))
+	}
+	prov := provider.Chain(docker, mws...)
+
+	srv := &http.Server{Addr: *listen, Handler: remote.NewServer(prov, tokens).WithMetrics(metrics)}
+	scheme := "http"
+	if *cert != "" {
+		scheme = "https"
+	}
+	fmt.Printf("Serving the Docker provider on %s://%s\n", scheme, *listen)
+	if *cert != "" {
+		return srv.ListenAndServeTLS(*cert, *key)
+	}
+	return srv.ListenAndServe()
+}
+
+// loopback reports whether addr only listens on this machine. An empty
+// host listens on every interface, so it is not.
+func loopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// serveTokens loads the tokens file, or falls back to the single -token
+// (generating one when unset) with the given permission.
+func serveTokens(file, token, perm string) ([]remote.Token, error) {
+	if file != "" {
+		return remote.LoadTokens(file)
+	}
+	p, err := provider.ParsePermission(perm)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		buf := make([]byte, 24)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		token = hex.EncodeToString(buf)
+		fmt.Printf("Generated token (%s): %s\n", p, token)
+	}
+	return []remote.Token{{Name: "default", Secret: token, Permission: p}}, nil
+}
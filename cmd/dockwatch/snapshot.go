@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"dockwatch/internal/snapshot"
+)
+
+func runSnapshot(args []string) error {
+	fs := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	prov, err := openProvider()
+	if err != nil {
+		return err
+	}
+	defer prov.Close()
+
+	vols, err := prov.ListVolumes(context.Background())
+	if err != nil {
+		return err
+	}
+
+	s := snapshot.New(vols)
+	if err := s.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Snapshot of %d volume(s) saved to %s\n", len(vols), snapshot.Path())
+	return nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/domain"
+)
+
+// watchEvent is one JSON line of `watch -format json`.
+type watchEvent struct {
+	Time     time.Time `json:"time"`
+	Kind     string    `json:"kind"` // created, removed, resized, attached or orphaned
+	Volume   string    `json:"volume"`
+	Size     int64     `json:"size_bytes"`
+	OldSize  int64     `json:"old_size_bytes,omitempty"`
+	Attached []string  `json:"attached,omitempty"`
+}
+
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	format := fs.String("format", "table", "table (refreshed in place) or json (one line per change)")
+	interval := fs.Duration("interval", 5*time.Second, "time between refreshes")
+	var filters stringList
+	fs.Var(&filters, "filter", filterUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("unknown format %q (want table or json)", *format)
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	f, err := domain.ParseVolumeFilter(filters)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	prov, err := openProvider()
+	if err != nil {
+		return err
+	}
+	defer prov.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var prev []domain.Volume
+	first := true
+	tick := time.NewTicker(*interval)
+	defer tick.Stop()
+	for {
+		vols, err := listVolumes(ctx, prov, f)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			// A daemon hiccup shouldn't end a long-running watch
+			fmt.Fprintf(os.Stderr, "dockwatch watch: %v\n", err)
+		case *format == "table":
+			printWatchTable(vols, cfg, *interval)
+		case !first:
+			if err := printChanges(prev, vols); err != nil {
+				return err
+			}
+		}
+		if err == nil {
+			prev, first = vols, false
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick.C:
+		}
+	}
+}
+
+// printWatchTable redraws the table in place on a terminal; when piped, or
+// in plain mode, each refresh is appended under a timestamp instead.
+func printWatchTable(vols []domain.Volume, cfg *config.Config, interval time.Duration) {
+	now := time.Now()
+	redraw := isTerminal(os.Stdout) && !config.PlainRequested()
+	if redraw {
+		fmt.Print("\x1b[H\x1b[2J")
+	}
+	fmt.Printf("Every %s: %d volume(s)  %s\n\n", interval, len(vols), now.Format(time.TimeOnly))
+	printVolumes(os.Stdout, vols, cfg, now)
+	if !redraw {
+		fmt.Println()
+	}
+}
+
+// printChanges writes one JSON line per volume that appeared, disappeared,
+// changed size or gained/lost its last container between two listings.
+func printChanges(prev, cur []domain.Volume) error {
+	now := time.Now().UTC()
+	enc := json.NewEncoder(os.Stdout)
+	for _, c := range volumeChanges(prev, cur, 0) {
+		ev := watchEvent{Time: now, Kind: c.kind, Volume: c.vol.Name, Size: c.vol.SizeBytes, OldSize: c.oldSize, Attached: c.vol.Attached}
+		if c.kind == "removed" {
+			ev.Size = -1
+		}
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// volumeChange is a difference between two listings of the same volumes.
+type volumeChange struct {
+	kind    string // created, removed, resized, attached, orphaned or threshold
+	vol     domain.Volume
+	oldSize int64 // for resized and threshold
+}
+
+// volumeChanges compares two listings. With threshold > 0 a volume whose
+// size reaches it, or that appears already past it, also yields a
+// threshold change.
+func volumeChanges(prev, cur []domain.Volume, threshold int64) []volumeChange {
+	was := make(map[string]domain.Volume, len(prev))
+	for _, v := range prev {
+		was[v.Name] = v
+	}
+	var changes []volumeChange
+	for _, v := range cur {
+		old, ok := was[v.Name]
+		delete(was, v.Name)
+		if !ok {
+			changes = append(changes, volumeChange{kind: "created", vol: v})
+			if threshold > 0 && v.SizeBytes >= threshold {
+				changes = append(changes, volumeChange{kind: "threshold", vol: v, oldSize: -1})
+			}
+			continue
+		}
+		if old.SizeBytes >= 0 && v.SizeBytes >= 0 && old.SizeBytes != v.SizeBytes {
+			changes = append(changes, volumeChange{kind: "resized", vol: v, oldSize: old.SizeBytes})
+			if threshold > 0 && old.SizeBytes < threshold && v.SizeBytes >= threshold {
+				changes = append(changes, volumeChange{kind: "threshold", vol: v, oldSize: old.SizeBytes})
+			}
+		}
+		if old.Orphan() != v.Orphan() {
+			kind := "attached"
+			if v.Orphan() {
+				kind = "orphaned"
+			}
+			changes = append(changes, volumeChange{kind: kind, vol: v})
+		}
+	}
+	for _, v := range prev {
+		if _, gone := was[v.Name]; gone {
+			changes = append(changes, volumeChange{kind: "removed", vol: v})
+		}
+	}
+	return changes
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
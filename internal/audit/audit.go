@@ -0,0 +1,55 @@
+// Package audit keeps an append-only record of the prunes dockwatch ran:
+// what went, what failed and what a cancel left untouched, so quitting
+// mid-prune never leaves the outcome a mystery.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"dockwatch/internal/config"
+)
+
+// Record is the outcome of one prune or teardown.
+type Record struct {
+	Time      time.Time `json:"time"`
+	Action    string    `json:"action"` // "prune" or "teardown"
+	Removed   []string  `json:"removed"`
+	Failed    []string  `json:"failed,omitempty"`    // one error per failed removal
+	Untouched []string  `json:"untouched,omitempty"` // never started because of a cancel
+	Freed     int64     `json:"freedBytes"`
+	Cancelled bool      `json:"cancelled,omitempty"`
+	DryRun    bool      `json:"dryRun,omitempty"`
+	Quit      bool      `json:"quit,omitempty"` // dockwatch exited right after
+}
+
+// Path is where the records are appended, one JSON object per line.
+func Path() string {
+	return filepath.Join(config.StateDir(), "audit.jsonl")
+}
+
+// Append adds r to the log.
+func Append(r Record) error {
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.StateDir(), 0o755); err != nil {
+		return fmt.Errorf("failed to create state dir: %w", err)
+	}
+	f, err := os.OpenFile(Path(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return f.Close()
+}
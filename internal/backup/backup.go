@@ -0,0 +1,381 @@
+// Package backup archives volumes as gzipped tarballs and records a SHA256
+// manifest beside each archive — of the archive and of every file in it —
+// so an archive can be checked before it is trusted with a restore.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Manifest describes an archive and its contents. The manifest of an
+// encrypted archive seals its file list with the same cipher, so paths and
+// checksums of secrets aren't left in the clear beside it.
+type Manifest struct {
+	Volume     string    `json:"volume"`
+	Created    time.Time `json:"created"`
+	Archive    string    `json:"archive"`              // name of the archive, beside the manifest
+	Size       int64     `json:"size"`                 // of the archive as stored
+	SHA256     string    `json:"sha256"`               // of the archive as stored
+	Encryption string    `json:"encryption,omitempty"` // cipher name, see Cipher
+	Files      []File    `json:"files,omitempty"`      // regular files, in archive order
+	Sealed     []byte    `json:"sealed,omitempty"`     // the files, encrypted, in place of Files
+}
+
+// File is one regular file in an archive.
+type File struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestName is the name the manifest of archive is stored under, beside
+// it.
+func ManifestName(archive string) string {
+	return archive + ".manifest.json"
+}
+
+// FileName is the default archive name for a backup of volume taken at t.
+func FileName(volume string, t time.Time) string {
+	return fmt.Sprintf("%s-%s.tar.gz", volume, t.Format(stampLayout))
+}
+
+// Write reads a tar stream of volume's contents from r and stores it,
+// gzipped and encrypted with c unless c is nil, in dest under name, then
+// the manifest beside it. The archive appears only once complete, so a
+// manifest always has its archive.
+func Write(ctx context.Context, r io.Reader, volume string, dest Destination, name string, c Cipher) (*Manifest, error) {
+	obj, err := dest.Create(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+	m, err := writeArchive(r, volume, name, obj, c)
+	if err != nil {
+		obj.Abort()
+		return nil, err
+	}
+	if err := obj.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := writeManifest(ctx, dest, m, c); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func writeArchive(r io.Reader, volume, name string, w io.Writer, c Cipher) (m *Manifest, err error) {
+	sum := sha256.New()
+	size := &countWriter{}
+	var stored io.WriteCloser = nopWriteCloser{io.MultiWriter(w, sum, size)}
+	m = &Manifest{Volume: volume, Created: time.Now().UTC(), Archive: name}
+	if c != nil {
+		enc, err := c.Encrypt(stored)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt archive: %w", err)
+		}
+		defer func() {
+			if err != nil {
+				enc.Close() // stops age; the archive is aborted anyway
+			}
+		}()
+		stored, m.Encryption = enc, c.Name()
+	}
+	gz := gzip.NewWriter(stored)
+
+	// Hash each file on its way through to the archive
+	stream := io.TeeReader(r, gz)
+	tr := tar.NewReader(stream)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read volume contents: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		h := sha256.New()
+		n, err := io.Copy(h, tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+		m.Files = append(m.Files, File{Path: cleanPath(hdr.Name), Size: n, SHA256: hex.EncodeToString(h.Sum(nil))})
+	}
+	// The end-of-archive blocks the tar reader leaves unread
+	if _, err := io.Copy(io.Discard, stream); err != nil {
+		return nil, fmt.Errorf("failed to read volume contents: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := stored.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt archive: %w", err)
+	}
+	m.Size, m.SHA256 = size.n, hex.EncodeToString(sum.Sum(nil))
+	return m, nil
+}
+
+func writeManifest(ctx context.Context, dest Destination, m *Manifest, c Cipher) error {
+	if c != nil {
+		sealed, err := seal(m.Files, c)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt manifest: %w", err)
+		}
+		sm := *m
+		sm.Files, sm.Sealed = nil, sealed
+		m = &sm
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	obj, err := dest.Create(ctx, ManifestName(m.Archive))
+	if err == nil {
+		if _, err = obj.Write(append(data, '\n')); err == nil {
+			err = obj.Commit()
+		} else {
+			obj.Abort()
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadManifest loads the manifest stored beside archive in dest.
+func ReadManifest(ctx context.Context, dest Destination, archive string) (*Manifest, error) {
+	rc, err := dest.Open(ctx, ManifestName(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	defer rc.Close()
+	m := &Manifest{}
+	if err := json.NewDecoder(rc).Decode(m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", dest.Location(ManifestName(archive)), err)
+	}
+	return m, nil
+}
+
+// seal encrypts the file list of a manifest.
+func seal(files []File, c Cipher) ([]byte, error) {
+	data, err := json.Marshal(files)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc, err := c.Encrypt(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := enc.Write(data); err != nil {
+		enc.Close()
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unseal decrypts the file list of m, if it is sealed.
+func unseal(m *Manifest, c Cipher) ([]File, error) {
+	if m.Sealed == nil {
+		return m.Files, nil
+	}
+	rc, err := decrypt(bytes.NewReader(m.Sealed), m.Archive, c)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	var files []File
+	if err := json.NewDecoder(rc).Decode(&files); err != nil {
+		return nil, fmt.Errorf("failed to decrypt manifest: %w", err)
+	}
+	return files, nil
+}
+
+// OpenTar reads archive in dest back as the plain tar stream it was made
+// from, for unpacking into a volume, decrypting it with c if need be.
+func OpenTar(ctx context.Context, dest Destination, archive string, c Cipher) (io.ReadCloser, error) {
+	rc, err := dest.Open(ctx, archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	plain, err := decrypt(rc, archive, c)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	gz, err := gzip.NewReader(plain)
+	if err != nil {
+		plain.Close()
+		rc.Close()
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	return tarReader{gz, []io.Closer{plain, rc}}, nil
+}
+
+// tarReader closes the archive along with the readers stacked on it.
+type tarReader struct {
+	*gzip.Reader
+	under []io.Closer
+}
+
+func (t tarReader) Close() error {
+	t.Reader.Close()
+	var err error
+	for _, c := range t.under {
+		err = errors.Join(err, c.Close())
+	}
+	return err
+}
+
+// Problem is one way an archive disagrees with its manifest.
+type Problem struct {
+	Path   string // file in the archive; empty for the archive itself
+	Reason string
+}
+
+func (p Problem) String() string {
+	if p.Path == "" {
+		return "archive: " + p.Reason
+	}
+	return p.Path + ": " + p.Reason
+}
+
+// Verify checks archive in dest against its manifest: the archive's
+// checksum, then every file in it, decrypting with c if need be. It
+// returns the manifest and what disagrees, nothing when the archive is
+// intact; the error is for a missing manifest, an archive that can't be
+// fetched, or one that can't be decrypted with c.
+func Verify(ctx context.Context, dest Destination, archive string, c Cipher) (*Manifest, []Problem, error) {
+	m, err := ReadManifest(ctx, dest, archive)
+	if err != nil {
+		return nil, nil, err
+	}
+	wantFiles, err := unseal(m, c)
+	if err != nil {
+		return m, nil, err
+	}
+	f, err := dest.Open(ctx, archive)
+	if err != nil {
+		return m, nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	var problems []Problem
+	sum := sha256.New()
+	size := &countWriter{}
+	var files []File
+	plain, readErr := decrypt(io.TeeReader(f, io.MultiWriter(sum, size)), archive, c)
+	if readErr == nil {
+		files, readErr = archiveFiles(plain)
+		plain.Close()
+	}
+	// Hash what the contents check didn't need, e.g. after corruption
+	if _, err := io.Copy(io.MultiWriter(sum, size), f); err != nil {
+		return m, nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	if got := hex.EncodeToString(sum.Sum(nil)); got != m.SHA256 || size.n != m.Size {
+		problems = append(problems, Problem{Reason: fmt.Sprintf("checksum %s (%d bytes), manifest says %s (%d bytes)", short(got), size.n, short(m.SHA256), m.Size)})
+	}
+	if readErr != nil {
+		return m, append(problems, Problem{Reason: "unreadable: " + readErr.Error()}), nil
+	}
+
+	want := make(map[string]File, len(wantFiles))
+	for _, fl := range wantFiles {
+		want[fl.Path] = fl
+	}
+	for _, fl := range files {
+		w, ok := want[fl.Path]
+		switch {
+		case !ok:
+			problems = append(problems, Problem{Path: fl.Path, Reason: "not in the manifest"})
+		case w.SHA256 != fl.SHA256 || w.Size != fl.Size:
+			problems = append(problems, Problem{Path: fl.Path, Reason: fmt.Sprintf("checksum %s, manifest says %s", short(fl.SHA256), short(w.SHA256))})
+		}
+		delete(want, fl.Path)
+	}
+	missing := make([]string, 0, len(want))
+	for p := range want {
+		missing = append(missing, p)
+	}
+	slices.Sort(missing)
+	for _, p := range missing {
+		problems = append(problems, Problem{Path: p, Reason: "missing from the archive"})
+	}
+	return m, problems, nil
+}
+
+// archiveFiles hashes the regular files of a gzipped tar stream.
+func archiveFiles(r io.Reader) ([]File, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	var files []File
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return files, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		h := sha256.New()
+		n, err := io.Copy(h, tr)
+		if err != nil {
+			return files, err
+		}
+		files = append(files, File{Path: cleanPath(hdr.Name), Size: n, SHA256: hex.EncodeToString(h.Sum(nil))})
+	}
+	// Reading to the end checks the gzip trailer's CRC too
+	if _, err := io.Copy(io.Discard, gz); err != nil {
+		return files, err
+	}
+	return files, gz.Close()
+}
+
+// cleanPath drops the "./" tar puts before paths relative to the volume.
+func cleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// short abbreviates a checksum for messages.
+func short(sum string) string {
+	if len(sum) > 12 {
+		return sum[:12]
+	}
+	return sum
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+type countWriter struct{ n int64 }
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,106 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// volumeTar is the tar stream the helper container produces for a volume.
+func volumeTar(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "./", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		body := files[name]
+		if err := tw.WriteHeader(&tar.Header{Name: "./" + name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(body))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+func TestWriteThenVerify(t *testing.T) {
+	dest := Local{Dir: t.TempDir()}
+	archive := filepath.Join(dest.Dir, "pgdata.tar.gz")
+	m, err := Write(context.Background(), volumeTar(t, map[string]string{"PG_VERSION": "16\n", "base/1/1259": "rows"}), "pgdata", dest, "pgdata.tar.gz", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != 2 || m.Volume != "pgdata" || m.Archive != "pgdata.tar.gz" {
+		t.Fatalf("manifest %+v, want both files of pgdata", m)
+	}
+
+	if _, problems, err := Verify(context.Background(), dest, "pgdata.tar.gz", nil); err != nil || len(problems) > 0 {
+		t.Fatalf("fresh archive: %v %v, want intact", problems, err)
+	}
+
+	// A flipped byte in the middle breaks the archive checksum and the
+	// contents with it
+	data, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)/2] ^= 0xff
+	if err := os.WriteFile(archive, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, problems, err := Verify(context.Background(), dest, "pgdata.tar.gz", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) == 0 || problems[0].Path != "" {
+		t.Errorf("corrupted archive: %v, want an archive checksum problem first", problems)
+	}
+}
+
+func TestVerifyReportsFileDrift(t *testing.T) {
+	ctx := context.Background()
+	dest := Local{Dir: t.TempDir()}
+	if _, err := Write(ctx, volumeTar(t, map[string]string{"a": "one"}), "cache", dest, "cache.tar.gz", nil); err != nil {
+		t.Fatal(err)
+	}
+	// Swap in an archive of other contents, with a matching archive checksum
+	om, err := Write(ctx, volumeTar(t, map[string]string{"a": "two", "b": "new"}), "cache", dest, "other.tar.gz", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := ReadManifest(ctx, dest, "cache.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Archive, m.Size, m.SHA256 = om.Archive, om.Size, om.SHA256
+	m.Files = append(m.Files, File{Path: "gone", SHA256: "00"})
+	if err := writeManifest(ctx, dest, m, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	_, problems, err := Verify(ctx, dest, "other.tar.gz", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a: checksum", "b: not in the manifest", "gone: missing from the archive"}
+	if len(problems) != len(want) {
+		t.Fatalf("problems %v, want %v", problems, want)
+	}
+	for i, p := range problems {
+		if got := p.String(); !strings.HasPrefix(got, want[i]) {
+			t.Errorf("problem %d is %q, want %q…", i, got, want[i])
+		}
+	}
+}
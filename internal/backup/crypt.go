@@ -0,0 +1,364 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"dockwatch/internal/config"
+)
+
+// Cipher encrypts archives on their way to a destination and decrypts them
+// on the way back. The archive's checksum in the manifest is of what is
+// stored, so an encrypted archive's integrity checks without the key.
+type Cipher interface {
+	// Name is recorded in the manifest, e.g. "age".
+	Name() string
+	// Ext is appended to the names of archives it encrypts.
+	Ext() string
+	Encrypt(w io.Writer) (io.WriteCloser, error)
+	Decrypt(r io.Reader) (io.ReadCloser, error)
+}
+
+// CipherFrom returns the cipher backup.encryption configures, or nil when
+// archives are stored in the clear.
+func CipherFrom(cfg *config.Config) (Cipher, error) {
+	e := cfg.Backup.Encryption
+	pass := e.Passphrase
+	if env := os.Getenv("DOCKWATCH_BACKUP_PASSPHRASE"); env != "" {
+		pass = env
+	}
+	switch {
+	case pass != "" && (e.Recipient != "" || e.Identity != ""):
+		return nil, errors.New("backup.encryption: use an age recipient or a passphrase, not both")
+	case pass != "":
+		return Passphrase(pass), nil
+	case e.Recipient != "" || e.Identity != "":
+		return Age{Recipient: e.Recipient, Identity: expandHome(e.Identity)}, nil
+	}
+	return nil, nil
+}
+
+// ciphers are the encryptions archive names can carry, by extension.
+var ciphers = map[string]string{ageExt: ageName, aesExt: aesName}
+
+// encryptionOf names the encryption archive was stored with, from its
+// extension; "" for none.
+func encryptionOf(archive string) string {
+	return ciphers[filepath.Ext(archive)]
+}
+
+// decrypt undoes the encryption archive was stored with, if any, using c.
+func decrypt(r io.Reader, archive string, c Cipher) (io.ReadCloser, error) {
+	enc := encryptionOf(archive)
+	switch {
+	case enc == "":
+		return io.NopCloser(r), nil
+	case c == nil:
+		return nil, fmt.Errorf("%s is encrypted with %s; set backup.encryption to decrypt it", archive, enc)
+	case c.Name() != enc:
+		return nil, fmt.Errorf("%s is encrypted with %s, but backup.encryption configures %s", archive, enc, c.Name())
+	}
+	return c.Decrypt(r)
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+const (
+	ageName = "age"
+	ageExt  = ".age"
+)
+
+// Age encrypts to an age recipient through the age CLI, which has to be
+// on the PATH; decrypting needs the matching identity file.
+type Age struct {
+	Recipient string
+	Identity  string
+}
+
+func (Age) Name() string { return ageName }
+func (Age) Ext() string  { return ageExt }
+
+func (a Age) Encrypt(w io.Writer) (io.WriteCloser, error) {
+	if a.Recipient == "" {
+		return nil, errors.New("age: no recipient; set backup.encryption.recipient")
+	}
+	cmd := exec.Command("age", "-r", a.Recipient)
+	cmd.Stdout = w
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := startAge(cmd); err != nil {
+		return nil, err
+	}
+	return &ageWriter{WriteCloser: in, cmd: cmd}, nil
+}
+
+func (a Age) Decrypt(r io.Reader) (io.ReadCloser, error) {
+	if a.Identity == "" {
+		return nil, errors.New("age: no identity; set backup.encryption.identity")
+	}
+	cmd := exec.Command("age", "-d", "-i", a.Identity)
+	cmd.Stdin = r
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := startAge(cmd); err != nil {
+		return nil, err
+	}
+	return &ageReader{ReadCloser: out, cmd: cmd}, nil
+}
+
+func startAge(cmd *exec.Cmd) error {
+	cmd.Stderr = &bytes.Buffer{}
+	if err := cmd.Start(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return errors.New("age: the age CLI is not installed (https://age-encryption.org)")
+		}
+		return fmt.Errorf("age: %w", err)
+	}
+	return nil
+}
+
+// waitAge waits for the age process and says why it failed.
+func waitAge(cmd *exec.Cmd) error {
+	if err := cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(cmd.Stderr.(*bytes.Buffer).String()); msg != "" {
+			return fmt.Errorf("age: %s", msg)
+		}
+		return fmt.Errorf("age: %w", err)
+	}
+	return nil
+}
+
+// ageWriter feeds age; closing it waits for the last of the ciphertext.
+type ageWriter struct {
+	io.WriteCloser
+	cmd *exec.Cmd
+}
+
+func (w *ageWriter) Close() error {
+	w.WriteCloser.Close()
+	return waitAge(w.cmd)
+}
+
+// ageReader reads what age decrypted. The exit status is what tells a
+// complete plaintext from a cut-off one, so it surfaces at EOF.
+type ageReader struct {
+	io.ReadCloser
+	cmd  *exec.Cmd
+	done bool
+}
+
+func (r *ageReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if errors.Is(err, io.EOF) && !r.done {
+		r.done = true
+		if werr := waitAge(r.cmd); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+func (r *ageReader) Close() error {
+	if r.done {
+		return nil
+	}
+	r.done = true
+	r.ReadCloser.Close()
+	r.cmd.Wait()
+	return nil
+}
+
+const (
+	aesName = "aes-256-gcm"
+	aesExt  = ".enc"
+
+	// aesMagic starts every passphrase-encrypted stream.
+	aesMagic = "dockwatch-aes1\n"
+	// aesChunk is how much plaintext each sealed chunk holds.
+	aesChunk = 64 << 10
+	// kdfIterations of PBKDF2-HMAC-SHA256 stretch the passphrase.
+	kdfIterations = 600_000
+)
+
+// Passphrase encrypts with AES-256-GCM under a key derived from a
+// passphrase. The stream is sealed in chunks whose nonces count up and
+// flag the last one, so chunks can't be reordered, dropped or truncated
+// unnoticed. Each stream has a salt of its own, hence a key of its own.
+//
+//	magic | salt (16) | iterations (uint32) | chunk… (each ≤ 64 KiB + tag)
+type Passphrase string
+
+func (Passphrase) Name() string { return aesName }
+func (Passphrase) Ext() string  { return aesExt }
+
+func (p Passphrase) Encrypt(w io.Writer) (io.WriteCloser, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := p.aead(salt, kdfIterations)
+	if err != nil {
+		return nil, err
+	}
+	header := append([]byte(aesMagic), salt...)
+	header = binary.BigEndian.AppendUint32(header, kdfIterations)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &sealer{w: w, aead: aead}, nil
+}
+
+func (p Passphrase) Decrypt(r io.Reader) (io.ReadCloser, error) {
+	header := make([]byte, len(aesMagic)+16+4)
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:len(aesMagic)]) != aesMagic {
+		return nil, errors.New("not a passphrase-encrypted archive")
+	}
+	salt := header[len(aesMagic) : len(aesMagic)+16]
+	// Bounded, so a forged header can't keep us hashing for hours
+	iterations := binary.BigEndian.Uint32(header[len(aesMagic)+16:])
+	if iterations == 0 || iterations > 100*kdfIterations {
+		return nil, fmt.Errorf("passphrase-encrypted archive asks for %d key derivation rounds", iterations)
+	}
+	aead, err := p.aead(salt, int(iterations))
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(&opener{r: bufio.NewReaderSize(r, aesChunk+aead.Overhead()+1), aead: aead}), nil
+}
+
+func (p Passphrase) aead(salt []byte, iterations int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2([]byte(p), salt, iterations, 32))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce is the nonce of chunk n: its number, then 1 for the last.
+func chunkNonce(n uint64, last bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce[3:11], n)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// sealer encrypts a chunk whenever a full one is buffered, and the rest,
+// possibly nothing, as the last chunk on Close.
+type sealer struct {
+	w    io.Writer
+	aead cipher.AEAD
+	buf  []byte
+	n    uint64
+}
+
+func (s *sealer) Write(p []byte) (int, error) {
+	s.buf = append(s.buf, p...)
+	// A full chunk stays buffered until more follows: it may be the last
+	for len(s.buf) > aesChunk {
+		if err := s.seal(s.buf[:aesChunk], false); err != nil {
+			return 0, err
+		}
+		s.buf = s.buf[aesChunk:]
+	}
+	return len(p), nil
+}
+
+func (s *sealer) seal(chunk []byte, last bool) error {
+	_, err := s.w.Write(s.aead.Seal(nil, chunkNonce(s.n, last), chunk, nil))
+	s.n++
+	return err
+}
+
+func (s *sealer) Close() error {
+	return s.seal(s.buf, true)
+}
+
+// opener decrypts the chunks of a sealer. A chunk is the last if the
+// stream ends after it, and must say so.
+type opener struct {
+	r    *bufio.Reader
+	aead cipher.AEAD
+	buf  []byte
+	n    uint64
+	done bool
+}
+
+var errTampered = errors.New("decryption failed: wrong passphrase, or the archive was modified")
+
+func (o *opener) Read(p []byte) (int, error) {
+	for len(o.buf) == 0 {
+		if o.done {
+			return 0, io.EOF
+		}
+		sealed := make([]byte, aesChunk+o.aead.Overhead())
+		n, err := io.ReadFull(o.r, sealed)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		_, peekErr := o.r.Peek(1)
+		last := n < len(sealed) || errors.Is(peekErr, io.EOF)
+		plain, openErr := o.aead.Open(nil, chunkNonce(o.n, last), sealed[:n], nil)
+		if openErr != nil {
+			if last && n > 0 {
+				// Sealed as not the last: the stream was cut short
+				if _, err := o.aead.Open(nil, chunkNonce(o.n, false), sealed[:n], nil); err == nil {
+					return 0, errors.New("decryption failed: the archive is truncated")
+				}
+			}
+			return 0, errTampered
+		}
+		o.buf, o.n, o.done = plain, o.n+1, last
+	}
+	n := copy(p, o.buf)
+	o.buf = o.buf[n:]
+	return n, nil
+}
+
+// pbkdf2 derives a key of keyLen bytes with PBKDF2-HMAC-SHA256 (RFC 8018).
+func pbkdf2(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, block))
+		u := prf.Sum(nil)
+		t := bytes.Clone(u)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
@@ -0,0 +1,102 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The PBKDF2-HMAC-SHA256 vector from RFC 7914, section 11.
+func TestPBKDF2(t *testing.T) {
+	got := hex.EncodeToString(pbkdf2([]byte("passwd"), []byte("salt"), 1, 64))
+	want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+		"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestPassphraseRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dest := Local{Dir: t.TempDir()}
+	c := Passphrase("correct horse")
+	// Several chunks, and random data so gzip doesn't shrink them to one
+	big := make([]byte, 3*aesChunk+100)
+	rand.Read(big)
+	name := FileName("vault", time.Now()) + c.Ext()
+	if _, err := Write(ctx, volumeTar(t, map[string]string{"big": string(big), "token": "hunter2"}), "vault", dest, name, c); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(dest.Dir, ManifestName(name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(manifest, []byte("token")) || !bytes.Contains(manifest, []byte(`"encryption": "aes-256-gcm"`)) {
+		t.Errorf("manifest leaks the file list or misses the encryption:\n%s", manifest)
+	}
+	if m, problems, err := Verify(ctx, dest, name, c); err != nil || len(problems) > 0 || m.Volume != "vault" {
+		t.Fatalf("verify: %v %v, want intact", problems, err)
+	}
+	if _, _, err := Verify(ctx, dest, name, nil); err == nil || !strings.Contains(err.Error(), "set backup.encryption") {
+		t.Errorf("verify without a key: %v", err)
+	}
+	if _, _, err := Verify(ctx, dest, name, Passphrase("wrong")); !errors.Is(err, errTampered) {
+		t.Errorf("verify with the wrong passphrase: %v", err)
+	}
+
+	rc, err := OpenTar(ctx, dest, name, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(rc)
+	got := map[string]int{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, _ := io.Copy(io.Discard, tr)
+		got[hdr.Name] = int(n)
+	}
+	rc.Close()
+	if got["./big"] != len(big) || got["./token"] != len("hunter2") {
+		t.Errorf("restored %v", got)
+	}
+
+	// Cut off after the first chunk: each chunk still decrypts, but the
+	// stream no longer ends on the one sealed as last
+	path := filepath.Join(dest.Dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data[:len(aesMagic)+20+aesChunk+16], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, problems, err := Verify(ctx, dest, name, c)
+	if err != nil || len(problems) != 2 || !strings.Contains(problems[1].Reason, "truncated") {
+		t.Errorf("truncated archive: %v %v, want a checksum and a truncation problem", problems, err)
+	}
+}
+
+func TestParseFileNameEncrypted(t *testing.T) {
+	taken := time.Date(2025, 3, 9, 2, 30, 0, 0, time.Local)
+	for _, ext := range []string{".age", ".enc"} {
+		if v, got, ok := ParseFileName(FileName("db", taken) + ext); !ok || v != "db" || !got.Equal(taken) {
+			t.Errorf("%s: got %q %v %v", ext, v, got, ok)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Destination is where archives and their manifests are stored.
+type Destination interface {
+	// Create starts writing the object name. Nothing is visible under
+	// that name until Commit; Abort throws the object away.
+	Create(ctx context.Context, name string) (Object, error)
+	// Open reads the object name back.
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// List returns the objects whose names start with prefix, in no
+	// particular order. Objects still being written aren't listed.
+	List(ctx context.Context, prefix string) ([]Entry, error)
+	// Remove deletes the object name.
+	Remove(ctx context.Context, name string) error
+	// Location spells out where name is stored, for messages.
+	Location(name string) string
+}
+
+// Entry is an object listed in a destination.
+type Entry struct {
+	Name string
+	Size int64
+}
+
+// Object is an object being written to a destination.
+type Object interface {
+	io.Writer
+	Commit() error
+	Abort()
+}
+
+// ParseDestination returns the destination target names: an
+// s3://bucket/prefix URL, or else a local directory.
+func ParseDestination(target string, opts S3Options) (Destination, error) {
+	if rest, ok := strings.CutPrefix(target, "s3://"); ok {
+		bucket, prefix, _ := strings.Cut(rest, "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("%s: no bucket", target)
+		}
+		return NewS3(bucket, prefix, opts)
+	}
+	return Local{Dir: target}, nil
+}
+
+// Locate splits the location of an archive, as printed by Location, into
+// its destination and name.
+func Locate(archive string, opts S3Options) (Destination, string, error) {
+	if strings.HasPrefix(archive, "s3://") {
+		i := strings.LastIndex(archive, "/")
+		if i < len("s3://") {
+			return nil, "", fmt.Errorf("%s: no object name", archive)
+		}
+		dest, err := ParseDestination(archive[:i], opts)
+		return dest, archive[i+1:], err
+	}
+	return Local{Dir: filepath.Dir(archive)}, filepath.Base(archive), nil
+}
+
+// Local stores archives as files in a directory.
+type Local struct {
+	Dir string
+}
+
+func (l Local) Create(_ context.Context, name string) (Object, error) {
+	if err := os.MkdirAll(l.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(l.Dir, name)
+	f, err := os.Create(path + ".partial")
+	if err != nil {
+		return nil, err
+	}
+	return &localObject{File: f, path: path}, nil
+}
+
+func (l Local) Open(_ context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(l.Dir, name))
+}
+
+func (l Local) List(_ context.Context, prefix string) ([]Entry, error) {
+	dirents, err := os.ReadDir(l.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, e := range dirents {
+		name := e.Name()
+		if !e.Type().IsRegular() || !strings.HasPrefix(name, prefix) || strings.HasSuffix(name, ".partial") {
+			continue
+		}
+		// Gone since the listing, e.g. pruned meanwhile
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{Name: name, Size: info.Size()})
+	}
+	return entries, nil
+}
+
+func (l Local) Remove(_ context.Context, name string) error {
+	return os.Remove(filepath.Join(l.Dir, name))
+}
+
+func (l Local) Location(name string) string {
+	return filepath.Join(l.Dir, name)
+}
+
+// localObject is written beside its final path and renamed into place.
+type localObject struct {
+	*os.File
+	path string
+}
+
+func (o *localObject) Commit() error {
+	if err := o.Sync(); err != nil {
+		o.Abort()
+		return err
+	}
+	if err := o.Close(); err != nil {
+		os.Remove(o.Name())
+		return err
+	}
+	return os.Rename(o.Name(), o.path)
+}
+
+func (o *localObject) Abort() {
+	o.Close()
+	os.Remove(o.Name())
+}
@@ -0,0 +1,388 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"dockwatch/internal/config"
+)
+
+// S3Options are the connection settings of s3:// destinations.
+type S3Options struct {
+	Endpoint     string // e.g. https://minio.lan:9000; empty for AWS
+	Region       string // default us-east-1
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// S3OptionsFrom reads the backup.s3 section of cfg, falling back to the
+// standard AWS environment variables for whatever it leaves out.
+func S3OptionsFrom(cfg *config.Config) S3Options {
+	c := cfg.Backup.S3
+	pick := func(v string, envs ...string) string {
+		for _, env := range envs {
+			if v == "" {
+				v = os.Getenv(env)
+			}
+		}
+		return v
+	}
+	return S3Options{
+		Endpoint:     pick(c.Endpoint, "AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"),
+		Region:       pick(c.Region, "AWS_REGION", "AWS_DEFAULT_REGION"),
+		AccessKey:    pick(c.AccessKey, "AWS_ACCESS_KEY_ID"),
+		SecretKey:    pick(c.SecretKey, "AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// S3 stores archives in a bucket of AWS S3 or a compatible service such as
+// MinIO. Archives stream up in parts, so no local disk is needed.
+type S3 struct {
+	bucket string
+	prefix string // key prefix, without trailing slash
+	opts   S3Options
+	base   *url.URL // bucket URL
+	client *http.Client
+}
+
+// minPart is the smallest part S3 accepts but for the last one; parts grow
+// as an upload does, so the 10000-part limit is never reached.
+const minPart = 8 << 20
+
+// NewS3 returns the destination for prefix in bucket. A custom endpoint is
+// addressed path-style, as MinIO expects; AWS virtual-hosted style.
+func NewS3(bucket, prefix string, opts S3Options) (*S3, error) {
+	if opts.AccessKey == "" || opts.SecretKey == "" {
+		return nil, errors.New("s3: no credentials; set backup.s3 in the config or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	var base *url.URL
+	var err error
+	if opts.Endpoint != "" {
+		base, err = url.Parse(strings.TrimSuffix(opts.Endpoint, "/") + "/" + bucket)
+	} else {
+		base, err = url.Parse(fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, opts.Region))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("s3: endpoint: %w", err)
+	}
+	return &S3{bucket: bucket, prefix: strings.Trim(prefix, "/"), opts: opts, base: base, client: http.DefaultClient}, nil
+}
+
+func (s *S3) key(name string) string {
+	if s.prefix == "" {
+		return name
+	}
+	return s.prefix + "/" + name
+}
+
+func (s *S3) Location(name string) string {
+	return "s3://" + s.bucket + "/" + s.key(name)
+}
+
+func (s *S3) Create(ctx context.Context, name string) (Object, error) {
+	return &s3Object{s: s, ctx: ctx, key: s.key(name)}, nil
+}
+
+func (s *S3) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.key(name), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3) List(ctx context.Context, prefix string) ([]Entry, error) {
+	base := s.key("")
+	query := url.Values{"list-type": {"2"}, "prefix": {base + prefix}}
+	var entries []Entry
+	for {
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Contents []struct {
+				Key  string `xml:"Key"`
+				Size int64  `xml:"Size"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("s3: listing %s: %w", s.Location(prefix), err)
+		}
+		for _, c := range page.Contents {
+			// Only objects right under the prefix, as a directory would list
+			if name := strings.TrimPrefix(c.Key, base); !strings.Contains(name, "/") {
+				entries = append(entries, Entry{Name: name, Size: c.Size})
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return entries, nil
+		}
+		query.Set("continuation-token", page.NextContinuationToken)
+	}
+}
+
+func (s *S3) Remove(ctx context.Context, name string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.key(name), nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// s3Object buffers a part at a time. An object that fits in one part is
+// uploaded with a plain PUT on Commit; a bigger one as a multipart upload.
+type s3Object struct {
+	s        *S3
+	ctx      context.Context
+	key      string
+	buf      bytes.Buffer
+	uploadID string
+	parts    []completedPart
+}
+
+type completedPart struct {
+	Number int    `xml:"PartNumber"`
+	ETag   string `xml:"ETag"`
+}
+
+func (o *s3Object) partSize() int {
+	return minPart << (len(o.parts) / 1000)
+}
+
+func (o *s3Object) Write(p []byte) (int, error) {
+	o.buf.Write(p)
+	for o.buf.Len() >= o.partSize() {
+		if err := o.uploadPart(o.buf.Next(o.partSize())); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (o *s3Object) uploadPart(part []byte) error {
+	if o.uploadID == "" {
+		resp, err := o.s.do(o.ctx, http.MethodPost, o.key, url.Values{"uploads": {""}}, nil)
+		if err != nil {
+			return err
+		}
+		var res struct {
+			UploadID string `xml:"UploadId"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&res)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("s3: starting upload: %w", err)
+		}
+		o.uploadID = res.UploadID
+	}
+	n := len(o.parts) + 1
+	resp, err := o.s.do(o.ctx, http.MethodPut, o.key, url.Values{"partNumber": {strconv.Itoa(n)}, "uploadId": {o.uploadID}}, part)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	o.parts = append(o.parts, completedPart{Number: n, ETag: resp.Header.Get("ETag")})
+	return nil
+}
+
+func (o *s3Object) Commit() error {
+	if o.uploadID == "" {
+		resp, err := o.s.do(o.ctx, http.MethodPut, o.key, nil, o.buf.Bytes())
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+	if o.buf.Len() > 0 {
+		if err := o.uploadPart(o.buf.Bytes()); err != nil {
+			o.Abort()
+			return err
+		}
+	}
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: o.parts})
+	if err != nil {
+		return err
+	}
+	resp, err := o.s.do(o.ctx, http.MethodPost, o.key, url.Values{"uploadId": {o.uploadID}}, body)
+	if err != nil {
+		o.Abort()
+		return err
+	}
+	defer resp.Body.Close()
+	// A completion can fail after the 200 has been sent
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(data, []byte("<Error>")) {
+		return s3Error(resp.StatusCode, data)
+	}
+	return nil
+}
+
+func (o *s3Object) Abort() {
+	if o.uploadID == "" {
+		return
+	}
+	// Best effort, on a fresh context: the upload's may be why we abort
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if resp, err := o.s.do(ctx, http.MethodDelete, o.key, url.Values{"uploadId": {o.uploadID}}, nil); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// do sends a signed request for key and returns the response of a 2xx
+// status; any other status is turned into an error.
+func (s *S3) do(ctx context.Context, method, key string, query url.Values, body []byte) (*http.Response, error) {
+	u := *s.base
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + key
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = canonicalQuery(query)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	sum := sha256.Sum256(body)
+	s.sign(req, hex.EncodeToString(sum[:]), time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return nil, s3Error(resp.StatusCode, data)
+	}
+	return resp, nil
+}
+
+func s3Error(status int, body []byte) error {
+	var e struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Errorf("s3: %s: %s", e.Code, e.Message)
+	}
+	return fmt.Errorf("s3: %s", http.StatusText(status))
+}
+
+// sign adds an AWS Signature Version 4 to req, covering the host and every
+// header already set.
+func (s *S3) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if s.opts.SessionToken != "" {
+		req.Header.Set("x-amz-security-token", s.opts.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var canonHeaders strings.Builder
+	for _, name := range names {
+		canonHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonHeaders.String(),
+		signed,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.opts.Region + "/s3/aws4_request"
+	hashed := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.opts.SecretKey), date)
+	for _, part := range []string{s.opts.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.opts.AccessKey, scope, signed, hex.EncodeToString(hmacSHA256(key, toSign))))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalQuery encodes query sorted by key, as SigV4 requires and S3
+// accepts.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything but the unreserved characters, and
+// slashes too unless encodeSlash is false.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Archive is a backup found in a destination by its name.
+type Archive struct {
+	Name      string
+	Volume    string
+	Taken     time.Time
+	Size      int64
+	Encrypted bool
+}
+
+const stampLayout = "20060102-150405"
+
+// ParseFileName reads the volume and time back from a name made by
+// FileName, with or without a Cipher's extension; ok is false for any
+// other name.
+func ParseFileName(name string) (volume string, taken time.Time, ok bool) {
+	if encryptionOf(name) != "" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	rest, found := strings.CutSuffix(name, ".tar.gz")
+	if !found || len(rest) < len(stampLayout)+2 || rest[len(rest)-len(stampLayout)-1] != '-' {
+		return "", time.Time{}, false
+	}
+	taken, err := time.ParseInLocation(stampLayout, rest[len(rest)-len(stampLayout):], time.Local)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return rest[:len(rest)-len(stampLayout)-1], taken, true
+}
+
+// Archives lists the backups of volume in dest, oldest first. Only archives
+// named by FileName count, so ones named by hand are never pruned.
+func Archives(ctx context.Context, dest Destination, volume string) ([]Archive, error) {
+	entries, err := dest.List(ctx, volume+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups of %s: %w", volume, err)
+	}
+	var out []Archive
+	for _, a := range archives(entries) {
+		// "db-20250101-..." lists under "db-" too; the volume is "db"
+		if a.Volume == volume {
+			out = append(out, a)
+		}
+	}
+	slices.SortFunc(out, func(a, b Archive) int { return a.Taken.Compare(b.Taken) })
+	return out, nil
+}
+
+// Index lists every backup in dest, by volume name and newest first within
+// a volume.
+func Index(ctx context.Context, dest Destination) ([]Archive, error) {
+	entries, err := dest.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dest.Location(""), err)
+	}
+	out := archives(entries)
+	slices.SortFunc(out, func(a, b Archive) int {
+		if c := strings.Compare(a.Volume, b.Volume); c != 0 {
+			return c
+		}
+		return b.Taken.Compare(a.Taken)
+	})
+	return out, nil
+}
+
+// archives picks the entries named by FileName.
+func archives(entries []Entry) []Archive {
+	var out []Archive
+	for _, e := range entries {
+		if v, taken, ok := ParseFileName(e.Name); ok {
+			out = append(out, Archive{Name: e.Name, Volume: v, Taken: taken, Size: e.Size, Encrypted: encryptionOf(e.Name) != ""})
+		}
+	}
+	return out
+}
+
+// Expired returns the backups of volume in dest past the newest keep, the
+// ones Prune would remove, oldest first. A keep of 0 keeps them all.
+func Expired(ctx context.Context, dest Destination, volume string, keep int) ([]Archive, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+	archives, err := Archives(ctx, dest, volume)
+	if err != nil || len(archives) <= keep {
+		return nil, err
+	}
+	return archives[:len(archives)-keep], nil
+}
+
+// Prune removes all but the newest keep backups of volume from dest, each
+// with its manifest, and returns the names of the archives removed. A keep
+// of 0 keeps them all.
+func Prune(ctx context.Context, dest Destination, volume string, keep int) ([]string, error) {
+	expired, err := Expired(ctx, dest, volume, keep)
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, a := range expired {
+		// The archive goes first: a stray manifest is only clutter, but an
+		// archive without one can't be verified
+		if err := dest.Remove(ctx, a.Name); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", dest.Location(a.Name), err)
+		}
+		if err := dest.Remove(ctx, ManifestName(a.Name)); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", dest.Location(ManifestName(a.Name)), err)
+		}
+		removed = append(removed, a.Name)
+	}
+	return removed, nil
+}
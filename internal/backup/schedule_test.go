@@ -0,0 +1,72 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseFileName(t *testing.T) {
+	taken := time.Date(2025, 3, 9, 2, 30, 0, 0, time.Local)
+	v, got, ok := ParseFileName(FileName("app-db-data", taken))
+	if !ok || v != "app-db-data" || !got.Equal(taken) {
+		t.Errorf("got %q %v %v, want app-db-data at %v", v, got, ok, taken)
+	}
+	for _, name := range []string{"pgdata.tar.gz", "pgdata-20250309.tar.gz", "x-20250309-023000.tar.gz.manifest.json", "-20250309-023000.tar.gz"} {
+		if _, _, ok := ParseFileName(name); ok {
+			t.Errorf("%s parsed as a scheduled backup", name)
+		}
+	}
+}
+
+func TestPruneKeepsNewest(t *testing.T) {
+	ctx := context.Background()
+	dest := Local{Dir: t.TempDir()}
+	day := time.Date(2025, 3, 1, 3, 0, 0, 0, time.Local)
+	for i := range 4 {
+		name := FileName("db", day.AddDate(0, 0, i))
+		if _, err := Write(ctx, volumeTar(t, map[string]string{"a": "x"}), "db", dest, name, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Neither another volume sharing the prefix nor a hand-named archive
+	// is a backup of db
+	for _, name := range []string{FileName("db-old", day), "db-before-upgrade.tar.gz"} {
+		if err := os.WriteFile(filepath.Join(dest.Dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := Prune(ctx, dest, "db", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{FileName("db", day), FileName("db", day.AddDate(0, 0, 1))}; !slices.Equal(removed, want) {
+		t.Errorf("removed %v, want the two oldest %v", removed, want)
+	}
+	entries, err := dest.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	for _, e := range entries {
+		left = append(left, e.Name)
+	}
+	slices.Sort(left)
+	want := []string{
+		FileName("db-old", day), "db-before-upgrade.tar.gz",
+		FileName("db", day.AddDate(0, 0, 2)), ManifestName(FileName("db", day.AddDate(0, 0, 2))),
+		FileName("db", day.AddDate(0, 0, 3)), ManifestName(FileName("db", day.AddDate(0, 0, 3))),
+	}
+	slices.Sort(want)
+	if !slices.Equal(left, want) {
+		t.Errorf("left %v, want %v", left, want)
+	}
+
+	if removed, _ := Prune(ctx, dest, "db", 0); removed != nil {
+		t.Errorf("keep 0 removed %v", removed)
+	}
+}
@@ -0,0 +1,60 @@
+// Package clipboard copies text to the system clipboard using whatever
+// helper the platform provides, falling back to the OSC 52 terminal escape
+// so it also works over SSH.
+package clipboard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// helpers are tried in order; the first one installed wins.
+func helpers() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	}
+	var hs [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		hs = append(hs, []string{"wl-copy"})
+	}
+	if os.Getenv("DISPLAY") != "" {
+		hs = append(hs, []string{"xclip", "-selection", "clipboard"}, []string{"xsel", "--clipboard", "--input"})
+	}
+	// WSL
+	return append(hs, []string{"clip.exe"})
+}
+
+// Copy puts text on the clipboard.
+func Copy(text string) error {
+	for _, h := range helpers() {
+		if _, err := exec.LookPath(h[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(h[0], h[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %w", h[0], err)
+		}
+		return nil
+	}
+	return osc52(text)
+}
+
+// osc52 asks the terminal itself to set the clipboard. Terminals that
+// don't support it ignore the sequence, so success isn't guaranteed.
+func osc52(text string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("no clipboard helper found and no terminal to fall back on")
+	}
+	defer tty.Close()
+	_, err = fmt.Fprintf(tty, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
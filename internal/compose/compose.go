@@ -0,0 +1,205 @@
+// Package compose finds the volumes that compose files on disk declare, so
+// deleting one a stack still expects can be warned about, and one deleted
+// by accident recreated the way compose would.
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the compose file names looked for, in compose's own order
+// of preference.
+var FileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// Ref is a compose file declaring a volume.
+type Ref struct {
+	Name    string // the Docker volume name
+	File    string
+	Project string
+	Key     string // the volume's key under the top-level volumes
+	// External volumes are never created by compose: `up` fails once they
+	// are gone. The others are recreated empty.
+	External bool
+
+	// How compose would create it
+	Driver     string
+	DriverOpts map[string]string
+	Labels     map[string]string
+}
+
+// CreateLabels are the labels to recreate the volume with: its own, plus
+// the ones compose puts on volumes it creates, so a later `up` adopts the
+// volume instead of warning that something else made it.
+func (r Ref) CreateLabels() map[string]string {
+	labels := map[string]string{}
+	for k, v := range r.Labels {
+		labels[k] = v
+	}
+	if !r.External {
+		labels["com.docker.compose.project"] = r.Project
+		labels["com.docker.compose.volume"] = r.Key
+	}
+	return labels
+}
+
+// Warning is what deleting the volume would do to the stack.
+func (r Ref) Warning() string {
+	if r.External {
+		return fmt.Sprintf("declared external in %s; `up` will fail without it", r.File)
+	}
+	return fmt.Sprintf("declared in %s; `up` will recreate it empty", r.File)
+}
+
+// Index maps Docker volume names to the compose files declaring them.
+type Index map[string][]Ref
+
+// Scan reads the compose files in each of dirs and in their immediate
+// subdirectories, which covers both a single project directory and a
+// directory of checkouts. Files that don't parse are skipped; the first
+// such error is returned along with everything that did.
+func Scan(dirs []string) (Index, error) {
+	idx := Index{}
+	var firstErr error
+	for _, dir := range dirs {
+		candidates := []string{dir}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if firstErr == nil && !errors.Is(err, fs.ErrNotExist) {
+				firstErr = err
+			}
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				candidates = append(candidates, filepath.Join(dir, e.Name()))
+			}
+		}
+		for _, d := range candidates {
+			for _, name := range FileNames {
+				file := filepath.Join(d, name)
+				if _, err := os.Stat(file); err != nil {
+					continue
+				}
+				if err := idx.add(file); err != nil && firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+	for _, refs := range idx {
+		sort.Slice(refs, func(i, j int) bool { return refs[i].File < refs[j].File })
+	}
+	return idx, firstErr
+}
+
+// file is the part of a compose file that declares volumes.
+type file struct {
+	Name    string                `yaml:"name"`
+	Volumes map[string]*volumeDef `yaml:"volumes"`
+}
+
+type volumeDef struct {
+	Name       string            `yaml:"name"`
+	External   yaml.Node         `yaml:"external"` // true, or {name: …} in the legacy format
+	Driver     string            `yaml:"driver"`
+	DriverOpts map[string]string `yaml:"driver_opts"`
+	Labels     yaml.Node         `yaml:"labels"` // a mapping, or a list of key=value
+}
+
+func (d *volumeDef) labels() (map[string]string, error) {
+	labels := map[string]string{}
+	switch d.Labels.Kind {
+	case yaml.MappingNode:
+		if err := d.Labels.Decode(&labels); err != nil {
+			return nil, err
+		}
+	case yaml.SequenceNode:
+		var list []string
+		if err := d.Labels.Decode(&list); err != nil {
+			return nil, err
+		}
+		for _, kv := range list {
+			k, v, _ := strings.Cut(kv, "=")
+			labels[k] = v
+		}
+	}
+	return labels, nil
+}
+
+func (idx Index) add(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	project := f.Name
+	if project == "" || strings.Contains(project, "$") {
+		project = projectName(filepath.Base(filepath.Dir(path)))
+	}
+	for key, def := range f.Volumes {
+		if def == nil {
+			def = &volumeDef{}
+		}
+		name, external := def.resolve(key, project)
+		// Interpolated names are only known to compose at run time
+		if strings.Contains(name, "$") {
+			continue
+		}
+		labels, err := def.labels()
+		if err != nil {
+			return fmt.Errorf("%s: volume %s: labels: %w", path, key, err)
+		}
+		idx[name] = append(idx[name], Ref{
+			Name: name, File: path, Project: project, Key: key, External: external,
+			Driver: def.Driver, DriverOpts: def.DriverOpts, Labels: labels,
+		})
+	}
+	return nil
+}
+
+// resolve is the Docker volume name compose uses for key, and whether the
+// volume is external.
+func (d *volumeDef) resolve(key, project string) (string, bool) {
+	external := false
+	legacyName := ""
+	switch d.External.Kind {
+	case yaml.ScalarNode:
+		external = d.External.Value == "true"
+	case yaml.MappingNode:
+		var legacy struct {
+			Name string `yaml:"name"`
+		}
+		external = d.External.Decode(&legacy) == nil
+		legacyName = legacy.Name
+	}
+	switch {
+	case d.Name != "":
+		return d.Name, external
+	case legacyName != "":
+		return legacyName, true
+	case external:
+		return key, true
+	}
+	return project + "_" + key, false
+}
+
+var notInProject = regexp.MustCompile(`[^a-z0-9_-]`)
+
+// projectName is compose's default project name for a directory: its
+// name, lowercased, with anything but letters, digits, dashes and
+// underscores dropped.
+func projectName(dir string) string {
+	return notInProject.ReplaceAllString(strings.ToLower(dir), "")
+}
@@ -0,0 +1,108 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	shop := filepath.Join(root, "Shop.App", "compose.yaml")
+	writeFile(t, shop, `
+services:
+  db:
+    image: postgres
+volumes:
+  pgdata:
+  uploads:
+    external: true
+  certs:
+    name: shared-certs
+    driver: local
+    driver_opts:
+      type: nfs
+      o: addr=10.0.0.2,rw
+    labels:
+      - tier=edge
+  legacy:
+    external:
+      name: old-cache
+  runtime:
+    name: ${STACK}_runtime
+`)
+	named := filepath.Join(root, "ci", "docker-compose.yml")
+	writeFile(t, named, `
+name: runners
+volumes:
+  cache: {}
+  certs:
+    external: true
+    name: shared-certs
+`)
+	// Hidden directories are skipped, and broken files don't stop the scan
+	writeFile(t, filepath.Join(root, ".git", "compose.yaml"), "volumes: {stray: {}}")
+	writeFile(t, filepath.Join(root, "broken", "compose.yml"), "volumes: [")
+
+	idx, err := Scan([]string{root, filepath.Join(root, "missing")})
+	if err == nil {
+		t.Error("the broken file wasn't reported")
+	}
+	want := map[string][]Ref{
+		"shopapp_pgdata": {{File: shop, Key: "pgdata"}},
+		"uploads":        {{File: shop, Key: "uploads", External: true}},
+		"old-cache":      {{File: shop, Key: "legacy", External: true}},
+		"runners_cache":  {{File: named, Key: "cache"}},
+		"shared-certs":   {{File: shop, Key: "certs"}, {File: named, Key: "certs", External: true}},
+	}
+	if len(idx) != len(want) {
+		t.Errorf("got %v, want %d volumes", idx, len(want))
+	}
+	for name, refs := range want {
+		got := idx[name]
+		if len(got) != len(refs) {
+			t.Errorf("%s: got %v, want %v", name, got, refs)
+			continue
+		}
+		for i := range refs {
+			if g := got[i]; g.Name != name || g.File != refs[i].File || g.Key != refs[i].Key || g.External != refs[i].External {
+				t.Errorf("%s: got %+v, want %+v", name, g, refs[i])
+			}
+		}
+	}
+}
+
+func TestCreateLabels(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "shop", "compose.yaml"), `
+volumes:
+  pgdata:
+    labels:
+      backup: nightly
+  certs:
+    external: true
+    labels: [tier=edge]
+`)
+	idx, err := Scan([]string{root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pg := idx["shop_pgdata"][0].CreateLabels()
+	if len(pg) != 3 || pg["backup"] != "nightly" || pg["com.docker.compose.project"] != "shop" || pg["com.docker.compose.volume"] != "pgdata" {
+		t.Errorf("pgdata labels %v", pg)
+	}
+	// compose never owned an external volume
+	if certs := idx["certs"][0].CreateLabels(); len(certs) != 1 || certs["tier"] != "edge" {
+		t.Errorf("certs labels %v", certs)
+	}
+}
@@ -0,0 +1,540 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"dockwatch/internal/domain"
+)
+
+// Config is the user configuration stored in config.yaml.
+type Config struct {
+	// Ignore lists volume names (or globs) excluded from orphan counts and plans.
+	Ignore []string `yaml:"ignore,omitempty"`
+
+	Images ImageConfig `yaml:"images,omitempty"`
+
+	Prune PruneConfig `yaml:"prune,omitempty"`
+
+	Concurrency ConcurrencyConfig `yaml:"concurrency,omitempty"`
+
+	// LabelColumns adds volume table columns showing label values, e.g.
+	// owner or team.
+	LabelColumns []LabelColumn `yaml:"labelColumns,omitempty"`
+
+	// Columns are the volume table column titles in order, used when the
+	// active view doesn't pick its own; default the standard set.
+	Columns []string `yaml:"columns,omitempty"`
+
+	// Views are named filter, sort and column presets for the volume list.
+	Views []View `yaml:"views,omitempty"`
+
+	History HistoryConfig `yaml:"history,omitempty"`
+
+	Chargeback ChargebackConfig `yaml:"chargeback,omitempty"`
+
+	Orphans OrphanConfig `yaml:"orphans,omitempty"`
+
+	Colors ColorConfig `yaml:"colors,omitempty"`
+
+	Backup BackupConfig `yaml:"backup,omitempty"`
+
+	Compose ComposeConfig `yaml:"compose,omitempty"`
+
+	Helper HelperConfig `yaml:"helper,omitempty"`
+
+	Policy PolicyConfig `yaml:"policy,omitempty"`
+
+	// Context is the docker context dockwatch uses when neither DOCKER_HOST
+	// nor DOCKER_CONTEXT picks one; default the CLI's current context.
+	Context string `yaml:"context,omitempty"`
+
+	// Theme is the TUI's color theme, one of Themes; default dark.
+	Theme string `yaml:"theme,omitempty"`
+
+	// Glyphs is "unicode" or "ascii", for consoles that can't show
+	// Unicode; default auto, which goes by the locale. See ASCII.
+	Glyphs string `yaml:"glyphs,omitempty"`
+
+	path   string
+	broken error // why loading the file failed; Save refuses then
+}
+
+// Themes are the color themes the TUI offers.
+var Themes = []string{"dark", "light"}
+
+// View is a saved volume list configuration.
+type View struct {
+	Name      string   `yaml:"name"`
+	Filter    []string `yaml:"filter,omitempty"`    // as for `dockwatch list --filter`, e.g. dangling=true
+	OlderThan string   `yaml:"olderThan,omitempty"` // e.g. "30d"
+	Sort      string   `yaml:"sort,omitempty"`      // size, age, lastUsed or name
+	Columns   []string `yaml:"columns,omitempty"`   // column titles in order; default all
+}
+
+// ViewSorts are the accepted View.Sort values.
+var ViewSorts = []string{"", "size", "age", "lastUsed", "name"}
+
+// SetView adds v, replacing a view of the same name.
+func (c *Config) SetView(v View) {
+	for i := range c.Views {
+		if c.Views[i].Name == v.Name {
+			c.Views[i] = v
+			return
+		}
+	}
+	c.Views = append(c.Views, v)
+}
+
+// LabelColumn is an extra volume column sourced from a label. In YAML it is
+// either the bare label key or a mapping with a title and width.
+type LabelColumn struct {
+	Label string `yaml:"label"`
+	Title string `yaml:"title,omitempty"` // default: the label key
+	Width int    `yaml:"width,omitempty"` // default: 12
+}
+
+func (c *LabelColumn) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&c.Label)
+	}
+	type plain LabelColumn
+	return node.Decode((*plain)(c))
+}
+
+// Heading returns the column title.
+func (c LabelColumn) Heading() string {
+	if c.Title != "" {
+		return c.Title
+	}
+	return c.Label
+}
+
+// ColumnWidth returns the column width in cells.
+func (c LabelColumn) ColumnWidth() int {
+	if c.Width > 0 {
+		return c.Width
+	}
+	return 12
+}
+
+// PruneConfig paces removals so a big prune doesn't hammer the daemon.
+type PruneConfig struct {
+	// BatchSize is how many objects are removed between pauses (default 10).
+	BatchSize int `yaml:"batchSize,omitempty"`
+
+	// Delay is the pause between batches as a Go duration, e.g. "500ms"
+	// (default none).
+	Delay string `yaml:"delay,omitempty"`
+}
+
+// ConcurrencyConfig bounds how many docker operations run at once.
+type ConcurrencyConfig struct {
+	// Inspects is how many volumes are inspected at once while the list
+	// loads (default 4).
+	Inspects int `yaml:"inspects,omitempty"`
+
+	// Removals is how many objects of a kind a prune removes at once
+	// (default 1).
+	Removals int `yaml:"removals,omitempty"`
+
+	// SizeScans is how many volume sizes are measured at once (default 2).
+	SizeScans int `yaml:"sizeScans,omitempty"`
+
+	// AutoThrottle lowers the parallelism while the daemon answers slowly
+	// (default true).
+	AutoThrottle *bool `yaml:"autoThrottle,omitempty"`
+}
+
+// Parallelism returns the configured inspect, removal and size scan
+// limits, defaulted, and whether they throttle under load.
+func (c *Config) Parallelism() (inspects, removals, sizeScans int, autoThrottle bool) {
+	cc := c.Concurrency
+	inspects, removals, sizeScans = cc.Inspects, cc.Removals, cc.SizeScans
+	if inspects <= 0 {
+		inspects = 4
+	}
+	if removals <= 0 {
+		removals = 1
+	}
+	if sizeScans <= 0 {
+		sizeScans = 2
+	}
+	return inspects, removals, sizeScans, cc.AutoThrottle == nil || *cc.AutoThrottle
+}
+
+// HistoryConfig controls the usage history database.
+type HistoryConfig struct {
+	// Retention is how long samples are kept, e.g. "90d" (default 90d).
+	Retention string `yaml:"retention,omitempty"`
+}
+
+// HistoryRetention returns how long history samples are kept.
+func (c *Config) HistoryRetention() time.Duration {
+	// LoadFile rejects malformed retentions, so an error here means unset
+	if d, err := domain.ParseAge(c.History.Retention); err == nil && d > 0 {
+		return d
+	}
+	return 90 * 24 * time.Hour
+}
+
+// OrphanConfig guards against flagging volumes orphan that scheduled jobs
+// still need between runs.
+type OrphanConfig struct {
+	// MinIdle keeps volumes used or created more recently than this,
+	// e.g. "7d" (default off).
+	MinIdle string `yaml:"minIdle,omitempty"`
+
+	// KeepLabels keeps volumes carrying any of these label keys.
+	KeepLabels []string `yaml:"keepLabels,omitempty"`
+}
+
+// OrphanMinIdle returns the configured grace period, zero when off.
+func (c *Config) OrphanMinIdle() time.Duration {
+	// LoadFile rejects malformed ages, so an error here means unset
+	d, _ := domain.ParseAge(c.Orphans.MinIdle)
+	return d
+}
+
+// ColorConfig tunes the volume table's highlighting.
+type ColorConfig struct {
+	// HugeVolume highlights the size of volumes at least this large, e.g.
+	// "10GB" (the default); "0" turns it off.
+	HugeVolume string `yaml:"hugeVolume,omitempty"`
+}
+
+// HugeVolumeSize returns the highlight threshold in bytes, 0 when off.
+func (c *Config) HugeVolumeSize() int64 {
+	if c.Colors.HugeVolume == "" {
+		return 10 << 30
+	}
+	// LoadFile rejects malformed sizes, so an error here means unset
+	n, _ := domain.ParseSize(c.Colors.HugeVolume)
+	return n
+}
+
+// BackupConfig controls `dockwatch backup`.
+type BackupConfig struct {
+	// Destination is where archives go unless -to says otherwise: a
+	// directory or an s3://bucket/prefix URL (default the current
+	// directory).
+	Destination string `yaml:"destination,omitempty"`
+
+	S3         S3Config         `yaml:"s3,omitempty"`
+	Encryption EncryptionConfig `yaml:"encryption,omitempty"`
+}
+
+// EncryptionConfig encrypts archives, either to an age recipient or with
+// a passphrase. Left empty, archives are stored in the clear.
+type EncryptionConfig struct {
+	Recipient  string `yaml:"recipient,omitempty"`  // age recipient (age1… or an SSH public key), used through the age CLI
+	Identity   string `yaml:"identity,omitempty"`   // age identity file, to decrypt for verify and restore
+	Passphrase string `yaml:"passphrase,omitempty"` // AES-256-GCM instead of age; $DOCKWATCH_BACKUP_PASSPHRASE overrides
+}
+
+// S3Config connects to S3 or a compatible service such as MinIO. Fields
+// left out fall back to the standard AWS environment variables.
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint,omitempty"` // e.g. https://minio.lan:9000; default AWS
+	Region    string `yaml:"region,omitempty"`   // default us-east-1
+	AccessKey string `yaml:"accessKey,omitempty"`
+	SecretKey string `yaml:"secretKey,omitempty"`
+}
+
+// BackupDestination returns where archives go by default.
+func (c *Config) BackupDestination() string {
+	if c.Backup.Destination == "" {
+		return "."
+	}
+	return c.Backup.Destination
+}
+
+// ComposeConfig points at compose projects on disk, so deleting a volume
+// one of them declares can be warned about.
+type ComposeConfig struct {
+	// Dirs are scanned for compose files, each along with its immediate
+	// subdirectories; "~/" expands to the home directory.
+	Dirs []string `yaml:"dirs,omitempty"`
+}
+
+// ComposeDirs returns the compose project directories to scan.
+func (c *Config) ComposeDirs() []string {
+	home, _ := os.UserHomeDir()
+	dirs := make([]string, 0, len(c.Compose.Dirs))
+	for _, d := range c.Compose.Dirs {
+		if rest, ok := strings.CutPrefix(d, "~/"); ok && home != "" {
+			d = filepath.Join(home, rest)
+		}
+		dirs = append(dirs, d)
+	}
+	return dirs
+}
+
+// HelperConfig picks the image of the throwaway containers backups,
+// restores, content scans and log truncation run in.
+type HelperConfig struct {
+	// Image is any image with a shell, tar, find, stat and truncate, e.g.
+	// a mirror of alpine or busybox on an air-gapped host (default
+	// alpine:3).
+	Image string `yaml:"image,omitempty"`
+
+	// Pull is when docker pulls it: missing (the default), never, to only
+	// use a local copy, or always.
+	Pull string `yaml:"pull,omitempty"`
+}
+
+// PolicyConfig points dockwatch at a centrally managed cleanup policy
+// instead of the local policy.yaml.
+type PolicyConfig struct {
+	// URL is where the policy is published; it must be https. Next to it,
+	// URL.sig holds the base64 ed25519 signature of the file.
+	URL string `yaml:"url,omitempty"`
+
+	// PublicKey is the base64 ed25519 key the signature must verify with,
+	// as printed by `dockwatch policy keygen`. Required with URL.
+	PublicKey string `yaml:"publicKey,omitempty"`
+}
+
+// Key decodes PublicKey.
+func (p PolicyConfig) Key() (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(p.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("publicKey is not a base64 ed25519 public key")
+	}
+	return key, nil
+}
+
+// ChargebackConfig controls `dockwatch chargeback`.
+type ChargebackConfig struct {
+	// Label is the label key naming a resource's owner (default "team").
+	Label string `yaml:"label,omitempty"`
+}
+
+// ChargebackLabel returns the label key usage is attributed by.
+func (c *Config) ChargebackLabel() string {
+	if c.Chargeback.Label == "" {
+		return "team"
+	}
+	return c.Chargeback.Label
+}
+
+// ImageConfig tunes the image cleanup heuristics.
+type ImageConfig struct {
+	// UnusedDays is how old an image without containers must be before
+	// it counts as unused (default 30).
+	UnusedDays int `yaml:"unusedDays,omitempty"`
+
+	// CheckRegistry queries each tag's registry when the Images view loads
+	// to tell re-pullable images from local-only ones.
+	CheckRegistry bool `yaml:"checkRegistry,omitempty"`
+
+	// Scanner picks the vulnerability scanner, "trivy" or "grype"; empty
+	// uses whichever is installed.
+	Scanner string `yaml:"scanner,omitempty"`
+}
+
+// UnusedImageAge returns the configured unused-image threshold.
+func (c *Config) UnusedImageAge() time.Duration {
+	days := c.Images.UnusedDays
+	if days <= 0 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// PruneBatch returns the configured batch size and delay between batches.
+func (c *Config) PruneBatch() (int, time.Duration) {
+	size := c.Prune.BatchSize
+	if size <= 0 {
+		size = 10
+	}
+	// LoadFile rejects malformed delays, so an error here means unset
+	delay, _ := time.ParseDuration(c.Prune.Delay)
+	return size, delay
+}
+
+// Path is the location of the user configuration file.
+func Path() string {
+	return filepath.Join(Dir(), "config.yaml")
+}
+
+// Load reads the user configuration. A missing file yields an empty config.
+func Load() (*Config, error) {
+	return LoadFile(Path())
+}
+
+// LoadFile reads a configuration from an explicit path. On error the
+// config returned holds whatever was read before it; it works with defaults
+// but won't Save, so a typo in the file doesn't lose the rest of it.
+func LoadFile(file string) (*Config, error) {
+	c, err := loadFile(file)
+	c.broken = err
+	return c, err
+}
+
+func loadFile(file string) (*Config, error) {
+	c := &Config{path: file}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return c, fmt.Errorf("failed to parse config %s: %w", file, err)
+	}
+	for i, col := range c.LabelColumns {
+		if col.Label == "" {
+			return c, fmt.Errorf("config %s: labelColumns[%d]: label is required", file, i)
+		}
+	}
+	for _, v := range c.Views {
+		if err := v.validate(); err != nil {
+			return c, fmt.Errorf("config %s: view %q: %w", file, v.Name, err)
+		}
+	}
+	if c.Prune.Delay != "" {
+		if _, err := time.ParseDuration(c.Prune.Delay); err != nil {
+			return c, fmt.Errorf("config %s: prune.delay: %w", file, err)
+		}
+	}
+	if cc := c.Concurrency; cc.Inspects < 0 || cc.Removals < 0 || cc.SizeScans < 0 {
+		return c, fmt.Errorf("config %s: concurrency: limits must not be negative", file)
+	}
+	if e := c.Backup.Encryption; e.Passphrase != "" && (e.Recipient != "" || e.Identity != "") {
+		return c, fmt.Errorf("config %s: backup.encryption: use an age recipient or a passphrase, not both", file)
+	}
+	if !slices.Contains([]string{"", "auto", "unicode", "ascii"}, c.Glyphs) {
+		return c, fmt.Errorf("config %s: glyphs: %q is not auto, unicode or ascii", file, c.Glyphs)
+	}
+	if !slices.Contains([]string{"", "trivy", "grype"}, c.Images.Scanner) {
+		return c, fmt.Errorf("config %s: images.scanner: %q is not trivy or grype", file, c.Images.Scanner)
+	}
+	if c.Theme != "" && !slices.Contains(Themes, c.Theme) {
+		return c, fmt.Errorf("config %s: theme: %q is not one of %s", file, c.Theme, strings.Join(Themes, ", "))
+	}
+	if !slices.Contains([]string{"", "missing", "never", "always"}, c.Helper.Pull) {
+		return c, fmt.Errorf("config %s: helper.pull: %q is not missing, never or always", file, c.Helper.Pull)
+	}
+	if c.Policy.URL != "" {
+		if u, err := url.Parse(c.Policy.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+			return c, fmt.Errorf("config %s: policy.url: %q is not an https URL", file, c.Policy.URL)
+		}
+		if _, err := c.Policy.Key(); err != nil {
+			return c, fmt.Errorf("config %s: policy: %w", file, err)
+		}
+	}
+	if c.History.Retention != "" {
+		if _, err := domain.ParseAge(c.History.Retention); err != nil {
+			return c, fmt.Errorf("config %s: history.retention: %w", file, err)
+		}
+	}
+	if c.Colors.HugeVolume != "" {
+		if _, err := domain.ParseSize(c.Colors.HugeVolume); err != nil {
+			return c, fmt.Errorf("config %s: colors.hugeVolume: %w", file, err)
+		}
+	}
+	if c.Orphans.MinIdle != "" {
+		if _, err := domain.ParseAge(c.Orphans.MinIdle); err != nil {
+			return c, fmt.Errorf("config %s: orphans.minIdle: %w", file, err)
+		}
+	}
+	return c, nil
+}
+
+func (v View) validate() error {
+	if v.Name == "" {
+		return errors.New("name is required")
+	}
+	if _, err := domain.ParseVolumeFilter(v.Filter); err != nil {
+		return err
+	}
+	if v.OlderThan != "" {
+		if _, err := domain.ParseAge(v.OlderThan); err != nil {
+			return err
+		}
+	}
+	if !slices.Contains(ViewSorts, v.Sort) {
+		return fmt.Errorf("unknown sort %q (want size, age, lastUsed or name)", v.Sort)
+	}
+	return nil
+}
+
+// Save writes the configuration back to the file it was loaded from.
+func (c *Config) Save() error {
+	if c.path == "" {
+		c.path = Path()
+	}
+	if c.broken != nil {
+		return fmt.Errorf("not saving over %s, which failed to load; fix it first: %w", c.path, c.broken)
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	// Credentials keep the file to its owner, an existing file included
+	dirMode, mode := os.FileMode(0o755), os.FileMode(0o644)
+	if c.hasSecrets() {
+		dirMode, mode = 0o700, 0o600
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), dirMode); err != nil {
+		return fmt.Errorf("failed to create config dir: %w", err)
+	}
+	f, err := os.OpenFile(c.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// hasSecrets reports whether the config holds credentials: S3 keys or a
+// backup passphrase.
+func (c *Config) hasSecrets() bool {
+	return c.Backup.S3.AccessKey != "" || c.Backup.S3.SecretKey != "" || c.Backup.Encryption.Passphrase != ""
+}
+
+// IsIgnored reports whether a volume name matches any ignore entry.
+func (c *Config) IsIgnored(name string) bool {
+	for _, pat := range c.Ignore {
+		if pat == name {
+			return true
+		}
+		if ok, _ := path.Match(pat, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleIgnore adds name to the ignore list, or removes it if already present.
+// It returns true when the name is ignored afterwards.
+func (c *Config) ToggleIgnore(name string) bool {
+	for i, pat := range c.Ignore {
+		if pat == name {
+			c.Ignore = append(c.Ignore[:i], c.Ignore[i+1:]...)
+			return false
+		}
+	}
+	c.Ignore = append(c.Ignore, name)
+	return true
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// PlainRequested reports whether plain output is asked for, for screen
+// readers and dumb terminals: --plain sets DOCKWATCH_PLAIN, and a terminal
+// that declares itself dumb asks too.
+func PlainRequested() bool {
+	return os.Getenv("DOCKWATCH_PLAIN") != "" || os.Getenv("TERM") == "dumb"
+}
+
+// NoColorRequested reports whether colors are turned off: --no-color sets
+// NO_COLOR (https://no-color.org), and plain output has none either.
+func NoColorRequested() bool {
+	return os.Getenv("NO_COLOR") != "" || PlainRequested()
+}
+
+// ASCII reports whether output is drawn in ASCII only: glyphs: ascii, or
+// by default a locale whose character set isn't UTF-8.
+func (c *Config) ASCII() bool {
+	switch c.Glyphs {
+	case "ascii":
+		return true
+	case "unicode":
+		return false
+	}
+	return !utf8Locale()
+}
+
+// utf8Locale reports whether the locale's character set is UTF-8, going by
+// the first of LC_ALL, LC_CTYPE and LANG that is set, as setlocale does.
+// Without any, UTF-8 is assumed: most terminals are, and plenty of
+// containers and macOS shells set no locale at all.
+func utf8Locale() bool {
+	for _, env := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := strings.ToLower(os.Getenv(env)); v != "" {
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Dir returns the dockwatch configuration directory
+// ($XDG_CONFIG_HOME/dockwatch, falling back to the OS default).
+func Dir() string {
+	if d := os.Getenv("DOCKWATCH_CONFIG_DIR"); d != "" {
+		return d
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		base = "."
+	}
+	return filepath.Join(base, "dockwatch")
+}
+
+// PolicyPath is the default location of the cleanup policy file.
+func PolicyPath() string {
+	return filepath.Join(Dir(), "policy.yaml")
+}
+
+// StateDir returns the directory for data dockwatch writes on its own
+// (snapshots, session state), following $XDG_STATE_HOME.
+func StateDir() string {
+	if d := os.Getenv("DOCKWATCH_STATE_DIR"); d != "" {
+		return d
+	}
+	if d := os.Getenv("XDG_STATE_HOME"); d != "" {
+		return filepath.Join(d, "dockwatch")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(Dir(), "state")
+	}
+	return filepath.Join(home, ".local", "state", "dockwatch")
+}
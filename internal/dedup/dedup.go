@@ -0,0 +1,242 @@
+// Package dedup finds volumes that appear to hold the same data, such as a
+// CI cache copied for every runner, from fingerprints that hash a sample of
+// each file rather than all of it.
+package dedup
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	// sampleSize is how much of a file is hashed at its start, middle
+	// and end. Smaller files are hashed whole.
+	sampleSize = 4 << 10
+	// Alike is the share of its data a volume must have in common with
+	// another for the two to be reported.
+	Alike = 0.9
+)
+
+// key identifies a file's content: its size and a hash of its samples.
+// Names don't take part, so a copy under another directory still matches.
+type key struct {
+	size   int64
+	sample [sha256.Size]byte
+}
+
+// Fingerprint is the sampled content of one volume.
+type Fingerprint struct {
+	Volume string
+	Files  int
+	Bytes  int64
+	files  map[key]int // content -> number of files with it
+}
+
+func newFingerprint(volume string) *Fingerprint {
+	return &Fingerprint{Volume: volume, files: map[key]int{}}
+}
+
+func (f *Fingerprint) add(k key) {
+	f.files[k]++
+	f.Files++
+	f.Bytes += k.size
+}
+
+// windows are the offsets of the samples of a file of size bytes, in order
+// and not overlapping.
+func windows(size int64) []int64 {
+	if size <= 3*sampleSize {
+		return nil
+	}
+	return []int64{0, size/2 - sampleSize/2, size - sampleSize}
+}
+
+// FromDir fingerprints the files under root, typically the mountpoint of a
+// volume on a local daemon. Only the samples of each file are read.
+func FromDir(volume, root string) (*Fingerprint, error) {
+	fp := newFingerprint(volume)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		k, err := sampleAt(f, info.Size())
+		if err != nil {
+			return err
+		}
+		fp.add(k)
+		return nil
+	})
+	return fp, err
+}
+
+// FromTar fingerprints the regular files of a tar stream, such as a volume
+// exported through a helper container. The whole stream is read, but only
+// the samples are hashed.
+func FromTar(volume string, r io.Reader) (*Fingerprint, error) {
+	fp := newFingerprint(volume)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return fp, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		k, err := sampleStream(tr, hdr.Size)
+		if err != nil {
+			return nil, err
+		}
+		fp.add(k)
+	}
+}
+
+func sampleAt(r io.ReaderAt, size int64) (key, error) {
+	h := sha256.New()
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(size)))
+	offs := windows(size)
+	if offs == nil {
+		if _, err := io.Copy(h, io.NewSectionReader(r, 0, size)); err != nil {
+			return key{}, err
+		}
+	}
+	for _, off := range offs {
+		if _, err := io.Copy(h, io.NewSectionReader(r, off, sampleSize)); err != nil {
+			return key{}, err
+		}
+	}
+	k := key{size: size}
+	h.Sum(k.sample[:0])
+	return k, nil
+}
+
+// sampleStream hashes the same samples as sampleAt, skipping over the rest
+// of the stream in between.
+func sampleStream(r io.Reader, size int64) (key, error) {
+	h := sha256.New()
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(size)))
+	offs := windows(size)
+	if offs == nil {
+		if _, err := io.CopyN(h, r, size); err != nil {
+			return key{}, err
+		}
+	}
+	var pos int64
+	for _, off := range offs {
+		if _, err := io.CopyN(io.Discard, r, off-pos); err != nil {
+			return key{}, err
+		}
+		if _, err := io.CopyN(h, r, sampleSize); err != nil {
+			return key{}, err
+		}
+		pos = off + sampleSize
+	}
+	k := key{size: size}
+	h.Sum(k.sample[:0])
+	return k, nil
+}
+
+// Shared is how many bytes of content a and b have in common: each file of
+// one counts once against a file of the other with the same content.
+func Shared(a, b *Fingerprint) int64 {
+	if len(b.files) < len(a.files) {
+		a, b = b, a
+	}
+	var shared int64
+	for k, n := range a.files {
+		shared += int64(min(n, b.files[k])) * k.size
+	}
+	return shared
+}
+
+// Group is a set of volumes that appear to hold the same data.
+type Group struct {
+	Volumes []string
+	// Identical groups have every sampled file in common; the others
+	// are a pair sharing at least Alike of the larger one's data.
+	Identical  bool
+	Similarity float64
+	// Bytes is the size of the data they have in common, which all but
+	// one of the volumes could give back.
+	Bytes int64
+}
+
+// Reclaimable is what consolidating the group onto one volume frees.
+func (g Group) Reclaimable() int64 { return g.Bytes * int64(len(g.Volumes)-1) }
+
+// Compare groups the fingerprints into sets of identical volumes, then
+// reports the pairs of distinct contents that share at least Alike of the
+// larger one's data. Empty volumes are never reported. Groups that free the most come first.
+func Compare(fps []*Fingerprint) []Group {
+	var live []*Fingerprint
+	for _, fp := range fps {
+		if fp.Bytes > 0 {
+			live = append(live, fp)
+		}
+	}
+	sort.Slice(live, func(i, j int) bool { return live[i].Volume < live[j].Volume })
+
+	// Identical volumes join the group of the first one they match, which
+	// then stands in for all of them when looking for alike pairs
+	var groups, pairs []Group
+	var reps []*Fingerprint
+	grouped := map[string]bool{}
+	for i, a := range live {
+		if grouped[a.Volume] {
+			continue
+		}
+		reps = append(reps, a)
+		g := Group{Volumes: []string{a.Volume}, Identical: true, Similarity: 1, Bytes: a.Bytes}
+		for _, b := range live[i+1:] {
+			if !grouped[b.Volume] && identical(a, b) {
+				g.Volumes = append(g.Volumes, b.Volume)
+				grouped[b.Volume] = true
+			}
+		}
+		if len(g.Volumes) > 1 {
+			groups = append(groups, g)
+		}
+	}
+	for i, a := range reps {
+		for _, b := range reps[i+1:] {
+			shared := Shared(a, b)
+			if sim := float64(shared) / float64(max(a.Bytes, b.Bytes)); sim >= Alike {
+				pairs = append(pairs, Group{Volumes: []string{a.Volume, b.Volume}, Similarity: sim, Bytes: shared})
+			}
+		}
+	}
+	groups = append(groups, pairs...)
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Reclaimable() > groups[j].Reclaimable() })
+	return groups
+}
+
+func identical(a, b *Fingerprint) bool {
+	if a.Bytes != b.Bytes || a.Files != b.Files || len(a.files) != len(b.files) {
+		return false
+	}
+	for k, n := range a.files {
+		if b.files[k] != n {
+			return false
+		}
+	}
+	return true
+}
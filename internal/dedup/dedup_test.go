@@ -0,0 +1,89 @@
+package dedup
+
+import (
+	"archive/tar"
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// blob is n bytes of reproducible noise.
+func blob(seed int64, n int) []byte {
+	b := make([]byte, n)
+	rand.New(rand.NewSource(seed)).Read(b)
+	return b
+}
+
+func TestDirAndTarAgree(t *testing.T) {
+	files := map[string][]byte{
+		"small":         []byte("hello"),
+		"cache/big.bin": blob(1, 3*sampleSize+1),
+		"cache/huge":    blob(2, 1<<20),
+	}
+	dir := t.TempDir()
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for name, body := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, body, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		// Under other names: only the content counts
+		if err := tw.WriteHeader(&tar.Header{Name: "./copy/" + name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(body))}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(body)
+	}
+	tw.Close()
+
+	a, err := FromDir("a", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := FromTar("b", buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Files != 3 || !identical(a, b) {
+		t.Errorf("dir %+v and tar %+v fingerprints differ", a, b)
+	}
+}
+
+func fingerprint(volume string, sizes ...int) *Fingerprint {
+	fp := newFingerprint(volume)
+	for i, n := range sizes {
+		k, _ := sampleAt(bytes.NewReader(blob(int64(i), n)), int64(n))
+		fp.add(k)
+	}
+	return fp
+}
+
+func TestCompare(t *testing.T) {
+	fps := []*Fingerprint{
+		fingerprint("ci-cache-2", 1000, 20000, 50000),
+		fingerprint("ci-cache-1", 1000, 20000, 50000),
+		fingerprint("ci-cache-3", 1000, 20000, 50000),
+		fingerprint("pgdata", 900, 800),
+		// All of ci-cache plus a little: alike, not identical
+		fingerprint("ci-cache-old", 1000, 20000, 50000, 3000),
+		fingerprint("empty-a"),
+		fingerprint("empty-b"),
+	}
+	groups := Compare(fps)
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups %+v, want one identical group and one alike pair", len(groups), groups)
+	}
+	g := groups[0]
+	if !g.Identical || len(g.Volumes) != 3 || g.Volumes[0] != "ci-cache-1" || g.Reclaimable() != 2*71000 {
+		t.Errorf("first group %+v, want ci-cache-1..3 freeing 142000", g)
+	}
+	// The identical group is compared once, through its first volume
+	if g := groups[1]; g.Identical || g.Volumes[0] != "ci-cache-1" || g.Volumes[1] != "ci-cache-old" || g.Bytes != 71000 || g.Similarity < Alike {
+		t.Errorf("pair %+v, want ci-cache-1 alike with ci-cache-old", g)
+	}
+}
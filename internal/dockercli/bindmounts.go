@@ -0,0 +1,97 @@
+package dockercli
+
+import (
+	"cmp"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"dockwatch/internal/domain"
+)
+
+// BindMounts groups the bind mounts of cs by host path, largest first.
+// Paths are measured on this machine, so call it only when the daemon is
+// local (see IsLocalDaemon); a path that can't be walked at all is -1.
+func BindMounts(ctx context.Context, cs []domain.Container) []domain.BindMount {
+	bySource := map[string]*domain.BindMount{}
+	var order []string
+	for _, c := range cs {
+		for _, m := range c.Mounts {
+			if m.Type != "bind" || m.Source == "" {
+				continue
+			}
+			b, ok := bySource[m.Source]
+			if !ok {
+				b = &domain.BindMount{Source: m.Source}
+				bySource[m.Source] = b
+				order = append(order, m.Source)
+			}
+			if !slices.Contains(b.Containers, c.Name) {
+				b.Containers = append(b.Containers, c.Name)
+			}
+		}
+	}
+
+	mounts := make([]domain.BindMount, 0, len(order))
+	for _, src := range order {
+		b := bySource[src]
+		b.SizeBytes, b.Skipped = walkSizePartial(ctx, src)
+		mounts = append(mounts, *b)
+	}
+	slices.SortStableFunc(mounts, func(a, b domain.BindMount) int { return cmp.Compare(b.SizeBytes, a.SizeBytes) })
+	return mounts
+}
+
+// walkSizePartial is walkSize for directories dockwatch doesn't own: it
+// skips what it can't read and counts it instead of giving up, since one
+// root-owned cache dir shouldn't hide the size of a whole project tree. It
+// stays on root's filesystem, so a bind of / measures neither /proc nor
+// network shares, and measures nothing on a pseudo filesystem.
+func walkSizePartial(ctx context.Context, root string) (size int64, skipped int) {
+	if pseudoFS(root) {
+		return 0, 0
+	}
+	var rootDev uint64
+	if info, err := os.Stat(root); err == nil {
+		rootDev, _ = device(info)
+	}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			if path == root {
+				return err
+			}
+			skipped++
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() && path != root {
+			// A mount point: another filesystem, not what root holds
+			if info, err := d.Info(); err == nil {
+				if dev, ok := device(info); ok && dev != rootDev {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				skipped++
+				return nil
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return -1, skipped
+	}
+	return size, skipped
+}
@@ -5,26 +5,39 @@ import (
 	"dockwatch/internal/domain"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // DockerProvider implements the Provider interface using Docker CLI commands
-type DockerProvider struct{}
+type DockerProvider struct {
+	sizes sizeCache
+	usage usageCache
+
+	mu     sync.Mutex
+	limits *Limits // nil for DefaultLimits
+	helper Helper
+}
 
 // NewDockerProvider creates a new Docker provider instance
 func NewDockerProvider() (*DockerProvider, error) {
 	// Check if docker command is available
 	_, err := exec.LookPath("docker")
 	if err != nil {
-		return nil, fmt.Errorf("docker command not found: %w", err)
+		return nil, &domain.KindError{Kind: domain.ErrDaemonUnavailable, Msg: "docker command not found", Err: err}
 	}
 
 	// Test if Docker daemon is accessible
 	cmd := exec.Command("docker", "version")
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("docker daemon not accessible: %w", err)
+	if _, err := cmd.Output(); err != nil {
+		// Whatever the CLI says, there is no daemon to work with
+		return nil, &domain.KindError{Kind: domain.ErrDaemonUnavailable, Msg: cliError("docker daemon not accessible", err, nil).Error(), Err: err}
 	}
 
 	return &DockerProvider{}, nil
@@ -37,11 +50,82 @@ func (d *DockerProvider) Close() error {
 
 // ListVolumes returns actual Docker volumes with container attachment info
 func (d *DockerProvider) ListVolumes(ctx context.Context) ([]domain.Volume, error) {
+	volumes, err := d.ListVolumesBasic(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return d.enrichAll(ctx, volumes)
+}
+
+// ListVolumesFiltered lists the volumes matching f. The daemon evaluates
+// what it can, so only the survivors are inspected; the rest of f (name
+// globs) is applied afterwards.
+func (d *DockerProvider) ListVolumesFiltered(ctx context.Context, f domain.VolumeFilter) ([]domain.Volume, error) {
+	volumes, err := d.listVolumes(ctx, f.Native())
+	if err != nil {
+		return nil, err
+	}
+	volumes, err = d.enrichAll(ctx, volumes)
+	if err != nil {
+		return nil, err
+	}
+	var matched []domain.Volume
+	for _, v := range volumes {
+		if f.Match(v) {
+			matched = append(matched, v)
+		}
+	}
+	return matched, nil
+}
+
+// enrichAll inspects and sizes every listed volume in place.
+func (d *DockerProvider) enrichAll(ctx context.Context, volumes []domain.Volume) ([]domain.Volume, error) {
+	index := make(map[string]int, len(volumes))
+	names := make([]string, len(volumes))
+	for i, v := range volumes {
+		index[v.Name] = i
+		names[i] = v.Name
+	}
+	err := d.EnrichVolumes(ctx, names, func(v domain.Volume) {
+		i := index[v.Name]
+		if v.Driver == "" {
+			v.Driver = volumes[i].Driver
+		}
+		volumes[i] = v
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return volumes, nil
+}
+
+// ListVolumesBasic returns volumes with only what `docker volume ls` knows:
+// name and driver. Sizes are -1 and attachments unknown until enriched.
+func (d *DockerProvider) ListVolumesBasic(ctx context.Context) ([]domain.Volume, error) {
+	volumes, err := d.listVolumes(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	live := make(map[string]bool, len(volumes))
+	for _, v := range volumes {
+		live[v.Name] = true
+	}
+	d.sizes.prune(live)
+	return volumes, nil
+}
+
+// listVolumes runs `docker volume ls` with the given native filters.
+func (d *DockerProvider) listVolumes(ctx context.Context, filters []string) ([]domain.Volume, error) {
 	// Get volumes in JSON format
-	cmd := exec.CommandContext(ctx, "docker", "volume", "ls", "--format", "{{json .}}")
+	args := []string{"volume", "ls", "--format", "{{json .}}"}
+	for _, f := range filters {
+		args = append(args, "--filter", f)
+	}
+	cmd := exec.CommandContext(ctx, "docker", args...)
 	output, err := cmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list volumes: %w", err)
+		return nil, cliError("failed to list volumes", err, nil)
 	}
 
 	// Parse volume lines
@@ -56,50 +140,168 @@ func (d *DockerProvider) ListVolumes(ctx context.Context) ([]domain.Volume, erro
 		var volInfo struct {
 			Name   string `json:"Name"`
 			Driver string `json:"Driver"`
+			Labels string `json:"Labels"`
 		}
 
 		if err := json.Unmarshal([]byte(line), &volInfo); err != nil {
 			continue // Skip malformed lines
 		}
-
-		// Get volume details
-		volume, err := d.getVolumeDetails(ctx, volInfo.Name)
-		if err != nil {
-			// Use basic info if details fail
-			volume = &domain.Volume{
-				Name:      volInfo.Name,
-				Driver:    volInfo.Driver,
-				SizeBytes: -1,
-				Attached:  []string{},
-				Project:   "",
-				Orphan:    true,
-				LastSeen:  time.Now(),
-			}
+		if isHelper(volInfo.Labels) {
+			continue
 		}
 
-		volumes = append(volumes, *volume)
+		// Not flagged orphan until inspect says so, so nothing gets
+		// pruned on the strength of a listing that hasn't been enriched
+		volumes = append(volumes, domain.Volume{
+			Name:      volInfo.Name,
+			Driver:    volInfo.Driver,
+			SizeBytes: -1,
+			Attached:  []string{},
+			LastSeen:  time.Now(),
+		})
 	}
 
 	return volumes, nil
 }
 
+// EnrichVolumes inspects and sizes each named volume, several at once within
+// the provider's Limits, calling each once per volume as soon as it is
+// ready, in no particular order and never concurrently. Volumes that could
+// only be sized by the daemon are reported a second time once `docker
+// system df` returns.
+func (d *DockerProvider) EnrichVolumes(ctx context.Context, names []string, each func(domain.Volume)) error {
+	local := IsLocalDaemon()
+	usage := d.volumeUsage(ctx)
+	services := d.serviceVolumes(ctx)
+	// One listing of the containers for all the volumes; on failure every
+	// volume's state is unknown
+	holders, _ := d.volumeHolders(ctx)
+
+	limits := d.currentLimits()
+	var load *daemonLoad
+	if limits.AutoThrottle {
+		load = &daemonLoad{}
+	}
+	inspects := newThrottle(limits.Inspects, load)
+	scans := newThrottle(limits.SizeScans, load)
+
+	var (
+		mu      sync.Mutex // serializes each and unsized
+		wg      sync.WaitGroup
+		unsized []domain.Volume
+	)
+	for _, name := range names {
+		if !inspects.acquire(ctx) {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			volume, err := d.getVolumeDetails(ctx, name, holders)
+			inspects.release()
+			if load != nil {
+				load.observe(time.Since(start))
+			}
+			if err != nil {
+				// Report the bare volume so it doesn't sit in the loading state
+				volume = &domain.Volume{
+					Name:      name,
+					SizeBytes: -1,
+					Attached:  []string{},
+					LastSeen:  time.Now(),
+				}
+			}
+
+			addServices(volume, services)
+			volume.InUse = usage[name].inUse
+			volume.LastUsed = usage[name].lastUsed
+
+			sized := false
+			if scans.acquire(ctx) {
+				sized = d.measure(ctx, volume, local, time.Now())
+				scans.release()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if !sized {
+				unsized = append(unsized, *volume)
+			}
+			each(*volume)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Docker Desktop keeps volumes in its VM, out of reach of the walk
+	var vm []string
+	for _, v := range unsized {
+		if inVM(v, local) {
+			vm = append(vm, v.Name)
+		}
+	}
+	if len(vm) > 0 {
+		sizes := d.vmSizes(ctx, vm, time.Now())
+		rest := unsized[:0]
+		for _, v := range unsized {
+			if size, ok := sizes[v.Name]; ok {
+				v.SizeBytes = size
+				each(v)
+			} else {
+				rest = append(rest, v)
+			}
+		}
+		unsized = rest
+	}
+
+	if len(unsized) == 0 {
+		return nil
+	}
+	sizes, err := d.dfVolumeSizes(ctx)
+	if err != nil {
+		// Sizes stay unknown; the listing itself is still good
+		return nil
+	}
+	for _, v := range unsized {
+		if size, ok := sizes[v.Name]; ok {
+			v.SizeBytes = size
+			each(v)
+		}
+	}
+	return nil
+}
+
 // GetVolumeDetails returns detailed information about a specific volume
 func (d *DockerProvider) GetVolumeDetails(ctx context.Context, name string) (*domain.Volume, error) {
-	return d.getVolumeDetails(ctx, name)
+	holders, _ := d.volumeHolders(ctx)
+	v, err := d.getVolumeDetails(ctx, name, holders)
+	if err != nil {
+		return nil, err
+	}
+	addServices(v, d.serviceVolumes(ctx))
+	return v, nil
 }
 
-func (d *DockerProvider) getVolumeDetails(ctx context.Context, name string) (*domain.Volume, error) {
+// getVolumeDetails inspects the volume name. holders is the volumeHolders
+// listing, nil when it failed, which leaves the volume's state unknown.
+func (d *DockerProvider) getVolumeDetails(ctx context.Context, name string, holders map[string]*volumeHolder) (*domain.Volume, error) {
 	// Get volume inspect info
 	cmd := exec.CommandContext(ctx, "docker", "volume", "inspect", name)
 	output, err := cmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("failed to inspect volume %s: %w", name, err)
+		return nil, cliError("failed to inspect volume "+name, err, nil)
 	}
 
 	var inspectInfo []struct {
-		Name   string            `json:"Name"`
-		Driver string            `json:"Driver"`
-		Labels map[string]string `json:"Labels"`
+		Name       string            `json:"Name"`
+		Driver     string            `json:"Driver"`
+		Labels     map[string]string `json:"Labels"`
+		CreatedAt  string            `json:"CreatedAt"`
+		Mountpoint string            `json:"Mountpoint"`
+		Options    map[string]string `json:"Options"`
+		Status     map[string]any    `json:"Status"`
 	}
 
 	if err := json.Unmarshal(output, &inspectInfo); err != nil {
@@ -107,15 +309,19 @@ func (d *DockerProvider) getVolumeDetails(ctx context.Context, name string) (*do
 	}
 
 	if len(inspectInfo) == 0 {
-		return nil, fmt.Errorf("volume %s not found", name)
+		return nil, &domain.KindError{Kind: domain.ErrNotFound, Msg: fmt.Sprintf("volume %s not found", name)}
 	}
 
 	volInfo := inspectInfo[0]
 
-	// Get containers using this volume
-	attached, err := d.getContainersUsingVolume(ctx, name)
-	if err != nil {
-		attached = []string{}
+	attached, state := []string{}, domain.OrphanUnknown
+	if holders != nil {
+		h := holders[name]
+		if h == nil {
+			h = &volumeHolder{}
+		}
+		attached = append(attached, h.names...)
+		state = domain.StateOf(len(h.names), h.running)
 	}
 
 	project := ""
@@ -123,33 +329,56 @@ func (d *DockerProvider) getVolumeDetails(ctx context.Context, name string) (*do
 		project = volInfo.Labels["com.docker.compose.project"]
 	}
 
-	// Try to get volume size (this may not work on all systems)
+	// CreatedAt is RFC3339; leave zero when the driver doesn't report it
+	createdAt, _ := time.Parse(time.RFC3339, volInfo.CreatedAt)
+
+	// Sized afterwards by EnrichVolumes: walked at the mountpoint when the
+	// daemon is local, else asked of the VM or `docker system df`
 	sizeBytes := int64(-1)
 
 	result := &domain.Volume{
-		Name:      volInfo.Name,
-		Driver:    volInfo.Driver,
-		SizeBytes: sizeBytes,
-		Attached:  attached,
-		Project:   project,
-		Orphan:    len(attached) == 0,
-		LastSeen:  time.Now(),
+		Name:       volInfo.Name,
+		Driver:     volInfo.Driver,
+		SizeBytes:  sizeBytes,
+		Attached:   attached,
+		Project:    project,
+		State:      state,
+		LastSeen:   time.Now(),
+		CreatedAt:  createdAt,
+		Labels:     volInfo.Labels,
+		Mountpoint: volInfo.Mountpoint,
+		Options:    volInfo.Options,
+	}
+	if len(volInfo.Status) > 0 {
+		result.DriverStatus = make(map[string]string, len(volInfo.Status))
+		for k, v := range volInfo.Status {
+			result.DriverStatus[k] = fmt.Sprint(v)
+		}
 	}
 
 	return result, nil
 }
 
-// getContainersUsingVolume finds containers that use a specific volume
-func (d *DockerProvider) getContainersUsingVolume(ctx context.Context, volumeName string) ([]string, error) {
-	// Get all containers with their mount info
-	cmd := exec.CommandContext(ctx, "docker", "ps", "-a", "--format", "{{json .}}")
+// volumeHolder is what uses one volume: the names of the containers
+// mounting it and whether one of them is running.
+type volumeHolder struct {
+	names   []string
+	running bool
+}
+
+// volumeHolders lists the containers once and indexes them by the volumes
+// they mount. The map is never nil unless the listing failed.
+func (d *DockerProvider) volumeHolders(ctx context.Context) (map[string]*volumeHolder, error) {
+	// Get all containers with their mount info; without --no-trunc long
+	// volume names are cut short and never match
+	cmd := exec.CommandContext(ctx, "docker", "ps", "-a", "--no-trunc", "--format", "{{json .}}")
 	output, err := cmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list containers: %w", err)
+		return nil, cliError("failed to list containers", err, nil)
 	}
 
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	var attached []string
+	holders := map[string]*volumeHolder{}
 
 	for _, line := range lines {
 		if line == "" {
@@ -159,25 +388,272 @@ func (d *DockerProvider) getContainersUsingVolume(ctx context.Context, volumeNam
 		var containerInfo struct {
 			Names  string `json:"Names"`
 			Mounts string `json:"Mounts"`
+			State  string `json:"State"`
+			Labels string `json:"Labels"`
 		}
 
 		if err := json.Unmarshal([]byte(line), &containerInfo); err != nil {
 			continue
 		}
+		if isHelper(containerInfo.Labels) {
+			continue
+		}
 
-		// Check if this container uses the volume
-		if strings.Contains(containerInfo.Mounts, volumeName) {
-			// Extract container name (remove leading slash)
-			name := strings.TrimPrefix(containerInfo.Names, "/")
-			attached = append(attached, name)
+		// Mounts is a comma-separated list of volume names and bind paths;
+		// bind paths never match a volume name asked for, so they can sit
+		// in the index too
+		name := strings.TrimPrefix(containerInfo.Names, "/")
+		for _, mount := range strings.Split(containerInfo.Mounts, ",") {
+			if mount == "" {
+				continue
+			}
+			h := holders[mount]
+			if h == nil {
+				h = &volumeHolder{}
+				holders[mount] = h
+			}
+			if !slices.Contains(h.names, name) {
+				h.names = append(h.names, name)
+			}
+			h.running = h.running || containerInfo.State == "running"
 		}
 	}
 
-	return attached, nil
+	return holders, nil
 }
 
 // RemoveVolume removes a Docker volume
 func (d *DockerProvider) RemoveVolume(ctx context.Context, name string) error {
 	cmd := exec.CommandContext(ctx, "docker", "volume", "rm", name)
-	return cmd.Run()
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return cliError("failed to remove volume "+name, err, output)
+	}
+	return nil
+}
+
+// CreateVolume creates an empty volume with the given driver (default
+// local), driver options and labels.
+func (d *DockerProvider) CreateVolume(ctx context.Context, name, driver string, opts, labels map[string]string) error {
+	args := []string{"volume", "create"}
+	if driver != "" {
+		args = append(args, "--driver", driver)
+	}
+	for _, k := range slices.Sorted(maps.Keys(opts)) {
+		args = append(args, "--opt", k+"="+opts[k])
+	}
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		args = append(args, "--label", k+"="+labels[k])
+	}
+	cmd := exec.CommandContext(ctx, "docker", append(args, name)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return cliError("failed to create volume "+name, err, output)
+	}
+	return nil
+}
+
+// ListContainers returns all containers, running or not
+func (d *DockerProvider) ListContainers(ctx context.Context) ([]domain.Container, error) {
+	cmd := exec.CommandContext(ctx, "docker", "ps", "-a", "--no-trunc", "--format", "{{json .}}")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, cliError("failed to list containers", err, nil)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	var containers []domain.Container
+
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		var info struct {
+			ID        string `json:"ID"`
+			Names     string `json:"Names"`
+			Image     string `json:"Image"`
+			State     string `json:"State"`
+			Status    string `json:"Status"`
+			CreatedAt string `json:"CreatedAt"`
+			Labels    string `json:"Labels"`
+		}
+
+		if err := json.Unmarshal([]byte(line), &info); err != nil {
+			continue
+		}
+		if isHelper(info.Labels) {
+			continue
+		}
+
+		// CreatedAt looks like "2024-01-02 10:00:00 +0000 UTC"
+		createdAt, _ := time.Parse("2006-01-02 15:04:05 -0700 MST", info.CreatedAt)
+
+		containers = append(containers, domain.Container{
+			ID:        info.ID,
+			Name:      strings.TrimPrefix(info.Names, "/"),
+			Image:     info.Image,
+			State:     info.State,
+			Status:    info.Status,
+			CreatedAt: createdAt,
+			SizeRw:    -1,
+			LogSize:   -1,
+		})
+	}
+
+	// Inspect details are best effort; the listing alone is still useful
+	d.inspectContainers(ctx, containers)
+
+	return containers, nil
+}
+
+// inspectContainers fills exit state and mounts with one batched inspect, and
+// writable-layer sizes for stopped containers (sizing running ones is costly)
+func (d *DockerProvider) inspectContainers(ctx context.Context, containers []domain.Container) {
+	if len(containers) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(containers))
+	var stopped []string
+	for _, c := range containers {
+		ids = append(ids, c.ID)
+		if !c.Running() {
+			stopped = append(stopped, c.ID)
+		}
+	}
+
+	type inspectInfo struct {
+		ID           string `json:"Id"`
+		Image        string `json:"Image"`
+		SizeRw       *int64 `json:"SizeRw"`
+		RestartCount int    `json:"RestartCount"`
+		State        struct {
+			ExitCode   int    `json:"ExitCode"`
+			StartedAt  string `json:"StartedAt"`
+			FinishedAt string `json:"FinishedAt"`
+			Health     *struct {
+				Status string `json:"Status"`
+				Log    []struct {
+					Output string `json:"Output"`
+				} `json:"Log"`
+			} `json:"Health"`
+		} `json:"State"`
+		Mounts []struct {
+			Type        string `json:"Type"`
+			Name        string `json:"Name"`
+			Source      string `json:"Source"`
+			Destination string `json:"Destination"`
+		} `json:"Mounts"`
+		NetworkSettings struct {
+			Networks map[string]json.RawMessage `json:"Networks"`
+		} `json:"NetworkSettings"`
+		Config struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"Config"`
+		LogPath    string `json:"LogPath"`
+		HostConfig struct {
+			LogConfig struct {
+				Type string `json:"Type"`
+			} `json:"LogConfig"`
+			RestartPolicy struct {
+				Name string `json:"Name"`
+			} `json:"RestartPolicy"`
+			ShmSize int64             `json:"ShmSize"`
+			IpcMode string            `json:"IpcMode"`
+			Tmpfs   map[string]string `json:"Tmpfs"` // --tmpfs mounts, which Mounts leaves out
+		} `json:"HostConfig"`
+	}
+	inspect := func(args ...string) map[string]inspectInfo {
+		output, err := exec.CommandContext(ctx, "docker", append([]string{"container", "inspect"}, args...)...).Output()
+		if err != nil {
+			return nil
+		}
+		var infos []inspectInfo
+		if json.Unmarshal(output, &infos) != nil {
+			return nil
+		}
+		byID := make(map[string]inspectInfo, len(infos))
+		for _, info := range infos {
+			byID[info.ID] = info
+		}
+		return byID
+	}
+
+	details := inspect(ids...)
+	local := IsLocalDaemon()
+	var sizes map[string]inspectInfo
+	if len(stopped) > 0 {
+		sizes = inspect(append([]string{"--size"}, stopped...)...)
+	}
+
+	for i := range containers {
+		c := &containers[i]
+		if info, ok := details[c.ID]; ok {
+			c.ExitCode = info.State.ExitCode
+			// Docker reports "0001-01-01T00:00:00Z" for never-finished containers
+			if t, err := time.Parse(time.RFC3339Nano, info.State.FinishedAt); err == nil && t.Year() > 1 {
+				c.FinishedAt = t
+			}
+			if t, err := time.Parse(time.RFC3339Nano, info.State.StartedAt); err == nil && t.Year() > 1 {
+				c.StartedAt = t
+			}
+			if h := info.State.Health; h != nil {
+				c.Health = h.Status
+				if len(h.Log) > 0 {
+					c.HealthOutput = strings.TrimSpace(h.Log[len(h.Log)-1].Output)
+				}
+			}
+			c.RestartCount, c.RestartPolicy = info.RestartCount, info.HostConfig.RestartPolicy.Name
+			for _, m := range info.Mounts {
+				c.Mounts = append(c.Mounts, domain.Mount{Type: m.Type, Name: m.Name, Source: m.Source, Destination: m.Destination})
+			}
+			c.ImageID = strings.TrimPrefix(info.Image, "sha256:")
+			for name := range info.NetworkSettings.Networks {
+				c.Networks = append(c.Networks, name)
+			}
+			sort.Strings(c.Networks)
+			c.Labels = info.Config.Labels
+			// --tmpfs mounts aren't listed in Mounts, --mount type=tmpfs ones are
+			for dest := range info.HostConfig.Tmpfs {
+				c.Mounts = append(c.Mounts, domain.Mount{Type: "tmpfs", Destination: dest})
+			}
+			switch info.HostConfig.IpcMode {
+			case "", "private", "shareable":
+				c.ShmSize = info.HostConfig.ShmSize
+			}
+			c.LogDriver, c.LogPath = info.HostConfig.LogConfig.Type, info.LogPath
+			if local && c.LogDriver == "json-file" {
+				c.LogSize = logSize(c.LogPath)
+			}
+		}
+		if info, ok := sizes[c.ID]; ok && info.SizeRw != nil {
+			c.SizeRw = *info.SizeRw
+		}
+	}
+}
+
+// RemoveContainer removes a stopped container, optionally with its anonymous volumes
+func (d *DockerProvider) RemoveContainer(ctx context.Context, id string, removeVolumes bool) error {
+	args := []string{"container", "rm"}
+	if removeVolumes {
+		args = append(args, "-v")
+	}
+	cmd := exec.CommandContext(ctx, "docker", append(args, id)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return cliError("failed to remove container "+id, err, output)
+	}
+	return nil
+}
+
+// ContainerLogs returns the last tail lines of a container's stdout/stderr
+func (d *DockerProvider) ContainerLogs(ctx context.Context, id string, tail int) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "docker", "logs", "--tail", strconv.Itoa(tail), id)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, cliError("failed to read logs for "+id, err, output)
+	}
+
+	text := strings.TrimRight(string(output), "\n")
+	if text == "" {
+		return []string{}, nil
+	}
+	return strings.Split(text, "\n"), nil
 }
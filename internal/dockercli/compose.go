@@ -0,0 +1,28 @@
+package dockercli
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+)
+
+// Compose runs a `docker compose` subcommand, such as "down" or "up -d",
+// against a project. dir and files are where compose recorded the project
+// on its containers; without them compose only knows it by name, which is
+// enough to take it down but not to bring it up.
+func (d *DockerProvider) Compose(ctx context.Context, project, dir string, files []string, args ...string) error {
+	cmdArgs := []string{"compose", "-p", project}
+	if dir != "" {
+		cmdArgs = append(cmdArgs, "--project-directory", dir)
+	}
+	for _, f := range files {
+		cmdArgs = append(cmdArgs, "-f", f)
+	}
+	cmd := exec.CommandContext(ctx, "docker", append(cmdArgs, args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		// The progress compose prints comes first; the reason is last
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		return cliError("docker compose "+strings.Join(args, " ")+" failed for "+project, err, []byte(lines[len(lines)-1]))
+	}
+	return nil
+}
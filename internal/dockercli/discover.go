@@ -0,0 +1,173 @@
+package dockercli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Endpoint is a daemon socket found outside the default location.
+type Endpoint struct {
+	Name string // what runs the daemon, e.g. "Colima"
+	Host string // as DOCKER_HOST would give it
+}
+
+// socketCandidates are where the usual alternatives to a system daemon put
+// their socket, most specific first.
+func socketCandidates(home string) []Endpoint {
+	candidates := []Endpoint{
+		{"Colima", "unix://" + filepath.Join(home, ".colima/default/docker.sock")},
+		{"Colima", "unix://" + filepath.Join(home, ".config/colima/default/docker.sock")},
+		{"Rancher Desktop", "unix://" + filepath.Join(home, ".rd/docker.sock")},
+		{"Docker Desktop", "unix://" + filepath.Join(home, ".docker/run/docker.sock")},
+		{"Docker Desktop", "unix://" + filepath.Join(home, ".docker/desktop/docker.sock")},
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		candidates = append(candidates, Endpoint{"rootless Docker", "unix://" + filepath.Join(dir, "docker.sock")})
+	}
+	return candidates
+}
+
+var discovered struct {
+	once     sync.Once
+	endpoint Endpoint
+	ok       bool
+}
+
+// DiscoverSocket points DOCKER_HOST at the first alternative socket that
+// exists, for when nothing chose an endpoint and the default socket is
+// missing: Colima and Rancher Desktop don't create it, and the docker CLI
+// only finds theirs through a context that may not be selected. It runs
+// once per process; later calls report what the first one chose. ok is
+// false when DOCKER_HOST was left alone.
+func DiscoverSocket() (Endpoint, bool) {
+	discovered.once.Do(func() {
+		if !endpointUnset() {
+			return
+		}
+		if _, err := os.Stat(defaultSocket); err == nil {
+			return
+		}
+		if found := FindSockets(); len(found) > 0 {
+			discovered.endpoint = found[0]
+			discovered.ok = os.Setenv("DOCKER_HOST", found[0].Host) == nil
+		}
+	})
+	return discovered.endpoint, discovered.ok
+}
+
+// FindSockets returns the alternative sockets that exist, in the order
+// DiscoverSocket prefers them.
+func FindSockets() []Endpoint {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	var found []Endpoint
+	for _, c := range socketCandidates(home) {
+		if _, err := os.Stat(strings.TrimPrefix(c.Host, "unix://")); err == nil {
+			found = append(found, c)
+		}
+	}
+	return found
+}
+
+// UseContext selects the docker context name for the docker CLI runs that
+// follow, unless DOCKER_HOST or DOCKER_CONTEXT already picks the daemon.
+// Call it before DiscoverSocket, which then leaves the endpoint alone.
+func UseContext(name string) {
+	if name == "" || os.Getenv("DOCKER_HOST") != "" || os.Getenv("DOCKER_CONTEXT") != "" {
+		return
+	}
+	os.Setenv("DOCKER_CONTEXT", name)
+	usedContext = name
+}
+
+// usedContext is the context UseContext or SwitchContext selected.
+var usedContext string
+
+// SwitchContext selects the docker context name in place of the one
+// UseContext selected or the socket DiscoverSocket found. It reports false,
+// changing nothing, when the environment picks the daemon itself.
+func SwitchContext(name string) bool {
+	if os.Getenv("DOCKER_HOST") != "" && !discovered.ok {
+		return false
+	}
+	if ctx := os.Getenv("DOCKER_CONTEXT"); ctx != "" && ctx != usedContext {
+		return false
+	}
+	if discovered.ok {
+		os.Unsetenv("DOCKER_HOST")
+		discovered.ok = false
+	}
+	os.Setenv("DOCKER_CONTEXT", name)
+	usedContext = name
+	return true
+}
+
+// endpointUnset reports whether neither DOCKER_HOST nor a docker context
+// picks the daemon, so the CLI would use the default socket.
+func endpointUnset() bool {
+	if os.Getenv("DOCKER_HOST") != "" {
+		return false
+	}
+	if ctx := os.Getenv("DOCKER_CONTEXT"); ctx != "" {
+		return ctx == "default"
+	}
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return true
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return true
+	}
+	var cfg struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if json.Unmarshal(data, &cfg) != nil {
+		return true
+	}
+	return cfg.CurrentContext == "" || cfg.CurrentContext == "default"
+}
+
+// Context is a docker context as `docker context ls` lists it.
+type Context struct {
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+	Host        string `json:"DockerEndpoint"`
+	Current     bool   `json:"Current"`
+}
+
+// ListContexts returns the docker contexts the CLI knows.
+func ListContexts(ctx context.Context) ([]Context, error) {
+	cmd := exec.CommandContext(ctx, "docker", "context", "ls", "--format", "{{json .}}")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, cliError("failed to list docker contexts", err, stderr.Bytes())
+	}
+	var contexts []Context
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		var c Context
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			return nil, fmt.Errorf("failed to parse docker context: %w", err)
+		}
+		contexts = append(contexts, c)
+	}
+	return contexts, nil
+}
@@ -0,0 +1,89 @@
+package dockercli
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultSocket = "/var/run/docker.sock"
+
+// engineClient returns an HTTP client talking to the Engine API directly,
+// for the few operations whose CLI output is too coarse (e.g. pull
+// progress). ok is false when DOCKER_HOST points somewhere we can't dial
+// ourselves (ssh://, npipe) or a docker context picks the daemon, in which
+// case callers fall back to the CLI so they reach the same daemon as the
+// rest of the app.
+func engineClient() (client *http.Client, base string, ok bool) {
+	host := os.Getenv("DOCKER_HOST")
+	switch {
+	case host == "" && !endpointUnset():
+		return nil, "", false
+	case host == "":
+		host = "unix://" + defaultSocket
+	case strings.HasPrefix(host, "tcp://"):
+		addr := strings.TrimPrefix(host, "tcp://")
+		if os.Getenv("DOCKER_TLS_VERIFY") == "" && os.Getenv("DOCKER_TLS") == "" {
+			return &http.Client{}, "http://" + addr, true
+		}
+		cfg, err := engineTLS()
+		if err != nil {
+			return nil, "", false
+		}
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}, "https://" + addr, true
+	case !strings.HasPrefix(host, "unix://"):
+		return nil, "", false
+	}
+
+	socket := strings.TrimPrefix(host, "unix://")
+	if _, err := os.Stat(socket); err != nil {
+		return nil, "", false
+	}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}
+	return &http.Client{Transport: transport}, "http://docker", true
+}
+
+// engineTLS builds the client TLS config the docker CLI would use for a tcp
+// host: the client certificate and key from DOCKER_CERT_PATH (~/.docker by
+// default), and, with DOCKER_TLS_VERIFY, the daemon checked against its
+// ca.pem. Without DOCKER_TLS_VERIFY the connection is encrypted but the
+// daemon's certificate isn't verified, as with `docker --tls`.
+func engineTLS() (*tls.Config, error) {
+	dir := os.Getenv("DOCKER_CERT_PATH")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cert, err := tls.LoadX509KeyPair(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")); err == nil {
+		cfg.Certificates = []tls.Certificate{cert}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	if os.Getenv("DOCKER_TLS_VERIFY") == "" {
+		cfg.InsecureSkipVerify = true
+		return cfg, nil
+	}
+	ca, err := os.ReadFile(filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.RootCAs = x509.NewCertPool()
+	if !cfg.RootCAs.AppendCertsFromPEM(ca) {
+		return nil, os.ErrInvalid
+	}
+	return cfg, nil
+}
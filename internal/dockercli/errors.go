@@ -0,0 +1,48 @@
+package dockercli
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+
+	"dockwatch/internal/domain"
+)
+
+// cliError describes a failed docker command as "what: reason", taking the
+// reason from output (or the command's stderr) and tagging it with the
+// domain error kind the daemon's message indicates. err stays reachable
+// through errors.Is/As.
+func cliError(what string, err error, output []byte) error {
+	msg := strings.TrimSpace(string(output))
+	var exitErr *exec.ExitError
+	if msg == "" && errors.As(err, &exitErr) {
+		msg = strings.TrimSpace(string(exitErr.Stderr))
+	}
+	if msg == "" {
+		msg = err.Error()
+	}
+	msg = strings.TrimPrefix(msg, "Error response from daemon: ")
+	if errors.Is(err, exec.ErrNotFound) {
+		return &domain.KindError{Kind: domain.ErrDaemonUnavailable, Msg: what + ": docker command not found", Err: err}
+	}
+	return &domain.KindError{Kind: classify(msg), Msg: what + ": " + msg, Err: err}
+}
+
+// classify maps a daemon or CLI message to an error kind.
+func classify(msg string) error {
+	msg = strings.ToLower(msg)
+	switch {
+	case strings.Contains(msg, "volume is in use"):
+		return domain.ErrVolumeInUse
+	case strings.Contains(msg, "has active endpoints"):
+		return domain.ErrNetworkInUse
+	case strings.Contains(msg, "no such"), strings.Contains(msg, "not found"):
+		return domain.ErrNotFound
+	case strings.Contains(msg, "permission denied"):
+		return domain.ErrPermission
+	case strings.Contains(msg, "cannot connect to the docker daemon"), strings.Contains(msg, "is the docker daemon running"),
+		strings.Contains(msg, "connection refused"):
+		return domain.ErrDaemonUnavailable
+	}
+	return nil
+}
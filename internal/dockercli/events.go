@@ -0,0 +1,55 @@
+package dockercli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// Events follows `docker events` for volumes and containers, returning nil
+// once ctx is cancelled.
+func (d *DockerProvider) Events(ctx context.Context, each func(domain.DaemonEvent)) error {
+	cmd := exec.CommandContext(ctx, "docker", "events", "--filter", "type=volume", "--filter", "type=container", "--format", "{{json .}}")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to follow events: %w", err)
+	}
+	sc := bufio.NewScanner(out)
+	for sc.Scan() {
+		var ev struct {
+			Type   string `json:"Type"`
+			Action string `json:"Action"`
+			Actor  struct {
+				ID         string            `json:"ID"`
+				Attributes map[string]string `json:"Attributes"`
+			} `json:"Actor"`
+			TimeNano int64 `json:"timeNano"`
+		}
+		if json.Unmarshal(sc.Bytes(), &ev) != nil {
+			continue
+		}
+		each(domain.DaemonEvent{
+			Time:       time.Unix(0, ev.TimeNano),
+			Type:       ev.Type,
+			Action:     ev.Action,
+			Actor:      ev.Actor.ID,
+			Attributes: ev.Actor.Attributes,
+		})
+	}
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("docker events: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package dockercli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// shells are tried in order when opening an interactive session
+var shells = []string{"bash", "ash", "sh"}
+
+// DetectShell returns the first shell available inside a running container
+func DetectShell(ctx context.Context, id string) (string, error) {
+	for _, sh := range shells {
+		cmd := exec.CommandContext(ctx, "docker", "exec", id, sh, "-c", "exit 0")
+		if cmd.Run() == nil {
+			return sh, nil
+		}
+	}
+	return "", fmt.Errorf("no shell found in container %s (tried %v)", id, shells)
+}
+
+// ExecShellCommand builds an interactive `docker exec -it` command for the
+// container's best available shell. The caller attaches the terminal.
+func ExecShellCommand(ctx context.Context, id string) (*exec.Cmd, error) {
+	sh, err := DetectShell(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command("docker", "exec", "-it", id, sh), nil
+}
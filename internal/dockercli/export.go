@@ -0,0 +1,41 @@
+package dockercli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+)
+
+// ExportVolume streams a tar of a volume's contents to w, read by a
+// throwaway helper container that mounts the volume read-only. It works
+// the same against a remote daemon: only the stream crosses over.
+func (d *DockerProvider) ExportVolume(ctx context.Context, name string, w io.Writer) error {
+	cmd := exec.CommandContext(ctx, "docker", d.helperRun([]string{"-v", name + ":/data:ro"}, "tar", "-C", "/data", "-cf", "-", ".")...)
+	cmd.Stdout = w
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return cliError("failed to export volume "+name, err, stderr.Bytes())
+	}
+	return nil
+}
+
+// ImportVolume unpacks a tar stream from r into a volume through the same
+// kind of helper container, creating the volume if it doesn't exist. With
+// replace the volume's current contents are deleted first; without it the
+// volume must be empty, so nothing is ever merged into existing data.
+func (d *DockerProvider) ImportVolume(ctx context.Context, name string, r io.Reader, replace bool) error {
+	script := `[ -z "$(ls -A /data)" ] || { echo "volume is not empty" >&2; exit 1; }; tar -C /data -xpf -`
+	if replace {
+		script = `find /data -mindepth 1 -delete && tar -C /data -xpf -`
+	}
+	cmd := exec.CommandContext(ctx, "docker", d.helperRun([]string{"-i", "-v", name + ":/data"}, "sh", "-c", script)...)
+	cmd.Stdin = r
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return cliError("failed to restore volume "+name, err, stderr.Bytes())
+	}
+	return nil
+}
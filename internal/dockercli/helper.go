@@ -0,0 +1,106 @@
+package dockercli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"dockwatch/internal/config"
+)
+
+// DefaultHelperImage runs the odd command dockwatch needs on the daemon's
+// host when it can't act there itself.
+const DefaultHelperImage = "alpine:3"
+
+// HelperLabel marks the containers and volumes dockwatch creates for its
+// own work. Listings leave them out, so a backup running in one tab never
+// shows up as a container to prune, or pins its volume as in use, in
+// another.
+const HelperLabel = "dockwatch.helper"
+
+// helperTools are the commands helper containers run; any image with a
+// shell and these will do.
+var helperTools = []string{"tar", "find", "stat", "truncate", "awk"}
+
+// Helper is the image helper containers run and when docker pulls it.
+type Helper struct {
+	Image string // default DefaultHelperImage
+	Pull  string // as for docker run --pull: missing (the default), never or always
+}
+
+// HelperFrom reads the helper section of cfg.
+func HelperFrom(cfg *config.Config) Helper {
+	return Helper{Image: cfg.Helper.Image, Pull: cfg.Helper.Pull}
+}
+
+// SetHelper changes the image later helper containers run.
+func (d *DockerProvider) SetHelper(h Helper) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.helper = h
+}
+
+// HelperImage is the image helper containers run.
+func (d *DockerProvider) HelperImage() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.helper.Image == "" {
+		return DefaultHelperImage
+	}
+	return d.helper.Image
+}
+
+// helperRun is the `docker run` arguments for a throwaway, offline helper
+// container with the extra options opts, running cmd.
+func (d *DockerProvider) helperRun(opts []string, cmd ...string) []string {
+	args := []string{"run", "--rm", "--network", "none", "--label", HelperLabel + "=true"}
+	d.mu.Lock()
+	if d.helper.Pull != "" {
+		args = append(args, "--pull", d.helper.Pull)
+	}
+	d.mu.Unlock()
+	args = append(args, opts...)
+	return append(append(args, d.HelperImage()), cmd...)
+}
+
+// isHelper reports whether labels, as the comma-separated key=value list
+// `docker ps` and `docker volume ls` print, mark a helper.
+func isHelper(labels string) bool {
+	return slices.Contains(strings.Split(labels, ","), HelperLabel+"=true")
+}
+
+// PullHelper pulls the helper image, so that later operations work without
+// registry access.
+func (d *DockerProvider) PullHelper(ctx context.Context) error {
+	image := d.HelperImage()
+	if output, err := exec.CommandContext(ctx, "docker", "pull", image).CombinedOutput(); err != nil {
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		return cliError("failed to pull "+image, err, []byte(lines[len(lines)-1]))
+	}
+	return nil
+}
+
+// CheckHelper makes sure the helper image is present without pulling it
+// and has the commands helper containers run.
+func (d *DockerProvider) CheckHelper(ctx context.Context) error {
+	image := d.HelperImage()
+	if output, err := exec.CommandContext(ctx, "docker", "image", "inspect", "--format", "{{.Id}}", image).CombinedOutput(); err != nil {
+		return cliError(image+" is not present; run dockwatch helper pull, or docker load it", err, output)
+	}
+	script := "for t in " + strings.Join(helperTools, " ") + `; do command -v "$t" >/dev/null || echo "missing $t"; done`
+	cmd := exec.CommandContext(ctx, "docker", "run", "--rm", "--network", "none", "--label", HelperLabel+"=true", "--pull", "never", image, "sh", "-c", script)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return cliError(image+" can't run a shell", err, stderr.Bytes())
+	}
+	if missing := strings.TrimSpace(string(out)); missing != "" {
+		return fmt.Errorf("%s lacks %s; pick an image with a shell and %s", image,
+			strings.Join(strings.Fields(strings.ReplaceAll(missing, "missing ", "")), ", "), strings.Join(helperTools, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package dockercli
+
+import (
+	"context"
+	"dockwatch/internal/domain"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListImages returns all image tags with shared/unique size accounting.
+// Sizes come from `docker system df -v`, which is the only CLI source for
+// per-image unique bytes; layers come from a single batched inspect.
+func (d *DockerProvider) ListImages(ctx context.Context) ([]domain.Image, error) {
+	cmd := exec.CommandContext(ctx, "docker", "system", "df", "-v", "--format", "{{json .}}")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, cliError("failed to list images", err, nil)
+	}
+
+	var df struct {
+		Images []struct {
+			ID         string `json:"ID"`
+			Repository string `json:"Repository"`
+			Tag        string `json:"Tag"`
+			Size       string `json:"Size"`
+			SharedSize string `json:"SharedSize"`
+			UniqueSize string `json:"UniqueSize"`
+			Containers string `json:"Containers"`
+			CreatedAt  string `json:"CreatedAt"`
+		} `json:"Images"`
+	}
+	if err := json.Unmarshal(output, &df); err != nil {
+		return nil, fmt.Errorf("failed to parse system df: %w", err)
+	}
+
+	images := make([]domain.Image, 0, len(df.Images))
+	ids := make([]string, 0, len(df.Images))
+	for _, info := range df.Images {
+		img := domain.Image{
+			ID:         strings.TrimPrefix(info.ID, "sha256:"),
+			Repository: info.Repository,
+			Tag:        info.Tag,
+			SizeBytes:  parseDockerSize(info.Size),
+			SharedSize: parseSizeOrUnknown(info.SharedSize),
+			UniqueSize: parseSizeOrUnknown(info.UniqueSize),
+		}
+		img.Containers, _ = strconv.Atoi(info.Containers)
+		img.CreatedAt, _ = time.Parse("2006-01-02 15:04:05 -0700 MST", info.CreatedAt)
+		images = append(images, img)
+		ids = append(ids, img.ID)
+	}
+
+	// Layers are best effort; shared-layer warnings degrade gracefully
+	if infos, err := d.inspectImages(ctx, ids); err == nil {
+		for i := range images {
+			images[i].Layers = infos[images[i].ID].layers
+			images[i].Labels = infos[images[i].ID].labels
+			images[i].RepoDigests = infos[images[i].ID].repoDigests
+		}
+	}
+
+	return images, nil
+}
+
+// imageInfo is what ListImages takes from image inspect.
+type imageInfo struct {
+	layers      []string
+	labels      map[string]string
+	repoDigests []string
+}
+
+// inspectImages maps (possibly short) image IDs to their RootFS diff IDs,
+// labels and repo digests
+func (d *DockerProvider) inspectImages(ctx context.Context, ids []string) (map[string]imageInfo, error) {
+	if len(ids) == 0 {
+		return map[string]imageInfo{}, nil
+	}
+	args := append([]string{"image", "inspect"}, uniq(ids)...)
+	output, err := exec.CommandContext(ctx, "docker", args...).Output()
+	if err != nil {
+		return nil, cliError("failed to inspect images", err, nil)
+	}
+
+	var inspect []struct {
+		ID          string   `json:"Id"`
+		RepoDigests []string `json:"RepoDigests"`
+		RootFS      struct {
+			Layers []string `json:"Layers"`
+		} `json:"RootFS"`
+		Config struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"Config"`
+	}
+	if err := json.Unmarshal(output, &inspect); err != nil {
+		return nil, fmt.Errorf("failed to parse image inspect: %w", err)
+	}
+
+	infos := make(map[string]imageInfo)
+	for _, img := range inspect {
+		full := strings.TrimPrefix(img.ID, "sha256:")
+		for _, id := range ids {
+			if strings.HasPrefix(full, id) {
+				infos[id] = imageInfo{layers: img.RootFS.Layers, labels: img.Config.Labels, repoDigests: img.RepoDigests}
+			}
+		}
+	}
+	return infos, nil
+}
+
+// parseSizeOrUnknown maps docker's "N/A" and empty sizes to -1
+func parseSizeOrUnknown(s string) int64 {
+	if s == "" || s == "N/A" {
+		return -1
+	}
+	return parseDockerSize(s)
+}
+
+func uniq(in []string) []string {
+	seen := make(map[string]bool, len(in))
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// RemoveImage removes an image tag (or untagged image by ID)
+func (d *DockerProvider) RemoveImage(ctx context.Context, ref string) error {
+	cmd := exec.CommandContext(ctx, "docker", "image", "rm", ref)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return cliError("failed to remove image "+ref, err, output)
+	}
+	return nil
+}
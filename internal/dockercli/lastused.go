@@ -0,0 +1,210 @@
+package dockercli
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// volumeUse is what container history says about one volume.
+type volumeUse struct {
+	inUse    bool      // mounted by a running container
+	lastUsed time.Time // latest time a container stopped using it
+}
+
+// usageCache carries volumeUsage's work from one refresh to the next, so a
+// refresh only inspects containers whose state changed and only replays the
+// events since the previous one. A container that ran and stopped again in
+// between keeps its old FinishedAt here, but its unmount events say when.
+type usageCache struct {
+	mu         sync.Mutex
+	containers map[string]containerUse // by ID
+	unmounted  map[string]time.Time    // volume -> newest unmount event
+	helpers    map[string]bool         // IDs of dockwatch's helper containers
+	replayed   string                  // --since for the next replay
+}
+
+// containerUse is one inspected container, as of state.
+type containerUse struct {
+	state    string
+	helper   bool
+	running  bool
+	finished time.Time
+	volumes  []string
+}
+
+// volumeUsage infers when each volume was last used: the newest FinishedAt
+// among containers that mount it, and the newest unmount event the daemon
+// still remembers, which also covers containers removed since. Both sources
+// are best effort, so volumes may be missing from the result.
+func (d *DockerProvider) volumeUsage(ctx context.Context) map[string]volumeUse {
+	c := &d.usage
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inspect(ctx)
+	c.replay(ctx)
+
+	usage := map[string]volumeUse{}
+	seen := func(name string, at time.Time) {
+		u := usage[name]
+		if at.After(u.lastUsed) {
+			u.lastUsed = at
+		}
+		usage[name] = u
+	}
+	for _, cu := range c.containers {
+		if cu.helper {
+			continue
+		}
+		for _, name := range cu.volumes {
+			if cu.running {
+				u := usage[name]
+				u.inUse = true
+				usage[name] = u
+			} else if cu.finished.Year() > 1 {
+				seen(name, cu.finished)
+			}
+		}
+	}
+	for name, at := range c.unmounted {
+		seen(name, at)
+	}
+	return usage
+}
+
+// inspect brings containers up to date, inspecting the containers that are
+// new or changed state since the last call. On failure it keeps what it had.
+func (c *usageCache) inspect(ctx context.Context) {
+	out, err := exec.CommandContext(ctx, "docker", "ps", "-a", "--no-trunc", "--format", "{{.ID}} {{.State}}").Output()
+	if err != nil {
+		return
+	}
+	states := map[string]string{}
+	var stale []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		id, state, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		states[id] = state
+		if cu, ok := c.containers[id]; !ok || cu.state != state {
+			stale = append(stale, id)
+		}
+	}
+	fresh := map[string]containerUse{}
+	for id, cu := range c.containers {
+		if states[id] == cu.state {
+			fresh[id] = cu
+		}
+	}
+	c.containers = fresh
+	if len(stale) == 0 {
+		return
+	}
+
+	out, err = exec.CommandContext(ctx, "docker", append([]string{"container", "inspect"}, stale...)...).Output()
+	var infos []struct {
+		ID     string `json:"Id"`
+		Config struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"Config"`
+		State struct {
+			Status     string `json:"Status"`
+			Running    bool   `json:"Running"`
+			FinishedAt string `json:"FinishedAt"`
+		} `json:"State"`
+		Mounts []struct {
+			Type string `json:"Type"`
+			Name string `json:"Name"`
+		} `json:"Mounts"`
+	}
+	if err != nil || json.Unmarshal(out, &infos) != nil {
+		return
+	}
+	for _, info := range infos {
+		finished, _ := time.Parse(time.RFC3339Nano, info.State.FinishedAt)
+		cu := containerUse{
+			state:    states[info.ID],
+			helper:   info.Config.Labels[HelperLabel] == "true",
+			running:  info.State.Running,
+			finished: finished,
+		}
+		for _, m := range info.Mounts {
+			if m.Type == "volume" {
+				cu.volumes = append(cu.volumes, m.Name)
+			}
+		}
+		c.containers[info.ID] = cu
+	}
+}
+
+// replay adds the unmount events since the last replay, leaving out those
+// of dockwatch's own helper containers: a backup or a size check reads a
+// volume without anyone using it.
+func (c *usageCache) replay(ctx context.Context) {
+	since := c.replayed
+	if since == "" {
+		since = "1" // the daemon's whole buffer
+	}
+	// --until makes docker replay its event buffer and exit instead of
+	// following. Events in that last second are replayed again next time,
+	// which is harmless, rather than missed.
+	until := strconv.FormatInt(time.Now().Unix(), 10)
+	events := func(filters ...string) ([]daemonEvent, error) {
+		args := append([]string{"events", "--since", since, "--until", until, "--format", "{{json .}}"}, filters...)
+		out, err := exec.CommandContext(ctx, "docker", args...).Output()
+		if err != nil {
+			return nil, err
+		}
+		var evs []daemonEvent
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			var ev daemonEvent
+			if json.Unmarshal([]byte(line), &ev) == nil && ev.Actor.ID != "" {
+				evs = append(evs, ev)
+			}
+		}
+		return evs, nil
+	}
+	// Every event of a helper, its die included, comes before its unmounts
+	helpers, err := events("--filter", "type=container", "--filter", "label="+HelperLabel+"=true")
+	if err != nil {
+		return
+	}
+	unmounts, err := events("--filter", "type=volume", "--filter", "event=unmount")
+	if err != nil {
+		return
+	}
+	c.replayed = until
+	if c.unmounted == nil {
+		c.unmounted, c.helpers = map[string]time.Time{}, map[string]bool{}
+	}
+	for _, ev := range helpers {
+		c.helpers[ev.Actor.ID] = true
+	}
+	for id, cu := range c.containers {
+		if cu.helper {
+			c.helpers[id] = true
+		}
+	}
+	for _, ev := range unmounts {
+		if c.helpers[ev.Actor.Attributes["container"]] {
+			continue
+		}
+		if at := time.Unix(0, ev.TimeNano); at.After(c.unmounted[ev.Actor.ID]) {
+			c.unmounted[ev.Actor.ID] = at
+		}
+	}
+}
+
+// daemonEvent is a line of `docker events --format '{{json .}}'`.
+type daemonEvent struct {
+	Actor struct {
+		ID         string            `json:"ID"`
+		Attributes map[string]string `json:"Attributes"`
+	} `json:"Actor"`
+	TimeNano int64 `json:"timeNano"`
+}
@@ -0,0 +1,120 @@
+package dockercli
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"dockwatch/internal/config"
+)
+
+// Limits bound how many docker calls EnrichVolumes makes at once.
+type Limits struct {
+	Inspects  int // volume inspects
+	SizeScans int // volume size measurements
+
+	// AutoThrottle halves both while the daemon answers inspects slowly.
+	AutoThrottle bool
+}
+
+// DefaultLimits apply until SetLimits is called.
+var DefaultLimits = Limits{Inspects: 4, SizeScans: 2, AutoThrottle: true}
+
+// LimitsFrom reads the concurrency section of cfg.
+func LimitsFrom(cfg *config.Config) Limits {
+	inspects, _, sizeScans, auto := cfg.Parallelism()
+	return Limits{Inspects: inspects, SizeScans: sizeScans, AutoThrottle: auto}
+}
+
+// slowCall is the average inspect latency past which the daemon counts as
+// loaded.
+const slowCall = 500 * time.Millisecond
+
+// SetLimits changes the parallelism of later EnrichVolumes calls.
+func (d *DockerProvider) SetLimits(l Limits) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.limits = &l
+}
+
+func (d *DockerProvider) currentLimits() Limits {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.limits == nil {
+		return DefaultLimits
+	}
+	return *d.limits
+}
+
+// daemonLoad is a moving average of how long the daemon takes to answer.
+type daemonLoad struct {
+	mu  sync.Mutex
+	avg time.Duration
+}
+
+func (l *daemonLoad) observe(took time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.avg == 0 {
+		l.avg = took
+		return
+	}
+	l.avg = (3*l.avg + took) / 4
+}
+
+func (l *daemonLoad) slow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.avg > slowCall
+}
+
+// throttle lets at most max callers in at once, half as many while load
+// reports a slow daemon. A nil load never throttles.
+type throttle struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	max  int
+	busy int
+	load *daemonLoad
+}
+
+func newThrottle(n int, load *daemonLoad) *throttle {
+	t := &throttle{max: max(n, 1), load: load}
+	t.cond = sync.NewCond(&t.mu)
+	return t
+}
+
+// limit is how many callers may be in right now.
+func (t *throttle) limit() int {
+	if t.load != nil && t.load.slow() {
+		return (t.max + 1) / 2
+	}
+	return t.max
+}
+
+// acquire waits for a free slot; false when ctx is done first.
+func (t *throttle) acquire(ctx context.Context) bool {
+	stop := context.AfterFunc(ctx, func() {
+		t.mu.Lock()
+		t.cond.Broadcast()
+		t.mu.Unlock()
+	})
+	defer stop()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.busy >= t.limit() && ctx.Err() == nil {
+		t.cond.Wait()
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	t.busy++
+	return true
+}
+
+func (t *throttle) release() {
+	t.mu.Lock()
+	t.busy--
+	t.cond.Broadcast()
+	t.mu.Unlock()
+}
@@ -0,0 +1,92 @@
+package dockercli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// logSize sums a json-file log and its rotated siblings (path.1, path.2,
+// ...). The logs are usually root-only, so -1 often just means no access.
+func logSize(path string) int64 {
+	if path == "" {
+		return -1
+	}
+	files, _ := filepath.Glob(path + "*")
+	var total int64
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return -1
+		}
+		total += info.Size()
+	}
+	if len(files) == 0 {
+		return -1
+	}
+	return total
+}
+
+// TruncateLogs empties a container's json-file log in place, which the
+// daemon tolerates: it keeps appending at the new end. Rotated files are
+// left alone. With a local daemon the file is truncated directly; when
+// that isn't possible (remote daemon, Docker Desktop VM, no root) a
+// throwaway helper container does it on the daemon's host.
+func (d *DockerProvider) TruncateLogs(ctx context.Context, id string) (int64, error) {
+	out, err := exec.CommandContext(ctx, "docker", "container", "inspect", "--format",
+		"{{json .LogPath}} {{json .HostConfig.LogConfig.Type}}", id).Output()
+	if err != nil {
+		return 0, cliError("failed to inspect container "+id, err, nil)
+	}
+	var path, driver string
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 || json.Unmarshal([]byte(fields[0]), &path) != nil || json.Unmarshal([]byte(fields[1]), &driver) != nil {
+		return 0, fmt.Errorf("failed to inspect container %s: unexpected output %q", id, out)
+	}
+	if driver != "json-file" || path == "" {
+		return 0, fmt.Errorf("%s logs to %s; only json-file logs can be truncated", id, driver)
+	}
+
+	if IsLocalDaemon() {
+		freed, err := truncateLocal(path)
+		if err == nil {
+			return freed, nil
+		}
+		// Missing means Docker Desktop keeps it in its VM; denied means no root
+		if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, os.ErrPermission) {
+			return 0, fmt.Errorf("failed to truncate %s: %w", path, err)
+		}
+	}
+	return d.truncateWithHelper(ctx, path)
+}
+
+func truncateLocal(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), os.Truncate(path, 0)
+}
+
+// truncateWithHelper truncates path on the daemon's host from a container
+// that bind-mounts its directory, printing the size it had first.
+func (d *DockerProvider) truncateWithHelper(ctx context.Context, path string) (int64, error) {
+	dir, file := filepath.Dir(path), filepath.Base(path)
+	cmd := exec.CommandContext(ctx, "docker", d.helperRun([]string{"-v", dir + ":/logs"},
+		"sh", "-c", `stat -c %s "/logs/$1" && truncate -s 0 "/logs/$1"`, "sh", file)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, cliError("failed to truncate logs via helper container", err, nil)
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("helper container printed %q instead of the log size", out)
+	}
+	return size, nil
+}
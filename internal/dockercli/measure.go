@@ -0,0 +1,112 @@
+package dockercli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"dockwatch/internal/domain"
+)
+
+// Measurer sizes the volumes of one kind of storage. Implementations are
+// registered with RegisterMeasurer, typically from an init function, so a
+// driver can be supported without changes to the provider.
+type Measurer interface {
+	// Measure returns v's size in bytes. local reports whether v.Mountpoint
+	// is a path on this machine. ErrUnmeasurable leaves the volume to the
+	// daemon's own accounting (`docker system df`).
+	Measure(ctx context.Context, v domain.Volume, local bool) (int64, error)
+}
+
+// MeasurerFunc adapts a function to Measurer.
+type MeasurerFunc func(ctx context.Context, v domain.Volume, local bool) (int64, error)
+
+func (f MeasurerFunc) Measure(ctx context.Context, v domain.Volume, local bool) (int64, error) {
+	return f(ctx, v, local)
+}
+
+// ErrUnmeasurable is returned by a Measurer that can't size a volume.
+var ErrUnmeasurable = errors.New("volume can't be measured here")
+
+var (
+	measurersMu sync.RWMutex
+	measurers   = map[string]Measurer{}
+)
+
+// RegisterMeasurer makes m size volumes of kind, see VolumeKind. A later
+// registration for the same kind replaces the earlier one.
+func RegisterMeasurer(kind string, m Measurer) {
+	measurersMu.Lock()
+	defer measurersMu.Unlock()
+	measurers[kind] = m
+}
+
+// VolumeKind names the storage behind v for measurer lookup: the mount type
+// for local-driver network shares ("nfs", "cifs"), otherwise the driver
+// without its tag, e.g. "rexray/ebs".
+func VolumeKind(v domain.Volume) string {
+	if v.Driver == "local" || v.Driver == "" {
+		switch t := v.Options["type"]; t {
+		case "nfs", "nfs4":
+			return "nfs"
+		case "cifs", "smb", "smb3":
+			return "cifs"
+		}
+		return "local"
+	}
+	kind, _, _ := strings.Cut(v.Driver, ":")
+	return kind
+}
+
+// measurerFor finds the measurer of v's kind, falling back to the driver
+// family so "rexray/ebs" uses "rexray" unless it has one of its own.
+func measurerFor(v domain.Volume) (Measurer, bool) {
+	measurersMu.RLock()
+	defer measurersMu.RUnlock()
+	kind := VolumeKind(v)
+	if m, ok := measurers[kind]; ok {
+		return m, true
+	}
+	family, _, _ := strings.Cut(kind, "/")
+	m, ok := measurers[family]
+	return m, ok
+}
+
+func init() {
+	RegisterMeasurer("local", MeasurerFunc(measureLocal))
+	RegisterMeasurer("nfs", MeasurerFunc(measureShare))
+	RegisterMeasurer("cifs", MeasurerFunc(measureShare))
+	RegisterMeasurer("rexray", MeasurerFunc(measureRexray))
+}
+
+// measureLocal walks the volume's directory.
+func measureLocal(_ context.Context, v domain.Volume, local bool) (int64, error) {
+	if !local || v.Mountpoint == "" {
+		return 0, ErrUnmeasurable
+	}
+	return walkSize(v.Mountpoint)
+}
+
+// measureShare walks an NFS or CIFS share, but only while a running
+// container has it mounted: otherwise the mountpoint is an empty local
+// directory and walking it would report zero.
+func measureShare(ctx context.Context, v domain.Volume, local bool) (int64, error) {
+	if !v.InUse {
+		return 0, ErrUnmeasurable
+	}
+	return measureLocal(ctx, v, local)
+}
+
+// measureRexray reports the provisioned size REX-Ray puts in the volume
+// status, in GiB. The data lives on a block device elsewhere, so this is
+// capacity rather than usage.
+func measureRexray(_ context.Context, v domain.Volume, _ bool) (int64, error) {
+	gib, err := strconv.ParseFloat(v.DriverStatus["size"], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: no size in driver status", ErrUnmeasurable)
+	}
+	return int64(gib * (1 << 30)), nil
+}
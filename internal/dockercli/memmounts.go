@@ -0,0 +1,93 @@
+package dockercli
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"dockwatch/internal/domain"
+)
+
+// MemMounts measures /dev/shm and the tmpfs mounts of the running
+// containers in cs with df inside each container. Images without df (e.g.
+// distroless) report the configured shm size and -1 as used. Stopped
+// containers hold nothing in memory and are left out. The fullest mounts
+// come first.
+func MemMounts(ctx context.Context, cs []domain.Container) []domain.MemMount {
+	var mounts []domain.MemMount
+	for _, c := range cs {
+		if !c.Running() {
+			continue
+		}
+		var dests []string
+		if c.ShmSize > 0 {
+			dests = append(dests, "/dev/shm")
+		}
+		for _, m := range c.Mounts {
+			if m.Type == "tmpfs" {
+				dests = append(dests, m.Destination)
+			}
+		}
+		if len(dests) == 0 {
+			continue
+		}
+		usage, _ := df(ctx, c.ID, dests)
+		for _, dest := range dests {
+			mm := domain.MemMount{Container: c.Name, Destination: dest, UsedBytes: -1}
+			if dest == "/dev/shm" {
+				mm.SizeBytes = c.ShmSize
+			}
+			if u, ok := usage[dest]; ok {
+				mm.SizeBytes, mm.UsedBytes = u[0], u[1]
+			}
+			mounts = append(mounts, mm)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	slices.SortStableFunc(mounts, func(a, b domain.MemMount) int { return cmp.Compare(b.UsedBytes, a.UsedBytes) })
+	return mounts
+}
+
+// df returns size and used bytes per mount point inside a container.
+func df(ctx context.Context, id string, dests []string) (map[string][2]int64, error) {
+	args := append([]string{"exec", id, "df", "-P", "-k"}, dests...)
+	// df exits non-zero when one path is missing but still reports the rest
+	output, err := exec.CommandContext(ctx, "docker", args...).Output()
+	if len(output) == 0 {
+		return nil, err
+	}
+	usage := map[string][2]int64{}
+	sc := bufio.NewScanner(bytes.NewReader(output))
+	sc.Scan() // header
+	for sc.Scan() {
+		// Filesystem 1024-blocks Used Available Capacity Mounted-on
+		f := strings.Fields(sc.Text())
+		if len(f) < 6 {
+			continue
+		}
+		size, err1 := strconv.ParseInt(f[1], 10, 64)
+		used, err2 := strconv.ParseInt(f[2], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		usage[strings.Join(f[5:], " ")] = [2]int64{size << 10, used << 10}
+	}
+	return usage, nil
+}
+
+// HostMemory is the daemon host's total memory in bytes, or 0 if unknown.
+func HostMemory(ctx context.Context) int64 {
+	output, err := exec.CommandContext(ctx, "docker", "info", "--format", "{{.MemTotal}}").Output()
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	return n
+}
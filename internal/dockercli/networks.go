@@ -0,0 +1,89 @@
+package dockercli
+
+import (
+	"context"
+	"dockwatch/internal/domain"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ListNetworks returns all networks with their attached containers
+func (d *DockerProvider) ListNetworks(ctx context.Context) ([]domain.Network, error) {
+	output, err := exec.CommandContext(ctx, "docker", "network", "ls", "-q", "--no-trunc").Output()
+	if err != nil {
+		return nil, cliError("failed to list networks", err, nil)
+	}
+	ids := strings.Fields(string(output))
+	if len(ids) == 0 {
+		return []domain.Network{}, nil
+	}
+
+	output, err = exec.CommandContext(ctx, "docker", append([]string{"network", "inspect"}, ids...)...).Output()
+	if err != nil {
+		return nil, cliError("failed to inspect networks", err, nil)
+	}
+
+	var infos []struct {
+		ID         string            `json:"Id"`
+		Name       string            `json:"Name"`
+		Driver     string            `json:"Driver"`
+		Scope      string            `json:"Scope"`
+		Internal   bool              `json:"Internal"`
+		Attachable bool              `json:"Attachable"`
+		EnableIPv6 bool              `json:"EnableIPv6"`
+		Labels     map[string]string `json:"Labels"`
+		IPAM       struct {
+			Driver string `json:"Driver"`
+			Config []struct {
+				Subnet  string `json:"Subnet"`
+				Gateway string `json:"Gateway"`
+				IPRange string `json:"IPRange"`
+			} `json:"Config"`
+		} `json:"IPAM"`
+		Created    string `json:"Created"`
+		Containers map[string]struct {
+			Name string `json:"Name"`
+		} `json:"Containers"`
+	}
+	if err := json.Unmarshal(output, &infos); err != nil {
+		return nil, fmt.Errorf("failed to parse network inspect: %w", err)
+	}
+
+	networks := make([]domain.Network, 0, len(infos))
+	for _, info := range infos {
+		n := domain.Network{
+			ID:         info.ID,
+			Name:       info.Name,
+			Driver:     info.Driver,
+			Scope:      info.Scope,
+			Internal:   info.Internal,
+			Attachable: info.Attachable,
+			IPv6:       info.EnableIPv6,
+			Labels:     info.Labels,
+			IPAMDriver: info.IPAM.Driver,
+		}
+		for _, c := range info.IPAM.Config {
+			n.IPAM = append(n.IPAM, domain.IPAMConfig{Subnet: c.Subnet, Gateway: c.Gateway, IPRange: c.IPRange})
+		}
+		n.CreatedAt, _ = time.Parse(time.RFC3339Nano, info.Created)
+		for _, c := range info.Containers {
+			n.Containers = append(n.Containers, c.Name)
+		}
+		sort.Strings(n.Containers)
+		networks = append(networks, n)
+	}
+	return networks, nil
+}
+
+// RemoveNetwork removes a network by ID or name
+func (d *DockerProvider) RemoveNetwork(ctx context.Context, id string) error {
+	cmd := exec.CommandContext(ctx, "docker", "network", "rm", id)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return cliError("failed to remove network "+id, err, output)
+	}
+	return nil
+}
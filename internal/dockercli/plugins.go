@@ -0,0 +1,51 @@
+package dockercli
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"dockwatch/internal/domain"
+)
+
+// Plugins lists the installed managed plugins. Each one's rootfs is under
+// root/plugins/ID and measured there; without the privileges to read it,
+// or with an empty root, sizes are -1.
+func Plugins(ctx context.Context, root string) ([]domain.Plugin, error) {
+	out, err := exec.CommandContext(ctx, "docker", "plugin", "ls", "-q", "--no-trunc").Output()
+	if err != nil {
+		return nil, cliError("docker plugin ls failed", err, nil)
+	}
+	ids := strings.Fields(string(out))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	out, err = exec.CommandContext(ctx, "docker", append([]string{"plugin", "inspect"}, ids...)...).Output()
+	if err != nil {
+		return nil, cliError("docker plugin inspect failed", err, nil)
+	}
+	var infos []struct {
+		ID      string `json:"Id"`
+		Name    string `json:"Name"`
+		Enabled bool   `json:"Enabled"`
+		Config  struct {
+			Interface struct {
+				Types []string `json:"Types"`
+			} `json:"Interface"`
+		} `json:"Config"`
+	}
+	if err := json.Unmarshal(out, &infos); err != nil {
+		return nil, err
+	}
+	plugins := make([]domain.Plugin, 0, len(infos))
+	for _, info := range infos {
+		p := domain.Plugin{ID: info.ID, Name: info.Name, Enabled: info.Enabled, Types: info.Config.Interface.Types, SizeBytes: -1}
+		if root != "" {
+			p.SizeBytes, _ = walkSizePartial(ctx, filepath.Join(root, "plugins", info.ID))
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, nil
+}
@@ -0,0 +1,164 @@
+package dockercli
+
+import (
+	"bufio"
+	"context"
+	"dockwatch/internal/domain"
+	"dockwatch/internal/registry"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+// PullImage pulls ref and reports aggregated progress. Byte-level progress
+// needs the Engine API; without a reachable socket it falls back to
+// `docker pull` and reports per-layer progress only.
+func (d *DockerProvider) PullImage(ctx context.Context, ref string, progress func(domain.PullProgress)) error {
+	client, base, ok := engineClient()
+	if !ok {
+		return d.pullCLI(ctx, ref, progress)
+	}
+
+	name, tag := ref, "latest"
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		name, tag = ref[:i], ref[i+1:]
+	}
+	q := url.Values{"fromImage": {name}, "tag": {tag}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/images/create?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if user, secret := registry.Credentials(ref); user != "" {
+		auth, _ := json.Marshal(map[string]string{"username": user, "password": secret})
+		req.Header.Set("X-Registry-Auth", base64.URLEncoding.EncodeToString(auth))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to pull %s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("failed to pull %s: %s", ref, ifEmpty(apiErr.Message, resp.Status))
+	}
+
+	t := newPullTracker()
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var ev struct {
+			ID             string `json:"id"`
+			Status         string `json:"status"`
+			Error          string `json:"error"`
+			ProgressDetail struct {
+				Current int64 `json:"current"`
+				Total   int64 `json:"total"`
+			} `json:"progressDetail"`
+		}
+		if err := dec.Decode(&ev); err != nil {
+			switch {
+			case errors.Is(err, context.Canceled) || ctx.Err() != nil:
+				return ctx.Err()
+			case errors.Is(err, io.EOF):
+				return nil // stream finished
+			}
+			// A reset connection or a cut-off stream is not a finished pull
+			return fmt.Errorf("failed to pull %s: %w", ref, err)
+		}
+		if ev.Error != "" {
+			return fmt.Errorf("failed to pull %s: %s", ref, ev.Error)
+		}
+		t.update(ev.ID, ev.Status, ev.ProgressDetail.Current, ev.ProgressDetail.Total)
+		progress(t.snapshot())
+	}
+}
+
+// pullCLI runs `docker pull` and derives progress from per-layer status lines
+func (d *DockerProvider) pullCLI(ctx context.Context, ref string, progress func(domain.PullProgress)) error {
+	cmd := exec.CommandContext(ctx, "docker", "pull", ref)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to pull %s: %w", ref, err)
+	}
+
+	t := newPullTracker()
+	sc := bufio.NewScanner(stdout)
+	for sc.Scan() {
+		// Lines look like "a1b2c3d4e5f6: Pull complete"
+		id, status, ok := strings.Cut(sc.Text(), ": ")
+		if !ok {
+			id, status = "", sc.Text()
+		}
+		t.update(id, status, 0, 0)
+		progress(t.snapshot())
+	}
+	if err := cmd.Wait(); err != nil {
+		return cliError("failed to pull "+ref, err, []byte(stderr.String()))
+	}
+	return nil
+}
+
+// pullTracker folds per-layer events into one PullProgress
+type pullTracker struct {
+	current, total map[string]int64
+	done           map[string]bool
+	order          []string
+	status         string
+}
+
+func newPullTracker() *pullTracker {
+	return &pullTracker{current: map[string]int64{}, total: map[string]int64{}, done: map[string]bool{}}
+}
+
+func (t *pullTracker) update(id, status string, current, total int64) {
+	t.status = strings.TrimSpace(id + " " + status)
+	if id == "" || strings.HasPrefix(status, "Pulling from") || strings.HasPrefix(status, "Digest") {
+		return
+	}
+	if _, seen := t.done[id]; !seen {
+		t.order = append(t.order, id)
+		t.done[id] = false
+	}
+	switch {
+	case status == "Downloading" && total > 0:
+		t.current[id], t.total[id] = current, total
+	case status == "Download complete", status == "Pull complete", status == "Already exists":
+		t.done[id] = true
+		if tot := t.total[id]; tot > 0 {
+			t.current[id] = tot
+		}
+	}
+}
+
+func (t *pullTracker) snapshot() domain.PullProgress {
+	p := domain.PullProgress{Layers: len(t.order), Status: t.status}
+	for _, id := range t.order {
+		p.Current += t.current[id]
+		p.Total += t.total[id]
+		if t.done[id] {
+			p.LayersDone++
+		}
+	}
+	return p
+}
+
+func ifEmpty(s, repl string) string {
+	if s == "" {
+		return repl
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package dockercli
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"dockwatch/internal/domain"
+)
+
+// rootSubsystems says what the usual top-level directories of the data root
+// hold. Storage drivers keep image and container layers under their name.
+var rootSubsystems = map[string]string{
+	"overlay2":       "image and container layers",
+	"fuse-overlayfs": "image and container layers",
+	"btrfs":          "image and container layers",
+	"zfs":            "image and container layers",
+	"vfs":            "image and container layers",
+	"aufs":           "image and container layers",
+	"image":          "image metadata and layer database",
+	"containerd":     "image content (containerd store)",
+	"containers":     "container configs and json-file logs",
+	"volumes":        "named volumes",
+	"buildkit":       "build cache metadata",
+	"network":        "network state",
+	"plugins":        "plugins",
+	"swarm":          "swarm state",
+	"tmp":            "temporary files",
+	"runtimes":       "runtime state",
+}
+
+// RootBreakdown measures each top-level directory of the data root and asks
+// the daemon what it thinks is used. The walks don't cross mount points:
+// a running container's overlay2/<id>/merged is its whole filesystem view,
+// image included, and a volume may be an NFS or CIFS share, neither of
+// which is space the data root takes. Reading the root usually takes root
+// privileges; without them the error is domain.ErrPermission.
+func RootBreakdown(ctx context.Context, root string) (domain.RootUsage, error) {
+	usage := domain.RootUsage{Root: root}
+	entries, err := os.ReadDir(root)
+	switch {
+	case os.IsPermission(err):
+		return usage, &domain.KindError{Kind: domain.ErrPermission, Msg: fmt.Sprintf("cannot read %s: permission denied", root), Err: err}
+	case os.IsNotExist(err):
+		return usage, &domain.KindError{Kind: domain.ErrNotFound, Msg: fmt.Sprintf("%s is not on this machine", root), Err: err}
+	case err != nil:
+		return usage, fmt.Errorf("cannot read %s: %w", root, err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		d := domain.RootDir{Name: e.Name(), Subsystem: rootSubsystems[e.Name()]}
+		d.SizeBytes, d.Skipped = walkSizePartial(ctx, filepath.Join(root, e.Name()))
+		if ctx.Err() != nil {
+			return usage, ctx.Err()
+		}
+		usage.Dirs = append(usage.Dirs, d)
+	}
+	slices.SortStableFunc(usage.Dirs, func(a, b domain.RootDir) int { return cmp.Compare(b.SizeBytes, a.SizeBytes) })
+	usage.Reported, _ = systemDF(ctx)
+	return usage, nil
+}
+
+// systemDF returns the size of each `docker system df` row by type.
+func systemDF(ctx context.Context) (map[string]int64, error) {
+	out, err := exec.CommandContext(ctx, "docker", "system", "df", "--format", "{{json .}}").Output()
+	if err != nil {
+		return nil, cliError("failed to read system df", err, nil)
+	}
+	rows := map[string]int64{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		var row struct {
+			Type string `json:"Type"`
+			Size string `json:"Size"`
+		}
+		if json.Unmarshal([]byte(line), &row) != nil || row.Type == "" {
+			continue
+		}
+		rows[row.Type] = parseDockerSize(row.Size)
+	}
+	return rows, nil
+}
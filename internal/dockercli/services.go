@@ -0,0 +1,106 @@
+package dockercli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"dockwatch/internal/domain"
+)
+
+// serviceSpec is the part of `docker service inspect` that says what a
+// service's tasks use.
+type serviceSpec struct {
+	Spec struct {
+		Name         string `json:"Name"`
+		TaskTemplate struct {
+			ContainerSpec struct {
+				Mounts []struct {
+					Type   string `json:"Type"`
+					Source string `json:"Source"`
+				} `json:"Mounts"`
+				Secrets []struct {
+					SecretID string `json:"SecretID"`
+				} `json:"Secrets"`
+				Configs []struct {
+					ConfigID string `json:"ConfigID"`
+				} `json:"Configs"`
+			} `json:"ContainerSpec"`
+		} `json:"TaskTemplate"`
+	} `json:"Spec"`
+}
+
+// inspectServices returns the specs of every swarm service, none unless
+// the daemon is a swarm manager. Any other failure is an error, not an
+// empty swarm: callers decide from it what nothing references.
+func inspectServices(ctx context.Context) ([]serviceSpec, error) {
+	out, err := exec.CommandContext(ctx, "docker", "info", "--format", "{{.Swarm.LocalNodeState}} {{.Swarm.ControlAvailable}}").Output()
+	if err != nil {
+		return nil, cliError("docker info failed", err, nil)
+	}
+	if strings.TrimSpace(string(out)) != "active true" {
+		return nil, ErrNotSwarmManager
+	}
+	out, err = exec.CommandContext(ctx, "docker", "service", "ls", "-q").Output()
+	if err != nil {
+		return nil, cliError("failed to list services", err, nil)
+	}
+	ids := strings.Fields(string(out))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	out, err = exec.CommandContext(ctx, "docker", append([]string{"service", "inspect"}, ids...)...).Output()
+	if err != nil {
+		return nil, cliError("failed to inspect services", err, nil)
+	}
+	var specs []serviceSpec
+	if err := json.Unmarshal(out, &specs); err != nil {
+		return nil, fmt.Errorf("failed to parse service inspect: %w", err)
+	}
+	return specs, nil
+}
+
+// serviceVolumes maps volume names to the swarm services whose spec mounts
+// them. A service scaled to zero or between cron-like runs has no
+// container, but its next task needs the volume. Empty unless the daemon
+// is a swarm manager; nil when the services couldn't be read.
+func (d *DockerProvider) serviceVolumes(ctx context.Context) map[string][]string {
+	specs, err := inspectServices(ctx)
+	if err != nil && !errors.Is(err, ErrNotSwarmManager) {
+		return nil
+	}
+	byVolume := map[string][]string{}
+	for _, s := range specs {
+		for _, m := range s.Spec.TaskTemplate.ContainerSpec.Mounts {
+			if m.Type == "volume" && m.Source != "" {
+				byVolume[m.Source] = append(byVolume[m.Source], s.Spec.Name)
+			}
+		}
+	}
+	return byVolume
+}
+
+// addServices lists the services mounting v as attached, "service:NAME",
+// so it isn't flagged orphan; their tasks count as stopped users. With
+// services nil, unread, a volume nothing else uses may still be a
+// service's, so its state is unknown rather than orphaned.
+func addServices(v *domain.Volume, services map[string][]string) {
+	if services == nil {
+		if v.State == domain.Orphaned {
+			v.State = domain.OrphanUnknown
+		}
+		return
+	}
+	for _, name := range services[v.Name] {
+		if ref := "service:" + name; !slices.Contains(v.Attached, ref) {
+			v.Attached = append(v.Attached, ref)
+		}
+	}
+	if v.State == domain.Orphaned && len(v.Attached) > 0 {
+		v.State = domain.InUseStopped
+	}
+}
@@ -0,0 +1,156 @@
+package dockercli
+
+import (
+	"context"
+	"dockwatch/internal/domain"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sizeTTL bounds how long a cached size is trusted when its volume's
+// top-level mtime hasn't moved; writes deeper in the tree don't bump it.
+const sizeTTL = 10 * time.Minute
+
+// sizeEntry is a walked size together with the change signal it was taken at.
+type sizeEntry struct {
+	mtime    time.Time
+	size     int64
+	measured time.Time
+}
+
+// sizeCache remembers walked sizes across refreshes so only volumes whose
+// mountpoint changed are re-measured.
+type sizeCache struct {
+	mu      sync.Mutex
+	entries map[string]sizeEntry
+}
+
+// lookup returns the cached size for name if it was taken at mtime and is
+// younger than sizeTTL.
+func (c *sizeCache) lookup(name string, mtime time.Time, now time.Time) (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[name]
+	if !ok || !e.mtime.Equal(mtime) || now.Sub(e.measured) > sizeTTL {
+		return 0, false
+	}
+	return e.size, true
+}
+
+func (c *sizeCache) store(name string, e sizeEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]sizeEntry{}
+	}
+	c.entries[name] = e
+}
+
+// prune drops entries for volumes that no longer exist.
+func (c *sizeCache) prune(live map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name := range c.entries {
+		if !live[name] {
+			delete(c.entries, name)
+		}
+	}
+}
+
+// IsLocalDaemon reports whether volume mountpoints live on this machine's
+// filesystem, i.e. docker talks to a local unix socket and no remote
+// context is selected.
+func IsLocalDaemon() bool {
+	host := os.Getenv("DOCKER_HOST")
+	if host != "" && !strings.HasPrefix(host, "unix://") {
+		return false
+	}
+	ctx := os.Getenv("DOCKER_CONTEXT")
+	return ctx == "" || ctx == "default"
+}
+
+// walkSize sums the apparent size of regular files under root. Any
+// unreadable entry fails the whole walk so that callers fall back to the
+// daemon instead of reporting a silently truncated size.
+func walkSize(root string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// dfVolumeSizes asks the daemon for every volume's size via `docker system df -v`.
+// It's slow on big hosts, so it is only used for volumes we couldn't walk.
+func (d *DockerProvider) dfVolumeSizes(ctx context.Context) (map[string]int64, error) {
+	output, err := exec.CommandContext(ctx, "docker", "system", "df", "-v", "--format", "{{json .}}").Output()
+	if err != nil {
+		return nil, cliError("failed to read volume sizes", err, nil)
+	}
+	var df struct {
+		Volumes []struct {
+			Name string `json:"Name"`
+			Size string `json:"Size"`
+		} `json:"Volumes"`
+	}
+	if err := json.Unmarshal(output, &df); err != nil {
+		return nil, fmt.Errorf("failed to parse system df: %w", err)
+	}
+	sizes := make(map[string]int64, len(df.Volumes))
+	for _, v := range df.Volumes {
+		if v.Size != "" && v.Size != "N/A" {
+			sizes[v.Name] = parseDockerSize(v.Size)
+		}
+	}
+	return sizes, nil
+}
+
+// measure sizes v with the measurer registered for its kind, reusing the
+// cached size when v's local mountpoint's mtime is unchanged. It reports
+// false when no measurer could size it and the daemon has to be asked.
+//
+// A cached size on an attached volume is flagged SizeStale, since a running
+// container may have written below the top level without touching it.
+func (d *DockerProvider) measure(ctx context.Context, v *domain.Volume, local bool, now time.Time) bool {
+	m, ok := measurerFor(*v)
+	if !ok {
+		return false
+	}
+	var info os.FileInfo
+	if local && v.Mountpoint != "" {
+		info, _ = os.Stat(v.Mountpoint)
+	}
+	if info != nil {
+		if size, ok := d.sizes.lookup(v.Name, info.ModTime(), now); ok {
+			v.SizeBytes = size
+			v.SizeStale = len(v.Attached) > 0
+			return true
+		}
+	}
+	size, err := m.Measure(ctx, *v, local)
+	if err != nil {
+		return false
+	}
+	if info != nil {
+		d.sizes.store(v.Name, sizeEntry{mtime: info.ModTime(), size: size, measured: now})
+	}
+	v.SizeBytes = size
+	return true
+}
@@ -0,0 +1,81 @@
+package dockercli
+
+import (
+	"context"
+	"dockwatch/internal/domain"
+	"encoding/json"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ContainerStats samples resource usage of all running containers once
+func (d *DockerProvider) ContainerStats(ctx context.Context) ([]domain.ContainerStats, error) {
+	cmd := exec.CommandContext(ctx, "docker", "stats", "--no-stream", "--format", "{{json .}}")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, cliError("failed to read container stats", err, nil)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	var stats []domain.ContainerStats
+
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		var info struct {
+			Name     string `json:"Name"`
+			CPUPerc  string `json:"CPUPerc"`
+			MemUsage string `json:"MemUsage"`
+			NetIO    string `json:"NetIO"`
+			BlockIO  string `json:"BlockIO"`
+			PIDs     string `json:"PIDs"`
+		}
+
+		if err := json.Unmarshal([]byte(line), &info); err != nil {
+			continue
+		}
+
+		s := domain.ContainerStats{Name: info.Name}
+		s.CPUPercent, _ = strconv.ParseFloat(strings.TrimSuffix(info.CPUPerc, "%"), 64)
+		s.MemUsage, s.MemLimit = parseIOPair(info.MemUsage)
+		s.NetRx, s.NetTx = parseIOPair(info.NetIO)
+		s.BlockRead, s.BlockWrite = parseIOPair(info.BlockIO)
+		s.PIDs, _ = strconv.Atoi(info.PIDs)
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
+
+// parseIOPair parses docker's "used / total" columns, e.g. "1.2MB / 3kB"
+func parseIOPair(s string) (int64, int64) {
+	a, b, _ := strings.Cut(s, "/")
+	return parseDockerSize(a), parseDockerSize(b)
+}
+
+// parseDockerSize understands both the decimal (kB, MB) units docker uses for
+// IO and the binary ones (KiB, MiB) it uses for memory. Unparseable values
+// yield 0.
+func parseDockerSize(s string) int64 {
+	s = strings.TrimSpace(s)
+	units := []struct {
+		suffix string
+		mult   float64
+	}{
+		{"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10},
+		{"TB", 1e12}, {"GB", 1e9}, {"MB", 1e6}, {"kB", 1e3}, {"B", 1},
+	}
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(s, u.suffix), 64)
+			if err != nil {
+				return 0
+			}
+			return int64(n * u.mult)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+package dockercli
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// ErrNotSwarmManager means the daemon can't list swarm objects: swarm mode
+// is off, or this node is a worker.
+var ErrNotSwarmManager = errors.New("the daemon is not a swarm manager")
+
+// SwarmObjects lists the swarm's secrets and configs with the services
+// referencing each, secrets first, then by name.
+func SwarmObjects(ctx context.Context) ([]domain.SwarmObject, error) {
+	// A secret is only unreferenced if the services could be read
+	specs, err := inspectServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []domain.SwarmObject
+	for _, kind := range []string{"secret", "config"} {
+		found, err := inspectSwarmObjects(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, found...)
+	}
+	byID := map[string][]string{}
+	for _, s := range specs {
+		cs := s.Spec.TaskTemplate.ContainerSpec
+		for _, sec := range cs.Secrets {
+			byID[sec.SecretID] = append(byID[sec.SecretID], s.Spec.Name)
+		}
+		for _, cfg := range cs.Configs {
+			byID[cfg.ConfigID] = append(byID[cfg.ConfigID], s.Spec.Name)
+		}
+	}
+	for i := range objs {
+		objs[i].Services = byID[objs[i].ID]
+	}
+	slices.SortFunc(objs, func(a, b domain.SwarmObject) int {
+		return cmp.Or(-cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Name, b.Name))
+	})
+	return objs, nil
+}
+
+// inspectSwarmObjects lists and inspects the secrets or configs.
+func inspectSwarmObjects(ctx context.Context, kind string) ([]domain.SwarmObject, error) {
+	out, err := exec.CommandContext(ctx, "docker", kind, "ls", "-q").Output()
+	if err != nil {
+		return nil, cliError("docker "+kind+" ls failed", err, nil)
+	}
+	ids := strings.Fields(string(out))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	out, err = exec.CommandContext(ctx, "docker", append([]string{kind, "inspect"}, ids...)...).Output()
+	if err != nil {
+		return nil, cliError("docker "+kind+" inspect failed", err, nil)
+	}
+	var infos []struct {
+		ID        string `json:"ID"`
+		CreatedAt string `json:"CreatedAt"`
+		UpdatedAt string `json:"UpdatedAt"`
+		Spec      struct {
+			Name   string            `json:"Name"`
+			Labels map[string]string `json:"Labels"`
+		} `json:"Spec"`
+	}
+	if err := json.Unmarshal(out, &infos); err != nil {
+		return nil, err
+	}
+	objs := make([]domain.SwarmObject, 0, len(infos))
+	for _, info := range infos {
+		o := domain.SwarmObject{Kind: kind, ID: info.ID, Name: info.Spec.Name, Labels: info.Spec.Labels}
+		o.CreatedAt, _ = time.Parse(time.RFC3339Nano, info.CreatedAt)
+		o.UpdatedAt, _ = time.Parse(time.RFC3339Nano, info.UpdatedAt)
+		objs = append(objs, o)
+	}
+	return objs, nil
+}
+
+// RemoveSwarmObject removes a secret or config. The daemon refuses while a
+// service references it.
+func RemoveSwarmObject(ctx context.Context, o domain.SwarmObject) error {
+	if output, err := exec.CommandContext(ctx, "docker", o.Kind, "rm", o.ID).CombinedOutput(); err != nil {
+		return cliError("remove "+o.Kind+" "+o.Name, err, output)
+	}
+	return nil
+}
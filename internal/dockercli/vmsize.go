@@ -0,0 +1,88 @@
+package dockercli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// vmBatch is how many volumes one helper container measures.
+const vmBatch = 32
+
+// inVM reports whether v lives in a VM the local daemon runs in, as on
+// Docker Desktop: the daemon is local, yet its local-driver mountpoint
+// doesn't exist on this machine.
+func inVM(v domain.Volume, local bool) bool {
+	if !local || v.Mountpoint == "" || VolumeKind(v) != "local" {
+		return false
+	}
+	_, err := os.Stat(v.Mountpoint)
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// vmSizes measures volumes from inside the daemon's VM by mounting them,
+// read-only and a batch at a time, into helper containers that add up their
+// files' apparent sizes like walkSize does. Sizes are cached for sizeTTL,
+// there being no mountpoint here to tell changes by. Volumes that fail are
+// left out of the result.
+func (d *DockerProvider) vmSizes(ctx context.Context, names []string, now time.Time) map[string]int64 {
+	sizes := map[string]int64{}
+	var todo []string
+	for _, name := range names {
+		if size, ok := d.sizes.lookup(name, time.Time{}, now); ok {
+			sizes[name] = size
+		} else {
+			todo = append(todo, name)
+		}
+	}
+	for len(todo) > 0 && ctx.Err() == nil {
+		batch := todo[:min(vmBatch, len(todo))]
+		todo = todo[len(batch):]
+		measured, err := d.measureInHelper(ctx, batch)
+		if err != nil {
+			continue
+		}
+		for name, size := range measured {
+			sizes[name] = size
+			d.sizes.store(name, sizeEntry{size: size, measured: now})
+		}
+	}
+	return sizes
+}
+
+// measureInHelper sizes the named volumes in one helper container.
+func (d *DockerProvider) measureInHelper(ctx context.Context, names []string) (map[string]int64, error) {
+	var opts []string
+	for i, name := range names {
+		opts = append(opts, "-v", fmt.Sprintf("%s:/v/%d:ro", name, i))
+	}
+	script := `for d in /v/*; do printf '%s ' "${d#/v/}"; find "$d" -type f -exec stat -c %s {} + | awk '{s+=$1} END {print s+0}'; done`
+	cmd := exec.CommandContext(ctx, "docker", d.helperRun(opts, "sh", "-c", script)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, cliError("failed to measure volumes via helper container", err, stderr.Bytes())
+	}
+	sizes := map[string]int64{}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		idx, size, ok := strings.Cut(sc.Text(), " ")
+		i, err1 := strconv.Atoi(idx)
+		n, err2 := strconv.ParseInt(size, 10, 64)
+		if ok && err1 == nil && err2 == nil && i >= 0 && i < len(names) {
+			sizes[names[i]] = n
+		}
+	}
+	return sizes, nil
+}
@@ -0,0 +1,43 @@
+package dockercli
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// pseudoFilesystems are the statfs magic numbers of kernel filesystems with
+// nothing on disk: /proc alone reports a 128 TiB kcore. autofs is here too,
+// since walking into it would trigger its mounts.
+var pseudoFilesystems = map[int64]bool{
+	0x9fa0:     true, // proc
+	0x62656572: true, // sysfs
+	0x1cd1:     true, // devpts
+	0x27e0eb:   true, // cgroup
+	0x63677270: true, // cgroup2
+	0x64626720: true, // debugfs
+	0x74726163: true, // tracefs
+	0x73636673: true, // securityfs
+	0xcafe4a11: true, // bpf
+	0x6165676c: true, // pstore
+	0x62656570: true, // configfs
+	0x65735543: true, // fusectl
+	0x19800202: true, // mqueue
+	0x42494e4d: true, // binfmt_misc
+	0x6e736673: true, // nsfs
+	0x0187:     true, // autofs
+}
+
+// pseudoFS reports whether path is on one of the pseudoFilesystems.
+func pseudoFS(path string) bool {
+	var st syscall.Statfs_t
+	return syscall.Statfs(path, &st) == nil && pseudoFilesystems[int64(st.Type)]
+}
+
+// device returns the device holding the file info describes.
+func device(info fs.FileInfo) (uint64, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(st.Dev), true
+}
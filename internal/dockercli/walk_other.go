@@ -0,0 +1,11 @@
+//go:build !linux
+
+package dockercli
+
+import "io/fs"
+
+// Elsewhere walks can't tell filesystems apart and cross mount points.
+
+func pseudoFS(string) bool { return false }
+
+func device(fs.FileInfo) (uint64, bool) { return 0, false }
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package doctor
+
+import "errors"
+
+func diskFree(string) (free, total int64, err error) {
+	return 0, 0, errors.New("not supported on this platform")
+}
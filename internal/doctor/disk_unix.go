@@ -0,0 +1,15 @@
+//go:build unix
+
+package doctor
+
+import "syscall"
+
+// diskFree returns the bytes available to unprivileged users and the total
+// size of the filesystem holding path.
+func diskFree(path string) (free, total int64, err error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, err
+	}
+	return int64(st.Bavail) * int64(st.Bsize), int64(st.Blocks) * int64(st.Bsize), nil
+}
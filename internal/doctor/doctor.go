@@ -0,0 +1,280 @@
+// Package doctor diagnoses why dockwatch can't reach the Docker daemon and
+// suggests how to fix it.
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/domain"
+)
+
+// Status is the outcome of a single check.
+type Status string
+
+const (
+	OK   Status = "ok"
+	Warn Status = "warn"
+	Fail Status = "fail"
+	Skip Status = "skip" // an earlier failure made the check meaningless
+)
+
+// Check is one line of the diagnostics checklist.
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"` // what to do about a warn or fail
+}
+
+// Report is the result of a diagnostics run.
+type Report struct {
+	Checks []Check `json:"checks"`
+}
+
+// Failed reports whether any check failed outright.
+func (r Report) Failed() bool {
+	for _, c := range r.Checks {
+		if c.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
+
+// MinAPIVersion is the oldest Engine API dockwatch is tested against
+// (Docker 20.10).
+const MinAPIVersion = "1.41"
+
+const (
+	lowDiskFraction = 0.10
+	minFreeBytes    = 1 << 30
+	checkTimeout    = 10 * time.Second
+)
+
+// Run checks, in order, the docker binary, the selected context, the
+// daemon socket, the API version and the free space under Docker's data
+// root. Checks that depend on a failed one are skipped.
+func Run(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	var r Report
+	add := func(c Check) bool {
+		r.Checks = append(r.Checks, c)
+		return c.Status != Fail && c.Status != Skip
+	}
+	skip := func(names ...string) {
+		for _, name := range names {
+			r.Checks = append(r.Checks, Check{Name: name, Status: Skip})
+		}
+	}
+
+	if !add(checkBinary()) {
+		skip("docker context", "daemon socket", "API version", "disk space")
+		return r
+	}
+	ctxCheck, host := checkContext(ctx)
+	if !add(ctxCheck) {
+		skip("daemon socket", "API version", "disk space")
+		return r
+	}
+	if !add(checkSocket(ctx, host)) {
+		skip("API version", "disk space")
+		return r
+	}
+	if !add(checkAPIVersion(ctx)) {
+		skip("disk space")
+		return r
+	}
+	add(checkDisk(ctx))
+	return r
+}
+
+func checkBinary() Check {
+	c := Check{Name: "docker binary"}
+	path, err := exec.LookPath("docker")
+	if err != nil {
+		c.Status = Fail
+		c.Detail = "docker not found in PATH"
+		c.Hint = "install the Docker CLI (https://docs.docker.com/get-docker/) or add it to PATH"
+		return c
+	}
+	c.Status, c.Detail = OK, path
+	return c
+}
+
+// checkContext verifies the selected docker context exists and returns the
+// daemon endpoint it points at.
+func checkContext(ctx context.Context) (Check, string) {
+	c := Check{Name: "docker context"}
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		c.Status, c.Detail = OK, "DOCKER_HOST="+host
+		if ep, ok := dockercli.DiscoverSocket(); ok && ep.Host == host {
+			c.Detail = fmt.Sprintf("%s (found %s; the default socket is missing)", host, ep.Name)
+		}
+		return c, host
+	}
+	name, err := docker(ctx, "context", "show")
+	if err != nil {
+		// Older CLIs lack contexts entirely; assume the default socket
+		c.Status, c.Detail = OK, "default"
+		return c, ""
+	}
+	host, err := docker(ctx, "context", "inspect", name, "--format", "{{.Endpoints.docker.Host}}")
+	if err != nil {
+		c.Status = Fail
+		c.Detail = fmt.Sprintf("context %q is not usable: %v", name, err)
+		c.Hint = "switch to a valid context with `docker context use default`, or unset DOCKER_CONTEXT"
+		return c, ""
+	}
+	c.Status, c.Detail = OK, fmt.Sprintf("%s (%s)", name, host)
+	return c, host
+}
+
+// checkSocket dials the daemon endpoint, telling a missing socket, a
+// stopped daemon and missing permissions apart.
+func checkSocket(ctx context.Context, host string) Check {
+	c := Check{Name: "daemon socket"}
+	if host == "" {
+		host = "unix:///var/run/docker.sock"
+	}
+	network, addr, ok := strings.Cut(host, "://")
+	if !ok || (network != "unix" && network != "tcp") {
+		c.Status, c.Detail = OK, host+" (not dialled directly; checked through the API below)"
+		return c
+	}
+	if network == "unix" {
+		if _, err := os.Stat(addr); err != nil {
+			c.Status = Fail
+			c.Detail = fmt.Sprintf("%s does not exist", addr)
+			c.Hint = "start Docker (`sudo systemctl start docker`, or open Docker Desktop, Colima or Rancher Desktop)"
+			return c
+		}
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, addr)
+	switch {
+	case errors.Is(err, syscall.EACCES) || errors.Is(err, os.ErrPermission):
+		c.Status = Fail
+		c.Detail = fmt.Sprintf("permission denied on %s", addr)
+		c.Hint = "add your user to the docker group (`sudo usermod -aG docker $USER`) and log in again"
+	case err != nil:
+		c.Status = Fail
+		c.Detail = err.Error()
+		c.Hint = "the daemon isn't accepting connections; start it (`sudo systemctl start docker`) or check DOCKER_HOST"
+	default:
+		conn.Close()
+		c.Status, c.Detail = OK, host
+	}
+	return c
+}
+
+func checkAPIVersion(ctx context.Context) Check {
+	c := Check{Name: "API version"}
+	out, err := docker(ctx, "version", "--format", "{{.Server.APIVersion}}")
+	if err != nil {
+		c.Status = Fail
+		c.Detail = err.Error()
+		c.Hint = "the daemon didn't answer `docker version`; check that it is running and healthy"
+		return c
+	}
+	c.Detail = out
+	if compareVersions(out, MinAPIVersion) < 0 {
+		c.Status = Warn
+		c.Hint = fmt.Sprintf("API %s is older than %s; upgrade Docker Engine to 20.10 or newer", out, MinAPIVersion)
+		return c
+	}
+	c.Status = OK
+	return c
+}
+
+// DiskUsage is the space on the filesystem holding Docker's data root.
+type DiskUsage struct {
+	Root        string
+	Free, Total int64
+}
+
+// ErrRemoteRoot is returned by DataRoot when the daemon's data root is not
+// on this machine.
+var ErrRemoteRoot = errors.New("data root is not on this machine")
+
+// DataRoot measures the filesystem holding Docker's data root. It only
+// works for a local daemon whose root this process can see.
+func DataRoot(ctx context.Context) (DiskUsage, error) {
+	root, err := docker(ctx, "info", "--format", "{{.DockerRootDir}}")
+	if err != nil || !dockercli.IsLocalDaemon() {
+		return DiskUsage{}, ErrRemoteRoot
+	}
+	free, total, err := diskFree(root)
+	if err != nil {
+		return DiskUsage{Root: root}, fmt.Errorf("%s: %w", root, err)
+	}
+	return DiskUsage{Root: root, Free: free, Total: total}, nil
+}
+
+// checkDisk looks at the free space under the data root.
+func checkDisk(ctx context.Context) Check {
+	c := Check{Name: "disk space"}
+	du, err := DataRoot(ctx)
+	if err != nil {
+		c.Status, c.Detail = Skip, err.Error()
+		return c
+	}
+	c.Detail = fmt.Sprintf("%s free of %s on %s", domain.HumanSize(du.Free), domain.HumanSize(du.Total), du.Root)
+	switch {
+	case du.Free < minFreeBytes:
+		c.Status = Fail
+		c.Hint = "the disk is almost full; reclaim space with dockwatch's prune plan or `docker system prune`"
+	case du.Total > 0 && float64(du.Free)/float64(du.Total) < lowDiskFraction:
+		c.Status = Warn
+		c.Hint = "less than 10% free; look for orphaned volumes and unused images"
+	default:
+		c.Status = OK
+	}
+	return c
+}
+
+// docker runs a docker CLI command and returns its trimmed stdout, or an
+// error carrying its stderr.
+func docker(ctx context.Context, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// compareVersions compares dotted numeric versions such as "1.41".
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "errors"
+
+// Kinds of provider errors. Providers wrap them, so callers branch with
+// errors.Is instead of matching daemon messages.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrVolumeInUse       = errors.New("volume is in use")
+	ErrNetworkInUse      = errors.New("network has connected containers")
+	ErrPermission        = errors.New("permission denied")
+	ErrDaemonUnavailable = errors.New("docker daemon unavailable")
+)
+
+// KindError is an error of one of the kinds above (or none) with its own
+// message; errors.Is sees both the kind and the underlying cause.
+type KindError struct {
+	Kind error // nil when unclassified
+	Msg  string
+	Err  error // underlying cause, if any
+}
+
+func (e *KindError) Error() string { return e.Msg }
+
+func (e *KindError) Unwrap() []error {
+	var errs []error
+	for _, err := range []error{e.Kind, e.Err} {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// ErrorKind returns which kind err is, or nil for an unclassified error.
+func ErrorKind(err error) error {
+	for _, kind := range []error{ErrNotFound, ErrVolumeInUse, ErrNetworkInUse, ErrPermission, ErrDaemonUnavailable} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
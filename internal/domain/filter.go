@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// VolumeFilter selects volumes the way `docker volume ls --filter` does:
+// values given for the same key are ORed, different keys are ANDed.
+//
+//	name=web        name contains "web"; with *, ? or [ it is a glob instead
+//	driver=local    exact driver
+//	label=key       has the label; label=key=value requires the value too
+//	dangling=true   not used by any container
+//	project=ci      compose project, glob allowed (dockwatch only)
+//	tag=migrate     has the dockwatch-local tag (dockwatch only)
+type VolumeFilter map[string][]string
+
+var volumeFilterKeys = map[string]bool{"name": true, "driver": true, "label": true, "dangling": true, "project": true, "tag": true}
+
+// ParseVolumeFilter parses "key=value" arguments into a filter.
+func ParseVolumeFilter(args []string) (VolumeFilter, error) {
+	f := VolumeFilter{}
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || !volumeFilterKeys[key] {
+			return nil, fmt.Errorf("invalid filter %q (want name=, driver=, label=, dangling=, project= or tag=)", arg)
+		}
+		if key == "dangling" {
+			if _, err := strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("invalid filter %q: dangling wants true or false", arg)
+			}
+		}
+		if (key == "name" || key == "project") && isGlob(value) {
+			if _, err := path.Match(value, ""); err != nil {
+				return nil, fmt.Errorf("invalid filter %q: %v", arg, err)
+			}
+		}
+		f[key] = append(f[key], value)
+	}
+	return f, nil
+}
+
+// Args returns the filter as "key=value" strings, the inverse of
+// ParseVolumeFilter.
+func (f VolumeFilter) Args() []string {
+	var args []string
+	for _, key := range []string{"name", "driver", "label", "dangling", "project", "tag"} {
+		for _, v := range f[key] {
+			args = append(args, key+"="+v)
+		}
+	}
+	return args
+}
+
+// Native returns the "key=value" pairs the daemon can evaluate itself.
+// Name globs, project and tag are dockwatch extensions, so name values only
+// go to the daemon when none of them is a glob.
+func (f VolumeFilter) Native() []string {
+	var args []string
+	for _, arg := range f.Without("tag").Args() {
+		if strings.HasPrefix(arg, "project=") || strings.HasPrefix(arg, "name=") && f.hasGlob() {
+			continue
+		}
+		args = append(args, arg)
+	}
+	return args
+}
+
+// Without returns a copy of f without key, e.g. "tag" for a provider that
+// can't see local annotations.
+func (f VolumeFilter) Without(key string) VolumeFilter {
+	out := make(VolumeFilter, len(f))
+	for k, v := range f {
+		if k != key {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func (f VolumeFilter) hasGlob() bool {
+	for _, v := range f["name"] {
+		if isGlob(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether v passes every key of the filter. dangling relies
+// on Orphan, so it only makes sense for inspected volumes.
+func (f VolumeFilter) Match(v Volume) bool {
+	for key, values := range f {
+		if !matchAny(values, func(value string) bool { return matchVolume(key, value, v) }) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchVolume(key, value string, v Volume) bool {
+	switch key {
+	case "name":
+		if isGlob(value) {
+			ok, _ := path.Match(value, v.Name)
+			return ok
+		}
+		return strings.Contains(v.Name, value)
+	case "driver":
+		return v.Driver == value
+	case "label":
+		k, want, hasValue := strings.Cut(value, "=")
+		got, ok := v.Labels[k]
+		return ok && (!hasValue || got == want)
+	case "dangling":
+		b, _ := strconv.ParseBool(value)
+		return v.Orphan() == b
+	case "project":
+		ok, _ := path.Match(value, v.Project)
+		return ok
+	case "tag":
+		return slices.Contains(v.Tags, value)
+	}
+	return false
+}
+
+func matchAny(values []string, match func(string) bool) bool {
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func isGlob(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
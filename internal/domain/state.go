@@ -0,0 +1,67 @@
+package domain
+
+import "fmt"
+
+// OrphanState says what uses a volume. The zero value is OrphanUnknown, so
+// a volume nobody has inspected yet is never taken for an orphan.
+type OrphanState int
+
+const (
+	OrphanUnknown OrphanState = iota // not inspected, or inspect failed
+	InUseRunning                     // mounted by a running container
+	InUseStopped                     // mounted only by stopped containers or a service spec
+	Orphaned                         // nothing mounts it
+	Protected                        // nothing mounts it, but a guard keeps it
+)
+
+var stateNames = [...]string{"unknown", "in-use-running", "in-use-stopped", "orphan", "protected"}
+
+// StateOf is the state of a volume mounted by attached users, running
+// when one of them is a running container.
+func StateOf(attached int, running bool) OrphanState {
+	switch {
+	case running:
+		return InUseRunning
+	case attached > 0:
+		return InUseStopped
+	}
+	return Orphaned
+}
+
+func (s OrphanState) String() string {
+	if s < 0 || int(s) >= len(stateNames) {
+		return fmt.Sprintf("OrphanState(%d)", int(s))
+	}
+	return stateNames[s]
+}
+
+// Label is the short status shown in tables, e.g. "ORPHAN".
+func (s OrphanState) Label() string {
+	switch s {
+	case InUseRunning:
+		return "IN USE"
+	case InUseStopped:
+		return "STOPPED"
+	case Orphaned:
+		return "ORPHAN"
+	case Protected:
+		return "PROTECTED"
+	}
+	return "UNKNOWN"
+}
+
+// MarshalText encodes the state by name, so JSON carries "orphan" rather
+// than a number that would shift if states were added.
+func (s OrphanState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *OrphanState) UnmarshalText(b []byte) error {
+	for i, name := range stateNames {
+		if string(b) == name {
+			*s = OrphanState(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown volume state %q", b)
+}
@@ -1,37 +1,404 @@
 package domain
 
 import (
-	"fmt"
+	"strings"
 	"time"
 )
 
 // Volume represents a Docker volume (or mock) with basic metadata.
 type Volume struct {
-	Name      string
-	Driver    string
-	SizeBytes int64    // may be -1 if unknown
-	Attached  []string // container names
-	Project   string   // from labels (compose)
-	Orphan    bool
-	LastSeen  time.Time // optional
+	Name         string
+	Driver       string
+	SizeBytes    int64    // may be -1 if unknown
+	Attached     []string // container names
+	Project      string   // from labels (compose)
+	State        OrphanState
+	LastSeen     time.Time // optional
+	CreatedAt    time.Time // zero if unknown
+	Labels       map[string]string
+	SizeStale    bool              // size came from cache and may lag recent writes
+	Mountpoint   string            // host path of the volume data (inside the VM on Docker Desktop)
+	Options      map[string]string // driver options, e.g. type=nfs for a local-driver share
+	DriverStatus map[string]string // driver-reported status fields, if any
+	InUse        bool              // mounted by a running container
+	LastUsed     time.Time         // when a container last stopped using it; zero if unknown
+	Note         string            // dockwatch-local annotation, not a Docker label
+	Tags         []string          // dockwatch-local tags, not Docker labels
+}
+
+// Orphan reports whether nothing uses the volume and nothing protects it.
+func (v Volume) Orphan() bool {
+	return v.State == Orphaned
+}
+
+// PluginDriver reports whether a volume plugin manages the volume. Its data
+// is wherever the plugin keeps it, outside the volumes directory and what
+// `docker system df` counts.
+func (v Volume) PluginDriver() bool {
+	return v.Driver != "" && v.Driver != "local"
 }
 
 func (v Volume) SizeHuman() string {
-	b := float64(v.SizeBytes)
-	if v.SizeBytes < 0 {
+	return HumanSize(v.SizeBytes)
+}
+
+// AgeHuman is the time since the volume was created, e.g. "12d", or "?"
+// when the driver doesn't report a creation time.
+func (v Volume) AgeHuman(now time.Time) string {
+	if v.CreatedAt.IsZero() {
 		return "?"
 	}
-	const kb = 1024
-	const mb = kb * 1024
-	const gb = mb * 1024
+	return HumanAge(now.Sub(v.CreatedAt))
+}
+
+// LastUsedHuman is "in use", the time since a container last used the
+// volume (e.g. "3d"), or "-" when history doesn't say.
+func (v Volume) LastUsedHuman(now time.Time) string {
+	switch {
+	case v.InUse:
+		return "in use"
+	case v.LastUsed.IsZero():
+		return "-"
+	}
+	return HumanAge(now.Sub(v.LastUsed))
+}
+
+// IdleSince is when the volume was last used, falling back to its creation
+// for volumes no known container ever used. It is zero while in use or when
+// neither is known.
+func (v Volume) IdleSince() time.Time {
+	switch {
+	case v.InUse:
+		return time.Time{}
+	case !v.LastUsed.IsZero():
+		return v.LastUsed
+	}
+	return v.CreatedAt
+}
+
+// Container represents a Docker container as listed by the daemon.
+type Container struct {
+	ID        string
+	Name      string
+	Image     string
+	State     string // running, exited, created, ...
+	Status    string // human status, e.g. "Up 3 hours"
+	CreatedAt time.Time
+
+	ExitCode   int       // meaningful once exited
+	StartedAt  time.Time // last (re)start; zero if never started or unknown
+	FinishedAt time.Time // zero while running or if unknown
+
+	Health        string // healthy, unhealthy or starting; "" without a healthcheck
+	HealthOutput  string // output of the last healthcheck
+	RestartCount  int    // restarts by the daemon under its restart policy
+	RestartPolicy string // no, always, on-failure, unless-stopped
+	SizeRw        int64  // writable layer bytes, -1 if unknown
+	LogDriver     string // json-file, local, journald, ...
+	LogPath       string // json-file log on the daemon's host
+	LogSize       int64  // json-file log bytes, rotated files included; -1 if unknown
+	ShmSize       int64  // size of its own /dev/shm; 0 when shared with the host or another container
+	Mounts        []Mount
+	ImageID       string   // full image ID the container was created from
+	Networks      []string // names of attached networks
+	Labels        map[string]string
+}
+
+// Project returns the compose project the container belongs to, if any.
+func (c Container) Project() string {
+	return c.Labels["com.docker.compose.project"]
+}
+
+// ProjectDir returns the directory compose ran in to create the container,
+// if it recorded one.
+func (c Container) ProjectDir() string {
+	return c.Labels["com.docker.compose.project.working_dir"]
+}
+
+// ProjectFiles returns the compose files the container was created from,
+// if compose recorded them.
+func (c Container) ProjectFiles() []string {
+	files := c.Labels["com.docker.compose.project.config_files"]
+	if files == "" {
+		return nil
+	}
+	return strings.Split(files, ",")
+}
+
+// Mount is a filesystem mount of a container.
+type Mount struct {
+	Type        string // volume, bind, tmpfs, ...
+	Name        string // volume name for volume mounts
+	Source      string
+	Destination string
+}
+
+// MemMount is memory-backed storage in a running container: its /dev/shm
+// or a tmpfs mount. What is written there counts against RAM, not disk.
+type MemMount struct {
+	Container   string
+	Destination string
+	SizeBytes   int64 // the mount's limit; for an unlimited tmpfs, half the RAM
+	UsedBytes   int64 // -1 if it couldn't be measured
+}
+
+// BindMount is a host path bind-mounted into containers. Docker doesn't own
+// the data, so no prune ever frees it.
+type BindMount struct {
+	Source     string
+	Containers []string // names of the containers mounting it
+	SizeBytes  int64    // -1 if it couldn't be measured
+	Skipped    int      // unreadable entries left out of SizeBytes
+}
+
+// RootUsage breaks Docker's data root (usually /var/lib/docker) down by
+// top-level directory, next to what `docker system df` accounts for.
+type RootUsage struct {
+	Root     string
+	Dirs     []RootDir
+	Reported map[string]int64 // system df rows (Images, Containers, Local Volumes, Build Cache); nil if df failed
+}
+
+// RootDir is one top-level directory of the data root.
+type RootDir struct {
+	Name      string // e.g. overlay2
+	Subsystem string // what it holds, empty when unknown
+	SizeBytes int64  // -1 if it couldn't be read
+	Skipped   int    // unreadable entries left out of SizeBytes
+}
+
+// Plugin is an installed managed Docker plugin.
+type Plugin struct {
+	ID        string
+	Name      string // e.g. vieux/sshfs:latest, also the driver name volumes give
+	Enabled   bool
+	Types     []string // interfaces it implements, e.g. docker.volumedriver/1.0
+	SizeBytes int64    // its rootfs under the data root; -1 if it couldn't be read
+}
+
+// VolumeDriver reports whether the plugin provides volumes.
+func (p Plugin) VolumeDriver() bool {
+	for _, t := range p.Types {
+		if strings.HasPrefix(t, "docker.volumedriver/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Drives reports whether the plugin is the driver of v. Volumes may name
+// it without the :latest tag it was installed with.
+func (p Plugin) Drives(v Volume) bool {
+	return v.Driver == p.Name || v.Driver+":latest" == p.Name
+}
+
+// AnonymousVolumes returns the names of volumes docker generated for this
+// container (64 hex chars), which `docker rm -v` removes along with it.
+func (c Container) AnonymousVolumes() []string {
+	var names []string
+	for _, m := range c.Mounts {
+		if m.Type == "volume" && IsAnonymousVolume(m.Name) {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
+
+// IsAnonymousVolume reports whether a volume name was generated by docker.
+func IsAnonymousVolume(name string) bool {
+	if len(name) != 64 {
+		return false
+	}
+	for _, r := range name {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Exited reports whether the container has stopped running.
+func (c Container) Exited() bool {
+	return c.State == "exited" || c.State == "dead"
+}
+
+// Running reports whether the container is currently running.
+func (c Container) Running() bool {
+	return c.State == "running"
+}
+
+// Crash loops: a container the daemon has restarted this many times that
+// hasn't stayed up for crashLoopUptime since is counted as looping.
+const (
+	crashLoopRestarts = 3
+	crashLoopUptime   = 10 * time.Minute
+)
+
+// CrashLooping reports whether the daemon keeps restarting the container:
+// it is between restarts now, or was restarted repeatedly and came up again
+// only recently.
+func (c Container) CrashLooping(now time.Time) bool {
+	if c.State == "restarting" {
+		return true
+	}
+	return c.Running() && c.RestartCount >= crashLoopRestarts && !c.StartedAt.IsZero() && now.Sub(c.StartedAt) < crashLoopUptime
+}
+
+// ContainerStats is a point-in-time resource usage sample for a container.
+type ContainerStats struct {
+	Name       string
+	CPUPercent float64
+	MemUsage   int64
+	MemLimit   int64
+	NetRx      int64
+	NetTx      int64
+	BlockRead  int64
+	BlockWrite int64
+	PIDs       int
+}
+
+// Image represents a local Docker image tag. An image ID tagged several
+// times appears once per tag.
+type Image struct {
+	ID         string
+	Repository string // "<none>" for dangling images
+	Tag        string
+	SizeBytes  int64
+	SharedSize int64 // bytes in layers shared with other images, -1 if unknown
+	UniqueSize int64 // bytes only this image uses, -1 if unknown
+	Containers int   // containers created from the image
+	CreatedAt  time.Time
+	Layers     []string // RootFS diff IDs
+	Labels     map[string]string
+	// RepoDigests are the "repo@sha256:…" manifest digests the image was
+	// pulled or pushed as; empty for images built locally.
+	RepoDigests []string
+}
+
+// Ref returns "repo:tag", or the image ID for untagged images.
+func (i Image) Ref() string {
+	if i.Dangling() {
+		return i.ID
+	}
+	return i.Repository + ":" + i.Tag
+}
+
+// Digest returns the manifest digest the image has in its repository, or
+// "" when it was never pulled from or pushed to it.
+func (i Image) Digest() string {
+	for _, rd := range i.RepoDigests {
+		repo, digest, ok := strings.Cut(rd, "@")
+		if ok && shortRepo(repo) == shortRepo(i.Repository) {
+			return digest
+		}
+	}
+	return ""
+}
+
+// shortRepo drops the Docker Hub defaults the daemon may or may not spell out.
+func shortRepo(repo string) string {
+	repo = strings.TrimPrefix(repo, "docker.io/")
+	return strings.TrimPrefix(repo, "library/")
+}
+
+// Dangling reports whether the image has no repository or tag.
+func (i Image) Dangling() bool {
+	return i.Repository == "<none>" || i.Repository == ""
+}
+
+// Unused reports whether no container references the image and it is older
+// than minAge. Images with an unknown creation date are never unused.
+func (i Image) Unused(now time.Time, minAge time.Duration) bool {
+	return i.Containers == 0 && !i.CreatedAt.IsZero() && now.Sub(i.CreatedAt) >= minAge
+}
+
+// PullProgress aggregates the progress of an image pull across layers.
+type PullProgress struct {
+	Current    int64 // bytes downloaded so far
+	Total      int64 // bytes expected, over layers whose size is known
+	LayersDone int
+	Layers     int
+	Status     string // latest status line from the daemon
+}
+
+// Fraction returns progress in [0,1], preferring bytes over layer counts.
+func (p PullProgress) Fraction() float64 {
 	switch {
-	case b >= gb:
-		return fmt.Sprintf("%.1f GB", b/gb)
-	case b >= mb:
-		return fmt.Sprintf("%.1f MB", b/mb)
-	case b >= kb:
-		return fmt.Sprintf("%.1f KB", b/kb)
-	default:
-		return fmt.Sprintf("%d B", int64(b))
+	case p.Total > 0:
+		return min(float64(p.Current)/float64(p.Total), 1)
+	case p.Layers > 0:
+		return float64(p.LayersDone) / float64(p.Layers)
+	}
+	return 0
+}
+
+// Network represents a Docker network.
+type Network struct {
+	ID         string
+	Name       string
+	Driver     string
+	Scope      string
+	Internal   bool // no route out of the network
+	Attachable bool // standalone containers may join a swarm network
+	IPv6       bool
+	Labels     map[string]string
+	Containers []string // names of attached containers
+	CreatedAt  time.Time
+	IPAMDriver string
+	IPAM       []IPAMConfig
+}
+
+// IPAMConfig is one address pool of a network.
+type IPAMConfig struct {
+	Subnet  string
+	Gateway string
+	IPRange string // the part of Subnet containers are given addresses from
+}
+
+// Project returns the compose project the network belongs to, if any.
+func (n Network) Project() string {
+	return n.Labels["com.docker.compose.project"]
+}
+
+// Predefined reports whether the network is one the daemon creates itself
+// and won't remove: bridge, host or none.
+func (n Network) Predefined() bool {
+	switch n.Name {
+	case "bridge", "host", "none":
+		return true
 	}
+	return false
+}
+
+// ComposeDefault reports whether compose created the network as its
+// project's default one, which services join unless told otherwise.
+func (n Network) ComposeDefault() bool {
+	return n.Labels["com.docker.compose.network"] == "default"
+}
+
+// SwarmObject is a swarm secret or config. Neither takes disk space to
+// speak of, but ones no service references are clutter, and a stale secret
+// is a liability.
+type SwarmObject struct {
+	Kind      string // "secret" or "config"
+	ID        string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Labels    map[string]string
+	Services  []string // services whose spec references it
+}
+
+// Unreferenced reports whether no service uses the object.
+func (o SwarmObject) Unreferenced() bool {
+	return len(o.Services) == 0
+}
+
+// DaemonEvent is one entry of the daemon's event stream.
+type DaemonEvent struct {
+	Time       time.Time
+	Type       string // volume or container
+	Action     string // create, destroy, mount, die, ...
+	Actor      string // volume name or container ID
+	Attributes map[string]string
 }
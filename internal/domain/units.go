@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseSize parses a human size such as "512MB", "1.5GB" or "2GiB" into bytes.
+// Units are binary (1KB = 1024 B) to match SizeHuman.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(strings.ToUpper(s))
+	if s == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+	units := []struct {
+		suffix string
+		mult   float64
+	}{
+		{"TIB", 1 << 40}, {"GIB", 1 << 30}, {"MIB", 1 << 20}, {"KIB", 1 << 10},
+		{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10},
+		{"B", 1},
+	}
+	mult := 1.0
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			mult = u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(n * mult), nil
+}
+
+// ParseAge parses an age such as "14d", "2w" or "3mo". Anything else is
+// handed to time.ParseDuration, so "36h" works too.
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	const day = 24 * time.Hour
+	for _, u := range []struct {
+		suffix string
+		mult   time.Duration
+	}{{"mo", 30 * day}, {"y", 365 * day}, {"w", 7 * day}, {"d", day}} {
+		if strings.HasSuffix(s, u.suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(s, u.suffix))
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(n) * u.mult, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
+
+// HumanAge formats a duration compactly, e.g. "45m", "12d", "3mo", "2y".
+func HumanAge(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < day:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 60*day:
+		return fmt.Sprintf("%dd", int(d/day))
+	case d < 365*day:
+		return fmt.Sprintf("%dmo", int(d/(30*day)))
+	default:
+		return fmt.Sprintf("%dy", int(d/(365*day)))
+	}
+}
+
+// HumanSize formats a byte count with binary units, e.g. "1.5 GB"; negative
+// counts mean unknown and print as "?".
+func HumanSize(b int64) string {
+	if b < 0 {
+		return "?"
+	}
+	const kb = 1024
+	const mb = kb * 1024
+	const gb = mb * 1024
+	f := float64(b)
+	switch {
+	case b >= gb:
+		return fmt.Sprintf("%.1f GB", f/gb)
+	case b >= mb:
+		return fmt.Sprintf("%.1f MB", f/mb)
+	case b >= kb:
+		return fmt.Sprintf("%.1f KB", f/kb)
+	default:
+		return fmt.Sprintf("%d B", b)
+	}
+}
@@ -0,0 +1,88 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrNotEnoughHistory is returned by Forecast until the window holds
+// enough disk readings to fit a trend.
+var ErrNotEnoughHistory = errors.New("not enough history to forecast; record samples with `dockwatch history record`")
+
+// Minimum readings and time span a forecast is fitted on.
+const (
+	minForecastSamples = 3
+	minForecastSpan    = 6 * time.Hour
+)
+
+// Forecast extrapolates when the data root fills up.
+type Forecast struct {
+	Samples     int       // readings the trend was fitted on
+	At          time.Time // latest reading
+	Free, Total int64     // at the latest reading
+	PerDay      float64   // growth in bytes per day; <= 0 when usage is flat or shrinking
+}
+
+// Growing reports whether usage is growing at all.
+func (f Forecast) Growing() bool {
+	return f.PerDay > 0
+}
+
+// DaysLeft is how many days from the latest reading until the disk is
+// full, +Inf when usage isn't growing.
+func (f Forecast) DaysLeft() float64 {
+	if !f.Growing() {
+		return math.Inf(1)
+	}
+	return float64(f.Free) / f.PerDay
+}
+
+// FullAt is when the disk is expected to be full; zero when usage isn't
+// growing.
+func (f Forecast) FullAt() time.Time {
+	if !f.Growing() {
+		return time.Time{}
+	}
+	return f.At.Add(time.Duration(f.DaysLeft() * float64(24*time.Hour)))
+}
+
+// Forecast fits a least-squares line through the used space of the
+// readings taken within window before now.
+func (h *DB) Forecast(ctx context.Context, window time.Duration, now time.Time) (Forecast, error) {
+	rows, err := h.db.QueryContext(ctx, `SELECT taken_at, disk_free_bytes, disk_total_bytes FROM samples
+		WHERE disk_total_bytes IS NOT NULL AND taken_at >= ? ORDER BY taken_at`, now.Add(-window).Unix())
+	if err != nil {
+		return Forecast{}, err
+	}
+	defer rows.Close()
+
+	var f Forecast
+	var first int64
+	var sx, sy, sxx, sxy float64
+	for rows.Next() {
+		var at int64
+		if err := rows.Scan(&at, &f.Free, &f.Total); err != nil {
+			return Forecast{}, err
+		}
+		if f.Samples == 0 {
+			first = at
+		}
+		f.Samples++
+		f.At = time.Unix(at, 0)
+		// Days since the first reading keep the sums well conditioned
+		x := float64(at-first) / 86400
+		y := float64(f.Total - f.Free)
+		sx, sy, sxx, sxy = sx+x, sy+y, sxx+x*x, sxy+x*y
+	}
+	if err := rows.Err(); err != nil {
+		return Forecast{}, err
+	}
+	if f.Samples < minForecastSamples || f.At.Sub(time.Unix(first, 0)) < minForecastSpan {
+		return f, ErrNotEnoughHistory
+	}
+	n := float64(f.Samples)
+	f.PerDay = (n*sxy - sx*sy) / (n*sxx - sx*sx)
+	return f, nil
+}
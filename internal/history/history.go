@@ -0,0 +1,255 @@
+// Package history records periodic usage samples in a SQLite database with
+// a stable, documented schema, so Grafana's SQLite data source or plain SQL
+// can chart trends. Times are stored as Unix seconds.
+package history
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/domain"
+)
+
+// migrations bring the schema from version i to i+1. Columns are only ever
+// added, never renamed or repurposed, so existing queries keep working.
+var migrations = []string{`
+CREATE TABLE IF NOT EXISTS samples (
+	id               INTEGER PRIMARY KEY,
+	taken_at         INTEGER NOT NULL,
+	volumes          INTEGER NOT NULL,
+	volume_bytes     INTEGER NOT NULL,
+	orphan_volumes   INTEGER NOT NULL,
+	orphan_bytes     INTEGER NOT NULL,
+	images           INTEGER NOT NULL,
+	image_bytes      INTEGER NOT NULL,
+	containers       INTEGER NOT NULL,
+	container_bytes  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS samples_taken_at ON samples (taken_at);
+
+CREATE TABLE IF NOT EXISTS volume_usage (
+	sample_id   INTEGER NOT NULL REFERENCES samples (id) ON DELETE CASCADE,
+	taken_at    INTEGER NOT NULL,
+	name        TEXT    NOT NULL,
+	driver      TEXT    NOT NULL,
+	project     TEXT    NOT NULL,
+	size_bytes  INTEGER NOT NULL,
+	orphan      INTEGER NOT NULL,
+	in_use      INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS volume_usage_name ON volume_usage (name, taken_at);
+
+CREATE TABLE IF NOT EXISTS image_usage (
+	sample_id   INTEGER NOT NULL REFERENCES samples (id) ON DELETE CASCADE,
+	taken_at    INTEGER NOT NULL,
+	id          TEXT    NOT NULL,
+	ref         TEXT    NOT NULL,
+	size_bytes  INTEGER NOT NULL,
+	unique_bytes INTEGER NOT NULL,
+	containers  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS image_usage_ref ON image_usage (ref, taken_at);
+
+CREATE TABLE IF NOT EXISTS container_usage (
+	sample_id   INTEGER NOT NULL REFERENCES samples (id) ON DELETE CASCADE,
+	taken_at    INTEGER NOT NULL,
+	id          TEXT    NOT NULL,
+	name        TEXT    NOT NULL,
+	image       TEXT    NOT NULL,
+	state       TEXT    NOT NULL,
+	size_rw     INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS container_usage_name ON container_usage (name, taken_at);
+`, `
+ALTER TABLE samples ADD COLUMN disk_free_bytes INTEGER;
+ALTER TABLE samples ADD COLUMN disk_total_bytes INTEGER;
+`, `
+ALTER TABLE volume_usage ADD COLUMN attached TEXT;
+`}
+
+// SchemaVersion is the schema this dockwatch writes, stored in
+// PRAGMA user_version.
+var SchemaVersion = len(migrations)
+
+// DB is an open history database.
+type DB struct {
+	db *sql.DB
+}
+
+// Path is the default database location.
+func Path() string {
+	return filepath.Join(config.StateDir(), "history.db")
+}
+
+// Open opens or creates the database at path and migrates its schema.
+func Open(path string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create state dir: %w", err)
+	}
+	// Grafana may read while a sample is written, so use WAL and wait on locks
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	h := &DB{db: db}
+	if err := h.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open history %s: %w", path, err)
+	}
+	return h, nil
+}
+
+func (h *DB) migrate() error {
+	var version int
+	if err := h.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("schema version %d is newer than this dockwatch (%d)", version, SchemaVersion)
+	}
+	for ; version < SchemaVersion; version++ {
+		tx, err := h.db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[version]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrating to schema version %d: %w", version+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the database.
+func (h *DB) Close() error {
+	return h.db.Close()
+}
+
+// Sample is one point-in-time reading of disk usage.
+type Sample struct {
+	TakenAt    time.Time
+	Volumes    []domain.Volume
+	Images     []domain.Image
+	Containers []domain.Container
+
+	// Space on the filesystem holding the data root; zero when unknown,
+	// e.g. for a remote daemon
+	DiskFree, DiskTotal int64
+}
+
+// Record stores s in a single transaction.
+func (h *DB) Record(ctx context.Context, s Sample) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	at := s.TakenAt.Unix()
+	var volBytes, orphanBytes, imgBytes, ctrBytes int64
+	orphans := 0
+	for _, v := range s.Volumes {
+		volBytes += max(v.SizeBytes, 0)
+		if v.Orphan() {
+			orphans++
+			orphanBytes += max(v.SizeBytes, 0)
+		}
+	}
+	seen := map[string]bool{}
+	for _, img := range s.Images {
+		// Tags of one image share their bytes; count them once
+		if !seen[img.ID] {
+			seen[img.ID] = true
+			imgBytes += max(img.SizeBytes, 0)
+		}
+	}
+	for _, c := range s.Containers {
+		ctrBytes += max(c.SizeRw, 0)
+	}
+	var diskFree, diskTotal sql.NullInt64
+	if s.DiskTotal > 0 {
+		diskFree = sql.NullInt64{Int64: s.DiskFree, Valid: true}
+		diskTotal = sql.NullInt64{Int64: s.DiskTotal, Valid: true}
+	}
+	res, err := tx.ExecContext(ctx, `INSERT INTO samples
+		(taken_at, volumes, volume_bytes, orphan_volumes, orphan_bytes, images, image_bytes, containers, container_bytes, disk_free_bytes, disk_total_bytes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		at, len(s.Volumes), volBytes, orphans, orphanBytes, len(s.Images), imgBytes, len(s.Containers), ctrBytes, diskFree, diskTotal)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for _, v := range s.Volumes {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO volume_usage VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, at, v.Name, v.Driver, v.Project, v.SizeBytes, v.Orphan(), v.InUse, strings.Join(v.Attached, ",")); err != nil {
+			return err
+		}
+	}
+	for _, img := range s.Images {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO image_usage VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			id, at, img.ID, img.Ref(), img.SizeBytes, img.UniqueSize, img.Containers); err != nil {
+			return err
+		}
+	}
+	for _, c := range s.Containers {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO container_usage VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			id, at, c.ID, strings.TrimPrefix(c.Name, "/"), c.Image, c.State, c.SizeRw); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// LastAttached returns the containers the volume was last recorded as
+// attached to and when, or no names if no sample ever saw it in use.
+func (h *DB) LastAttached(ctx context.Context, volume string) ([]string, time.Time, error) {
+	var attached string
+	var at int64
+	err := h.db.QueryRowContext(ctx, `SELECT attached, taken_at FROM volume_usage
+		WHERE name = ? AND attached != '' ORDER BY taken_at DESC LIMIT 1`, volume).Scan(&attached, &at)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return strings.Split(attached, ","), time.Unix(at, 0), nil
+}
+
+// Prune deletes samples taken before cutoff and returns how many went.
+// The file shrinks only after Vacuum.
+func (h *DB) Prune(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := h.db.ExecContext(ctx, "DELETE FROM samples WHERE taken_at < ?", cutoff.Unix())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Vacuum returns the space freed by Prune to the filesystem.
+func (h *DB) Vacuum(ctx context.Context) error {
+	_, err := h.db.ExecContext(ctx, "VACUUM")
+	return err
+}
@@ -0,0 +1,276 @@
+// Package jobs runs long operations — prunes, size scans — in the
+// background, a few at a time, keeping track of their progress and outcome
+// so a UI can list them and cancel any of them.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// State is where a job is in its life.
+type State int
+
+const (
+	Queued State = iota
+	Running
+	Done
+	Failed
+	Cancelled
+)
+
+func (s State) String() string {
+	switch s {
+	case Queued:
+		return "queued"
+	case Running:
+		return "running"
+	case Done:
+		return "done"
+	case Failed:
+		return "failed"
+	case Cancelled:
+		return "cancelled"
+	}
+	return "unknown"
+}
+
+// Finished reports whether the job has stopped for good.
+func (s State) Finished() bool { return s >= Done }
+
+// Progress reports how far a job got: done of total steps (total 0 when
+// unknown) and what it is working on.
+type Progress func(done, total int, detail string)
+
+// Func is the work of a job. It should stop soon after ctx is cancelled.
+type Func func(ctx context.Context, progress Progress) error
+
+// Job is a snapshot of one job.
+type Job struct {
+	ID       int
+	Kind     string // e.g. "prune" or "scan"
+	Title    string
+	State    State
+	Done     int
+	Total    int
+	Detail   string
+	Err      error
+	Started  time.Time // zero while queued
+	Finished time.Time // zero until finished
+}
+
+// Fraction is how much of the job is done, 0 when unknown.
+func (j Job) Fraction() float64 {
+	if j.Total <= 0 {
+		return 0
+	}
+	return float64(j.Done) / float64(j.Total)
+}
+
+type job struct {
+	Job
+	fn       Func
+	ctx      context.Context
+	cancel   context.CancelFunc
+	finished chan struct{} // closed once the job has finished
+}
+
+// finishLocked records the job's end state.
+func (j *job) finishLocked(s State) {
+	j.State, j.Finished = s, time.Now()
+	j.cancel()
+	close(j.finished)
+}
+
+// Queue runs jobs in the order they were added, at most workers at once.
+type Queue struct {
+	mu      sync.Mutex
+	jobs    []*job
+	running int
+	workers int
+	nextID  int
+	ctx     context.Context
+	stop    context.CancelFunc
+	changed chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New returns a queue running up to workers jobs at once (at least one).
+// Cancelling ctx cancels every job.
+func New(ctx context.Context, workers int) *Queue {
+	ctx, stop := context.WithCancel(ctx)
+	return &Queue{workers: max(workers, 1), ctx: ctx, stop: stop, changed: make(chan struct{}, 1)}
+}
+
+// Add enqueues fn and returns the job's ID.
+func (q *Queue) Add(kind, title string, fn Func) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextID++
+	ctx, cancel := context.WithCancel(q.ctx)
+	q.jobs = append(q.jobs, &job{Job: Job{ID: q.nextID, Kind: kind, Title: title}, fn: fn, ctx: ctx, cancel: cancel,
+		finished: make(chan struct{})})
+	q.startLocked()
+	q.notifyLocked()
+	return q.nextID
+}
+
+// Cancel stops the job with id: a queued job never starts, a running one
+// has its context cancelled. It returns false when the job has finished
+// already or doesn't exist.
+func (q *Queue) Cancel(id int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, j := range q.jobs {
+		if j.ID != id || j.State.Finished() {
+			continue
+		}
+		j.cancel()
+		if j.State == Queued {
+			j.finishLocked(Cancelled)
+			q.notifyLocked()
+		}
+		return true
+	}
+	return false
+}
+
+// CancelAll cancels every job that hasn't finished and returns how many
+// there were. Jobs added later run as usual.
+func (q *Queue) CancelAll() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for _, j := range q.jobs {
+		if j.State.Finished() {
+			continue
+		}
+		n++
+		j.cancel()
+		if j.State == Queued {
+			j.finishLocked(Cancelled)
+		}
+	}
+	if n > 0 {
+		q.notifyLocked()
+	}
+	return n
+}
+
+// Done returns a channel closed once the job with id has finished, however
+// it ended; nil when there is no such job.
+func (q *Queue) Done(id int) <-chan struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, j := range q.jobs {
+		if j.ID == id {
+			return j.finished
+		}
+	}
+	return nil
+}
+
+// Jobs returns a snapshot of every job, oldest first.
+func (q *Queue) Jobs() []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]Job, len(q.jobs))
+	for i, j := range q.jobs {
+		out[i] = j.Job
+	}
+	return out
+}
+
+// Active counts the jobs queued or running.
+func (q *Queue) Active() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for _, j := range q.jobs {
+		if !j.State.Finished() {
+			n++
+		}
+	}
+	return n
+}
+
+// Clear forgets the finished jobs.
+func (q *Queue) Clear() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	kept := q.jobs[:0]
+	for _, j := range q.jobs {
+		if !j.State.Finished() {
+			kept = append(kept, j)
+		}
+	}
+	q.jobs = kept
+	q.notifyLocked()
+}
+
+// Changed receives a value after jobs change. Changes coalesce, so a
+// reader that falls behind sees one value for several of them.
+func (q *Queue) Changed() <-chan struct{} { return q.changed }
+
+// Close cancels every job and waits for the running ones to return.
+func (q *Queue) Close() {
+	q.stop()
+	q.mu.Lock()
+	for _, j := range q.jobs {
+		if j.State == Queued {
+			j.finishLocked(Cancelled)
+		}
+	}
+	q.mu.Unlock()
+	q.wg.Wait()
+}
+
+// startLocked starts queued jobs while workers are free.
+func (q *Queue) startLocked() {
+	for _, j := range q.jobs {
+		if q.running >= q.workers {
+			return
+		}
+		if j.State != Queued {
+			continue
+		}
+		j.State, j.Started = Running, time.Now()
+		q.running++
+		q.wg.Add(1)
+		go q.run(j)
+	}
+}
+
+func (q *Queue) run(j *job) {
+	defer q.wg.Done()
+	err := j.fn(j.ctx, func(done, total int, detail string) {
+		q.mu.Lock()
+		j.Done, j.Total, j.Detail = done, total, detail
+		q.notifyLocked()
+		q.mu.Unlock()
+	})
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j.Err = err
+	switch {
+	case j.ctx.Err() != nil && (err == nil || errors.Is(err, context.Canceled)):
+		j.finishLocked(Cancelled)
+	case err != nil:
+		j.finishLocked(Failed)
+	default:
+		j.finishLocked(Done)
+	}
+	q.running--
+	q.startLocked()
+	q.notifyLocked()
+}
+
+func (q *Queue) notifyLocked() {
+	select {
+	case q.changed <- struct{}{}:
+	default:
+	}
+}
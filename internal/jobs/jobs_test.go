@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// wait blocks until the job with id finishes.
+func wait(t *testing.T, q *Queue, id int) Job {
+	t.Helper()
+	select {
+	case <-q.Done(id):
+	case <-time.After(5 * time.Second):
+		t.Fatalf("job %d did not finish", id)
+	}
+	for _, j := range q.Jobs() {
+		if j.ID == id {
+			return j
+		}
+	}
+	t.Fatalf("job %d is gone", id)
+	return Job{}
+}
+
+func TestQueueRunsInOrderWithinWorkers(t *testing.T) {
+	q := New(context.Background(), 1)
+	defer q.Close()
+
+	release := make(chan struct{})
+	first := q.Add("scan", "first", func(ctx context.Context, progress Progress) error {
+		progress(1, 2, "halfway")
+		<-release
+		return nil
+	})
+	second := q.Add("scan", "second", func(ctx context.Context, progress Progress) error {
+		return errors.New("disk on fire")
+	})
+
+	if got := q.Jobs()[1].State; got != Queued {
+		t.Errorf("second job is %s while the only worker is busy, want queued", got)
+	}
+	close(release)
+	if j := wait(t, q, first); j.State != Done || j.Done != 1 || j.Total != 2 || j.Detail != "halfway" {
+		t.Errorf("first job ended %+v, want done at 1/2 halfway", j)
+	}
+	if j := wait(t, q, second); j.State != Failed || j.Err == nil {
+		t.Errorf("second job ended %s (%v), want failed", j.State, j.Err)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	q := New(context.Background(), 1)
+	defer q.Close()
+
+	running := q.Add("prune", "running", func(ctx context.Context, _ Progress) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	ran := false
+	queued := q.Add("prune", "queued", func(context.Context, Progress) error {
+		ran = true
+		return nil
+	})
+
+	if !q.Cancel(queued) {
+		t.Fatal("cancelling the queued job failed")
+	}
+	if !q.Cancel(running) {
+		t.Fatal("cancelling the running job failed")
+	}
+	for _, id := range []int{running, queued} {
+		if j := wait(t, q, id); j.State != Cancelled {
+			t.Errorf("job %q ended %s, want cancelled", j.Title, j.State)
+		}
+	}
+	if ran {
+		t.Error("a job cancelled while queued still ran")
+	}
+	if q.Cancel(running) {
+		t.Error("cancelling a finished job succeeded")
+	}
+	if q.Active() != 0 {
+		t.Errorf("%d job(s) still active", q.Active())
+	}
+}
@@ -0,0 +1,143 @@
+// Package livesize keeps the size of a directory tree current while files in
+// it change, to watch a cleanup or a leak as it happens. On Linux it follows
+// inotify events and re-reads only what changed; elsewhere, or when the tree
+// has more directories than inotify will watch, it re-walks the tree
+// periodically.
+package livesize
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// pollEvery is how often a tree that can't be watched is walked again.
+const pollEvery = 2 * time.Second
+
+// Sample is the size of the tree at one moment.
+type Sample struct {
+	Bytes int64 // apparent size of the regular files
+	Files int
+	At    time.Time
+	Live  bool  // kept current from change notifications, not by polling
+	Err   error // the tree couldn't be read; no samples follow
+}
+
+// errUnwatchable means change notifications aren't available for the tree.
+var errUnwatchable = errors.New("change notifications unavailable")
+
+// Watch sends the size of dir on the returned channel once it has been
+// walked, and again whenever it changes, until ctx is done. The channel
+// holds only the latest sample, so a slow reader skips intermediate ones;
+// it is closed once the watch ends.
+func Watch(ctx context.Context, dir string) <-chan Sample {
+	ch := make(chan Sample, 1)
+	send := func(s Sample) {
+		// Replace an unread sample rather than block on it
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- s
+	}
+	go func() {
+		defer close(ch)
+		t := &tree{root: dir, sizes: map[string]int64{}}
+		err := notify(ctx, t, func() { send(t.sample(true)) })
+		if errors.Is(err, errUnwatchable) {
+			err = poll(ctx, t, func() { send(t.sample(false)) })
+		}
+		if err != nil && ctx.Err() == nil {
+			send(Sample{At: time.Now(), Err: err})
+		}
+	}()
+	return ch
+}
+
+// poll walks the tree every pollEvery.
+func poll(ctx context.Context, t *tree, changed func()) error {
+	ticker := time.NewTicker(pollEvery)
+	defer ticker.Stop()
+	for {
+		t.sizes, t.total = map[string]int64{}, 0
+		if err := t.walk(t.root, nil); err != nil {
+			return err
+		}
+		changed()
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// tree is the size of every regular file under root, and their total.
+type tree struct {
+	root  string
+	sizes map[string]int64
+	total int64
+}
+
+func (t *tree) sample(live bool) Sample {
+	return Sample{Bytes: t.total, Files: len(t.sizes), At: time.Now(), Live: live}
+}
+
+func (t *tree) set(path string, size int64) {
+	t.total += size - t.sizes[path]
+	t.sizes[path] = size
+}
+
+// walk records the files under dir, calling onDir for each directory
+// before its entries are read. Unreadable entries fail the walk, as a
+// partial size would look like data being deleted.
+func (t *tree) walk(dir string, onDir func(string) error) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path != t.root && errors.Is(err, fs.ErrNotExist) {
+				return nil // removed while walking
+			}
+			return err
+		}
+		switch {
+		case d.IsDir() && onDir != nil:
+			return onDir(path)
+		case d.Type().IsRegular():
+			if info, err := d.Info(); err == nil {
+				t.set(path, info.Size())
+			}
+		}
+		return nil
+	})
+}
+
+// update re-reads one changed path.
+func (t *tree) update(path string) {
+	info, err := os.Lstat(path)
+	switch {
+	case err != nil:
+		t.drop(path)
+	case info.Mode().IsRegular():
+		t.set(path, info.Size())
+	}
+}
+
+// drop forgets path and, for a directory, everything under it.
+func (t *tree) drop(path string) {
+	if n, ok := t.sizes[path]; ok {
+		t.total -= n
+		delete(t.sizes, path)
+		return
+	}
+	prefix := path + string(filepath.Separator)
+	for p, n := range t.sizes {
+		if strings.HasPrefix(p, prefix) {
+			t.total -= n
+			delete(t.sizes, p)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package livesize
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// next waits for a sample matching ok.
+func next(t *testing.T, ch <-chan Sample, ok func(Sample) bool) Sample {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case s, open := <-ch:
+			if !open {
+				t.Fatal("watch ended")
+			}
+			if s.Err != nil {
+				t.Fatal(s.Err)
+			}
+			if ok(s) {
+				return s
+			}
+		case <-timeout:
+			t.Fatal("no matching sample")
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a"), make([]byte, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := Watch(ctx, dir)
+	next(t, ch, func(s Sample) bool { return s.Bytes == 100 && s.Files == 1 })
+
+	sub := filepath.Join(dir, "logs")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "b"), make([]byte, 50), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	next(t, ch, func(s Sample) bool { return s.Bytes == 150 && s.Files == 2 })
+
+	if err := os.RemoveAll(sub); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(filepath.Join(dir, "a"), 10); err != nil {
+		t.Fatal(err)
+	}
+	next(t, ch, func(s Sample) bool { return s.Bytes == 10 && s.Files == 1 })
+
+	cancel()
+	for range ch {
+	}
+}
@@ -0,0 +1,96 @@
+package livesize
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// watchMask is what a watched directory reports: entries appearing,
+// disappearing and being written to.
+const watchMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_ATTRIB
+
+// notify walks the tree watching every directory in it, then applies
+// inotify events to t until ctx is done.
+func notify(ctx context.Context, t *tree, changed func()) error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUnwatchable, err)
+	}
+	// A non-blocking descriptor goes through the runtime poller, so closing
+	// the file interrupts a pending read
+	f := os.NewFile(uintptr(fd), "inotify")
+	stop := context.AfterFunc(ctx, func() { f.Close() })
+	defer func() {
+		if stop() {
+			f.Close()
+		}
+	}()
+
+	dirs := map[int32]string{}
+	watch := func(dir string) error {
+		wd, err := syscall.InotifyAddWatch(fd, dir, watchMask)
+		if errors.Is(err, syscall.ENOSPC) {
+			return fmt.Errorf("%w: more directories than fs.inotify.max_user_watches", errUnwatchable)
+		}
+		if err != nil {
+			return err
+		}
+		dirs[int32(wd)] = dir
+		return nil
+	}
+	// Directories are watched before they are read, so nothing written
+	// during the walk goes unnoticed
+	if err := t.walk(t.root, watch); err != nil {
+		return err
+	}
+	changed()
+
+	buf := make([]byte, 64<<10)
+	for {
+		n, err := f.Read(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+			wd := int32(binary.NativeEndian.Uint32(buf[off:]))
+			mask := binary.NativeEndian.Uint32(buf[off+4:])
+			size := int(binary.NativeEndian.Uint32(buf[off+12:]))
+			name := string(bytes.TrimRight(buf[off+syscall.SizeofInotifyEvent:off+syscall.SizeofInotifyEvent+size], "\x00"))
+			off += syscall.SizeofInotifyEvent + size
+
+			dir, ok := dirs[wd]
+			switch {
+			case mask&syscall.IN_Q_OVERFLOW != 0:
+				// Events were lost; start over from a fresh walk
+				t.sizes, t.total = map[string]int64{}, 0
+				if err := t.walk(t.root, watch); err != nil {
+					return err
+				}
+			case mask&syscall.IN_IGNORED != 0:
+				delete(dirs, wd)
+			case !ok:
+			case mask&(syscall.IN_DELETE|syscall.IN_MOVED_FROM) != 0:
+				t.drop(filepath.Join(dir, name))
+			case mask&syscall.IN_ISDIR != 0:
+				if mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+					if err := t.walk(filepath.Join(dir, name), watch); err != nil {
+						return err
+					}
+				}
+			default:
+				t.update(filepath.Join(dir, name))
+			}
+		}
+		changed()
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package livesize
+
+import "context"
+
+// notify is only implemented with inotify; other platforms poll.
+func notify(context.Context, *tree, func()) error {
+	return errUnwatchable
+}
@@ -0,0 +1,99 @@
+// Package notes keeps dockwatch-local notes and tags on volumes, such as
+// "pending migration" or "ask Sam". They live in the state directory and
+// never touch the volumes or their Docker labels.
+package notes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/domain"
+)
+
+// Store holds the annotations of every volume, keyed by name.
+type Store struct {
+	Volumes map[string]Entry `json:"volumes"`
+}
+
+// Entry is what is attached to one volume.
+type Entry struct {
+	Note string   `json:"note,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Path is where the notes are stored.
+func Path() string {
+	return filepath.Join(config.StateDir(), "notes.json")
+}
+
+// Load reads the notes; a missing file yields an empty store.
+func Load() (*Store, error) {
+	s := &Store{Volumes: map[string]Entry{}}
+	data, err := os.ReadFile(Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to read notes: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return s, fmt.Errorf("failed to parse notes: %w", err)
+	}
+	if s.Volumes == nil {
+		s.Volumes = map[string]Entry{}
+	}
+	return s, nil
+}
+
+// Save writes the notes back.
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.StateDir(), 0o755); err != nil {
+		return fmt.Errorf("failed to create state dir: %w", err)
+	}
+	return os.WriteFile(Path(), data, 0o644)
+}
+
+// SetNote replaces the note on a volume; an empty note removes it.
+func (s *Store) SetNote(name, note string) {
+	e := s.Volumes[name]
+	e.Note = strings.TrimSpace(note)
+	s.set(name, e)
+}
+
+// SetTags replaces the tags on a volume, dropping blanks and duplicates.
+func (s *Store) SetTags(name string, tags []string) {
+	e := s.Volumes[name]
+	e.Tags = nil
+	for _, t := range tags {
+		if t = strings.TrimSpace(t); t != "" && !slices.Contains(e.Tags, t) {
+			e.Tags = append(e.Tags, t)
+		}
+	}
+	s.set(name, e)
+}
+
+func (s *Store) set(name string, e Entry) {
+	if e.Note == "" && len(e.Tags) == 0 {
+		delete(s.Volumes, name)
+		return
+	}
+	s.Volumes[name] = e
+}
+
+// Annotate copies notes and tags onto the listed volumes.
+func (s *Store) Annotate(vols []domain.Volume) {
+	for i := range vols {
+		e := s.Volumes[vols[i].Name]
+		vols[i].Note, vols[i].Tags = e.Note, e.Tags
+	}
+}
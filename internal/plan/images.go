@@ -0,0 +1,114 @@
+package plan
+
+import (
+	"fmt"
+	"sort"
+
+	"dockwatch/internal/domain"
+)
+
+// ImageItem is one marked image tag and what removing it would free.
+type ImageItem struct {
+	Image    domain.Image
+	Reclaim  int64 // bytes freed by removing this tag, given the rest of the plan
+	Warnings []string
+}
+
+// RepoGroup collects the marked tags of one repository.
+type RepoGroup struct {
+	Repository string
+	Items      []ImageItem
+	Reclaim    int64
+}
+
+// ImagePlan groups marked images by repository and estimates reclaimable
+// space. Only an image's unique bytes are counted, and only once the last
+// tag pointing at its ID is removed, so the estimate is a lower bound when
+// several marked images share layers among themselves.
+func ImagePlan(all []domain.Image, marked map[string]bool) ([]RepoGroup, int64) {
+	// An image ID is freed only if every tag referencing it is marked
+	refs := map[string]int{}
+	markedRefs := map[string]int{}
+	for _, img := range all {
+		refs[img.ID]++
+		if marked[img.Ref()] {
+			markedRefs[img.ID]++
+		}
+	}
+	freed := func(id string) bool { return markedRefs[id] == refs[id] }
+
+	// Layers still needed by images that survive the plan
+	keptLayers := map[string]string{} // layer -> a kept image ref
+	for _, img := range all {
+		if freed(img.ID) {
+			continue
+		}
+		for _, l := range img.Layers {
+			if _, ok := keptLayers[l]; !ok {
+				keptLayers[l] = img.Ref()
+			}
+		}
+	}
+
+	groups := map[string]*RepoGroup{}
+	counted := map[string]bool{}
+	var total int64
+	for _, img := range all {
+		if !marked[img.Ref()] {
+			continue
+		}
+		item := ImageItem{Image: img}
+
+		switch {
+		case !freed(img.ID):
+			item.Warnings = append(item.Warnings, "image is still tagged elsewhere; removing this tag only untags it")
+		case counted[img.ID]:
+			// Another tag of the same ID already carries the reclaim
+		default:
+			counted[img.ID] = true
+			if img.UniqueSize > 0 {
+				item.Reclaim = img.UniqueSize
+			}
+			if img.Containers > 0 {
+				item.Warnings = append(item.Warnings, fmt.Sprintf("used by %d container(s); removal will fail until they are removed", img.Containers))
+			}
+			if shared, by := sharedWithKept(img, keptLayers); shared > 0 {
+				item.Warnings = append(item.Warnings, fmt.Sprintf("%d layer(s) shared with kept images (e.g. %s) stay on disk", shared, by))
+			}
+		}
+
+		repo := img.Repository
+		if img.Dangling() {
+			repo = "<none>"
+		}
+		g, ok := groups[repo]
+		if !ok {
+			g = &RepoGroup{Repository: repo}
+			groups[repo] = g
+		}
+		g.Items = append(g.Items, item)
+		g.Reclaim += item.Reclaim
+		total += item.Reclaim
+	}
+
+	out := make([]RepoGroup, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Repository < out[j].Repository })
+	return out, total
+}
+
+// sharedWithKept counts an image's layers that a surviving image still uses.
+func sharedWithKept(img domain.Image, keptLayers map[string]string) (int, string) {
+	n, by := 0, ""
+	for _, l := range img.Layers {
+		if ref, ok := keptLayers[l]; ok {
+			n++
+			if by == "" {
+				by = ref
+			}
+		}
+	}
+	return n, by
+}
@@ -0,0 +1,113 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// Version is the current plan file format version.
+const Version = 1
+
+// Plan is a reviewed, pinned list of removals written by `dockwatch plan`
+// and executed by `dockwatch apply`.
+type Plan struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Items     []Item    `json:"items"`
+}
+
+// Item pins a single volume as it looked at planning time.
+type Item struct {
+	Name      string    `json:"name"`
+	Driver    string    `json:"driver"`
+	CreatedAt time.Time `json:"created_at,omitempty"` // identifies this incarnation of the name
+	SizeBytes int64     `json:"size_bytes"`           // -1 if unknown
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// Drift describes why a planned item no longer matches the live state.
+type Drift struct {
+	Name   string
+	Reason string
+}
+
+// New builds a plan from the selected volumes. reasons maps volume names to
+// why they were selected (e.g. the policy rule) and may be nil.
+func New(vols []domain.Volume, reasons map[string]string) *Plan {
+	p := &Plan{Version: Version, CreatedAt: time.Now().UTC()}
+	for _, v := range vols {
+		p.Items = append(p.Items, Item{
+			Name:      v.Name,
+			Driver:    v.Driver,
+			CreatedAt: v.CreatedAt,
+			SizeBytes: v.SizeBytes,
+			Reason:    reasons[v.Name],
+		})
+	}
+	return p
+}
+
+// TotalBytes sums the known sizes of all items.
+func (p *Plan) TotalBytes() int64 {
+	total := int64(0)
+	for _, it := range p.Items {
+		if it.SizeBytes > 0 {
+			total += it.SizeBytes
+		}
+	}
+	return total
+}
+
+// Write saves the plan as JSON.
+func (p *Plan) Write(file string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0o644)
+}
+
+// Read loads a plan file.
+func Read(file string) (*Plan, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", file, err)
+	}
+	if p.Version != Version {
+		return nil, fmt.Errorf("unsupported plan version %d", p.Version)
+	}
+	return &p, nil
+}
+
+// Check compares the plan with the current volumes and returns every item
+// that drifted. Apply must refuse to run when the result is non-empty.
+func (p *Plan) Check(current []domain.Volume) []Drift {
+	byName := make(map[string]domain.Volume, len(current))
+	for _, v := range current {
+		byName[v.Name] = v
+	}
+
+	var drift []Drift
+	for _, it := range p.Items {
+		v, ok := byName[it.Name]
+		switch {
+		case !ok:
+			drift = append(drift, Drift{it.Name, "volume no longer exists"})
+		case !it.CreatedAt.IsZero() && !v.CreatedAt.Equal(it.CreatedAt):
+			drift = append(drift, Drift{it.Name, "volume was recreated since planning"})
+		case len(v.Attached) > 0:
+			drift = append(drift, Drift{it.Name, fmt.Sprintf("now attached to %v", v.Attached)})
+		case it.SizeBytes >= 0 && v.SizeBytes >= 0 && v.SizeBytes != it.SizeBytes:
+			drift = append(drift, Drift{it.Name, fmt.Sprintf("size changed from %d to %d bytes", it.SizeBytes, v.SizeBytes)})
+		}
+	}
+	return drift
+}
@@ -0,0 +1,105 @@
+package policy
+
+import (
+	"path"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// RuleMatch lists the volumes a single rule matched.
+type RuleMatch struct {
+	Rule    Rule
+	Volumes []domain.Volume
+}
+
+// Decision is the effective outcome for one volume after all rules ran.
+type Decision struct {
+	Volume domain.Volume
+	Action Action
+	Rule   string // rule that decided the action
+}
+
+// MatchVolume reports whether v satisfies every condition of the matcher.
+func (m Matcher) MatchVolume(v domain.Volume, now time.Time) bool {
+	if m.Name != "" {
+		if ok, _ := path.Match(m.Name, v.Name); !ok {
+			return false
+		}
+	}
+	if m.Project != "" {
+		if ok, _ := path.Match(m.Project, v.Project); !ok {
+			return false
+		}
+	}
+	for k, want := range m.Labels {
+		got, ok := v.Labels[k]
+		if !ok || (want != "" && want != "*" && got != want) {
+			return false
+		}
+	}
+	if m.Orphan != nil && *m.Orphan != v.Orphan() {
+		return false
+	}
+	// Unknown age/size never satisfies a threshold
+	if m.olderThan > 0 && (v.CreatedAt.IsZero() || now.Sub(v.CreatedAt) < m.olderThan) {
+		return false
+	}
+	if idle := v.IdleSince(); m.unusedFor > 0 && (idle.IsZero() || now.Sub(idle) < m.unusedFor) {
+		return false
+	}
+	if m.largerThan > 0 && v.SizeBytes < m.largerThan {
+		return false
+	}
+	return true
+}
+
+// Test runs every rule independently and returns what each one matched.
+func (p *Policy) Test(vols []domain.Volume, now time.Time) []RuleMatch {
+	out := make([]RuleMatch, 0, len(p.Rules))
+	for _, r := range p.Rules {
+		rm := RuleMatch{Rule: r}
+		for _, v := range vols {
+			if r.Match.MatchVolume(v, now) {
+				rm.Volumes = append(rm.Volumes, v)
+			}
+		}
+		out = append(out, rm)
+	}
+	return out
+}
+
+// Evaluate resolves every volume to a single action. Protect always wins over
+// prune, and prune over notify; within the same action the first rule wins.
+// Volumes that match no rule are omitted.
+func (p *Policy) Evaluate(vols []domain.Volume, now time.Time) []Decision {
+	var out []Decision
+	for _, v := range vols {
+		d := Decision{Volume: v}
+		for _, r := range p.Rules {
+			if !r.Match.MatchVolume(v, now) {
+				continue
+			}
+			if rank(r.Action) > rank(d.Action) {
+				d.Action = r.Action
+				d.Rule = r.Name
+			}
+		}
+		if d.Action != "" {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func rank(a Action) int {
+	switch a {
+	case ActionProtect:
+		return 3
+	case ActionPrune:
+		return 2
+	case ActionNotify:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,199 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"dockwatch/internal/domain"
+)
+
+// Action is what a rule asks dockwatch to do with the resources it matches.
+type Action string
+
+const (
+	ActionPrune   Action = "prune"
+	ActionProtect Action = "protect"
+	ActionNotify  Action = "notify"
+)
+
+// Policy is a declarative set of cleanup rules, usually loaded from policy.yaml:
+//
+//	version: 1
+//	rules:
+//	  - name: stale-ci-caches
+//	    match:
+//	      name: "ci-*"
+//	      orphan: true
+//	      olderThan: 14d
+//	      unusedFor: 7d
+//	      largerThan: 100MB
+//	    action: prune
+//	  - name: keep-databases
+//	    match:
+//	      labels: {tier: db}
+//	    action: protect
+//	backups:
+//	  - name: nightly-db
+//	    match:
+//	      labels: {tier: db}
+//	    every: 1d
+//	    keep: 7
+type Policy struct {
+	Version int          `yaml:"version"`
+	Rules   []Rule       `yaml:"rules"`
+	Backups []BackupRule `yaml:"backups,omitempty"`
+}
+
+// Rule pairs a matcher with an action.
+type Rule struct {
+	Name   string  `yaml:"name"`
+	Match  Matcher `yaml:"match"`
+	Action Action  `yaml:"action"`
+}
+
+// BackupRule has `dockwatch serve` snapshot the volumes it matches every so
+// often, keeping the newest few archives of each.
+type BackupRule struct {
+	Name  string  `yaml:"name"`
+	Match Matcher `yaml:"match"`
+	Every string  `yaml:"every"`        // e.g. "6h", "1d", "1w"
+	Keep  int     `yaml:"keep"`         // archives kept per volume; 0 keeps all
+	To    string  `yaml:"to,omitempty"` // directory or s3:// URL; default backup.destination
+
+	every time.Duration
+}
+
+// Interval is how long a volume's newest archive lasts before the rule takes
+// another.
+func (r BackupRule) Interval() time.Duration {
+	return r.every
+}
+
+// Matcher selects resources. Every field that is set must match (logical AND);
+// an empty matcher matches everything.
+type Matcher struct {
+	Labels     map[string]string `yaml:"labels,omitempty"`     // value "" or "*" only requires the key
+	Name       string            `yaml:"name,omitempty"`       // glob, e.g. "ci-*"
+	Project    string            `yaml:"project,omitempty"`    // compose project, glob
+	OlderThan  string            `yaml:"olderThan,omitempty"`  // e.g. "14d", "3mo"
+	UnusedFor  string            `yaml:"unusedFor,omitempty"`  // no container used it for this long
+	LargerThan string            `yaml:"largerThan,omitempty"` // e.g. "500MB"
+	Orphan     *bool             `yaml:"orphan,omitempty"`
+
+	olderThan  time.Duration
+	unusedFor  time.Duration
+	largerThan int64
+}
+
+// Load reads and validates a policy file.
+func Load(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	return Parse(data)
+}
+
+// LoadIfExists loads the policy file, returning nil if it is missing.
+func LoadIfExists(file string) (*Policy, error) {
+	if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return Load(file)
+}
+
+// Parse decodes and validates a YAML policy document.
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+	if err := p.compile(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// compile validates rules and pre-parses size/age thresholds.
+func (p *Policy) compile() error {
+	if p.Version == 0 {
+		p.Version = 1
+	}
+	if p.Version != 1 {
+		return fmt.Errorf("unsupported policy version %d", p.Version)
+	}
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		switch r.Action {
+		case ActionPrune, ActionProtect, ActionNotify:
+		default:
+			return fmt.Errorf("rule %q: unknown action %q", r.Name, r.Action)
+		}
+		if err := r.Match.compile(); err != nil {
+			return fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+	}
+	for i := range p.Backups {
+		r := &p.Backups[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("backup-%d", i+1)
+		}
+		if r.Every == "" {
+			return fmt.Errorf("backup %q: no schedule; set every, e.g. every: 1d", r.Name)
+		}
+		d, err := domain.ParseAge(r.Every)
+		if err != nil {
+			return fmt.Errorf("backup %q: %w", r.Name, err)
+		}
+		if d < time.Minute {
+			return fmt.Errorf("backup %q: every %s is too often", r.Name, r.Every)
+		}
+		r.every = d
+		if r.Keep < 0 {
+			return fmt.Errorf("backup %q: keep must not be negative", r.Name)
+		}
+		if err := r.Match.compile(); err != nil {
+			return fmt.Errorf("backup %q: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// compile checks the patterns and pre-parses the thresholds.
+func (m *Matcher) compile() error {
+	for _, glob := range []string{m.Name, m.Project} {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("bad pattern %q: %w", glob, err)
+		}
+	}
+	if m.OlderThan != "" {
+		d, err := domain.ParseAge(m.OlderThan)
+		if err != nil {
+			return err
+		}
+		m.olderThan = d
+	}
+	if m.UnusedFor != "" {
+		d, err := domain.ParseAge(m.UnusedFor)
+		if err != nil {
+			return err
+		}
+		m.unusedFor = d
+	}
+	if m.LargerThan != "" {
+		n, err := domain.ParseSize(m.LargerThan)
+		if err != nil {
+			return err
+		}
+		m.largerThan = n
+	}
+	return nil
+}
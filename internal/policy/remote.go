@@ -0,0 +1,242 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"dockwatch/internal/config"
+)
+
+// maxRemoteSize bounds a downloaded policy or signature.
+const maxRemoteSize = 1 << 20
+
+// Remote is a policy published at an HTTPS URL so a fleet of hosts can share
+// one set of rules. The file is signed: URL.sig holds the base64 ed25519
+// signature of its exact bytes, and nothing that fails to verify is used.
+type Remote struct {
+	URL       string
+	PublicKey ed25519.PublicKey
+
+	// CacheFile keeps the last verified copy with its ETag, so an unchanged
+	// policy costs a 304 and an unreachable server doesn't stop cleanups.
+	CacheFile string
+
+	Client *http.Client // default one with a 30s timeout
+}
+
+// remoteCache is the CacheFile.
+type remoteCache struct {
+	URL       string    `json:"url"`
+	ETag      string    `json:"etag,omitempty"`
+	Body      []byte    `json:"body"`
+	Signature []byte    `json:"signature"`
+	Fetched   time.Time `json:"fetched"`
+}
+
+// StaleError reports that the server could not be asked and the cached copy
+// fetched at Fetched was used instead.
+type StaleError struct {
+	Fetched time.Time
+	Err     error
+}
+
+func (e *StaleError) Error() string {
+	return fmt.Sprintf("using the policy cached %s: %v", e.Fetched.Local().Format("2006-01-02 15:04"), e.Err)
+}
+
+func (e *StaleError) Unwrap() error { return e.Err }
+
+// Fetch downloads, verifies and parses the policy. When the server is
+// unreachable or failing and a verified copy is cached, it returns that copy
+// along with a *StaleError; a bad signature is never papered over that way.
+func (r Remote) Fetch(ctx context.Context) (*Policy, error) {
+	cached := r.cached()
+	c, err := r.fetch(ctx, cached)
+	var bad *signatureError
+	switch {
+	case err == nil:
+	case cached != nil && !errors.As(err, &bad):
+		pol, perr := Parse(cached.Body)
+		if perr != nil {
+			return nil, perr
+		}
+		return pol, &StaleError{Fetched: cached.Fetched, Err: err}
+	default:
+		return nil, err
+	}
+	pol, err := Parse(c.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", r.URL, err)
+	}
+	if c != cached {
+		r.save(c)
+	}
+	return pol, nil
+}
+
+// signatureError is a policy that doesn't verify with the configured key.
+type signatureError struct{ url string }
+
+func (e *signatureError) Error() string {
+	return fmt.Sprintf("%s: signature does not verify with the configured public key", e.url)
+}
+
+// fetch asks the server for the policy, returning cached itself when the
+// server says it is unchanged.
+func (r Remote) fetch(ctx context.Context, cached *remoteCache) (*remoteCache, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	resp, err := r.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch policy: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		return cached, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to fetch policy: %s: %s", r.URL, resp.Status)
+	}
+	body, err := readLimited(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch policy: %w", err)
+	}
+	sig, err := r.signature(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(r.PublicKey, body, sig) {
+		return nil, &signatureError{url: r.URL}
+	}
+	return &remoteCache{URL: r.URL, ETag: resp.Header.Get("ETag"), Body: body, Signature: sig, Fetched: time.Now()}, nil
+}
+
+// signature downloads URL.sig. It is fetched with every new body, since it
+// changes whenever the policy does.
+func (r Remote) signature(ctx context.Context) ([]byte, error) {
+	sigURL, err := r.signatureURL()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sigURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch policy signature: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch policy signature: %s: %s", sigURL, resp.Status)
+	}
+	data, err := readLimited(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch policy signature: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return nil, &signatureError{url: r.URL}
+	}
+	return sig, nil
+}
+
+// signatureURL is URL with .sig added to its path, keeping any query such
+// as ?ref=main.
+func (r Remote) signatureURL() (string, error) {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return "", err
+	}
+	u.Path += ".sig"
+	if u.RawPath != "" {
+		u.RawPath += ".sig"
+	}
+	return u.String(), nil
+}
+
+func readLimited(rd io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(rd, maxRemoteSize+1))
+	if err == nil && len(data) > maxRemoteSize {
+		err = fmt.Errorf("larger than %d bytes", maxRemoteSize)
+	}
+	return data, err
+}
+
+func (r Remote) client() *http.Client {
+	if r.Client != nil {
+		return r.Client
+	}
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// cached returns the cached copy when it is of this URL and still verifies
+// with the key, which may have been rotated since.
+func (r Remote) cached() *remoteCache {
+	if r.CacheFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(r.CacheFile)
+	if err != nil {
+		return nil
+	}
+	var c remoteCache
+	if json.Unmarshal(data, &c) != nil || c.URL != r.URL || !ed25519.Verify(r.PublicKey, c.Body, c.Signature) {
+		return nil
+	}
+	return &c
+}
+
+// save writes the cache; failing to is not worth failing the load over.
+func (r Remote) save(c *remoteCache) {
+	if r.CacheFile == "" {
+		return
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(r.CacheFile), 0o755) == nil {
+		os.WriteFile(r.CacheFile, data, 0o644)
+	}
+}
+
+// Configured loads the policy this host follows: the one at policy.url when
+// the config sets it, else the local policy file, nil when there is none.
+// warn receives problems that didn't stop it loading, such as a policy
+// server that was down while a cached copy stood in.
+func Configured(ctx context.Context, cfg *config.Config, warn func(error)) (*Policy, error) {
+	if cfg.Policy.URL == "" {
+		return LoadIfExists(config.PolicyPath())
+	}
+	key, err := cfg.Policy.Key()
+	if err != nil {
+		return nil, err
+	}
+	r := Remote{URL: cfg.Policy.URL, PublicKey: key, CacheFile: filepath.Join(config.StateDir(), "policy-cache.json")}
+	pol, err := r.Fetch(ctx)
+	var stale *StaleError
+	if errors.As(err, &stale) {
+		if warn != nil {
+			warn(err)
+		}
+		return pol, nil
+	}
+	return pol, err
+}
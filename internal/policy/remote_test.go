@@ -0,0 +1,72 @@
+package policy
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const served = "version: 1\nrules:\n  - name: keep-db\n    match:\n      labels: {tier: db}\n    action: protect\n"
+
+func TestRemoteFetch(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, etag, sig := served, `"v1"`, base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(served)))
+	var down bool
+	var gets, notModified int
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case down:
+			http.Error(w, "down", http.StatusBadGateway)
+		case r.URL.Query().Get("ref") != "main":
+			http.NotFound(w, r)
+		case r.URL.Path == "/policy.yaml.sig":
+			w.Write([]byte(sig + "\n"))
+		case r.Header.Get("If-None-Match") == etag:
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			gets++
+			w.Header().Set("ETag", etag)
+			w.Write([]byte(body))
+		}
+	}))
+	defer srv.Close()
+
+	r := Remote{URL: srv.URL + "/policy.yaml?ref=main", PublicKey: pub, CacheFile: filepath.Join(t.TempDir(), "cache.json"), Client: srv.Client()}
+	ctx := context.Background()
+	for range 2 {
+		pol, err := r.Fetch(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pol.Rules) != 1 || pol.Rules[0].Name != "keep-db" {
+			t.Fatalf("rules %+v", pol.Rules)
+		}
+	}
+	if gets != 1 || notModified != 1 {
+		t.Errorf("%d full fetch(es) and %d 304(s), want the second load to be a 304", gets, notModified)
+	}
+
+	down = true
+	pol, err := r.Fetch(ctx)
+	var stale *StaleError
+	if !errors.As(err, &stale) || pol == nil || len(pol.Rules) != 1 {
+		t.Errorf("server down: got %v, %v; want the cached policy and a StaleError", pol, err)
+	}
+
+	// A tampered policy is refused, and the cache doesn't stand in for it
+	down = false
+	body, etag = strings.Replace(served, "protect", "prune", 1), `"v2"`
+	if _, err := r.Fetch(ctx); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("got %v, want a signature error", err)
+	}
+}
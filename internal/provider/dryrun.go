@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// DryRunRequested reports whether $DOCKWATCH_DRY_RUN asks for dry-run mode.
+func DryRunRequested() bool {
+	switch strings.ToLower(os.Getenv("DOCKWATCH_DRY_RUN")) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// DryRun wraps prov so that every destructive call is reported through
+// logf as "would remove …" and succeeds without touching the daemon.
+func DryRun(prov Provider, logf func(format string, args ...any)) Provider {
+	return &dryRun{Provider: prov, logf: logf}
+}
+
+type dryRun struct {
+	Provider
+	logf func(format string, args ...any)
+}
+
+func (d *dryRun) RemoveVolume(ctx context.Context, name string) error {
+	d.logf("would remove volume %s", name)
+	return nil
+}
+
+func (d *dryRun) RemoveContainer(ctx context.Context, id string, removeVolumes bool) error {
+	if removeVolumes {
+		d.logf("would remove container %s and its anonymous volumes", id)
+	} else {
+		d.logf("would remove container %s", id)
+	}
+	return nil
+}
+
+// TruncateLogs reports the current log size as what would be freed.
+func (d *dryRun) TruncateLogs(ctx context.Context, id string) (int64, error) {
+	d.logf("would truncate logs of container %s", id)
+	cs, err := d.ListContainers(ctx)
+	if err != nil {
+		return 0, nil
+	}
+	for _, c := range cs {
+		if c.ID == id || c.Name == id {
+			return max(c.LogSize, 0), nil
+		}
+	}
+	return 0, nil
+}
+
+func (d *dryRun) RemoveImage(ctx context.Context, ref string) error {
+	d.logf("would remove image %s", ref)
+	return nil
+}
+
+func (d *dryRun) RemoveNetwork(ctx context.Context, id string) error {
+	d.logf("would remove network %s", id)
+	return nil
+}
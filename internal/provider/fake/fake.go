@@ -0,0 +1,387 @@
+// Package fake is an in-memory Provider for tests. It behaves like a small
+// daemon (removing a mounted volume fails, removing a container frees its
+// volumes) and can be scripted to be slow or to fail.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/provider"
+)
+
+var _ provider.Provider = (*Provider)(nil)
+
+// Provider holds the daemon state. Set the fields before handing it out;
+// once it is in use, change them inside Do.
+type Provider struct {
+	Volumes    []domain.Volume
+	Containers []domain.Container
+	Images     []domain.Image
+	Networks   []domain.Network
+	Logs       map[string][]string // container ID → log lines
+	Stats      []domain.ContainerStats
+
+	// Latency delays every call; a cancelled context cuts it short.
+	Latency time.Duration
+
+	mu     sync.Mutex
+	next   map[string][]error // one-shot errors per operation, in order
+	sticky map[string]error   // errors returned until cleared
+	calls  []Call
+	closed bool
+}
+
+// Call is one recorded method call.
+type Call struct {
+	Op  string // method name, e.g. "RemoveVolume"
+	Arg string // volume name, container ID or image ref; empty for listings
+}
+
+// New returns a provider with the given volumes and nothing else.
+func New(vols ...domain.Volume) *Provider {
+	return &Provider{Volumes: vols}
+}
+
+// Do runs f with the state locked, for changing it while the provider is
+// in use.
+func (p *Provider) Do(f func(p *Provider)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f(p)
+}
+
+// FailNext makes the next calls to op return errs in turn; a nil entry lets
+// that call through.
+func (p *Provider) FailNext(op string, errs ...error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.next == nil {
+		p.next = map[string][]error{}
+	}
+	p.next[op] = append(p.next[op], errs...)
+}
+
+// Fail makes every call to op return err until Fail(op, nil).
+func (p *Provider) Fail(op string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.sticky == nil {
+		p.sticky = map[string]error{}
+	}
+	if err == nil {
+		delete(p.sticky, op)
+		return
+	}
+	p.sticky[op] = err
+}
+
+// Calls returns the recorded calls to op, or every call for "".
+func (p *Provider) Calls(op string) []Call {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var calls []Call
+	for _, c := range p.calls {
+		if op == "" || c.Op == op {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Closed reports whether Close was called.
+func (p *Provider) Closed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+// begin records a call, waits out the latency and returns the scripted
+// error, if any. On success the state is left locked for the caller.
+func (p *Provider) begin(ctx context.Context, op, arg string) error {
+	p.mu.Lock()
+	p.calls = append(p.calls, Call{Op: op, Arg: arg})
+	latency := p.Latency
+	p.mu.Unlock()
+
+	if latency > 0 {
+		t := time.NewTimer(latency)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if errs := p.next[op]; len(errs) > 0 {
+		p.next[op] = errs[1:]
+		if errs[0] != nil {
+			p.mu.Unlock()
+			return errs[0]
+		}
+	}
+	if err := p.sticky[op]; err != nil {
+		p.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// volumes returns the volumes with Attached, State and InUse derived from
+// the containers, as the daemon would report them.
+func (p *Provider) volumes() []domain.Volume {
+	vols := make([]domain.Volume, len(p.Volumes))
+	for i, v := range p.Volumes {
+		v.Attached, v.InUse = nil, false
+		for _, c := range p.Containers {
+			if mounts(c, v.Name) {
+				v.Attached = append(v.Attached, c.Name)
+				v.InUse = v.InUse || c.Running()
+			}
+		}
+		v.State = domain.StateOf(len(v.Attached), v.InUse)
+		vols[i] = v
+	}
+	return vols
+}
+
+func mounts(c domain.Container, volume string) bool {
+	return slices.ContainsFunc(c.Mounts, func(m domain.Mount) bool { return m.Type == "volume" && m.Name == volume })
+}
+
+func notFound(what, name string) error {
+	return &domain.KindError{Kind: domain.ErrNotFound, Msg: fmt.Sprintf("no such %s: %s", what, name)}
+}
+
+func (p *Provider) ListVolumes(ctx context.Context) ([]domain.Volume, error) {
+	if err := p.begin(ctx, "ListVolumes", ""); err != nil {
+		return nil, err
+	}
+	defer p.mu.Unlock()
+	return p.volumes(), nil
+}
+
+func (p *Provider) ListVolumesBasic(ctx context.Context) ([]domain.Volume, error) {
+	if err := p.begin(ctx, "ListVolumesBasic", ""); err != nil {
+		return nil, err
+	}
+	defer p.mu.Unlock()
+	return p.volumes(), nil
+}
+
+func (p *Provider) ListVolumesFiltered(ctx context.Context, f domain.VolumeFilter) ([]domain.Volume, error) {
+	if err := p.begin(ctx, "ListVolumesFiltered", ""); err != nil {
+		return nil, err
+	}
+	defer p.mu.Unlock()
+	var vols []domain.Volume
+	for _, v := range p.volumes() {
+		if f.Match(v) {
+			vols = append(vols, v)
+		}
+	}
+	return vols, nil
+}
+
+func (p *Provider) EnrichVolumes(ctx context.Context, names []string, each func(domain.Volume)) error {
+	if err := p.begin(ctx, "EnrichVolumes", ""); err != nil {
+		return err
+	}
+	vols := p.volumes()
+	p.mu.Unlock()
+	for _, v := range vols {
+		if slices.Contains(names, v.Name) {
+			each(v)
+		}
+	}
+	return nil
+}
+
+func (p *Provider) GetVolumeDetails(ctx context.Context, name string) (*domain.Volume, error) {
+	if err := p.begin(ctx, "GetVolumeDetails", name); err != nil {
+		return nil, err
+	}
+	defer p.mu.Unlock()
+	for _, v := range p.volumes() {
+		if v.Name == name {
+			return &v, nil
+		}
+	}
+	return nil, notFound("volume", name)
+}
+
+func (p *Provider) RemoveVolume(ctx context.Context, name string) error {
+	if err := p.begin(ctx, "RemoveVolume", name); err != nil {
+		return err
+	}
+	defer p.mu.Unlock()
+	i := slices.IndexFunc(p.Volumes, func(v domain.Volume) bool { return v.Name == name })
+	if i < 0 {
+		return notFound("volume", name)
+	}
+	for _, c := range p.Containers {
+		if mounts(c, name) {
+			return &domain.KindError{Kind: domain.ErrVolumeInUse, Msg: fmt.Sprintf("remove %s: volume is in use - [%s]", name, c.ID)}
+		}
+	}
+	p.Volumes = slices.Delete(p.Volumes, i, i+1)
+	return nil
+}
+
+func (p *Provider) ListContainers(ctx context.Context) ([]domain.Container, error) {
+	if err := p.begin(ctx, "ListContainers", ""); err != nil {
+		return nil, err
+	}
+	defer p.mu.Unlock()
+	return slices.Clone(p.Containers), nil
+}
+
+func (p *Provider) RemoveContainer(ctx context.Context, id string, removeVolumes bool) error {
+	if err := p.begin(ctx, "RemoveContainer", id); err != nil {
+		return err
+	}
+	defer p.mu.Unlock()
+	i := slices.IndexFunc(p.Containers, func(c domain.Container) bool { return c.ID == id || c.Name == id })
+	if i < 0 {
+		return notFound("container", id)
+	}
+	c := p.Containers[i]
+	if c.Running() {
+		return fmt.Errorf("cannot remove container %s: container is running", c.Name)
+	}
+	p.Containers = slices.Delete(p.Containers, i, i+1)
+	if removeVolumes {
+		anon := c.AnonymousVolumes()
+		p.Volumes = slices.DeleteFunc(p.Volumes, func(v domain.Volume) bool { return slices.Contains(anon, v.Name) })
+	}
+	return nil
+}
+
+func (p *Provider) ContainerLogs(ctx context.Context, id string, tail int) ([]string, error) {
+	if err := p.begin(ctx, "ContainerLogs", id); err != nil {
+		return nil, err
+	}
+	defer p.mu.Unlock()
+	lines, ok := p.Logs[id]
+	if !ok && !slices.ContainsFunc(p.Containers, func(c domain.Container) bool { return c.ID == id }) {
+		return nil, notFound("container", id)
+	}
+	if tail > 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	return slices.Clone(lines), nil
+}
+
+// TruncateLogs zeroes the container's LogSize, refusing other log drivers
+// the way the real provider does.
+func (p *Provider) TruncateLogs(ctx context.Context, id string) (int64, error) {
+	if err := p.begin(ctx, "TruncateLogs", id); err != nil {
+		return 0, err
+	}
+	defer p.mu.Unlock()
+	i := slices.IndexFunc(p.Containers, func(c domain.Container) bool { return c.ID == id || c.Name == id })
+	if i < 0 {
+		return 0, notFound("container", id)
+	}
+	c := &p.Containers[i]
+	if c.LogDriver != "" && c.LogDriver != "json-file" {
+		return 0, fmt.Errorf("%s logs to %s; only json-file logs can be truncated", id, c.LogDriver)
+	}
+	freed := max(c.LogSize, 0)
+	c.LogSize = 0
+	return freed, nil
+}
+
+func (p *Provider) ContainerStats(ctx context.Context) ([]domain.ContainerStats, error) {
+	if err := p.begin(ctx, "ContainerStats", ""); err != nil {
+		return nil, err
+	}
+	defer p.mu.Unlock()
+	return slices.Clone(p.Stats), nil
+}
+
+func (p *Provider) ListImages(ctx context.Context) ([]domain.Image, error) {
+	if err := p.begin(ctx, "ListImages", ""); err != nil {
+		return nil, err
+	}
+	defer p.mu.Unlock()
+	return slices.Clone(p.Images), nil
+}
+
+// PullImage reports a single layer downloading and then done; the image
+// list is left alone.
+func (p *Provider) PullImage(ctx context.Context, ref string, progress func(domain.PullProgress)) error {
+	if err := p.begin(ctx, "PullImage", ref); err != nil {
+		return err
+	}
+	p.mu.Unlock()
+	progress(domain.PullProgress{Current: 50, Total: 100, Layers: 1, Status: "Downloading"})
+	progress(domain.PullProgress{Current: 100, Total: 100, Layers: 1, LayersDone: 1, Status: "Pull complete"})
+	return nil
+}
+
+func (p *Provider) RemoveImage(ctx context.Context, ref string) error {
+	if err := p.begin(ctx, "RemoveImage", ref); err != nil {
+		return err
+	}
+	defer p.mu.Unlock()
+	i := slices.IndexFunc(p.Images, func(img domain.Image) bool { return img.Ref() == ref || img.ID == ref })
+	if i < 0 {
+		return notFound("image", ref)
+	}
+	if p.Images[i].Containers > 0 {
+		return fmt.Errorf("conflict: unable to remove %s: image is being used by %d container(s)", ref, p.Images[i].Containers)
+	}
+	p.Images = slices.Delete(p.Images, i, i+1)
+	return nil
+}
+
+func (p *Provider) ListNetworks(ctx context.Context) ([]domain.Network, error) {
+	if err := p.begin(ctx, "ListNetworks", ""); err != nil {
+		return nil, err
+	}
+	defer p.mu.Unlock()
+	return slices.Clone(p.Networks), nil
+}
+
+func (p *Provider) RemoveNetwork(ctx context.Context, id string) error {
+	if err := p.begin(ctx, "RemoveNetwork", id); err != nil {
+		return err
+	}
+	defer p.mu.Unlock()
+	i := slices.IndexFunc(p.Networks, func(n domain.Network) bool { return n.ID == id || n.Name == id })
+	if i < 0 {
+		return notFound("network", id)
+	}
+	if len(p.Networks[i].Containers) > 0 {
+		return &domain.KindError{Kind: domain.ErrNetworkInUse, Msg: fmt.Sprintf("error while removing network: network %s has active endpoints", p.Networks[i].Name)}
+	}
+	p.Networks = slices.Delete(p.Networks, i, i+1)
+	return nil
+}
+
+// Events emits nothing; it blocks until ctx is cancelled.
+func (p *Provider) Events(ctx context.Context, each func(domain.DaemonEvent)) error {
+	if err := p.begin(ctx, "Events", ""); err != nil {
+		return err
+	}
+	p.mu.Unlock()
+	<-ctx.Done()
+	return nil
+}
+
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"dockwatch/internal/domain"
+)
+
+// Permission is what a caller may do through a gated provider.
+type Permission string
+
+const (
+	// PermView allows listing and inspecting only.
+	PermView Permission = "view"
+	// PermPruneOrphans additionally allows pulling images and removing
+	// what nothing uses: orphaned volumes, stopped containers, dangling
+	// images and networks without containers.
+	PermPruneOrphans Permission = "prune-orphans"
+	// PermPruneAny allows every operation.
+	PermPruneAny Permission = "prune-any"
+)
+
+// ErrForbidden is returned by a gated provider for operations the
+// permission doesn't allow. It is a domain.ErrPermission.
+var ErrForbidden error = &domain.KindError{Kind: domain.ErrPermission, Msg: "operation not permitted"}
+
+// ParsePermission validates a permission name.
+func ParsePermission(s string) (Permission, error) {
+	switch p := Permission(s); p {
+	case PermView, PermPruneOrphans, PermPruneAny:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown permission %q (want view, prune-orphans or prune-any)", s)
+}
+
+// Gate wraps prov so that only operations allowed by perm go through.
+// Reads always pass.
+func Gate(prov Provider, perm Permission) Provider {
+	if perm == PermPruneAny {
+		return prov
+	}
+	return &gated{Provider: prov, perm: perm}
+}
+
+type gated struct {
+	Provider
+	perm Permission
+}
+
+func (g *gated) deny(op string) error {
+	return fmt.Errorf("%s: %w with %s permission", op, ErrForbidden, g.perm)
+}
+
+func (g *gated) RemoveVolume(ctx context.Context, name string) error {
+	if g.perm == PermView {
+		return g.deny("remove volume " + name)
+	}
+	v, err := g.GetVolumeDetails(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !v.Orphan() {
+		return g.deny("remove in-use volume " + name)
+	}
+	// Don't rest a removal on the state alone: look at the containers again
+	// and refuse when it can't be told that nothing mounts the volume
+	cs, err := g.ListContainers(ctx)
+	if err != nil {
+		return fmt.Errorf("remove volume %s: %w: containers could not be listed to check it is unused: %v", name, ErrForbidden, err)
+	}
+	for _, c := range cs {
+		for _, mt := range c.Mounts {
+			if mt.Type == "volume" && mt.Name == name {
+				return g.deny("remove in-use volume " + name)
+			}
+		}
+	}
+	return g.Provider.RemoveVolume(ctx, name)
+}
+
+func (g *gated) RemoveContainer(ctx context.Context, id string, removeVolumes bool) error {
+	if g.perm == PermView {
+		return g.deny("remove container " + id)
+	}
+	cs, err := g.ListContainers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range cs {
+		if c.ID == id || c.Name == id {
+			if c.Running() {
+				return g.deny("remove running container " + c.Name)
+			}
+			return g.Provider.RemoveContainer(ctx, id, removeVolumes)
+		}
+	}
+	return fmt.Errorf("container %s not found", id)
+}
+
+// TruncateLogs is allowed with prune-orphans for stopped containers only,
+// like removing them, which would drop their logs anyway.
+func (g *gated) TruncateLogs(ctx context.Context, id string) (int64, error) {
+	if g.perm == PermView {
+		return 0, g.deny("truncate logs of " + id)
+	}
+	cs, err := g.ListContainers(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range cs {
+		if c.ID == id || c.Name == id {
+			if c.Running() {
+				return 0, g.deny("truncate logs of running container " + c.Name)
+			}
+			return g.Provider.TruncateLogs(ctx, id)
+		}
+	}
+	return 0, fmt.Errorf("container %s not found", id)
+}
+
+func (g *gated) RemoveImage(ctx context.Context, ref string) error {
+	if g.perm == PermView {
+		return g.deny("remove image " + ref)
+	}
+	imgs, err := g.ListImages(ctx)
+	if err != nil {
+		return err
+	}
+	for _, img := range imgs {
+		if img.Ref() == ref || img.ID == ref {
+			if !img.Dangling() || img.Containers > 0 {
+				return g.deny("remove tagged or in-use image " + ref)
+			}
+			return g.Provider.RemoveImage(ctx, ref)
+		}
+	}
+	return fmt.Errorf("image %s not found", ref)
+}
+
+func (g *gated) RemoveNetwork(ctx context.Context, id string) error {
+	if g.perm == PermView {
+		return g.deny("remove network " + id)
+	}
+	ns, err := g.ListNetworks(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range ns {
+		if n.ID == id || n.Name == id {
+			if len(n.Containers) > 0 {
+				return g.deny("remove network in use " + n.Name)
+			}
+			return g.Provider.RemoveNetwork(ctx, id)
+		}
+	}
+	return fmt.Errorf("network %s not found", id)
+}
+
+func (g *gated) PullImage(ctx context.Context, ref string, progress func(domain.PullProgress)) error {
+	if g.perm == PermView {
+		return g.deny("pull " + ref)
+	}
+	return g.Provider.PullImage(ctx, ref, progress)
+}
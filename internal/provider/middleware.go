@@ -0,0 +1,250 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// Middleware adds a cross-cutting concern (dry-run, permissions, logging,
+// metrics, ...) around a Provider. A middleware embeds the Provider it wraps
+// and overrides only the calls it cares about, or uses Intercept to see
+// every call.
+type Middleware func(Provider) Provider
+
+// Chain wraps prov in mws. The first middleware is the outermost and sees
+// each call first; nil middlewares are skipped so optional ones can be
+// passed inline.
+func Chain(prov Provider, mws ...Middleware) Provider {
+	for i := len(mws) - 1; i >= 0; i-- {
+		if mws[i] != nil {
+			prov = mws[i](prov)
+		}
+	}
+	return prov
+}
+
+// WithDryRun is DryRun as a middleware.
+func WithDryRun(logf func(format string, args ...any)) Middleware {
+	return func(prov Provider) Provider { return DryRun(prov, logf) }
+}
+
+// WithGate is Gate as a middleware.
+func WithGate(perm Permission) Middleware {
+	return func(prov Provider) Provider { return Gate(prov, perm) }
+}
+
+// ReadOnly refuses every removal and pull with ErrForbidden.
+func ReadOnly() Middleware {
+	return WithGate(PermView)
+}
+
+// TraceRequested reports whether $DOCKWATCH_TRACE asks for every provider
+// call to be logged.
+func TraceRequested() bool {
+	switch strings.ToLower(os.Getenv("DOCKWATCH_TRACE")) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// Logging logs every call with its duration and error, if any.
+func Logging(logf func(format string, args ...any)) Middleware {
+	return Intercept(func(ctx context.Context, op string, call func(context.Context) error) error {
+		start := time.Now()
+		err := call(ctx)
+		if err != nil {
+			logf("%s failed after %s: %v", op, time.Since(start).Round(time.Millisecond), err)
+		} else {
+			logf("%s took %s", op, time.Since(start).Round(time.Millisecond))
+		}
+		return err
+	})
+}
+
+// Metrics counts calls, errors and time spent per operation.
+type Metrics struct {
+	mu  sync.Mutex
+	ops map[string]OpStats
+}
+
+// OpStats is what Metrics knows about one operation.
+type OpStats struct {
+	Calls  int64         `json:"calls"`
+	Errors int64         `json:"errors"`
+	Total  time.Duration `json:"total_ns"`
+}
+
+// Middleware records into m.
+func (m *Metrics) Middleware() Middleware {
+	return Intercept(func(ctx context.Context, op string, call func(context.Context) error) error {
+		start := time.Now()
+		err := call(ctx)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.ops == nil {
+			m.ops = map[string]OpStats{}
+		}
+		s := m.ops[op]
+		s.Calls++
+		s.Total += time.Since(start)
+		if err != nil {
+			s.Errors++
+		}
+		m.ops[op] = s
+		return err
+	})
+}
+
+// Snapshot returns a copy of the stats by operation name.
+func (m *Metrics) Snapshot() map[string]OpStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]OpStats, len(m.ops))
+	for op, s := range m.ops {
+		out[op] = s
+	}
+	return out
+}
+
+// Interceptor runs around every provider call except Close. op is the
+// method name, e.g. "ListVolumes"; call performs it and may be invoked
+// with a derived context.
+type Interceptor func(ctx context.Context, op string, call func(context.Context) error) error
+
+// Intercept turns fn into a middleware that sees every call.
+func Intercept(fn Interceptor) Middleware {
+	return func(prov Provider) Provider { return &intercepted{Provider: prov, around: fn} }
+}
+
+type intercepted struct {
+	Provider
+	around Interceptor
+}
+
+func (i *intercepted) ListVolumes(ctx context.Context) (vols []domain.Volume, err error) {
+	err = i.around(ctx, "ListVolumes", func(ctx context.Context) error {
+		vols, err = i.Provider.ListVolumes(ctx)
+		return err
+	})
+	return vols, err
+}
+
+func (i *intercepted) ListVolumesBasic(ctx context.Context) (vols []domain.Volume, err error) {
+	err = i.around(ctx, "ListVolumesBasic", func(ctx context.Context) error {
+		vols, err = i.Provider.ListVolumesBasic(ctx)
+		return err
+	})
+	return vols, err
+}
+
+func (i *intercepted) ListVolumesFiltered(ctx context.Context, f domain.VolumeFilter) (vols []domain.Volume, err error) {
+	err = i.around(ctx, "ListVolumesFiltered", func(ctx context.Context) error {
+		vols, err = i.Provider.ListVolumesFiltered(ctx, f)
+		return err
+	})
+	return vols, err
+}
+
+func (i *intercepted) EnrichVolumes(ctx context.Context, names []string, each func(domain.Volume)) error {
+	return i.around(ctx, "EnrichVolumes", func(ctx context.Context) error {
+		return i.Provider.EnrichVolumes(ctx, names, each)
+	})
+}
+
+func (i *intercepted) GetVolumeDetails(ctx context.Context, name string) (v *domain.Volume, err error) {
+	err = i.around(ctx, "GetVolumeDetails", func(ctx context.Context) error {
+		v, err = i.Provider.GetVolumeDetails(ctx, name)
+		return err
+	})
+	return v, err
+}
+
+func (i *intercepted) RemoveVolume(ctx context.Context, name string) error {
+	return i.around(ctx, "RemoveVolume", func(ctx context.Context) error {
+		return i.Provider.RemoveVolume(ctx, name)
+	})
+}
+
+func (i *intercepted) ListContainers(ctx context.Context) (cs []domain.Container, err error) {
+	err = i.around(ctx, "ListContainers", func(ctx context.Context) error {
+		cs, err = i.Provider.ListContainers(ctx)
+		return err
+	})
+	return cs, err
+}
+
+func (i *intercepted) RemoveContainer(ctx context.Context, id string, removeVolumes bool) error {
+	return i.around(ctx, "RemoveContainer", func(ctx context.Context) error {
+		return i.Provider.RemoveContainer(ctx, id, removeVolumes)
+	})
+}
+
+func (i *intercepted) ContainerLogs(ctx context.Context, id string, tail int) (lines []string, err error) {
+	err = i.around(ctx, "ContainerLogs", func(ctx context.Context) error {
+		lines, err = i.Provider.ContainerLogs(ctx, id, tail)
+		return err
+	})
+	return lines, err
+}
+
+func (i *intercepted) TruncateLogs(ctx context.Context, id string) (freed int64, err error) {
+	err = i.around(ctx, "TruncateLogs", func(ctx context.Context) error {
+		freed, err = i.Provider.TruncateLogs(ctx, id)
+		return err
+	})
+	return freed, err
+}
+
+func (i *intercepted) ContainerStats(ctx context.Context) (stats []domain.ContainerStats, err error) {
+	err = i.around(ctx, "ContainerStats", func(ctx context.Context) error {
+		stats, err = i.Provider.ContainerStats(ctx)
+		return err
+	})
+	return stats, err
+}
+
+func (i *intercepted) ListImages(ctx context.Context) (imgs []domain.Image, err error) {
+	err = i.around(ctx, "ListImages", func(ctx context.Context) error {
+		imgs, err = i.Provider.ListImages(ctx)
+		return err
+	})
+	return imgs, err
+}
+
+func (i *intercepted) PullImage(ctx context.Context, ref string, progress func(domain.PullProgress)) error {
+	return i.around(ctx, "PullImage", func(ctx context.Context) error {
+		return i.Provider.PullImage(ctx, ref, progress)
+	})
+}
+
+func (i *intercepted) RemoveImage(ctx context.Context, ref string) error {
+	return i.around(ctx, "RemoveImage", func(ctx context.Context) error {
+		return i.Provider.RemoveImage(ctx, ref)
+	})
+}
+
+func (i *intercepted) ListNetworks(ctx context.Context) (ns []domain.Network, err error) {
+	err = i.around(ctx, "ListNetworks", func(ctx context.Context) error {
+		ns, err = i.Provider.ListNetworks(ctx)
+		return err
+	})
+	return ns, err
+}
+
+func (i *intercepted) RemoveNetwork(ctx context.Context, id string) error {
+	return i.around(ctx, "RemoveNetwork", func(ctx context.Context) error {
+		return i.Provider.RemoveNetwork(ctx, id)
+	})
+}
+
+func (i *intercepted) Events(ctx context.Context, each func(domain.DaemonEvent)) error {
+	return i.around(ctx, "Events", func(ctx context.Context) error {
+		return i.Provider.Events(ctx, each)
+	})
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// OrphanGuard keeps volumes that nothing mounts right now from being
+// flagged orphan when something is likely to come back for them, like a
+// cron job started with `docker run --rm`.
+type OrphanGuard struct {
+	// MinIdle keeps volumes used (or created) more recently than this.
+	MinIdle time.Duration
+	// Labels keeps volumes carrying any of these label keys.
+	Labels []string
+}
+
+// Keeps reports whether g keeps v from being an orphan, and why.
+func (g OrphanGuard) Keeps(v domain.Volume, now time.Time) (string, bool) {
+	for _, k := range g.Labels {
+		if _, ok := v.Labels[k]; ok {
+			return "it carries the " + k + " label", true
+		}
+	}
+	if g.MinIdle > 0 {
+		last := v.LastUsed
+		if v.CreatedAt.After(last) {
+			last = v.CreatedAt
+		}
+		if !last.IsZero() && now.Sub(last) < g.MinIdle {
+			return "it was used or created in the last " + domain.HumanAge(g.MinIdle), true
+		}
+	}
+	return "", false
+}
+
+// GuardOrphans is a middleware marking the orphans g keeps Protected,
+// so nothing downstream — plans, policies, the agent's gate — treats them
+// as orphans.
+func GuardOrphans(g OrphanGuard) Middleware {
+	if g.MinIdle <= 0 && len(g.Labels) == 0 {
+		return nil
+	}
+	return func(prov Provider) Provider { return &guarded{Provider: prov, guard: g} }
+}
+
+type guarded struct {
+	Provider
+	guard OrphanGuard
+}
+
+func (g *guarded) apply(v *domain.Volume) {
+	if _, keep := g.guard.Keeps(*v, time.Now()); v.State == domain.Orphaned && keep {
+		v.State = domain.Protected
+	}
+}
+
+func (g *guarded) ListVolumes(ctx context.Context) ([]domain.Volume, error) {
+	vols, err := g.Provider.ListVolumes(ctx)
+	for i := range vols {
+		g.apply(&vols[i])
+	}
+	return vols, err
+}
+
+// ListVolumesFiltered re-checks the filter, since dangling=true may have
+// let through volumes the guard keeps.
+func (g *guarded) ListVolumesFiltered(ctx context.Context, f domain.VolumeFilter) ([]domain.Volume, error) {
+	vols, err := g.Provider.ListVolumesFiltered(ctx, f)
+	kept := vols[:0]
+	for _, v := range vols {
+		g.apply(&v)
+		if f.Match(v) {
+			kept = append(kept, v)
+		}
+	}
+	return kept, err
+}
+
+func (g *guarded) EnrichVolumes(ctx context.Context, names []string, each func(domain.Volume)) error {
+	return g.Provider.EnrichVolumes(ctx, names, func(v domain.Volume) {
+		g.apply(&v)
+		each(v)
+	})
+}
+
+func (g *guarded) GetVolumeDetails(ctx context.Context, name string) (*domain.Volume, error) {
+	v, err := g.Provider.GetVolumeDetails(ctx, name)
+	if v != nil {
+		g.apply(v)
+	}
+	return v, err
+}
@@ -0,0 +1,48 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/provider"
+	"dockwatch/internal/provider/fake"
+)
+
+func TestGuardOrphans(t *testing.T) {
+	now := time.Now()
+	f := fake.New(
+		domain.Volume{Name: "nightly", LastUsed: now.Add(-20 * time.Hour), CreatedAt: now.Add(-90 * 24 * time.Hour)},
+		domain.Volume{Name: "fresh", CreatedAt: now.Add(-time.Hour)},
+		domain.Volume{Name: "backup", CreatedAt: now.Add(-90 * 24 * time.Hour), Labels: map[string]string{"com.example.schedule": "weekly"}},
+		domain.Volume{Name: "stale", LastUsed: now.Add(-30 * 24 * time.Hour)},
+		domain.Volume{Name: "unknown"},
+	)
+	p := provider.Chain(f, provider.GuardOrphans(provider.OrphanGuard{MinIdle: 7 * 24 * time.Hour, Labels: []string{"com.example.schedule"}}))
+
+	vols, err := p.ListVolumes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"nightly": false, "fresh": false, "backup": false, "stale": true, "unknown": true}
+	for _, v := range vols {
+		if v.Orphan() != want[v.Name] {
+			t.Errorf("%s: state = %v, want orphan %v", v.Name, v.State, want[v.Name])
+		}
+	}
+
+	dangling, err := p.ListVolumesFiltered(context.Background(), domain.VolumeFilter{"dangling": {"true"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dangling) != 2 {
+		t.Errorf("dangling=true listed %d volumes, want stale and unknown", len(dangling))
+	}
+}
+
+func TestGuardOrphansOff(t *testing.T) {
+	if provider.GuardOrphans(provider.OrphanGuard{}) != nil {
+		t.Error("an empty guard should add no middleware")
+	}
+}
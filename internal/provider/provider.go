@@ -8,7 +8,23 @@ import (
 // Provider defines the interface for volume data providers
 type Provider interface {
 	ListVolumes(ctx context.Context) ([]domain.Volume, error)
+	ListVolumesBasic(ctx context.Context) ([]domain.Volume, error)
+	ListVolumesFiltered(ctx context.Context, f domain.VolumeFilter) ([]domain.Volume, error)
+	EnrichVolumes(ctx context.Context, names []string, each func(domain.Volume)) error
 	GetVolumeDetails(ctx context.Context, name string) (*domain.Volume, error)
 	RemoveVolume(ctx context.Context, name string) error
+	ListContainers(ctx context.Context) ([]domain.Container, error)
+	RemoveContainer(ctx context.Context, id string, removeVolumes bool) error
+	ContainerLogs(ctx context.Context, id string, tail int) ([]string, error)
+	// TruncateLogs empties a container's json-file log, returning the bytes freed.
+	TruncateLogs(ctx context.Context, id string) (int64, error)
+	ContainerStats(ctx context.Context) ([]domain.ContainerStats, error)
+	ListImages(ctx context.Context) ([]domain.Image, error)
+	PullImage(ctx context.Context, ref string, progress func(domain.PullProgress)) error
+	RemoveImage(ctx context.Context, ref string) error
+	ListNetworks(ctx context.Context) ([]domain.Network, error)
+	RemoveNetwork(ctx context.Context, id string) error
+	// Events streams volume and container events until ctx is cancelled.
+	Events(ctx context.Context, each func(domain.DaemonEvent)) error
 	Close() error
 }
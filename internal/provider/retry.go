@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// RetryPolicy bounds how often and how patiently a read is retried.
+type RetryPolicy struct {
+	Attempts int           // total tries, including the first
+	Initial  time.Duration // wait before the first retry, doubled each time
+	Max      time.Duration // longest wait between tries
+}
+
+// DefaultRetry rides out a daemon restart of a few seconds.
+var DefaultRetry = RetryPolicy{Attempts: 5, Initial: 250 * time.Millisecond, Max: 4 * time.Second}
+
+// RetryStatus reports a retried call: once before every retry, then with
+// Done set when the call finally succeeded or gave up.
+type RetryStatus struct {
+	Op      string
+	Attempt int // the try about to start, from 2
+	Of      int
+	Err     error // the error that caused the retry, or the final one
+	Done    bool
+}
+
+// readOps are the calls safe to repeat. Removals might have gone through
+// before the connection dropped, and streams would replay their callbacks.
+var readOps = map[string]bool{
+	"ListVolumes": true, "ListVolumesBasic": true, "ListVolumesFiltered": true, "GetVolumeDetails": true,
+	"ListContainers": true, "ContainerLogs": true, "ContainerStats": true,
+	"ListImages": true, "ListNetworks": true,
+}
+
+// Retry retries reads that fail with a transient error (see IsTransient)
+// with exponential backoff. notify, if not nil, hears about each retry.
+func Retry(p RetryPolicy, notify func(RetryStatus)) Middleware {
+	if notify == nil {
+		notify = func(RetryStatus) {}
+	}
+	return Intercept(func(ctx context.Context, op string, call func(context.Context) error) error {
+		if !readOps[op] {
+			return call(ctx)
+		}
+		wait := p.Initial
+		for attempt := 1; ; attempt++ {
+			err := call(ctx)
+			if err == nil || attempt >= p.Attempts || !IsTransient(err) {
+				if attempt > 1 {
+					notify(RetryStatus{Op: op, Attempt: attempt, Of: p.Attempts, Err: err, Done: true})
+				}
+				return err
+			}
+			notify(RetryStatus{Op: op, Attempt: attempt + 1, Of: p.Attempts, Err: err})
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(wait):
+			}
+			wait = min(wait*2, p.Max)
+		}
+	})
+}
+
+// transientMessages are fragments of docker CLI and agent errors that mean
+// the daemon was briefly unreachable rather than that the request was wrong.
+var transientMessages = []string{
+	"cannot connect to the docker daemon",
+	"connection refused",
+	"connection reset",
+	"unexpected eof",
+	": eof",
+	"500 internal server error",
+	"502 bad gateway",
+	"503 service unavailable",
+	"status code 500",
+	"status code 502",
+	"status code 503",
+	"i/o timeout",
+}
+
+// IsTransient reports whether err looks like a hiccup worth retrying: an
+// EOF or refused/reset connection (e.g. while the daemon restarts) or a
+// 5xx from the daemon or agent. Cancellation never is.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, domain.ErrDaemonUnavailable) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	// The CLI's reason is on its stderr, not in "exit status 1"
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		msg += " " + strings.ToLower(string(exitErr.Stderr))
+	}
+	for _, m := range transientMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
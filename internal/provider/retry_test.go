@@ -0,0 +1,74 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/provider"
+	"dockwatch/internal/provider/fake"
+)
+
+var quick = provider.RetryPolicy{Attempts: 3, Initial: time.Millisecond, Max: time.Millisecond}
+
+func TestRetryRidesOutTransientErrors(t *testing.T) {
+	f := fake.New(domain.Volume{Name: "data"})
+	f.FailNext("ListVolumes", io.EOF, domain.ErrDaemonUnavailable)
+	var statuses []provider.RetryStatus
+	p := provider.Chain(f, provider.Retry(quick, func(s provider.RetryStatus) { statuses = append(statuses, s) }))
+
+	vols, err := p.ListVolumes(context.Background())
+	if err != nil || len(vols) != 1 {
+		t.Fatalf("ListVolumes = %v, %v; want the volume after two retries", vols, err)
+	}
+	if len(statuses) != 3 || !statuses[2].Done || statuses[2].Err != nil {
+		t.Errorf("statuses = %+v, want two retries and a successful finish", statuses)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	f := fake.New()
+	f.Fail("ListVolumes", io.EOF)
+	p := provider.Chain(f, provider.Retry(quick, nil))
+
+	if _, err := p.ListVolumes(context.Background()); !errors.Is(err, io.EOF) {
+		t.Errorf("err = %v, want the last EOF", err)
+	}
+	if n := len(f.Calls("ListVolumes")); n != quick.Attempts {
+		t.Errorf("tried %d times, want %d", n, quick.Attempts)
+	}
+}
+
+func TestRetryLeavesPermanentErrorsAndWrites(t *testing.T) {
+	f := fake.New(domain.Volume{Name: "data"})
+	f.Fail("GetVolumeDetails", &domain.KindError{Kind: domain.ErrPermission, Msg: "permission denied"})
+	f.FailNext("RemoveVolume", io.EOF)
+	p := provider.Chain(f, provider.Retry(quick, nil))
+
+	if _, err := p.GetVolumeDetails(context.Background(), "data"); !errors.Is(err, domain.ErrPermission) {
+		t.Errorf("GetVolumeDetails err = %v, want ErrPermission", err)
+	}
+	if err := p.RemoveVolume(context.Background(), "data"); !errors.Is(err, io.EOF) {
+		t.Errorf("RemoveVolume err = %v, want EOF unretried", err)
+	}
+	if n := len(f.Calls("GetVolumeDetails")) + len(f.Calls("RemoveVolume")); n != 2 {
+		t.Errorf("made %d calls, want one each", n)
+	}
+}
+
+func TestRetryStopsOnCancel(t *testing.T) {
+	f := fake.New()
+	f.Fail("ListVolumes", io.EOF)
+	ctx, cancel := context.WithCancel(context.Background())
+	p := provider.Chain(f, provider.Retry(provider.RetryPolicy{Attempts: 5, Initial: time.Hour, Max: time.Hour}, func(provider.RetryStatus) { cancel() }))
+
+	if _, err := p.ListVolumes(ctx); err == nil {
+		t.Error("cancelled retry reported success")
+	}
+	if n := len(f.Calls("ListVolumes")); n != 1 {
+		t.Errorf("tried %d times after cancel, want 1", n)
+	}
+}
@@ -0,0 +1,82 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// dockerConfig is the subset of ~/.docker/config.json used for auth.
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth string `json:"auth"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+func dockerConfigPath() string {
+	if d := os.Getenv("DOCKER_CONFIG"); d != "" {
+		return filepath.Join(d, "config.json")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// Credentials returns the stored username/secret for the registry hosting ref.
+func Credentials(ref string) (user, secret string) {
+	return credentials(ParseRef(ref).authKey())
+}
+
+// credentials looks up a username/secret for the registry key the same way
+// the docker CLI does: per-registry helper, then global store, then the
+// inline base64 auth. Anonymous access is used when nothing is found.
+func credentials(key string) (user, secret string) {
+	data, err := os.ReadFile(dockerConfigPath())
+	if err != nil {
+		return "", ""
+	}
+	var cfg dockerConfig
+	if json.Unmarshal(data, &cfg) != nil {
+		return "", ""
+	}
+
+	if helper := cfg.CredHelpers[key]; helper != "" {
+		return fromHelper(helper, key)
+	}
+	if cfg.CredsStore != "" {
+		if u, s := fromHelper(cfg.CredsStore, key); u != "" {
+			return u, s
+		}
+	}
+	if a, ok := cfg.Auths[key]; ok && a.Auth != "" {
+		raw, err := base64.StdEncoding.DecodeString(a.Auth)
+		if err == nil {
+			u, s, _ := strings.Cut(string(raw), ":")
+			return u, s
+		}
+	}
+	return "", ""
+}
+
+// fromHelper runs docker-credential-<helper> get.
+func fromHelper(helper, key string) (string, string) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(key)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", ""
+	}
+	var creds struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if json.Unmarshal(bytes.TrimSpace(out), &creds) != nil {
+		return "", ""
+	}
+	return creds.Username, creds.Secret
+}
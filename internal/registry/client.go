@@ -0,0 +1,145 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotFound means the registry does not have the tag (the image is local-only).
+var ErrNotFound = errors.New("tag not found in registry")
+
+// manifestTypes are accepted so that multi-arch indexes resolve too.
+var manifestTypes = strings.Join([]string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}, ", ")
+
+// Client queries registries over the v2 HTTP API.
+type Client struct {
+	HTTP *http.Client
+}
+
+// NewClient returns a client with a conservative timeout.
+func NewClient() *Client {
+	return &Client{HTTP: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Digest returns the registry's current manifest digest for ref, or
+// ErrNotFound if the tag doesn't exist upstream.
+func (c *Client) Digest(ctx context.Context, ref string) (string, error) {
+	r := ParseRef(ref)
+	u := fmt.Sprintf("https://%s/v2/%s/manifests/%s", r.Registry, r.Repository, r.Tag)
+
+	resp, err := c.head(ctx, u, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := c.token(ctx, r, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return "", err
+		}
+		if resp, err = c.head(ctx, u, token); err != nil {
+			return "", err
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Header.Get("Docker-Content-Digest"), nil
+	case http.StatusNotFound:
+		return "", ErrNotFound
+	default:
+		return "", fmt.Errorf("registry %s: %s", r.Registry, resp.Status)
+	}
+}
+
+func (c *Client) head(ctx context.Context, u, auth string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", manifestTypes)
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// token answers a 401 challenge. Bearer challenges are exchanged for a
+// token (with credentials if we have any); Basic challenges use the
+// credentials directly. The result is a ready Authorization header value.
+func (c *Client) token(ctx context.Context, r Reference, challenge string) (string, error) {
+	user, secret := credentials(r.authKey())
+	scheme, params := parseChallenge(challenge)
+
+	switch scheme {
+	case "basic":
+		if user == "" {
+			return "", fmt.Errorf("registry %s requires credentials", r.Registry)
+		}
+		req, _ := http.NewRequest(http.MethodGet, "", nil)
+		req.SetBasicAuth(user, secret)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("registry %s: unsupported auth challenge %q", r.Registry, challenge)
+	}
+
+	q := url.Values{}
+	q.Set("service", params["service"])
+	q.Set("scope", "repository:"+r.Repository+":pull")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, params["realm"]+"?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if user != "" {
+		req.SetBasicAuth(user, secret)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry %s token: %s", r.Registry, resp.Status)
+	}
+
+	var tok struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("registry %s token: %w", r.Registry, err)
+	}
+	if tok.Token == "" {
+		tok.Token = tok.AccessToken
+	}
+	return "Bearer " + tok.Token, nil
+}
+
+// parseChallenge splits `Bearer realm="...",service="..."`.
+func parseChallenge(h string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(h, " ")
+	params := map[string]string{}
+	for _, part := range strings.Split(rest, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			params[k] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToLower(scheme), params
+}
@@ -0,0 +1,42 @@
+package registry
+
+import "strings"
+
+const dockerHub = "registry-1.docker.io"
+
+// Reference is an image reference split into its registry parts.
+type Reference struct {
+	Registry   string // e.g. "registry-1.docker.io", "ghcr.io"
+	Repository string // e.g. "library/nginx"
+	Tag        string
+}
+
+// ParseRef splits "repo[:tag]" the way docker does: the first path
+// component is a registry host only if it contains "." or ":" or is
+// "localhost"; otherwise the image lives on Docker Hub.
+func ParseRef(ref string) Reference {
+	r := Reference{Registry: dockerHub, Tag: "latest"}
+
+	name := ref
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		name, r.Tag = ref[:i], ref[i+1:]
+	}
+
+	if first, rest, ok := strings.Cut(name, "/"); ok &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		r.Registry, name = first, rest
+	}
+	if r.Registry == dockerHub && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	r.Repository = name
+	return r
+}
+
+// authKey is the key docker uses for this registry in config.json.
+func (r Reference) authKey() string {
+	if r.Registry == dockerHub {
+		return "https://index.docker.io/v1/"
+	}
+	return r.Registry
+}
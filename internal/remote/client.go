@@ -0,0 +1,278 @@
+package remote
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// requestTimeout bounds a whole request to the agent, so one that hangs
+// doesn't hang the app. It is generous because listing sizes on a big host
+// is slow. Streams have no overall bound, only connecting to the agent does.
+const (
+	requestTimeout = 5 * time.Minute
+	connectTimeout = 10 * time.Second
+)
+
+// Client implements provider.Provider against a `dockwatch serve` agent.
+type Client struct {
+	base    string
+	token   string
+	http    *http.Client // requests, bounded by requestTimeout
+	streams *http.Client // event and progress streams
+}
+
+// NewClient returns a client for the agent at base (e.g. "http://host:8080").
+func NewClient(base, token string) (*Client, error) {
+	u, err := url.Parse(base)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid agent address %q: want http(s)://host:port", base)
+	}
+	if token == "" {
+		return nil, errors.New("an agent token is required (DOCKWATCH_TOKEN)")
+	}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: connectTimeout}).DialContext,
+		TLSHandshakeTimeout:   connectTimeout,
+		MaxIdleConnsPerHost:   4,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+	return &Client{
+		base:    strings.TrimRight(base, "/"),
+		token:   token,
+		http:    &http.Client{Transport: transport, Timeout: requestTimeout},
+		streams: &http.Client{Transport: transport},
+	}, nil
+}
+
+// FromEnv returns a client when $DOCKWATCH_REMOTE names an agent, nil when
+// it is unset and the local daemon should be used.
+func FromEnv() (*Client, error) {
+	addr := os.Getenv("DOCKWATCH_REMOTE")
+	if addr == "" {
+		return nil, nil
+	}
+	return NewClient(addr, os.Getenv("DOCKWATCH_TOKEN"))
+}
+
+// Close is a no-op; connections are pooled by net/http.
+func (c *Client) Close() error { return nil }
+
+// statusKind restores the domain error kind from the status, see the
+// server's kindStatus, for replies that don't name it.
+var statusKind = map[int]error{
+	http.StatusNotFound:           domain.ErrNotFound,
+	http.StatusConflict:           domain.ErrVolumeInUse,
+	http.StatusUnauthorized:       domain.ErrPermission,
+	http.StatusForbidden:          domain.ErrPermission,
+	http.StatusServiceUnavailable: domain.ErrDaemonUnavailable,
+}
+
+// replyKind is the kind the agent named, else the one its status implies.
+func replyKind(name string, status int) error {
+	for kind, n := range kindNames {
+		if n == name {
+			return kind
+		}
+	}
+	return statusKind[status]
+}
+
+// do sends a request and returns the response, turning non-2xx replies into
+// errors carrying the agent's message.
+func (c *Client) do(ctx context.Context, hc *http.Client, method, path string, body any) (*http.Response, error) {
+	var rd io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		rd = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, rd)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, &domain.KindError{Kind: domain.ErrDaemonUnavailable, Msg: "agent unreachable: " + err.Error(), Err: err}
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var e apiError
+		if json.NewDecoder(resp.Body).Decode(&e) != nil || e.Error == "" {
+			e.Error = resp.Status
+		}
+		return nil, &domain.KindError{Kind: replyKind(e.Kind, resp.StatusCode), Msg: "agent: " + e.Error}
+	}
+	return resp, nil
+}
+
+// get decodes a JSON response into out.
+func (c *Client) get(ctx context.Context, path string, out any) error {
+	resp, err := c.do(ctx, c.http, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) remove(ctx context.Context, path string) error {
+	resp, err := c.do(ctx, c.http, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// stream reads NDJSON events until the final one, returning its error.
+func (c *Client) stream(ctx context.Context, method, path string, body any, each func(streamEvent)) error {
+	resp, err := c.do(ctx, c.streams, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var ev streamEvent
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			return fmt.Errorf("agent sent a malformed event: %w", err)
+		}
+		if ev.Done {
+			if ev.Error != "" {
+				return fmt.Errorf("agent: %s", ev.Error)
+			}
+			return nil
+		}
+		each(ev)
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return errors.New("agent closed the stream early")
+}
+
+func (c *Client) ListVolumes(ctx context.Context) ([]domain.Volume, error) {
+	var vols []domain.Volume
+	return vols, c.get(ctx, "/v1/volumes", &vols)
+}
+
+func (c *Client) ListVolumesBasic(ctx context.Context) ([]domain.Volume, error) {
+	var vols []domain.Volume
+	return vols, c.get(ctx, "/v1/volumes/basic", &vols)
+}
+
+func (c *Client) ListVolumesFiltered(ctx context.Context, f domain.VolumeFilter) ([]domain.Volume, error) {
+	q := url.Values{"filter": f.Args()}
+	var vols []domain.Volume
+	return vols, c.get(ctx, "/v1/volumes?"+q.Encode(), &vols)
+}
+
+func (c *Client) EnrichVolumes(ctx context.Context, names []string, each func(domain.Volume)) error {
+	return c.stream(ctx, http.MethodPost, "/v1/volumes/enrich", names, func(ev streamEvent) {
+		if ev.Volume != nil {
+			each(*ev.Volume)
+		}
+	})
+}
+
+func (c *Client) GetVolumeDetails(ctx context.Context, name string) (*domain.Volume, error) {
+	var v domain.Volume
+	if err := c.get(ctx, "/v1/volumes/"+url.PathEscape(name), &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (c *Client) RemoveVolume(ctx context.Context, name string) error {
+	return c.remove(ctx, "/v1/volumes/"+url.PathEscape(name))
+}
+
+func (c *Client) ListContainers(ctx context.Context) ([]domain.Container, error) {
+	var cs []domain.Container
+	return cs, c.get(ctx, "/v1/containers", &cs)
+}
+
+func (c *Client) RemoveContainer(ctx context.Context, id string, removeVolumes bool) error {
+	return c.remove(ctx, "/v1/containers/"+url.PathEscape(id)+"?volumes="+strconv.FormatBool(removeVolumes))
+}
+
+func (c *Client) ContainerLogs(ctx context.Context, id string, tail int) ([]string, error) {
+	var lines []string
+	return lines, c.get(ctx, "/v1/containers/"+url.PathEscape(id)+"/logs?tail="+strconv.Itoa(tail), &lines)
+}
+
+func (c *Client) TruncateLogs(ctx context.Context, id string) (int64, error) {
+	resp, err := c.do(ctx, c.http, http.MethodPost, "/v1/containers/"+url.PathEscape(id)+"/logs/truncate", nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	var res truncateResult
+	return res.Freed, json.NewDecoder(resp.Body).Decode(&res)
+}
+
+func (c *Client) ContainerStats(ctx context.Context) ([]domain.ContainerStats, error) {
+	var stats []domain.ContainerStats
+	return stats, c.get(ctx, "/v1/stats", &stats)
+}
+
+func (c *Client) ListImages(ctx context.Context) ([]domain.Image, error) {
+	var imgs []domain.Image
+	return imgs, c.get(ctx, "/v1/images", &imgs)
+}
+
+func (c *Client) PullImage(ctx context.Context, ref string, progress func(domain.PullProgress)) error {
+	return c.stream(ctx, http.MethodPost, "/v1/images/pull?ref="+url.QueryEscape(ref), nil, func(ev streamEvent) {
+		if ev.Progress != nil {
+			progress(*ev.Progress)
+		}
+	})
+}
+
+func (c *Client) RemoveImage(ctx context.Context, ref string) error {
+	return c.remove(ctx, "/v1/images?ref="+url.QueryEscape(ref))
+}
+
+func (c *Client) ListNetworks(ctx context.Context) ([]domain.Network, error) {
+	var ns []domain.Network
+	return ns, c.get(ctx, "/v1/networks", &ns)
+}
+
+func (c *Client) RemoveNetwork(ctx context.Context, id string) error {
+	return c.remove(ctx, "/v1/networks/"+url.PathEscape(id))
+}
+
+// Events follows the agent's event stream; cancelling ctx ends it cleanly.
+func (c *Client) Events(ctx context.Context, each func(domain.DaemonEvent)) error {
+	err := c.stream(ctx, http.MethodGet, "/v1/events", nil, func(ev streamEvent) {
+		if ev.Event != nil {
+			each(*ev.Event)
+		}
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/provider"
+	"dockwatch/internal/provider/fake"
+)
+
+func TestErrorKindsSurviveTheAgent(t *testing.T) {
+	p := fake.New(domain.Volume{Name: "db"})
+	p.Networks = []domain.Network{{ID: "n1", Name: "backend", Containers: []string{"api"}}}
+	p.Containers = []domain.Container{{ID: "c1", Name: "api", Mounts: []domain.Mount{{Type: "volume", Name: "db"}}}}
+	srv := httptest.NewServer(NewServer(p, []Token{{Name: "ops", Secret: "s3cret", Permission: provider.PermPruneAny}}))
+	defer srv.Close()
+	c, err := NewClient(srv.URL, "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"volume in use", c.RemoveVolume(ctx, "db"), domain.ErrVolumeInUse},
+		{"network in use", c.RemoveNetwork(ctx, "backend"), domain.ErrNetworkInUse},
+		{"missing network", c.RemoveNetwork(ctx, "frontend"), domain.ErrNotFound},
+	} {
+		if !errors.Is(tc.err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, tc.err, tc.want)
+		}
+	}
+}
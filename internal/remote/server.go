@@ -0,0 +1,308 @@
+// Package remote exposes a Provider over a small authenticated HTTP API and
+// provides a client Provider that talks to it, so the TUI can run on one
+// machine against an agent next to the daemon without exposing the socket.
+package remote
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/provider"
+)
+
+// Server serves prov's operations under /v1.
+type Server struct {
+	tokens []Token
+	gated  []provider.Provider // tokens[i]'s view of the provider
+	mux    *http.ServeMux
+}
+
+// callerKey carries the authenticated token index in the request context.
+type callerKey struct{}
+
+// NewServer returns a handler for prov that requires one of tokens as a
+// bearer token on every request and limits each to its permission.
+func NewServer(prov provider.Provider, tokens []Token) *Server {
+	s := &Server{tokens: tokens, mux: http.NewServeMux()}
+	for _, t := range tokens {
+		s.gated = append(s.gated, provider.Chain(prov, provider.WithGate(t.Permission)))
+	}
+
+	s.mux.HandleFunc("GET /v1/volumes", s.listVolumes)
+	s.mux.HandleFunc("GET /v1/volumes/basic", s.listVolumesBasic)
+	s.mux.HandleFunc("POST /v1/volumes/enrich", s.enrichVolumes)
+	s.mux.HandleFunc("GET /v1/volumes/{name}", s.getVolume)
+	s.mux.HandleFunc("DELETE /v1/volumes/{name}", s.removeVolume)
+
+	s.mux.HandleFunc("GET /v1/containers", s.listContainers)
+	s.mux.HandleFunc("DELETE /v1/containers/{id}", s.removeContainer)
+	s.mux.HandleFunc("GET /v1/containers/{id}/logs", s.containerLogs)
+	s.mux.HandleFunc("POST /v1/containers/{id}/logs/truncate", s.truncateLogs)
+	s.mux.HandleFunc("GET /v1/stats", s.containerStats)
+
+	// Image refs contain slashes and colons, so they travel as a query parameter
+	s.mux.HandleFunc("GET /v1/images", s.listImages)
+	s.mux.HandleFunc("POST /v1/images/pull", s.pullImage)
+	s.mux.HandleFunc("DELETE /v1/images", s.removeImage)
+
+	s.mux.HandleFunc("GET /v1/networks", s.listNetworks)
+	s.mux.HandleFunc("DELETE /v1/networks/{id}", s.removeNetwork)
+
+	s.mux.HandleFunc("GET /v1/events", s.events)
+	return s
+}
+
+// WithMetrics serves m's per-operation call stats at GET /v1/metrics.
+func (s *Server) WithMetrics(m *provider.Metrics) *Server {
+	s.mux.HandleFunc("GET /v1/metrics", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, m.Snapshot())
+	})
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	caller := -1
+	for i, t := range s.tokens {
+		if ok && subtle.ConstantTimeCompare([]byte(got), []byte(t.Secret)) == 1 {
+			caller = i
+		}
+	}
+	if caller < 0 {
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+		return
+	}
+	if r.Method == http.MethodDelete || r.URL.Path == "/v1/images/pull" || strings.HasSuffix(r.URL.Path, "/logs/truncate") {
+		log.Printf("%s: %s %s", s.tokens[caller].Name, r.Method, r.URL.RequestURI())
+	}
+	s.mux.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), callerKey{}, caller)))
+}
+
+// prov returns the provider as seen by the request's token.
+func (s *Server) prov(r *http.Request) provider.Provider {
+	return s.gated[r.Context().Value(callerKey{}).(int)]
+}
+
+// apiError is the body of every non-2xx response. Kind names the domain
+// error kind, which the status alone can't tell apart for the two in-use
+// conflicts.
+type apiError struct {
+	Error string `json:"error"`
+	Kind  string `json:"kind,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(apiError{Error: err.Error(), Kind: kindNames[domain.ErrorKind(err)]})
+}
+
+// kindNames are the kinds as they travel in apiError.
+var kindNames = map[error]string{
+	domain.ErrNotFound:          "not-found",
+	domain.ErrVolumeInUse:       "volume-in-use",
+	domain.ErrNetworkInUse:      "network-in-use",
+	domain.ErrPermission:        "permission",
+	domain.ErrDaemonUnavailable: "daemon-unavailable",
+}
+
+// kindStatus is the response status for each domain error kind, so the
+// client can restore the kind; unclassified errors come from the daemon
+// and are 502s.
+var kindStatus = map[error]int{
+	domain.ErrNotFound:          http.StatusNotFound,
+	domain.ErrVolumeInUse:       http.StatusConflict,
+	domain.ErrNetworkInUse:      http.StatusConflict,
+	domain.ErrPermission:        http.StatusForbidden,
+	domain.ErrDaemonUnavailable: http.StatusServiceUnavailable,
+}
+
+// reply writes v, or err with the status of its kind: 403 when the token's
+// permission refused it, 502 for errors without a kind.
+func reply(w http.ResponseWriter, v any, err error) {
+	if err != nil {
+		code, ok := kindStatus[domain.ErrorKind(err)]
+		if !ok {
+			code = http.StatusBadGateway
+		}
+		writeError(w, code, err)
+		return
+	}
+	if v == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, v)
+}
+
+// streamEvent is one line of an NDJSON streaming response. The last line
+// of a stream has Done set, with Error when the operation failed.
+type streamEvent struct {
+	Volume   *domain.Volume       `json:"volume,omitempty"`
+	Progress *domain.PullProgress `json:"progress,omitempty"`
+	Event    *domain.DaemonEvent  `json:"event,omitempty"`
+	Done     bool                 `json:"done,omitempty"`
+	Error    string               `json:"error,omitempty"`
+}
+
+// streamer writes NDJSON events, flushing each so the client sees them live.
+type streamer struct {
+	w   http.ResponseWriter
+	enc *json.Encoder
+}
+
+func newStreamer(w http.ResponseWriter) *streamer {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	return &streamer{w: w, enc: json.NewEncoder(w)}
+}
+
+func (s *streamer) send(ev streamEvent) {
+	s.enc.Encode(ev)
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *streamer) done(err error) {
+	ev := streamEvent{Done: true}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	s.send(ev)
+}
+
+// listVolumes takes optional repeated ?filter=key=value parameters.
+func (s *Server) listVolumes(w http.ResponseWriter, r *http.Request) {
+	args := r.URL.Query()["filter"]
+	if len(args) == 0 {
+		vols, err := s.prov(r).ListVolumes(r.Context())
+		reply(w, vols, err)
+		return
+	}
+	f, err := domain.ParseVolumeFilter(args)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	vols, err := s.prov(r).ListVolumesFiltered(r.Context(), f)
+	reply(w, vols, err)
+}
+
+func (s *Server) listVolumesBasic(w http.ResponseWriter, r *http.Request) {
+	vols, err := s.prov(r).ListVolumesBasic(r.Context())
+	reply(w, vols, err)
+}
+
+func (s *Server) enrichVolumes(w http.ResponseWriter, r *http.Request) {
+	var names []string
+	if err := json.NewDecoder(r.Body).Decode(&names); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	st := newStreamer(w)
+	err := s.prov(r).EnrichVolumes(r.Context(), names, func(v domain.Volume) {
+		st.send(streamEvent{Volume: &v})
+	})
+	st.done(err)
+}
+
+func (s *Server) getVolume(w http.ResponseWriter, r *http.Request) {
+	v, err := s.prov(r).GetVolumeDetails(r.Context(), r.PathValue("name"))
+	reply(w, v, err)
+}
+
+func (s *Server) removeVolume(w http.ResponseWriter, r *http.Request) {
+	reply(w, nil, s.prov(r).RemoveVolume(r.Context(), r.PathValue("name")))
+}
+
+func (s *Server) listContainers(w http.ResponseWriter, r *http.Request) {
+	cs, err := s.prov(r).ListContainers(r.Context())
+	reply(w, cs, err)
+}
+
+func (s *Server) removeContainer(w http.ResponseWriter, r *http.Request) {
+	volumes := r.URL.Query().Get("volumes") == "true"
+	reply(w, nil, s.prov(r).RemoveContainer(r.Context(), r.PathValue("id"), volumes))
+}
+
+func (s *Server) containerLogs(w http.ResponseWriter, r *http.Request) {
+	tail, err := strconv.Atoi(r.URL.Query().Get("tail"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("tail must be a number"))
+		return
+	}
+	lines, err := s.prov(r).ContainerLogs(r.Context(), r.PathValue("id"), tail)
+	reply(w, lines, err)
+}
+
+// truncateResult is the body of a log truncation response.
+type truncateResult struct {
+	Freed int64 `json:"freed"`
+}
+
+func (s *Server) truncateLogs(w http.ResponseWriter, r *http.Request) {
+	freed, err := s.prov(r).TruncateLogs(r.Context(), r.PathValue("id"))
+	reply(w, truncateResult{Freed: freed}, err)
+}
+
+func (s *Server) containerStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.prov(r).ContainerStats(r.Context())
+	reply(w, stats, err)
+}
+
+func (s *Server) listImages(w http.ResponseWriter, r *http.Request) {
+	imgs, err := s.prov(r).ListImages(r.Context())
+	reply(w, imgs, err)
+}
+
+func (s *Server) pullImage(w http.ResponseWriter, r *http.Request) {
+	ref := r.URL.Query().Get("ref")
+	if ref == "" {
+		writeError(w, http.StatusBadRequest, errors.New("ref is required"))
+		return
+	}
+	st := newStreamer(w)
+	err := s.prov(r).PullImage(r.Context(), ref, func(p domain.PullProgress) {
+		st.send(streamEvent{Progress: &p})
+	})
+	st.done(err)
+}
+
+func (s *Server) removeImage(w http.ResponseWriter, r *http.Request) {
+	ref := r.URL.Query().Get("ref")
+	if ref == "" {
+		writeError(w, http.StatusBadRequest, errors.New("ref is required"))
+		return
+	}
+	reply(w, nil, s.prov(r).RemoveImage(r.Context(), ref))
+}
+
+func (s *Server) listNetworks(w http.ResponseWriter, r *http.Request) {
+	ns, err := s.prov(r).ListNetworks(r.Context())
+	reply(w, ns, err)
+}
+
+func (s *Server) removeNetwork(w http.ResponseWriter, r *http.Request) {
+	reply(w, nil, s.prov(r).RemoveNetwork(r.Context(), r.PathValue("id")))
+}
+
+// events streams daemon events until the client goes away.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	st := newStreamer(w)
+	err := s.prov(r).Events(r.Context(), func(ev domain.DaemonEvent) {
+		st.send(streamEvent{Event: &ev})
+	})
+	st.done(err)
+}
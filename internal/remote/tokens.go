@@ -0,0 +1,54 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"dockwatch/internal/provider"
+)
+
+// Token is one credential accepted by the agent and what it may do.
+type Token struct {
+	Name       string              `yaml:"name"`
+	Secret     string              `yaml:"token"`
+	Permission provider.Permission `yaml:"permission"`
+}
+
+// LoadTokens reads a tokens file:
+//
+//	tokens:
+//	  - name: dashboard
+//	    token: 9f2c…
+//	    permission: view
+func LoadTokens(file string) ([]Token, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tokens: %w", err)
+	}
+	var doc struct {
+		Tokens []Token `yaml:"tokens"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse tokens: %w", err)
+	}
+	if len(doc.Tokens) == 0 {
+		return nil, errors.New("tokens file lists no tokens")
+	}
+	seen := map[string]bool{}
+	for i, t := range doc.Tokens {
+		if t.Secret == "" {
+			return nil, fmt.Errorf("token %d (%s): token is required", i+1, t.Name)
+		}
+		if seen[t.Secret] {
+			return nil, fmt.Errorf("token %d (%s): duplicate token", i+1, t.Name)
+		}
+		seen[t.Secret] = true
+		if _, err := provider.ParsePermission(string(t.Permission)); err != nil {
+			return nil, fmt.Errorf("token %d (%s): %w", i+1, t.Name, err)
+		}
+	}
+	return doc.Tokens, nil
+}
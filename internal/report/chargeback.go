@@ -0,0 +1,127 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"dockwatch/internal/domain"
+)
+
+// Unlabelled is the owner of resources nobody could be found for.
+const Unlabelled = "(unlabelled)"
+
+// OwnerUsage is the disk usage attributed to one owner.
+type OwnerUsage struct {
+	Owner       string
+	Volumes     int
+	VolumeBytes int64
+	Images      int
+	ImageBytes  int64
+}
+
+// TotalBytes is what the owner is charged for.
+func (o OwnerUsage) TotalBytes() int64 { return o.VolumeBytes + o.ImageBytes }
+
+// Chargeback attributes volume and image sizes to the value of the label
+// key. A resource without the label is charged to the owner of the first
+// container using it that has one, so volumes created by compose or
+// images pulled by a labelled service still land with their team. Every
+// tag of an image counts once, and layers shared between images are
+// charged to each owner using them. Largest owners come first.
+func Chargeback(vols []domain.Volume, imgs []domain.Image, ctrs []domain.Container, key string) []OwnerUsage {
+	byName := map[string]domain.Container{}
+	for _, c := range ctrs {
+		byName[c.Name] = c
+	}
+	byOwner := map[string]*OwnerUsage{}
+	usage := func(owner string) *OwnerUsage {
+		if owner == "" {
+			owner = Unlabelled
+		}
+		if byOwner[owner] == nil {
+			byOwner[owner] = &OwnerUsage{Owner: owner}
+		}
+		return byOwner[owner]
+	}
+
+	for _, v := range vols {
+		owner := v.Labels[key]
+		for _, name := range v.Attached {
+			if owner != "" {
+				break
+			}
+			owner = byName[name].Labels[key]
+		}
+		u := usage(owner)
+		u.Volumes++
+		u.VolumeBytes += max(v.SizeBytes, 0)
+	}
+
+	seen := map[string]bool{}
+	for _, img := range imgs {
+		if seen[img.ID] {
+			continue
+		}
+		seen[img.ID] = true
+		owner := img.Labels[key]
+		for _, c := range ctrs {
+			if owner != "" {
+				break
+			}
+			if img.ID != "" && strings.HasPrefix(c.ImageID, img.ID) {
+				owner = c.Labels[key]
+			}
+		}
+		u := usage(owner)
+		u.Images++
+		u.ImageBytes += max(img.SizeBytes, 0)
+	}
+
+	owners := make([]OwnerUsage, 0, len(byOwner))
+	for _, u := range byOwner {
+		owners = append(owners, *u)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if owners[i].TotalBytes() != owners[j].TotalBytes() {
+			return owners[i].TotalBytes() > owners[j].TotalBytes()
+		}
+		return owners[i].Owner < owners[j].Owner
+	})
+	return owners
+}
+
+// WriteChargebackTable writes owners as an aligned table with a total row.
+func WriteChargebackTable(w io.Writer, owners []OwnerUsage, key string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\tVOLUMES\tVOLUME SIZE\tIMAGES\tIMAGE SIZE\tTOTAL\n", strings.ToUpper(key))
+	var sum OwnerUsage
+	for _, o := range owners {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%s\t%s\n", o.Owner, o.Volumes, domain.HumanSize(o.VolumeBytes),
+			o.Images, domain.HumanSize(o.ImageBytes), domain.HumanSize(o.TotalBytes()))
+		sum.Volumes += o.Volumes
+		sum.VolumeBytes += o.VolumeBytes
+		sum.Images += o.Images
+		sum.ImageBytes += o.ImageBytes
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\t%s\t%d\t%s\t%s\n", sum.Volumes, domain.HumanSize(sum.VolumeBytes),
+		sum.Images, domain.HumanSize(sum.ImageBytes), domain.HumanSize(sum.TotalBytes()))
+	return tw.Flush()
+}
+
+// WriteChargebackCSV writes owners as CSV with sizes in bytes, for
+// spreadsheets and billing scripts.
+func WriteChargebackCSV(w io.Writer, owners []OwnerUsage, key string) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{key, "volumes", "volume_bytes", "images", "image_bytes", "total_bytes"})
+	for _, o := range owners {
+		cw.Write([]string{o.Owner, strconv.Itoa(o.Volumes), strconv.FormatInt(o.VolumeBytes, 10),
+			strconv.Itoa(o.Images), strconv.FormatInt(o.ImageBytes, 10), strconv.FormatInt(o.TotalBytes(), 10)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,41 @@
+package report
+
+import (
+	"testing"
+
+	"dockwatch/internal/domain"
+)
+
+func TestChargeback(t *testing.T) {
+	ctrs := []domain.Container{
+		{Name: "api", ImageID: "aaa111", Labels: map[string]string{"team": "web"}},
+		{Name: "runner", ImageID: "bbb222"},
+	}
+	vols := []domain.Volume{
+		{Name: "pgdata", SizeBytes: 300, Labels: map[string]string{"team": "data"}},
+		{Name: "uploads", SizeBytes: 100, Attached: []string{"api"}},
+		{Name: "cache", SizeBytes: 50, Attached: []string{"runner"}},
+		{Name: "unsized", SizeBytes: -1, Labels: map[string]string{"team": "data"}},
+	}
+	imgs := []domain.Image{
+		{ID: "aaa", Repository: "api", Tag: "1", SizeBytes: 1000},
+		{ID: "aaa", Repository: "api", Tag: "latest", SizeBytes: 1000},
+		{ID: "bbb", Repository: "runner", Tag: "1", SizeBytes: 20},
+		{ID: "ccc", Repository: "etl", Tag: "1", SizeBytes: 400, Labels: map[string]string{"team": "data"}},
+	}
+
+	got := Chargeback(vols, imgs, ctrs, "team")
+	want := []OwnerUsage{
+		{Owner: "web", Volumes: 1, VolumeBytes: 100, Images: 1, ImageBytes: 1000},
+		{Owner: "data", Volumes: 2, VolumeBytes: 300, Images: 1, ImageBytes: 400},
+		{Owner: Unlabelled, Volumes: 1, VolumeBytes: 50, Images: 1, ImageBytes: 20},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d owners %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("owner %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Package report renders a point-in-time disk usage report of the daemon as
+// Markdown or HTML, for attaching to capacity-planning tickets.
+package report
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/provider"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Format selects the report output.
+type Format string
+
+const (
+	Markdown Format = "md"
+	HTML     Format = "html"
+)
+
+// topN is how many of the largest volumes and images get their own chart.
+const topN = 10
+
+// Report is everything the templates render.
+type Report struct {
+	GeneratedAt time.Time
+	Host        string
+
+	Volumes    []domain.Volume
+	Images     []domain.Image
+	Containers []domain.Container
+
+	Usage      []Bar // disk usage by kind
+	TopVolumes []Bar
+	TopImages  []Bar
+
+	OrphanVolumes     []domain.Volume
+	DanglingImages    []domain.Image
+	ExitedContainers  []domain.Container
+	ReclaimableBytes  int64
+	TotalBytes        int64
+	UnknownSizeVolume int // volumes whose size couldn't be measured
+}
+
+// Bar is one labelled bar of a chart; Percent is relative to the largest
+// bar in the same chart.
+type Bar struct {
+	Label   string
+	Bytes   int64
+	Percent int
+}
+
+// Collect lists volumes, images and containers from prov.
+func Collect(ctx context.Context, prov provider.Provider) (*Report, error) {
+	vols, err := prov.ListVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	imgs, err := prov.ListImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctrs, err := prov.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return New(vols, imgs, ctrs, time.Now()), nil
+}
+
+// New builds a report from already-listed resources.
+func New(vols []domain.Volume, imgs []domain.Image, ctrs []domain.Container, now time.Time) *Report {
+	host, _ := os.Hostname()
+	r := &Report{GeneratedAt: now, Host: host, Volumes: vols, Images: imgs, Containers: ctrs}
+
+	var volBytes, imgBytes, ctrBytes int64
+	var topVols, topImgs []Bar
+	for _, v := range vols {
+		if v.SizeBytes < 0 {
+			r.UnknownSizeVolume++
+		}
+		volBytes += max(v.SizeBytes, 0)
+		topVols = append(topVols, Bar{Label: v.Name, Bytes: max(v.SizeBytes, 0)})
+		if v.Orphan() {
+			r.OrphanVolumes = append(r.OrphanVolumes, v)
+			r.ReclaimableBytes += max(v.SizeBytes, 0)
+		}
+	}
+	seen := map[string]bool{}
+	for _, img := range imgs {
+		// Tags of one image share their bytes; count them once
+		if !seen[img.ID] {
+			seen[img.ID] = true
+			imgBytes += max(img.UniqueSize, 0) + max(img.SharedSize, 0)
+			topImgs = append(topImgs, Bar{Label: img.Ref(), Bytes: img.SizeBytes})
+		}
+		if img.Dangling() {
+			r.DanglingImages = append(r.DanglingImages, img)
+			r.ReclaimableBytes += max(img.UniqueSize, 0)
+		}
+	}
+	for _, c := range ctrs {
+		ctrBytes += max(c.SizeRw, 0)
+		if c.Exited() {
+			r.ExitedContainers = append(r.ExitedContainers, c)
+			r.ReclaimableBytes += max(c.SizeRw, 0)
+		}
+	}
+	r.TotalBytes = volBytes + imgBytes + ctrBytes
+
+	r.Usage = scale([]Bar{
+		{Label: "Volumes", Bytes: volBytes},
+		{Label: "Images", Bytes: imgBytes},
+		{Label: "Containers (writable layers)", Bytes: ctrBytes},
+	})
+	r.TopVolumes = scale(top(topVols))
+	r.TopImages = scale(top(topImgs))
+	return r
+}
+
+// top returns the topN largest bars.
+func top(bars []Bar) []Bar {
+	sort.SliceStable(bars, func(i, j int) bool { return bars[i].Bytes > bars[j].Bytes })
+	return bars[:min(len(bars), topN)]
+}
+
+// scale sets each bar's Percent relative to the largest.
+func scale(bars []Bar) []Bar {
+	var peak int64
+	for _, b := range bars {
+		peak = max(peak, b.Bytes)
+	}
+	for i := range bars {
+		if peak > 0 {
+			bars[i].Percent = int(bars[i].Bytes * 100 / peak)
+		}
+	}
+	return bars
+}
+
+var funcs = map[string]any{
+	"size": domain.HumanSize,
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format("2006-01-02")
+	},
+	"bar": func(percent int) string {
+		// 30 cells wide for Markdown charts
+		return strings.Repeat("█", percent*30/100) + strings.Repeat("░", 30-percent*30/100)
+	},
+	"orNone": func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	},
+	"join": strings.Join,
+	"shortID": func(id string) string {
+		id = strings.TrimPrefix(id, "sha256:")
+		return id[:min(len(id), 12)]
+	},
+}
+
+// Write renders r in the given format.
+func Write(w io.Writer, r *Report, format Format) error {
+	switch format {
+	case Markdown:
+		t, err := texttemplate.New("report.md.tmpl").Funcs(funcs).ParseFS(templates, "templates/report.md.tmpl")
+		if err != nil {
+			return err
+		}
+		return t.Execute(w, r)
+	case HTML:
+		t, err := htmltemplate.New("report.html.tmpl").Funcs(funcs).ParseFS(templates, "templates/report.html.tmpl")
+		if err != nil {
+			return err
+		}
+		return t.Execute(w, r)
+	}
+	return fmt.Errorf("unknown report format %q (want md or html)", format)
+}
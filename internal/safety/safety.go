@@ -0,0 +1,232 @@
+// Package safety moves a curated safety configuration between machines: the
+// ignore list, the protect rules of the cleanup policy and the local volume
+// tags, in one portable YAML file. Importing merges into what is already
+// there and never removes anything.
+package safety
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/notes"
+	"dockwatch/internal/policy"
+)
+
+// Bundle is the portable file:
+//
+//	version: 1
+//	ignore: ["ci-cache", "keep-*"]
+//	protect:
+//	  - name: keep-databases
+//	    match:
+//	      labels: {tier: db}
+//	    action: protect
+//	tags:
+//	  pgdata: [prod, backed-up]
+type Bundle struct {
+	Version int                 `yaml:"version"`
+	Ignore  []string            `yaml:"ignore,omitempty"`
+	Protect []policy.Rule       `yaml:"protect,omitempty"`
+	Tags    map[string][]string `yaml:"tags,omitempty"` // volume name -> tags
+}
+
+// Export collects the bundle from the config, the policy (nil when there is
+// none) and the notes store. Notes stay behind; they are personal.
+func Export(cfg *config.Config, pol *policy.Policy, store *notes.Store) Bundle {
+	b := Bundle{Version: 1, Ignore: slices.Clone(cfg.Ignore), Tags: map[string][]string{}}
+	if pol != nil {
+		for _, r := range pol.Rules {
+			if r.Action == policy.ActionProtect {
+				b.Protect = append(b.Protect, r)
+			}
+		}
+	}
+	for name, e := range store.Volumes {
+		if len(e.Tags) > 0 {
+			b.Tags[name] = slices.Clone(e.Tags)
+		}
+	}
+	return b
+}
+
+// Marshal encodes the bundle as YAML.
+func (b Bundle) Marshal() ([]byte, error) {
+	return encode(b)
+}
+
+// encode writes YAML indented like hand-written policies.
+func encode(v any) ([]byte, error) {
+	out := &bytes.Buffer{}
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// Load reads and validates a bundle file.
+func Load(file string) (*Bundle, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates a bundle. Only protect rules are accepted: a
+// shared file that could prune on another machine is not a safety file.
+func Parse(data []byte) (*Bundle, error) {
+	var b Bundle
+	if err := yaml.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse safety file: %w", err)
+	}
+	if b.Version != 1 {
+		return nil, fmt.Errorf("unsupported safety file version %d", b.Version)
+	}
+	for i, r := range b.Protect {
+		if r.Name == "" {
+			return nil, fmt.Errorf("protect[%d]: name is required", i)
+		}
+		if r.Action != policy.ActionProtect {
+			return nil, fmt.Errorf("rule %q: action %q; only protect rules can be imported", r.Name, r.Action)
+		}
+	}
+	// The policy package validates the matchers
+	rules, err := yaml.Marshal(policy.Policy{Version: 1, Rules: b.Protect})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := policy.Parse(rules); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Changes is what an import added, and the rules it left alone because a
+// different local rule already has their name.
+type Changes struct {
+	Ignore   []string
+	Protect  []string
+	Tags     int // tags added across volumes
+	Conflict []string
+}
+
+// Empty reports whether the import changed nothing.
+func (c Changes) Empty() bool {
+	return len(c.Ignore)+len(c.Protect)+c.Tags == 0
+}
+
+// Merge adds what the bundle has and the local configuration lacks, without
+// saving anything. pol may be nil when there is no policy file yet.
+func (b Bundle) Merge(cfg *config.Config, pol *policy.Policy, store *notes.Store) Changes {
+	var ch Changes
+	for _, pat := range b.Ignore {
+		if !slices.Contains(cfg.Ignore, pat) {
+			cfg.Ignore = append(cfg.Ignore, pat)
+			ch.Ignore = append(ch.Ignore, pat)
+		}
+	}
+	for _, r := range b.Protect {
+		i := -1
+		if pol != nil {
+			i = slices.IndexFunc(pol.Rules, func(l policy.Rule) bool { return l.Name == r.Name })
+		}
+		switch {
+		case i < 0:
+			ch.Protect = append(ch.Protect, r.Name)
+		case !sameRule(pol.Rules[i], r):
+			ch.Conflict = append(ch.Conflict, r.Name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(b.Tags)) {
+		have := store.Volumes[name].Tags
+		tags := slices.Clone(have)
+		for _, t := range b.Tags[name] {
+			if !slices.Contains(tags, t) {
+				tags = append(tags, t)
+			}
+		}
+		store.SetTags(name, tags)
+		ch.Tags += len(store.Volumes[name].Tags) - len(have)
+	}
+	return ch
+}
+
+// sameRule compares rules as they are written, since loading fills in
+// fields of its own.
+func sameRule(a, b policy.Rule) bool {
+	x, errX := yaml.Marshal(a)
+	y, errY := yaml.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
+
+// AppendRules adds the named protect rules of the bundle to the policy
+// file, creating it if needed. The file is edited as a YAML document, so
+// its comments and layout survive.
+func (b Bundle) AppendRules(file string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	var doc yaml.Node
+	data, err := os.ReadFile(file)
+	switch {
+	case errors.Is(err, os.ErrNotExist), err == nil && len(bytes.TrimSpace(data)) == 0:
+		data = []byte("version: 1\n")
+	case err != nil:
+		return fmt.Errorf("failed to read policy: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse policy: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("policy %s is not a YAML mapping", file)
+	}
+	top := doc.Content[0]
+	rules := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	i := -1
+	for k := 0; k+1 < len(top.Content); k += 2 {
+		if top.Content[k].Value == "rules" {
+			i = k
+		}
+	}
+	switch {
+	case i < 0:
+		top.Content = append(top.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "rules"}, rules)
+	case top.Content[i+1].Kind == yaml.SequenceNode:
+		rules = top.Content[i+1]
+	case top.Content[i+1].Tag == "!!null":
+		top.Content[i+1] = rules // "rules:" with nothing under it
+	default:
+		return fmt.Errorf("policy %s: rules is not a list", file)
+	}
+	for _, r := range b.Protect {
+		if !slices.Contains(names, r.Name) {
+			continue
+		}
+		var n yaml.Node
+		if err := n.Encode(r); err != nil {
+			return err
+		}
+		rules.Content = append(rules.Content, &n)
+	}
+	out, err := encode(&doc)
+	if err != nil {
+		return err
+	}
+	if _, err := policy.Parse(out); err != nil {
+		return fmt.Errorf("policy would not load after the import: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("failed to create config dir: %w", err)
+	}
+	return os.WriteFile(file, out, 0o644)
+}
@@ -0,0 +1,106 @@
+package safety
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/notes"
+	"dockwatch/internal/policy"
+)
+
+const bundle = `
+version: 1
+ignore: [ci-cache, "keep-*"]
+protect:
+  - name: keep-db
+    match:
+      labels: {tier: db}
+    action: protect
+  - name: keep-certs
+    match: {name: "certs-*"}
+    action: protect
+tags:
+  pgdata: [prod, backed-up]
+`
+
+func TestParseRejectsPruneRules(t *testing.T) {
+	_, err := Parse([]byte("version: 1\nprotect:\n  - name: wipe\n    match: {name: \"*\"}\n    action: prune\n"))
+	if err == nil || !strings.Contains(err.Error(), "only protect rules") {
+		t.Errorf("got %v, want a refusal of the prune rule", err)
+	}
+	if _, err := Parse([]byte("version: 1\nprotect:\n  - name: bad\n    match: {name: \"[\"}\n    action: protect\n")); err == nil {
+		t.Error("accepted a bad pattern")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	b, err := Parse([]byte(bundle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Ignore: []string{"ci-cache"}}
+	pol, err := policy.Parse([]byte(`
+version: 1
+rules:
+  - name: keep-db
+    match:
+      labels: {tier: db}
+    action: protect
+  - name: keep-certs
+    match: {name: "tls-*"}
+    action: protect
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &notes.Store{Volumes: map[string]notes.Entry{"pgdata": {Note: "mine", Tags: []string{"prod"}}}}
+
+	ch := b.Merge(cfg, pol, store)
+	if !slices.Equal(ch.Ignore, []string{"keep-*"}) || !slices.Equal(cfg.Ignore, []string{"ci-cache", "keep-*"}) {
+		t.Errorf("ignore added %v, now %v", ch.Ignore, cfg.Ignore)
+	}
+	// keep-db is the same rule; keep-certs differs and the local one stays
+	if len(ch.Protect) != 0 || !slices.Equal(ch.Conflict, []string{"keep-certs"}) {
+		t.Errorf("protect added %v, conflicts %v", ch.Protect, ch.Conflict)
+	}
+	if e := store.Volumes["pgdata"]; ch.Tags != 1 || e.Note != "mine" || !slices.Equal(e.Tags, []string{"prod", "backed-up"}) {
+		t.Errorf("%d tag(s) added, pgdata now %+v", ch.Tags, e)
+	}
+}
+
+func TestAppendRulesKeepsComments(t *testing.T) {
+	b, err := Parse([]byte(bundle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(file, []byte("# shared by the platform team\nversion: 1\nrules:\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AppendRules(file, []string{"keep-certs"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(file)
+	if !strings.HasPrefix(string(data), "# shared by the platform team\n") {
+		t.Errorf("comment lost:\n%s", data)
+	}
+	pol, err := policy.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pol.Rules) != 1 || pol.Rules[0].Name != "keep-certs" {
+		t.Errorf("rules %+v, want only keep-certs", pol.Rules)
+	}
+
+	missing := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := b.AppendRules(missing, []string{"keep-db"}); err != nil {
+		t.Fatal(err)
+	}
+	if pol, err := policy.Load(missing); err != nil || len(pol.Rules) != 1 {
+		t.Errorf("new policy %+v, %v", pol, err)
+	}
+}
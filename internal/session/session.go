@@ -0,0 +1,89 @@
+// Package session remembers where the TUI left off — tab, filters, sort,
+// marks and cursor — for each Docker context, so an interrupted cleanup
+// picks up where it stopped on the next launch.
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"dockwatch/internal/config"
+)
+
+// State is one context's session. Volumes and images are kept by name, so
+// a relisting in another order can't move a mark to the wrong row.
+type State struct {
+	Tab           string    `json:"tab,omitempty"`           // tab name, e.g. "Images"
+	View          string    `json:"view,omitempty"`          // saved view in effect
+	Sort          string    `json:"sort,omitempty"`          // as in a saved view, e.g. "size"
+	OlderThanDays int       `json:"olderThanDays,omitempty"` // the A age filter
+	Tag           string    `json:"tag,omitempty"`
+	Project       string    `json:"project,omitempty"`
+	Cursor        string    `json:"cursor,omitempty"` // volume under the cursor
+	Marked        []string  `json:"marked,omitempty"` // marked volumes
+	MarkedImages  []string  `json:"markedImages,omitempty"`
+	Saved         time.Time `json:"saved"`
+}
+
+// file holds the sessions of every context, keyed by engine host.
+type file struct {
+	Contexts map[string]State `json:"contexts"`
+}
+
+// Path is where the sessions are stored.
+func Path() string {
+	return filepath.Join(config.StateDir(), "session.json")
+}
+
+func read() (file, error) {
+	f := file{Contexts: map[string]State{}}
+	data, err := os.ReadFile(Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return f, fmt.Errorf("failed to read session: %w", err)
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("failed to parse session: %w", err)
+	}
+	if f.Contexts == nil {
+		f.Contexts = map[string]State{}
+	}
+	return f, nil
+}
+
+// Load returns the session saved for context; false when there is none.
+func Load(context string) (State, bool, error) {
+	f, err := read()
+	if err != nil {
+		return State{}, false, err
+	}
+	s, ok := f.Contexts[context]
+	return s, ok, nil
+}
+
+// Save replaces the session of context, leaving the others alone.
+func Save(context string, s State) error {
+	f, err := read()
+	if err != nil {
+		// A corrupt file only loses the other contexts' sessions
+		f = file{Contexts: map[string]State{}}
+	}
+	if s.Saved.IsZero() {
+		s.Saved = time.Now()
+	}
+	f.Contexts[context] = s
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.StateDir(), 0o755); err != nil {
+		return fmt.Errorf("failed to create state dir: %w", err)
+	}
+	return os.WriteFile(Path(), data, 0o644)
+}
@@ -0,0 +1,118 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/domain"
+)
+
+// Snapshot records the volume inventory at a point in time.
+type Snapshot struct {
+	TakenAt time.Time `json:"taken_at"`
+	Volumes []Entry   `json:"volumes"`
+}
+
+// Entry is the part of a volume a snapshot remembers.
+type Entry struct {
+	Name      string    `json:"name"`
+	SizeBytes int64     `json:"size_bytes"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// ChangeKind classifies a difference between a snapshot and the live state.
+type ChangeKind string
+
+const (
+	Created ChangeKind = "created"
+	Removed ChangeKind = "removed"
+	Resized ChangeKind = "resized"
+)
+
+// Change is one volume that differs from the snapshot.
+type Change struct {
+	Kind    ChangeKind
+	Name    string
+	OldSize int64 // -1 if unknown or not applicable
+	NewSize int64
+}
+
+// Path is where the latest snapshot is stored.
+func Path() string {
+	return filepath.Join(config.StateDir(), "snapshot.json")
+}
+
+// New captures the given volumes.
+func New(vols []domain.Volume) *Snapshot {
+	s := &Snapshot{TakenAt: time.Now().UTC()}
+	for _, v := range vols {
+		s.Volumes = append(s.Volumes, Entry{Name: v.Name, SizeBytes: v.SizeBytes, CreatedAt: v.CreatedAt})
+	}
+	return s
+}
+
+// Save writes the snapshot, replacing the previous one.
+func (s *Snapshot) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.StateDir(), 0o755); err != nil {
+		return fmt.Errorf("failed to create state dir: %w", err)
+	}
+	return os.WriteFile(Path(), data, 0o644)
+}
+
+// Latest loads the last saved snapshot, or nil if none was taken yet.
+func Latest() (*Snapshot, error) {
+	data, err := os.ReadFile(Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	return &s, nil
+}
+
+// Diff lists volumes created, removed or resized since the snapshot, sorted
+// by kind then name. A volume recreated under the same name counts as created.
+func (s *Snapshot) Diff(current []domain.Volume) []Change {
+	old := make(map[string]Entry, len(s.Volumes))
+	for _, e := range s.Volumes {
+		old[e.Name] = e
+	}
+
+	var changes []Change
+	for _, v := range current {
+		e, ok := old[v.Name]
+		delete(old, v.Name)
+		switch {
+		case !ok, !e.CreatedAt.IsZero() && !v.CreatedAt.Equal(e.CreatedAt):
+			changes = append(changes, Change{Kind: Created, Name: v.Name, OldSize: -1, NewSize: v.SizeBytes})
+		case e.SizeBytes >= 0 && v.SizeBytes >= 0 && e.SizeBytes != v.SizeBytes:
+			changes = append(changes, Change{Kind: Resized, Name: v.Name, OldSize: e.SizeBytes, NewSize: v.SizeBytes})
+		}
+	}
+	for _, e := range old {
+		changes = append(changes, Change{Kind: Removed, Name: e.Name, OldSize: e.SizeBytes, NewSize: -1})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind < changes[j].Kind
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
@@ -0,0 +1,303 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/jobs"
+)
+
+// applyStep is one removal of a prune.
+type applyStep struct {
+	kind  string // steps of one kind may run side by side
+	label string
+	freed int64 // bytes credited when the step succeeds
+	image bool  // image bytes are credited all at once, see runApply
+	run   func(ctx context.Context) error
+}
+
+// applyState tracks a running prune: its progress, and the pause and cancel
+// controls shared with the goroutine doing the removals.
+type applyState struct {
+	events chan tea.Msg
+	cancel context.CancelFunc
+	gate   *pauseGate
+	total  int
+	done   int
+	label  string
+	bar    progress.Model
+}
+
+// applyProgressMsg is sent after each step.
+type applyProgressMsg struct {
+	done  int
+	label string
+}
+
+// pauseGate blocks the apply goroutine between steps while paused.
+type pauseGate struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	paused bool
+}
+
+func newPauseGate() *pauseGate {
+	g := &pauseGate{}
+	g.cond = sync.NewCond(&g.mu)
+	return g
+}
+
+func (g *pauseGate) toggle() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.paused = !g.paused
+	g.cond.Broadcast()
+	return g.paused
+}
+
+func (g *pauseGate) isPaused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.paused
+}
+
+// wait returns once unpaused or ctx is done.
+func (g *pauseGate) wait(ctx context.Context) {
+	stop := context.AfterFunc(ctx, func() {
+		g.mu.Lock()
+		g.cond.Broadcast()
+		g.mu.Unlock()
+	})
+	defer stop()
+	g.mu.Lock()
+	for g.paused && ctx.Err() == nil {
+		g.cond.Wait()
+	}
+	g.mu.Unlock()
+}
+
+// applySteps orders removals: containers first (with their anonymous
+// volumes) so the volumes, images and networks they held are free by the
+// time those are removed.
+func (m model) applySteps(ps pruneSet) []applyStep {
+	prov := m.provider
+	var steps []applyStep
+	for _, c := range ps.containers {
+		steps = append(steps, applyStep{kind: "container", label: "container " + c.Name, freed: max(c.SizeRw, 0), run: func(ctx context.Context) error {
+			return prov.RemoveContainer(ctx, c.ID, true)
+		}})
+	}
+	for _, v := range ps.volumes {
+		steps = append(steps, applyStep{kind: "volume", label: "volume " + v.Name, freed: max(v.SizeBytes, 0), run: func(ctx context.Context) error {
+			if err := prov.RemoveVolume(ctx, v.Name); err != nil {
+				return fmt.Errorf("volume %s: %w", v.Name, err)
+			}
+			return nil
+		}})
+	}
+	for _, img := range ps.images {
+		steps = append(steps, applyStep{kind: "image", label: "image " + img.Ref(), image: true, run: func(ctx context.Context) error {
+			return prov.RemoveImage(ctx, img.Ref())
+		}})
+	}
+	for _, n := range ps.networks {
+		steps = append(steps, applyStep{kind: "network", label: "network " + n.Name, run: func(ctx context.Context) error {
+			return prov.RemoveNetwork(ctx, n.ID)
+		}})
+	}
+	return steps
+}
+
+// startApply queues the prune as a background job, in batches paced by the
+// prune config, streaming progress back like a pull does.
+func (m model) startApply(ps pruneSet, imgReclaim int64, teardown bool) (model, tea.Cmd) {
+	steps := m.applySteps(ps)
+	events := make(chan tea.Msg, 16)
+	gate := newPauseGate()
+	batch, delay := m.cfg.PruneBatch()
+	_, workers, _, _ := m.cfg.Parallelism()
+	started := make(chan struct{})
+	id := m.jobs.Add("prune", fmt.Sprintf("%s %d resource(s)", tern(teardown, "Tear down", "Prune"), len(steps)),
+		func(ctx context.Context, progress jobs.Progress) error {
+			close(started)
+			return runApply(ctx, steps, batch, delay, workers, gate, imgReclaim, teardown, events, progress)
+		})
+	queue := m.jobs
+	// A job cancelled before it starts never reports, so report for it
+	go func() {
+		<-queue.Done(id)
+		select {
+		case <-started:
+		default:
+			events <- applyDoneMsg{teardown: teardown, cancelled: true, remaining: len(steps), untouched: stepLabels(steps)}
+		}
+	}()
+	m.apply = &applyState{
+		events: events,
+		cancel: func() { queue.Cancel(id) },
+		gate:   gate,
+		total:  len(steps),
+		bar:    progress.New(progress.WithDefaultGradient(), progress.WithWidth(60)),
+	}
+	return m, waitApply(events)
+}
+
+// runApply removes steps in order, up to workers consecutive steps of a
+// kind at once, reporting each to events and progress, and returns the
+// failures.
+func runApply(ctx context.Context, steps []applyStep, batch int, delay time.Duration, workers int, gate *pauseGate, imgReclaim int64, teardown bool, events chan tea.Msg, progress jobs.Progress) error {
+	res := applyDoneMsg{teardown: teardown}
+	failedImages, images := 0, 0
+	for i := 0; i < len(steps); {
+		gate.wait(ctx)
+		if ctx.Err() != nil {
+			res.cancelled, res.remaining = true, len(steps)-i
+			break
+		}
+		if i > 0 && i%batch == 0 && delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				res.cancelled, res.remaining = true, len(steps)-i
+			}
+			if res.cancelled {
+				break
+			}
+		}
+
+		group := stepGroup(steps, i, batch, workers)
+		errs := make([]error, len(group))
+		var wg sync.WaitGroup
+		for j, step := range group {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[j] = step.run(ctx)
+			}()
+		}
+		wg.Wait()
+
+		for j, step := range group {
+			err := errs[j]
+			if step.image {
+				images++
+			}
+			switch {
+			case err != nil && step.image:
+				failedImages++
+				res.errs = append(res.errs, err)
+			case err != nil:
+				res.errs = append(res.errs, err)
+			default:
+				res.removed++
+				res.freed += step.freed
+				res.gone = append(res.gone, step.label)
+			}
+			events <- applyProgressMsg{done: i + j + 1, label: step.label}
+			progress(i+j+1, len(steps), step.label)
+		}
+		i += len(group)
+	}
+	if res.cancelled {
+		res.untouched = stepLabels(steps[len(steps)-res.remaining:])
+	}
+	// Shared layers make per-image accounting meaningless; credit the
+	// plan's reclaim estimate only when every marked image went
+	if images > 0 && failedImages == 0 && images == countImages(steps) {
+		res.freed += imgReclaim
+	}
+	events <- res
+	return errors.Join(res.errs...)
+}
+
+// stepGroup returns the steps from i on that may run side by side: up to
+// workers of the same kind, within one batch.
+func stepGroup(steps []applyStep, i, batch, workers int) []applyStep {
+	end := i + 1
+	for end < len(steps) && end-i < workers && end%batch != 0 && steps[end].kind == steps[i].kind {
+		end++
+	}
+	return steps[i:end]
+}
+
+func stepLabels(steps []applyStep) []string {
+	labels := make([]string, len(steps))
+	for i, s := range steps {
+		labels[i] = s.label
+	}
+	return labels
+}
+
+func countImages(steps []applyStep) int {
+	n := 0
+	for _, s := range steps {
+		if s.image {
+			n++
+		}
+	}
+	return n
+}
+
+func waitApply(events chan tea.Msg) tea.Cmd {
+	return func() tea.Msg { return <-events }
+}
+
+func (m model) onApplyProgress(msg applyProgressMsg) (tea.Model, tea.Cmd) {
+	if m.apply == nil {
+		return m, nil
+	}
+	st := *m.apply
+	st.done, st.label = msg.done, msg.label
+	m.apply = &st
+	return m, waitApply(st.events)
+}
+
+// updateApply handles the pause and cancel keys while a prune runs; every
+// other key works as usual.
+func (m model) updateApply(msg tea.KeyMsg) (bool, model) {
+	if m.apply == nil {
+		return false, m
+	}
+	switch msg.String() {
+	case " ":
+		if m.apply.gate.toggle() {
+			m.notice = "apply paused"
+		} else {
+			m.notice = "apply resumed"
+		}
+	case "esc":
+		m.apply.cancel()
+		m.notice = "cancelling apply…"
+	default:
+		return false, m
+	}
+	return true, m
+}
+
+func (m model) renderApply() string {
+	return fmt.Sprintf("%s\n\n[Space] %s  [Esc] Cancel", m.applyProgress(), tern(m.apply.gate.isPaused(), "Resume", "Pause"))
+}
+
+// applyProgress is the apply's state, progress bar and last removal.
+func (m model) applyProgress() string {
+	st := m.apply
+	frac := 0.0
+	if st.total > 0 {
+		frac = float64(st.done) / float64(st.total)
+	}
+	state := "Applying"
+	if st.gate.isPaused() {
+		state = "Paused"
+	}
+	last := ""
+	if st.label != "" {
+		last = fmt.Sprintf("\nLast: %s %s", m.removedVerb(), st.label)
+	}
+	return fmt.Sprintf("%s %d/%d\n%s%s", state, st.done, st.total, st.bar.ViewAs(frac), last)
+}
@@ -0,0 +1,321 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/backup"
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/domain"
+	"dockwatch/internal/jobs"
+)
+
+// backupRows caps the archives listed at once; the cursor scrolls the rest.
+const backupRows = 12
+
+// backupsState is the Backups pane: the archives in the backup destination,
+// grouped by volume.
+type backupsState struct {
+	dest     string           // where the archives were listed
+	archives []backup.Archive // by volume, newest first, see backup.Index
+	cursor   int
+	loading  bool
+	err      error
+}
+
+// backupsMsg carries a finished index of the backup destination.
+type backupsMsg struct {
+	dest     string
+	archives []backup.Archive
+	err      error
+}
+
+// backupDoneMsg reports the outcome of a backup taken from the quick-actions
+// menu.
+type backupDoneMsg struct {
+	volume  string
+	archive string // where it went
+	err     error
+}
+
+// restoreDoneMsg reports the outcome of a restore.
+type restoreDoneMsg struct {
+	volume  string
+	archive string
+	err     error
+}
+
+// openBackups opens the Backups pane and indexes the destination.
+func (m model) openBackups() (model, tea.Cmd) {
+	m.active = paneBackups
+	dest, err := backup.ParseDestination(m.cfg.BackupDestination(), backup.S3OptionsFrom(m.cfg))
+	if err != nil {
+		m.backups = &backupsState{err: err}
+		return m, nil
+	}
+	m.backups = &backupsState{dest: dest.Location(""), loading: true}
+	ctx := m.ctx
+	return m, func() tea.Msg {
+		archives, err := backup.Index(ctx, dest)
+		return backupsMsg{dest: dest.Location(""), archives: archives, err: err}
+	}
+}
+
+// setBackups shows a finished index, with the cursor on the newest backup
+// of the volume selected in the table, if it has any.
+func (m model) setBackups(msg backupsMsg) model {
+	st := &backupsState{dest: msg.dest, archives: msg.archives, err: msg.err}
+	if idx := m.table.Cursor(); idx >= 0 && idx < len(m.vols) {
+		for i, a := range st.archives {
+			if a.Volume == m.vols[idx].Name {
+				st.cursor = i
+				break
+			}
+		}
+	}
+	m.backups = st
+	return m
+}
+
+// updateBackups handles keys while the Backups pane is active. It reports
+// whether the key was consumed.
+func (m model) updateBackups(msg tea.KeyMsg) (bool, model, tea.Cmd) {
+	if m.backups == nil {
+		return false, m, nil
+	}
+	st := *m.backups
+	m.backups = &st
+	switch msg.String() {
+	case "up":
+		st.cursor = max(st.cursor-1, 0)
+	case "down":
+		st.cursor = min(st.cursor+1, max(len(st.archives)-1, 0))
+	case "b":
+		next, cmd := m.openBackups()
+		return true, next, cmd
+	case "i", "n":
+		if st.loading || st.cursor >= len(st.archives) {
+			return true, m, nil
+		}
+		return true, m.askRestore(st.archives[st.cursor], msg.String() == "i"), nil
+	default:
+		return false, m, nil
+	}
+	return true, m, nil
+}
+
+// askRestore asks where to restore a: typing the volume's name to confirm
+// replacing its contents, or the name of a new volume.
+func (m model) askRestore(a backup.Archive, inPlace bool) model {
+	if os.Getenv("DOCKWATCH_REMOTE") != "" {
+		m.notice = "restores run a helper container through the docker CLI; not available against an agent"
+		return m
+	}
+	if !inPlace {
+		return m.askThen("Restore "+a.Name+" to new volume", a.Volume+"-restored", "restores", func(m model, name string) (model, tea.Cmd) {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				m.notice = "restore cancelled"
+				return m, nil
+			}
+			if m.volumeListed(name) {
+				m.notice = fmt.Sprintf("%s already exists; pick a new name or restore in place", name)
+				return m, nil
+			}
+			return m.restoreVolume(a, name, false)
+		})
+	}
+	for _, v := range m.allVols {
+		if v.Name == a.Volume && v.InUse {
+			m.notice = fmt.Sprintf("not restoring %s: a running container uses it; stop it first", a.Volume)
+			return m
+		}
+	}
+	return m.askThen(fmt.Sprintf("Replace the contents of %s with %s? Type its name", a.Volume, a.Name), "", "restores", func(m model, name string) (model, tea.Cmd) {
+		if name != a.Volume {
+			m.notice = "restore cancelled"
+			return m, nil
+		}
+		return m.restoreVolume(a, a.Volume, true)
+	})
+}
+
+func (m model) volumeListed(name string) bool {
+	for _, v := range m.allVols {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreVolume verifies a and unpacks it into the volume target as a background
+// job. Nothing is written unless the archive matches its manifest.
+func (m model) restoreVolume(a backup.Archive, target string, replace bool) (model, tea.Cmd) {
+	dest, err := backup.ParseDestination(m.cfg.BackupDestination(), backup.S3OptionsFrom(m.cfg))
+	if err != nil {
+		m.notice = err.Error()
+		return m, nil
+	}
+	c, err := backup.CipherFrom(m.cfg)
+	if err != nil {
+		m.notice = err.Error()
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("restoring %s into %s…", a.Name, target)
+	dryRun, helper := m.dryRun, dockercli.HelperFrom(m.cfg)
+	return m, m.enqueue("restore", fmt.Sprintf("Restore %s into %s", a.Name, target), restoreDoneMsg{volume: target, archive: a.Name, err: context.Canceled},
+		func(ctx context.Context, progress jobs.Progress) (tea.Msg, error) {
+			err := restoreArchive(ctx, dest, c, helper, a.Name, target, replace, dryRun, progress)
+			return restoreDoneMsg{volume: target, archive: a.Name, err: err}, err
+		})
+}
+
+// restoreArchive decrypts with c, when the archive is encrypted, and unpacks
+// in a helper container running helper's image.
+func restoreArchive(ctx context.Context, dest backup.Destination, c backup.Cipher, helper dockercli.Helper, archive, target string, replace, dryRun bool, progress jobs.Progress) error {
+	progress(0, 2, "verifying "+archive)
+	_, problems, err := backup.Verify(ctx, dest, archive, c)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s fails verification (%s); not restoring from it", archive, problems[0])
+	}
+	if dryRun {
+		stateLogger("dry-run.log")("restore %s into %s (replace %t)", dest.Location(archive), target, replace)
+		return nil
+	}
+	progress(1, 2, "unpacking into "+target)
+	docker, err := dockercli.NewDockerProvider()
+	if err != nil {
+		return err
+	}
+	defer docker.Close()
+	docker.SetHelper(helper)
+	tr, err := backup.OpenTar(ctx, dest, archive, c)
+	if err != nil {
+		return err
+	}
+	defer tr.Close()
+	return docker.ImportVolume(ctx, target, tr, replace)
+}
+
+// backupNow archives the volume name into the backup destination as a
+// background job, like `dockwatch backup`.
+func (m model) backupNow(name string) (model, tea.Cmd) {
+	if os.Getenv("DOCKWATCH_REMOTE") != "" {
+		m.notice = "backups run a helper container through the docker CLI; not available against an agent"
+		return m, nil
+	}
+	dest, err := backup.ParseDestination(m.cfg.BackupDestination(), backup.S3OptionsFrom(m.cfg))
+	if err != nil {
+		m.notice = err.Error()
+		return m, nil
+	}
+	c, err := backup.CipherFrom(m.cfg)
+	if err != nil {
+		m.notice = err.Error()
+		return m, nil
+	}
+	archive := backup.FileName(name, time.Now())
+	if c != nil {
+		archive += c.Ext()
+	}
+	where := dest.Location(archive)
+	m.notice = fmt.Sprintf("backing up %s…", name)
+	dryRun, helper := m.dryRun, dockercli.HelperFrom(m.cfg)
+	return m, m.enqueue("backup", "Back up "+name, backupDoneMsg{volume: name, archive: where, err: context.Canceled},
+		func(ctx context.Context, progress jobs.Progress) (tea.Msg, error) {
+			err := backupVolume(ctx, dest, c, helper, name, archive, dryRun, progress)
+			return backupDoneMsg{volume: name, archive: where, err: err}, err
+		})
+}
+
+func backupVolume(ctx context.Context, dest backup.Destination, c backup.Cipher, helper dockercli.Helper, volume, archive string, dryRun bool, progress jobs.Progress) error {
+	if dryRun {
+		stateLogger("dry-run.log")("back up %s to %s", volume, dest.Location(archive))
+		return nil
+	}
+	progress(0, 1, "archiving "+volume)
+	docker, err := dockercli.NewDockerProvider()
+	if err != nil {
+		return err
+	}
+	defer docker.Close()
+	docker.SetHelper(helper)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(docker.ExportVolume(ctx, volume, pw))
+	}()
+	_, err = backup.Write(ctx, pr, volume, dest, archive, c)
+	// Unblocks the export if the archive failed first
+	pr.CloseWithError(err)
+	return err
+}
+
+func (m model) onBackupDone(msg backupDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("backup %s: %v", msg.volume, msg.err)
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("%s %s to %s", tern(m.dryRun, "would back up", "backed up"), msg.volume, msg.archive)
+	return m, nil
+}
+
+func (m model) onRestoreDone(msg restoreDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("restore %s: %v", msg.volume, msg.err)
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("%s %s from %s", tern(m.dryRun, "would restore", "restored"), msg.volume, msg.archive)
+	return m, m.loadVolumes()
+}
+
+func (m model) renderBackups() string {
+	sb := &strings.Builder{}
+	st := m.backups
+	switch {
+	case st == nil:
+		sb.WriteString("Backups:\n  <press B to list the archives in the backup destination>\n")
+		return m.pane().Render(sb.String())
+	case st.loading:
+		fmt.Fprintf(sb, "Backups in %s:\n  indexing…\n", st.dest)
+		return m.pane().Render(sb.String())
+	case st.err != nil:
+		fmt.Fprintf(sb, "Backups:\n  %v\n", st.err)
+		return m.pane().Render(sb.String() + "\n[B] Reindex")
+	}
+
+	volumes := 0
+	for i, a := range st.archives {
+		if i == 0 || st.archives[i-1].Volume != a.Volume {
+			volumes++
+		}
+	}
+	fmt.Fprintf(sb, "Backups in %s: %d archive(s) of %d volume(s)\n", st.dest, len(st.archives), volumes)
+	if len(st.archives) == 0 {
+		sb.WriteString("  <no archives named VOLUME-TIMESTAMP.tar.gz; take one with dockwatch backup>\n")
+	}
+	lo := clamp(st.cursor-backupRows/2, 0, max(len(st.archives)-backupRows, 0))
+	for i := lo; i < min(lo+backupRows, len(st.archives)); i++ {
+		a := st.archives[i]
+		if i == lo || st.archives[i-1].Volume != a.Volume {
+			live := tern(m.volumeListed(a.Volume), "", dimStyle.Render(" (volume gone)"))
+			fmt.Fprintf(sb, "  %s%s\n", headerStyle.Render(a.Volume), live)
+		}
+		cursor := "    "
+		if i == st.cursor {
+			cursor = "  > "
+		}
+		fmt.Fprintf(sb, "%s%s  %9s  %s  %s\n", cursor, a.Taken.Format("2006-01-02 15:04"), domain.HumanSize(a.Size), tern(a.Encrypted, "enc", "   "), a.Name)
+	}
+	sb.WriteString("\n[↑/↓] Move  [I] Restore in place  [N] Restore to new volume  [B] Reindex  [Tab] Switch")
+	return m.pane().Render(sb.String())
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/domain"
+)
+
+// bindPaneRows caps the bind mount pane; the largest paths come first.
+const bindPaneRows = 10
+
+// bindState is the bind mount pane of the Containers view.
+type bindState struct {
+	mounts  []domain.BindMount
+	loading bool
+	cancel  context.CancelFunc // stops the scan
+}
+
+// bindMountsMsg carries a finished bind mount scan.
+type bindMountsMsg struct {
+	scan   *bindState
+	mounts []domain.BindMount
+}
+
+// toggleBinds opens the bind mount pane and scans, or closes it. Host
+// paths are walked from here, so the daemon has to be on this machine.
+func (m model) toggleBinds() (model, tea.Cmd) {
+	if m.binds != nil {
+		return m.closeBinds(), nil
+	}
+	if os.Getenv("DOCKWATCH_REMOTE") != "" || !dockercli.IsLocalDaemon() {
+		m.notice = "the daemon is remote; its bind mounts are not on this machine"
+		return m, nil
+	}
+	m.mem, m.swarm = nil, nil
+	return m.scanBinds()
+}
+
+// scanBinds measures the bind mounts, replacing any scan still running.
+// Walking a large host tree takes a while, so closing the pane stops it.
+func (m model) scanBinds() (model, tea.Cmd) {
+	m = m.closeBinds()
+	ctx, cancel := context.WithCancel(m.ctx)
+	scan := &bindState{loading: true, cancel: cancel}
+	m.binds = scan
+	cs := m.containers
+	return m, func() tea.Msg {
+		return bindMountsMsg{scan: scan, mounts: dockercli.BindMounts(ctx, cs)}
+	}
+}
+
+// closeBinds closes the bind mount pane, stopping its scan.
+func (m model) closeBinds() model {
+	if m.binds != nil {
+		m.binds.cancel()
+		m.binds = nil
+	}
+	return m
+}
+
+func (m model) setBinds(msg bindMountsMsg) model {
+	if m.binds != msg.scan {
+		return m // closed or rescanned while scanning
+	}
+	msg.scan.cancel() // done; releases the context
+	m.binds = &bindState{mounts: msg.mounts, cancel: msg.scan.cancel}
+	return m
+}
+
+func (m model) renderBinds() string {
+	sb := &strings.Builder{}
+	sb.WriteString("Bind Mounts — host directories, not prunable via Docker\n\n")
+	switch {
+	case m.binds.loading:
+		sb.WriteString("  measuring…\n")
+	case len(m.binds.mounts) == 0:
+		sb.WriteString("  <no container has a bind mount>\n")
+	}
+	var total int64
+	skipped := 0
+	for i, b := range m.binds.mounts {
+		total += max(b.SizeBytes, 0)
+		skipped += b.Skipped
+		if i >= bindPaneRows {
+			continue
+		}
+		size := domain.HumanSize(b.SizeBytes)
+		if b.Skipped > 0 {
+			size = "≥" + size // unreadable parts are left out
+		}
+		fmt.Fprintf(sb, "  %10s  %-36s %s\n", size, b.Source, strings.Join(b.Containers, ", "))
+	}
+	if n := len(m.binds.mounts) - bindPaneRows; n > 0 {
+		fmt.Fprintf(sb, "  … %d smaller\n", n)
+	}
+	if !m.binds.loading && len(m.binds.mounts) > 0 {
+		fmt.Fprintf(sb, "\n  Total: %s", humanBytes(total))
+		if skipped > 0 {
+			fmt.Fprintf(sb, " (%d unreadable entries skipped)", skipped)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n[B] Close  [R] Rescan")
+	return m.pane().Render(sb.String())
+}
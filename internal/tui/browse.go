@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/dockercli"
+)
+
+// browseDoneMsg is sent when the terminal browser exits and the TUI resumes.
+type browseDoneMsg struct {
+	name string
+	err  error
+}
+
+// localMountpoint returns the mountpoint of the selected volume if this
+// process can actually read it, or explains why not.
+func (m model) localMountpoint() (string, string, error) {
+	idx := m.table.Cursor()
+	if idx < 0 || idx >= len(m.vols) {
+		return "", "", fmt.Errorf("no volume selected")
+	}
+	v := m.vols[idx]
+	switch {
+	case os.Getenv("DOCKWATCH_REMOTE") != "" || !dockercli.IsLocalDaemon():
+		return "", "", fmt.Errorf("the daemon is remote; %s is not on this machine", v.Name)
+	case v.Mountpoint == "":
+		return "", "", fmt.Errorf("mountpoint of %s is not known yet", v.Name)
+	}
+	if _, err := os.ReadDir(v.Mountpoint); err != nil {
+		return "", "", fmt.Errorf("cannot open %s: %w", v.Mountpoint, err)
+	}
+	return v.Name, v.Mountpoint, nil
+}
+
+// terminalBrowser picks what to run in dir: ranger if installed, then
+// $EDITOR (most can open a directory), then a shell.
+func terminalBrowser(dir string) *exec.Cmd {
+	var cmd *exec.Cmd
+	switch {
+	case lookPath("ranger"):
+		cmd = exec.Command("ranger", dir)
+	case os.Getenv("EDITOR") != "":
+		cmd = exec.Command("sh", "-c", `exec $EDITOR "$1"`, "sh", dir)
+	default:
+		cmd = exec.Command(ifEmpty(os.Getenv("SHELL"), "sh"))
+	}
+	cmd.Dir = dir
+	return cmd
+}
+
+func lookPath(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// browseVolume suspends the TUI and opens the mountpoint in the terminal.
+func (m model) browseVolume() (tea.Model, tea.Cmd) {
+	name, dir, err := m.localMountpoint()
+	if err != nil {
+		m.notice = err.Error()
+		return m, nil
+	}
+	return m, tea.ExecProcess(terminalBrowser(dir), func(err error) tea.Msg {
+		return browseDoneMsg{name: name, err: err}
+	})
+}
+
+// openFileManager opens the mountpoint in the desktop file manager,
+// leaving the TUI running.
+func (m model) openFileManager() model {
+	_, dir, err := m.localMountpoint()
+	if err != nil {
+		m.notice = err.Error()
+		return m
+	}
+	opener := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		opener = "open"
+	}
+	cmd := exec.Command(opener, dir)
+	if err := cmd.Start(); err != nil {
+		m.notice = fmt.Sprintf("%s failed: %v", opener, err)
+		return m
+	}
+	go cmd.Wait() // reap it; the file manager outlives the opener anyway
+	m.notice = fmt.Sprintf("opened %s", dir)
+	return m
+}
+
+func (m model) browseDone(msg browseDoneMsg) model {
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("browsing %s: %v", msg.name, msg.err)
+	}
+	return m
+}
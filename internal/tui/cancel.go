@@ -0,0 +1,36 @@
+package tui
+
+import "strings"
+
+// cancelRunning aborts every long operation in flight: a prune, volume
+// detail loading, an image pull and a registry check. Each reports its
+// partial results through its usual completion message. It returns false
+// when nothing was running.
+func (m model) cancelRunning() (model, bool) {
+	var what []string
+	if m.apply != nil {
+		m.apply.cancel()
+		what = append(what, "apply")
+	}
+	if m.enrich != nil && !m.enrich.stopped {
+		m.enrich.cancel()
+		what = append(what, "volume details")
+	}
+	if m.pull != nil {
+		m.pull.cancel()
+		what = append(what, "pull")
+	}
+	if m.registryCancel != nil {
+		m.registryCancel()
+		what = append(what, "registry check")
+	}
+	// The apply is a job too, named above
+	if n := m.jobs.CancelAll(); n > tern(m.apply != nil, 1, 0) {
+		what = append(what, "background jobs")
+	}
+	if len(what) == 0 {
+		return m, false
+	}
+	m.notice = "cancelling " + strings.Join(what, ", ") + "…"
+	return m, true
+}
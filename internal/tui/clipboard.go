@@ -0,0 +1,31 @@
+package tui
+
+import (
+	"fmt"
+
+	"dockwatch/internal/clipboard"
+)
+
+// copyVolume copies the selected volume's name, or its mountpoint when
+// mountpoint is set, to the system clipboard.
+func (m model) copyVolume(mountpoint bool) model {
+	idx := m.table.Cursor()
+	if idx < 0 || idx >= len(m.vols) {
+		return m
+	}
+	v := m.vols[idx]
+	text, what := v.Name, "name"
+	if mountpoint {
+		if v.Mountpoint == "" {
+			m.notice = fmt.Sprintf("mountpoint of %s is not known yet", v.Name)
+			return m
+		}
+		text, what = v.Mountpoint, "mountpoint"
+	}
+	if err := clipboard.Copy(text); err != nil {
+		m.notice = fmt.Sprintf("copy failed: %v", err)
+		return m
+	}
+	m.notice = fmt.Sprintf("copied %s: %s", what, text)
+	return m
+}
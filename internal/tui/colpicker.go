@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// colPicker is the column chooser opened with C: every available column
+// in display order, shown ones checked.
+type colPicker struct {
+	cursor int
+	titles []string
+	shown  map[string]bool
+}
+
+// openColumns opens the column picker on the current layout, with the
+// columns not shown listed after the shown ones.
+func (m model) openColumns() model {
+	p := &colPicker{shown: map[string]bool{}}
+	for _, c := range m.shownColumns() {
+		p.titles = append(p.titles, c.title)
+		p.shown[c.title] = true
+	}
+	for _, c := range m.volumeColumns() {
+		if !p.shown[c.title] {
+			p.titles = append(p.titles, c.title)
+		}
+	}
+	m.columns = p
+	return m
+}
+
+// updateColumns handles every key while the picker is open: Space toggles
+// a column, Shift+↑/↓ moves it, Enter saves and Esc cancels.
+func (m model) updateColumns(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := *m.columns
+	p.titles = slices.Clone(p.titles)
+	switch msg.String() {
+	case "esc", "q":
+		m.columns = nil
+		return m, nil
+	case "enter":
+		m.columns = nil
+		return m.saveColumns(p.picked()), nil
+	case "up", "k":
+		p.cursor = max(p.cursor-1, 0)
+	case "down", "j":
+		p.cursor = min(p.cursor+1, len(p.titles)-1)
+	case "shift+up", "K":
+		if p.cursor > 0 {
+			p.titles[p.cursor-1], p.titles[p.cursor] = p.titles[p.cursor], p.titles[p.cursor-1]
+			p.cursor--
+		}
+	case "shift+down", "J":
+		if p.cursor < len(p.titles)-1 {
+			p.titles[p.cursor+1], p.titles[p.cursor] = p.titles[p.cursor], p.titles[p.cursor+1]
+			p.cursor++
+		}
+	case " ":
+		p.shown = maps.Clone(p.shown)
+		title := p.titles[p.cursor]
+		p.shown[title] = !p.shown[title]
+	}
+	m.columns = &p
+	return m, nil
+}
+
+// picked returns the checked titles in order.
+func (p colPicker) picked() []string {
+	var titles []string
+	for _, t := range p.titles {
+		if p.shown[t] {
+			titles = append(titles, t)
+		}
+	}
+	return titles
+}
+
+// saveColumns shows titles and stores them with the active view, or in
+// the config when no view is active.
+func (m model) saveColumns(titles []string) model {
+	if len(titles) == 0 {
+		m.notice = "at least one column must stay shown"
+		return m
+	}
+	where := "config"
+	if m.view != nil {
+		v := *m.view
+		v.Columns = titles
+		m.cfg.SetView(v)
+		m.view = &v
+		where = fmt.Sprintf("view %q", v.Name)
+	} else {
+		m.cfg.Columns = titles
+	}
+	m = m.applyVolumeView()
+	if err := m.cfg.Save(); err != nil {
+		m.notice = fmt.Sprintf("failed to save columns: %v", err)
+		return m
+	}
+	m.notice = fmt.Sprintf("saved %d column(s) to %s", len(titles), where)
+	return m
+}
+
+func (m model) renderColumns() string {
+	p := m.columns
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Columns\n\n")
+	for i, t := range p.titles {
+		line := fmt.Sprintf("[%s] %s", tern(p.shown[t], "x", " "), t)
+		if i == p.cursor {
+			line = selectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		fmt.Fprintln(sb, line)
+	}
+	fmt.Fprintf(sb, "\n[↑/↓] Move  [Space] Show/hide  [Shift+↑/↓] Reorder  [Enter] Save  [Esc] Cancel")
+	return m.pane().Render(sb.String())
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+
+	"dockwatch/internal/domain"
+)
+
+// volumeColumn is one column of the Volumes table after the mark column.
+type volumeColumn struct {
+	title   string
+	width   int
+	value   func(v domain.Volume, now time.Time) string
+	listed  bool   // known from the listing alone
+	loading string // shown instead of value until the volume is inspected
+	hidden  bool   // left out unless the view or config asks for it
+}
+
+// volumeColumns returns every available column: the built-ins, the label
+// columns from the config, then Status.
+func (m model) volumeColumns() []volumeColumn {
+	host := engineHost()
+	cols := []volumeColumn{
+		{title: "Name", width: 28, listed: true, value: func(v domain.Volume, _ time.Time) string { return v.Name }},
+		{title: "Size", width: 10, loading: "…", value: func(v domain.Volume, _ time.Time) string {
+			return v.SizeHuman() + tern(v.SizeStale, "*", "")
+		}},
+		{title: "Age", width: 5, loading: "…", value: func(v domain.Volume, now time.Time) string { return v.AgeHuman(now) }},
+		{title: "Last Used", width: 9, loading: "…", value: func(v domain.Volume, now time.Time) string { return v.LastUsedHuman(now) }},
+		{title: "Attached", width: 18, loading: "…", value: func(v domain.Volume, _ time.Time) string {
+			if len(v.Attached) == 0 {
+				return "<none>"
+			}
+			return strings.Join(v.Attached, ",")
+		}},
+		{title: "Project", width: 14, value: func(v domain.Volume, _ time.Time) string { return v.Project }},
+		{title: "Tags", width: 14, listed: true, value: func(v domain.Volume, _ time.Time) string { return strings.Join(v.Tags, ",") }},
+		{title: "Driver", width: 8, listed: true, hidden: true, value: func(v domain.Volume, _ time.Time) string { return v.Driver }},
+		{title: "Labels", width: 24, listed: true, hidden: true, value: func(v domain.Volume, _ time.Time) string { return joinLabels(v.Labels) }},
+		{title: "Host", width: 16, listed: true, hidden: true, value: func(domain.Volume, time.Time) string { return host }},
+	}
+	for _, lc := range m.cfg.LabelColumns {
+		cols = append(cols, volumeColumn{title: lc.Heading(), width: lc.ColumnWidth(), value: func(v domain.Volume, _ time.Time) string {
+			return v.Labels[lc.Label]
+		}})
+	}
+	ignored := m.cfg.IsIgnored
+	return append(cols, volumeColumn{title: "Status", width: 9, loading: "…", value: func(v domain.Volume, _ time.Time) string {
+		if ignored(v.Name) {
+			return "IGNORED"
+		}
+		return v.State.Label()
+	}})
+}
+
+// stateStyle colors a volume state: green while a running container uses
+// it, yellow for orphans, blue when something protects it.
+func stateStyle(s domain.OrphanState) lipgloss.Style {
+	switch s {
+	case domain.InUseRunning:
+		return lipgloss.NewStyle().Foreground(activeTheme.running)
+	case domain.InUseStopped:
+		return lipgloss.NewStyle().Foreground(activeTheme.stopped)
+	case domain.Orphaned:
+		return lipgloss.NewStyle().Foreground(activeTheme.orphaned)
+	case domain.Protected:
+		return lipgloss.NewStyle().Foreground(activeTheme.protected)
+	}
+	return dimStyle
+}
+
+// volumeCellStyle colors each shown volume's row by its state, so orphans
+// stand out in long lists, and flags huge sizes. Volumes still loading
+// stay plain.
+func (m model) volumeCellStyle() cellStyler {
+	size := -1
+	for i, c := range m.shownColumns() {
+		if c.title == "Size" {
+			size = i + 1 // after the mark column
+		}
+	}
+	pending := m.enrich.pendingSet()
+	huge := m.cfg.HugeVolumeSize()
+	return func(row, col int) (lipgloss.Style, bool) {
+		if row >= len(m.vols) || pending[m.vols[row].Name] {
+			return lipgloss.Style{}, false
+		}
+		v := m.vols[row]
+		if col == size && huge > 0 && v.SizeBytes >= huge {
+			return hugeStyle, true
+		}
+		if m.cfg.IsIgnored(v.Name) {
+			return stateStyle(domain.Protected), true
+		}
+		return stateStyle(v.State), true
+	}
+}
+
+// joinLabels renders labels as sorted key=value pairs.
+func joinLabels(labels map[string]string) string {
+	var pairs []string
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// engineHost names the Docker engine the volumes come from: the remote
+// agent, DOCKER_HOST or the docker context, else local.
+func engineHost() string {
+	for _, env := range []string{"DOCKWATCH_REMOTE", "DOCKER_HOST", "DOCKER_CONTEXT"} {
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+	}
+	return "local"
+}
+
+// columnTitles returns the column titles the active view asks for, else
+// those from the config; nil means the default set.
+func (m model) columnTitles() []string {
+	if m.view != nil && len(m.view.Columns) > 0 {
+		return m.view.Columns
+	}
+	return m.cfg.Columns
+}
+
+// shownColumns returns the columns the active view or the config asks
+// for, in their order, or every column that isn't hidden by default.
+// Unknown titles are skipped.
+func (m model) shownColumns() []volumeColumn {
+	all := m.volumeColumns()
+	defaults := slices.DeleteFunc(slices.Clone(all), func(c volumeColumn) bool { return c.hidden })
+	titles := m.columnTitles()
+	if len(titles) == 0 {
+		return defaults
+	}
+	var cols []volumeColumn
+	for _, title := range titles {
+		for _, c := range all {
+			if strings.EqualFold(c.title, title) {
+				cols = append(cols, c)
+			}
+		}
+	}
+	if len(cols) == 0 {
+		return defaults
+	}
+	return cols
+}
+
+// tableColumns converts cols into the table header, mark column first.
+func tableColumns(cols []volumeColumn) []table.Column {
+	out := []table.Column{{Title: " ", Width: 1}}
+	for _, c := range cols {
+		out = append(out, table.Column{Title: c.title, Width: c.width})
+	}
+	return out
+}
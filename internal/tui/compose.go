@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"fmt"
+
+	"dockwatch/internal/compose"
+)
+
+// scanCompose re-reads the compose files under compose.dirs. It runs when
+// the prune plan opens or a delete is asked, so edits to the files count
+// without a restart; without compose.dirs there is nothing to read.
+func (m model) scanCompose() model {
+	if len(m.cfg.Compose.Dirs) == 0 {
+		m.compose = nil
+		return m
+	}
+	idx, err := compose.Scan(m.cfg.ComposeDirs())
+	m.compose = idx
+	if err != nil {
+		m.notice = "compose scan: " + err.Error()
+	}
+	return m
+}
+
+// composeWarning says which compose file declares the volume, preferring
+// one that declares it external, or returns "" when none does.
+func (m model) composeWarning(name string) string {
+	refs := m.compose[name]
+	if len(refs) == 0 {
+		return ""
+	}
+	ref := refs[0]
+	for _, r := range refs {
+		if r.External {
+			ref = r
+			break
+		}
+	}
+	if len(refs) > 1 {
+		return fmt.Sprintf("%s (and %d more)", ref.Warning(), len(refs)-1)
+	}
+	return ref.Warning()
+}
@@ -0,0 +1,358 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"dockwatch/internal/domain"
+)
+
+// resource selects which kind of Docker object the main table shows.
+type resource int
+
+const (
+	resVolumes resource = iota
+	resContainers
+	resImages
+	resProjects
+	resNetworks
+)
+
+const statsPollEvery = 3 * time.Second
+
+// containersMsg carries the result of an asynchronous container listing.
+type containersMsg struct {
+	containers []domain.Container
+	err        error
+}
+
+// statsMsg carries one resource usage sample for all running containers.
+type statsMsg struct {
+	stats []domain.ContainerStats
+	err   error
+}
+
+// statsTickMsg schedules the next stats sample.
+type statsTickMsg struct{}
+
+func newContainerTable() table.Model {
+	cols := []table.Column{
+		{Title: "Name", Width: 18},
+		{Title: "Image", Width: 16},
+		{Title: "State", Width: 8},
+		{Title: "Health", Width: 9},
+		{Title: "Rst", Width: 3},
+		{Title: "CPU%", Width: 6},
+		{Title: "Mem", Width: 12},
+		{Title: "Net I/O", Width: 12},
+		{Title: "Block I/O", Width: 12},
+		{Title: "Logs", Width: 8},
+	}
+	t := table.New(table.WithColumns(cols), table.WithFocused(true), table.WithStyles(tableStyles()))
+	t.KeyMap.LineUp.SetKeys("up")
+	t.KeyMap.LineDown.SetKeys("down")
+	return t
+}
+
+// loadContainers lists containers in the background.
+func (m model) loadContainers() tea.Cmd {
+	if m.provider == nil {
+		return nil
+	}
+	prov, ctx := m.provider, m.ctx
+	return func() tea.Msg {
+		cs, err := prov.ListContainers(ctx)
+		return containersMsg{containers: cs, err: err}
+	}
+}
+
+func (m model) setContainers(msg containersMsg) model {
+	if msg.err != nil {
+		m.notice = msg.err.Error()
+		return m
+	}
+	m.containers = msg.containers
+	m.ctable.SetRows(m.containerRows())
+	if m.pendingContainer != "" {
+		m = m.selectContainer(m.pendingContainer)
+	}
+	return m.refreshProjects()
+}
+
+func (m model) containerRows() []table.Row {
+	rows := make([]table.Row, 0, len(m.containers))
+	now := time.Now()
+	for _, c := range m.containers {
+		cpu, mem, net, blk := "-", "-", "-", "-"
+		if st, ok := m.stats[c.Name]; ok && c.Running() {
+			cpu = fmt.Sprintf("%.1f", st.CPUPercent)
+			mem = shortBytes(st.MemUsage) + "/" + shortBytes(st.MemLimit)
+			net = shortBytes(st.NetRx) + "/" + shortBytes(st.NetTx)
+			blk = shortBytes(st.BlockRead) + "/" + shortBytes(st.BlockWrite)
+		}
+		state := c.State
+		if c.CrashLooping(now) {
+			state = "LOOPING"
+		}
+		rows = append(rows, table.Row{c.Name, c.Image, state, ifEmpty(c.Health, "-"), fmt.Sprint(c.RestartCount), cpu, mem, net, blk, logSize(c)})
+	}
+	return rows
+}
+
+// logSize is the Logs column: the json-file log size, or the driver when
+// logs don't live in a file dockwatch can measure.
+func logSize(c domain.Container) string {
+	switch {
+	case c.LogDriver != "" && c.LogDriver != "json-file":
+		return c.LogDriver
+	case c.LogSize < 0:
+		return "?"
+	}
+	return shortBytes(c.LogSize)
+}
+
+// loadStats samples container stats in the background.
+func (m model) loadStats() tea.Cmd {
+	if m.provider == nil {
+		return nil
+	}
+	prov, ctx := m.provider, m.ctx
+	return func() tea.Msg {
+		st, err := prov.ContainerStats(ctx)
+		return statsMsg{stats: st, err: err}
+	}
+}
+
+// setStats stores a sample and schedules the next one while the Containers
+// view is visible.
+func (m model) setStats(msg statsMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = msg.err.Error()
+	} else {
+		prev, prevAt := m.stats, m.statsAt
+		m.stats = make(map[string]domain.ContainerStats, len(msg.stats))
+		for _, st := range msg.stats {
+			m.stats[st.Name] = st
+		}
+		m.statsAt = time.Now()
+		// A sample from before polling last stopped would average the
+		// rates over the time nobody was looking
+		m.writeRates = nil
+		if dt := m.statsAt.Sub(prevAt); prev != nil && dt <= 2*statsPollEvery {
+			m.writeRates = writeRates(prev, m.stats, dt)
+		}
+		m.ctable.SetRows(m.containerRows())
+	}
+	if m.resource != resContainers {
+		m.statsPolling = false
+		return m, nil
+	}
+	return m, tea.Tick(statsPollEvery, func(time.Time) tea.Msg { return statsTickMsg{} })
+}
+
+func (m model) onStatsTick() (tea.Model, tea.Cmd) {
+	if m.resource != resContainers {
+		m.statsPolling = false
+		return m, nil
+	}
+	return m, m.loadStats()
+}
+
+// startStats begins the polling loop unless it is already running.
+func (m model) startStats() (model, tea.Cmd) {
+	if m.statsPolling {
+		return m, nil
+	}
+	m.statsPolling = true
+	return m, m.loadStats()
+}
+
+// shortBytes is a compact humanBytes for narrow columns, e.g. "1.2G".
+func shortBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%dB", max(b, 0))
+	}
+	f, suffix := float64(b), ""
+	for _, s := range []string{"K", "M", "G", "T"} {
+		if f < unit {
+			break
+		}
+		f /= unit
+		suffix = s
+	}
+	if f >= 100 {
+		return fmt.Sprintf("%.0f%s", f, suffix)
+	}
+	return fmt.Sprintf("%.1f%s", f, suffix)
+}
+
+// selectedContainer returns the container under the cursor, if any.
+func (m model) selectedContainer() (domain.Container, bool) {
+	idx := m.ctable.Cursor()
+	if idx < 0 || idx >= len(m.containers) {
+		return domain.Container{}, false
+	}
+	return m.containers[idx], true
+}
+
+// updateContainers handles keys for the Containers view. Navigation keys fall
+// through to the container table.
+func (m model) updateContainers(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.logs != nil {
+		if handled, next, cmd := m.updateLogs(msg); handled {
+			return next, cmd
+		}
+	}
+	if m.confirmTruncate != nil {
+		return m.updateTruncate(msg)
+	}
+	if m.swarm != nil {
+		if handled, next, cmd := m.updateSwarm(msg); handled {
+			return next, cmd
+		}
+	}
+	switch msg.String() {
+	case "l":
+		if c, ok := m.selectedContainer(); ok {
+			return m.push().openLogs(c)
+		}
+		return m, nil
+	case "t":
+		if c, ok := m.selectedContainer(); ok {
+			m = m.askTruncate(c)
+		}
+		return m, nil
+	case "e":
+		c, ok := m.selectedContainer()
+		if !ok {
+			return m, nil
+		}
+		if !c.Running() {
+			m.notice = fmt.Sprintf("%s is not running", c.Name)
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("opening shell in %s…", c.Name)
+		return m, m.prepareExec(c)
+	case "i":
+		m.showContainerInfo = !m.showContainerInfo
+		return m, nil
+	case "w":
+		m.showWriters = !m.showWriters
+		return m.startStats()
+	case "g":
+		m.showGraph = !m.showGraph
+		return m, nil
+	case "b":
+		return m.toggleBinds()
+	case "m":
+		return m.toggleMem()
+	case "s":
+		return m.toggleSwarm()
+	case "r":
+		var scan tea.Cmd
+		switch {
+		case m.binds != nil:
+			m, scan = m.scanBinds()
+		case m.mem != nil:
+			m, scan = m.scanMem()
+		}
+		return m, tea.Batch(m.loadContainers(), scan)
+	}
+
+	var cmd tea.Cmd
+	m.ctable, cmd = m.ctable.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewContainers() string {
+	header := m.renderTabs() + "\n" + m.title("Docker Containers")
+	running, unhealthy, looping := 0, 0, 0
+	now := time.Now()
+	for _, c := range m.containers {
+		if c.Running() {
+			running++
+		}
+		if c.Health == "unhealthy" {
+			unhealthy++
+		}
+		if c.CrashLooping(now) {
+			looping++
+		}
+	}
+	statusInfo := fmt.Sprintf("Containers: %d  Running: %d", len(m.containers), running)
+	if m.notice != "" {
+		statusInfo += "  — " + m.notice
+	}
+	header = header + "\n" + dimStyle.Render(statusInfo)
+	if looping > 0 || unhealthy > 0 {
+		header += "\n" + failStyle.Render(fmt.Sprintf("Crash-looping: %d  Unhealthy: %d — [I] shows why, [L] their logs", looping, unhealthy))
+	}
+
+	lower := m.pane().Render("[↑/↓] Move  [L] Logs  [I] Inspect  [E] Exec  [T] Truncate logs  [G] Graph\n" +
+		"[W] Top writers  [B] Bind mounts  [M] shm/tmpfs  [S] Secrets/configs\n" +
+		"[R] Refresh  [1-5] Views  [Q] Quit")
+	switch {
+	case m.confirmTruncate != nil:
+		lower = m.pane().Render(m.truncatePrompt())
+	case m.logs != nil:
+		lower = m.renderLogs()
+	case m.binds != nil:
+		lower = m.renderBinds()
+	case m.mem != nil:
+		lower = m.renderMem()
+	case m.swarm != nil:
+		lower = m.renderSwarm()
+	case m.showWriters:
+		lower = m.renderWriters()
+	case m.showContainerInfo:
+		lower = m.renderContainerInfo()
+	case m.showGraph:
+		if c, ok := m.selectedContainer(); ok {
+			lower = m.renderGraph(m.containerNode(c))
+		}
+	}
+	if m.height > 0 {
+		// Leave the lower pane its full height
+		m.ctable.SetHeight(max(m.height-lipgloss.Height(header)-lipgloss.Height(lower)-tableFrameLines, 3))
+	}
+	// Stats columns need more than 80 cells, so this box sizes to the table
+	return header + "\n" + borderStyle.Copy().UnsetWidth().Render(m.ctable.View()) + "\n" + lower
+}
+
+// renderContainerInfo describes the container under the cursor: its
+// health, restarts and last exit, to tell a crash loop from a slow start.
+func (m model) renderContainerInfo() string {
+	c, ok := m.selectedContainer()
+	if !ok {
+		return m.pane().Render("Inspect:\n  <no container selected>")
+	}
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Inspect: %s (%s), %s\n", c.Name, c.Image, ifEmpty(c.Status, c.State))
+	if c.CrashLooping(time.Now()) {
+		sb.WriteString(failStyle.Render("Crash-looping: the daemon keeps restarting it") + "\n")
+	}
+	fmt.Fprintf(sb, "Restarts: %d (policy %s)", c.RestartCount, ifEmpty(c.RestartPolicy, "no"))
+	if !c.StartedAt.IsZero() {
+		fmt.Fprintf(sb, ", last started %s", c.StartedAt.Local().Format("2006-01-02 15:04"))
+	}
+	if !c.FinishedAt.IsZero() {
+		fmt.Fprintf(sb, ", last exit code %d", c.ExitCode)
+	}
+	health := "Health: " + ifEmpty(c.Health, "<no healthcheck>")
+	if c.HealthOutput != "" {
+		health += " — " + firstLine(c.HealthOutput)
+	}
+	sb.WriteString("\n" + tern(c.Health == "unhealthy", failStyle, lipgloss.NewStyle()).Render(health) + "\n")
+	fmt.Fprintf(sb, "Logs: %s", logSize(c))
+	if c.LogDriver == "json-file" && c.LogPath != "" {
+		fmt.Fprintf(sb, " in %s", c.LogPath)
+	}
+	sb.WriteString("\n")
+	return m.pane().Render(sb.String() + "\n[L] Logs  [T] Truncate logs  [I] Close")
+}
@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/dedup"
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/domain"
+	"dockwatch/internal/jobs"
+)
+
+// dedupState is the duplicate content report shown in paneDedup.
+type dedupState struct {
+	volumes []string // the marked volumes compared
+	groups  []dedup.Group
+	skipped []string // volumes that couldn't be read, with why
+	loading bool
+	err     error
+}
+
+// dedupMsg carries a finished comparison.
+type dedupMsg struct {
+	groups  []dedup.Group
+	skipped []string
+	err     error
+}
+
+// findDuplicates opens the duplicate content pane and fingerprints every
+// marked volume as a background job. Reading volumes takes as long as
+// their data takes to stream, so it's never done on a plain reload.
+func (m model) findDuplicates() (model, tea.Cmd) {
+	m.active = paneDedup
+	var vols []domain.Volume
+	for _, v := range m.allVols {
+		if m.marked[v.Name] {
+			vols = append(vols, v)
+		}
+	}
+	if len(vols) < 2 {
+		m.dedup = &dedupState{err: errors.New("mark two or more volumes to compare their content")}
+		return m, nil
+	}
+	names := make([]string, len(vols))
+	for i, v := range vols {
+		names[i] = v.Name
+	}
+	if os.Getenv("DOCKWATCH_REMOTE") != "" {
+		m.dedup = &dedupState{volumes: names, err: errors.New("reading volume content needs the docker CLI; not available against an agent")}
+		return m, nil
+	}
+	m.dedup = &dedupState{volumes: names, loading: true}
+	helper := dockercli.HelperFrom(m.cfg)
+	return m, m.enqueue("scan", fmt.Sprintf("Compare content of %d volumes", len(vols)), dedupMsg{err: context.Canceled},
+		func(ctx context.Context, progress jobs.Progress) (tea.Msg, error) {
+			// The docker CLI is only needed for volumes read through it
+			var docker *dockercli.DockerProvider
+			defer func() {
+				if docker != nil {
+					docker.Close()
+				}
+			}()
+			export := func(ctx context.Context, name string, w io.Writer) error {
+				if docker == nil {
+					var err error
+					if docker, err = dockercli.NewDockerProvider(); err != nil {
+						return err
+					}
+					docker.SetHelper(helper)
+				}
+				return docker.ExportVolume(ctx, name, w)
+			}
+			var fps []*dedup.Fingerprint
+			var skipped []string
+			for i, v := range vols {
+				progress(i, len(vols), "sampling "+v.Name)
+				fp, err := fingerprintVolume(ctx, v, export)
+				if ctx.Err() != nil {
+					return dedupMsg{err: ctx.Err()}, ctx.Err()
+				}
+				if err != nil {
+					skipped = append(skipped, fmt.Sprintf("%s: %v", v.Name, err))
+					continue
+				}
+				fps = append(fps, fp)
+			}
+			return dedupMsg{groups: dedup.Compare(fps), skipped: skipped}, nil
+		})
+}
+
+// fingerprintVolume reads the mountpoint directly on a local daemon, and
+// otherwise, or when it isn't readable, streams the volume out through a
+// helper container with export.
+func fingerprintVolume(ctx context.Context, v domain.Volume, export func(context.Context, string, io.Writer) error) (*dedup.Fingerprint, error) {
+	if dockercli.IsLocalDaemon() && v.Mountpoint != "" {
+		fp, err := dedup.FromDir(v.Name, v.Mountpoint)
+		if err == nil || !errors.Is(err, fs.ErrPermission) && !errors.Is(err, fs.ErrNotExist) {
+			return fp, err
+		}
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(export(ctx, v.Name, pw))
+	}()
+	fp, err := dedup.FromTar(v.Name, pr)
+	pr.CloseWithError(err)
+	return fp, err
+}
+
+func (m model) setDedup(msg dedupMsg) model {
+	st := &dedupState{groups: msg.groups, skipped: msg.skipped, err: msg.err}
+	if m.dedup != nil {
+		st.volumes = m.dedup.volumes
+	}
+	m.dedup = st
+	return m
+}
+
+func (m model) renderDedup() string {
+	sb := &strings.Builder{}
+	st := m.dedup
+	switch {
+	case st == nil:
+		sb.WriteString("Duplicate Content:\n  <mark volumes, then press = to compare their content>\n")
+		return m.pane().Render(sb.String())
+	case st.loading:
+		fmt.Fprintf(sb, "Duplicate Content in %d marked volumes:\n  sampling…\n", len(st.volumes))
+		return m.pane().Render(sb.String())
+	case st.err != nil:
+		fmt.Fprintf(sb, "Duplicate Content:\n  %v\n", st.err)
+		return m.pane().Render(sb.String() + "\n[=] Rescan")
+	}
+
+	fmt.Fprintf(sb, "Duplicate Content in %d marked volumes (by sampled hashes):\n", len(st.volumes))
+	if len(st.groups) == 0 {
+		sb.WriteString("  <no two volumes appear to hold the same data>\n")
+	}
+	var reclaimable int64
+	for _, g := range st.groups {
+		reclaimable += g.Reclaimable()
+		if g.Identical {
+			fmt.Fprintf(sb, "  identical  %s — %s each, keeping one frees %s\n", strings.Join(g.Volumes, ", "), humanBytes(g.Bytes), humanBytes(g.Reclaimable()))
+			continue
+		}
+		fmt.Fprintf(sb, "  %3.0f%% alike %s — %s in common\n", g.Similarity*100, strings.Join(g.Volumes, ", "), humanBytes(g.Bytes))
+	}
+	if reclaimable > 0 {
+		fmt.Fprintf(sb, "Consolidating could free about %s\n", humanBytes(reclaimable))
+	}
+	for _, s := range st.skipped {
+		fmt.Fprintf(sb, "  skipped %s\n", s)
+	}
+	return m.pane().Render(sb.String() + "\n[=] Rescan  [Tab] Switch")
+}
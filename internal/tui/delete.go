@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/policy"
+)
+
+// deleteDoneMsg reports the outcome of a single-volume delete. When the
+// daemon said the volume is in use, holders are the containers holding it.
+type deleteDoneMsg struct {
+	name    string
+	err     error
+	holders []domain.Container
+}
+
+// policyMsg carries the policy loaded in the background; warn is a problem
+// that didn't stop it loading, such as the cached copy standing in for an
+// unreachable policy server.
+type policyMsg struct {
+	pol  *policy.Policy
+	err  error
+	warn error
+}
+
+// loadPolicy reads the policy this host follows. With policy.url set that
+// is an HTTPS fetch, so it is done here, once per refresh, and protection
+// reads the copy kept on the model.
+func (m model) loadPolicy() tea.Cmd {
+	cfg, ctx := m.cfg, m.ctx
+	return func() tea.Msg {
+		var warn error
+		pol, err := policy.Configured(ctx, cfg, func(err error) { warn = err })
+		return policyMsg{pol: pol, err: err, warn: warn}
+	}
+}
+
+func (m model) setPolicy(msg policyMsg) model {
+	m.policy, m.policyErr, m.policyLoaded = msg.pol, msg.err, true
+	if msg.warn != nil {
+		m.notice = msg.warn.Error()
+	}
+	return m
+}
+
+// protection explains why v must not be deleted directly, or returns ""
+// when nothing protects it: the ignore list, a policy protect rule, or a
+// container still using it. Until the policy has loaded, everything is
+// protected by it.
+func (m model) protection(v domain.Volume) string {
+	if m.cfg.IsIgnored(v.Name) {
+		return "it is on the ignore list"
+	}
+	switch {
+	case !m.policyLoaded:
+		return "the policy is still loading"
+	case m.policyErr != nil:
+		return fmt.Sprintf("the policy could not be read (%v)", m.policyErr)
+	case m.policy != nil:
+		for _, d := range m.policy.Evaluate([]domain.Volume{v}, time.Now()) {
+			if d.Action == policy.ActionProtect {
+				return fmt.Sprintf("policy rule %q protects it", d.Rule)
+			}
+		}
+	}
+	if len(v.Attached) > 0 {
+		return "it is used by " + strings.Join(v.Attached, ", ")
+	}
+	return ""
+}
+
+// askDelete asks for confirmation to delete the volume under the cursor,
+// unless it is protected.
+func (m model) askDelete() model {
+	idx := m.table.Cursor()
+	if idx < 0 || idx >= len(m.vols) || m.provider == nil {
+		return m
+	}
+	v := m.vols[idx]
+	if m.enrich.pendingSet()[v.Name] {
+		m.notice = fmt.Sprintf("%s is still loading", v.Name)
+		return m
+	}
+	if why := m.protection(v); why != "" {
+		m.notice = fmt.Sprintf("not deleting %s: %s", v.Name, why)
+		return m
+	}
+	m.confirmDelete, m.notice = v.Name, ""
+	return m.scanCompose()
+}
+
+// updateDelete consumes the key answering the delete prompt; anything but
+// y cancels. If the prompt offered it, the stopped containers holding the
+// volume are removed first.
+func (m model) updateDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	name, holders := m.confirmDelete, m.deleteHolders
+	m.confirmDelete, m.deleteHolders = "", nil
+	if msg.String() != "y" {
+		m.notice = "delete cancelled"
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("deleting %s…", name)
+	prov, ctx := m.provider, m.ctx
+	return m, func() tea.Msg {
+		for _, id := range holders {
+			if err := prov.RemoveContainer(ctx, id, false); err != nil {
+				return deleteDoneMsg{name: name, err: err}
+			}
+		}
+		err := prov.RemoveVolume(ctx, name)
+		if !errors.Is(err, domain.ErrVolumeInUse) {
+			return deleteDoneMsg{name: name, err: err}
+		}
+		// The listing was stale; find out who holds it now
+		cs, _ := prov.ListContainers(ctx)
+		var held []domain.Container
+		for _, c := range cs {
+			if slices.ContainsFunc(c.Mounts, func(mt domain.Mount) bool { return mt.Type == "volume" && mt.Name == name }) {
+				held = append(held, c)
+			}
+		}
+		return deleteDoneMsg{name: name, err: err, holders: held}
+	}
+}
+
+func (m model) onDeleteDone(msg deleteDoneMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case errors.Is(msg.err, domain.ErrVolumeInUse) && len(msg.holders) > 0:
+		var running, stopped []string
+		for _, c := range msg.holders {
+			if c.Running() {
+				running = append(running, c.Name)
+			} else {
+				stopped = append(stopped, c.Name)
+			}
+		}
+		if len(running) > 0 {
+			m.notice = fmt.Sprintf("%s is in use by running %s — stop it first (Containers view, 2)", msg.name, strings.Join(running, ", "))
+			return m, m.loadVolumes()
+		}
+		// Only stopped containers hold it: offer to remove them too
+		m.confirmDelete, m.deleteHolders, m.notice = msg.name, stopped, ""
+		return m, m.loadVolumes()
+	case errors.Is(msg.err, domain.ErrNotFound):
+		m.notice = fmt.Sprintf("%s no longer exists", msg.name)
+		return m, m.loadVolumes()
+	case msg.err != nil:
+		m.notice = fmt.Sprintf("failed to delete %s: %v", msg.name, msg.err)
+		return m, nil
+	}
+	delete(m.marked, msg.name)
+	m.notice = fmt.Sprintf("%s %s", m.removedVerb(), msg.name)
+	if len(m.compose[msg.name]) > 0 {
+		m.notice += "; M lists it for recreating"
+	}
+	return m, m.loadVolumes()
+}
+
+// deletePrompt is the header question while a delete awaits y/N.
+func (m model) deletePrompt() string {
+	if len(m.deleteHolders) > 0 {
+		return fmt.Sprintf("  %s is held by stopped %s — remove them and the volume? [y/N]", m.confirmDelete, strings.Join(m.deleteHolders, ", "))
+	}
+	if warning := m.composeWarning(m.confirmDelete); warning != "" {
+		return fmt.Sprintf("  %s is %s — delete it anyway? [y/N]", m.confirmDelete, warning)
+	}
+	return fmt.Sprintf("  Delete volume %s now? [y/N]", m.confirmDelete)
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"dockwatch/internal/snapshot"
+)
+
+// takeSnapshot records the current volumes as the new diff baseline.
+func (m model) takeSnapshot() model {
+	snap := snapshot.New(m.allVols)
+	if err := snap.Save(); err != nil {
+		m.notice = fmt.Sprintf("failed to save snapshot: %v", err)
+		return m
+	}
+	m.snap = snap
+	m.diff = nil
+	m.notice = fmt.Sprintf("snapshot of %d volume(s) saved", len(m.allVols))
+	return m
+}
+
+func (m model) renderDiff() string {
+	if m.snap == nil {
+		return m.pane().Render("Diff:\n  <no snapshot yet — press S or run `dockwatch snapshot`>")
+	}
+
+	var created, removed int
+	var grown int64
+	lines := make([]string, 0, len(m.diff))
+	for _, c := range m.diff {
+		switch c.Kind {
+		case snapshot.Created:
+			created++
+			lines = append(lines, fmt.Sprintf("  + %s (%s)", c.Name, humanSize(c.NewSize)))
+		case snapshot.Removed:
+			removed++
+			lines = append(lines, fmt.Sprintf("  - %s (%s)", c.Name, humanSize(c.OldSize)))
+		case snapshot.Resized:
+			grown += c.NewSize - c.OldSize
+			lines = append(lines, fmt.Sprintf("  ~ %s (%s → %s)", c.Name, humanSize(c.OldSize), humanSize(c.NewSize)))
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "  <no changes>")
+	}
+
+	sign := "+"
+	if grown < 0 {
+		sign, grown = "-", -grown
+	}
+	body := fmt.Sprintf("Diff since %s:\n", m.snap.TakenAt.Local().Format(time.DateTime)) +
+		strings.Join(lines, "\n") +
+		fmt.Sprintf("\n\n%d created, %d removed, resized %s%s\n\n[S] New snapshot   [Tab] Switch", created, removed, sign, humanBytes(grown))
+	return m.pane().Render(body)
+}
+
+// humanSize is humanBytes that keeps "?" for unknown sizes.
+func humanSize(b int64) string {
+	if b < 0 {
+		return "?"
+	}
+	return humanBytes(b)
+}
@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/domain"
+)
+
+// enrichBatch caps how many streamed volumes are applied per update, so a
+// big host repaints the table in chunks rather than once per volume.
+const enrichBatch = 256
+
+// enrichState tracks volumes whose details are still streaming in.
+type enrichState struct {
+	events  chan tea.Msg
+	cancel  context.CancelFunc
+	pending map[string]bool
+	stopped bool // cancelled; pending rows stay incomplete until a reload
+}
+
+// pendingSet returns the names still waiting for details, nil when idle.
+func (e *enrichState) pendingSet() map[string]bool {
+	if e == nil {
+		return nil
+	}
+	return e.pending
+}
+
+// volumeDetailMsg is one enriched volume from the provider.
+type volumeDetailMsg struct{ vol domain.Volume }
+
+// enrichDoneMsg ends the stream.
+type enrichDoneMsg struct{ err error }
+
+// volumeBatchMsg carries every stream message that was ready at once.
+type volumeBatchMsg struct {
+	events chan tea.Msg
+	vols   []domain.Volume
+	done   bool
+	err    error
+}
+
+// startEnrich inspects and sizes m.allVols in a goroutine, cancelling any
+// stream still running from a previous reload.
+func (m model) startEnrich() (model, tea.Cmd) {
+	if m.enrich != nil {
+		m.enrich.cancel()
+		m.enrich = nil
+	}
+	if m.provider == nil || len(m.allVols) == 0 {
+		return m, nil
+	}
+
+	names := make([]string, len(m.allVols))
+	pending := make(map[string]bool, len(m.allVols))
+	for i, v := range m.allVols {
+		names[i] = v.Name
+		pending[v.Name] = true
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	events := make(chan tea.Msg, enrichBatch)
+	m.enrich = &enrichState{events: events, cancel: cancel, pending: pending}
+
+	prov := m.provider
+	send := func(msg tea.Msg) {
+		select {
+		case events <- msg:
+		case <-ctx.Done():
+		}
+	}
+	go func() {
+		defer close(events)
+		err := prov.EnrichVolumes(ctx, names, func(v domain.Volume) {
+			send(volumeDetailMsg{vol: v})
+		})
+		send(enrichDoneMsg{err: err})
+	}()
+	return m, waitVolumes(events)
+}
+
+// waitVolumes blocks for the next stream message, then drains whatever
+// else is already queued into the same batch.
+func waitVolumes(events chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		batch := volumeBatchMsg{events: events}
+		add := func(msg tea.Msg) {
+			switch msg := msg.(type) {
+			case volumeDetailMsg:
+				batch.vols = append(batch.vols, msg.vol)
+			case enrichDoneMsg:
+				batch.done, batch.err = true, msg.err
+			}
+		}
+		// A closed channel means the stream was cancelled before its done message
+		closed := func() { batch.done, batch.err = true, context.Canceled }
+		msg, ok := <-events
+		if !ok {
+			closed()
+			return batch
+		}
+		add(msg)
+		for !batch.done && len(batch.vols) < enrichBatch {
+			select {
+			case msg, ok := <-events:
+				if !ok {
+					closed()
+					return batch
+				}
+				add(msg)
+			default:
+				return batch
+			}
+		}
+		return batch
+	}
+}
+
+// onVolumeBatch updates the streamed rows in place. Batches from a stream
+// that a newer reload replaced are dropped.
+func (m model) onVolumeBatch(msg volumeBatchMsg) (tea.Model, tea.Cmd) {
+	if m.enrich == nil || msg.events != m.enrich.events {
+		return m, nil
+	}
+
+	index := make(map[string]int, len(m.allVols))
+	for i, v := range m.allVols {
+		index[v.Name] = i
+	}
+	for _, v := range msg.vols {
+		i, ok := index[v.Name]
+		if !ok {
+			continue
+		}
+		if v.Driver == "" {
+			v.Driver = m.allVols[i].Driver
+		}
+		m.allVols[i] = v
+		delete(m.enrich.pending, v.Name)
+	}
+
+	if !msg.done {
+		m = m.applyVolumeView()
+		return m, waitVolumes(msg.events)
+	}
+
+	m.enrich.cancel()
+	if errors.Is(msg.err, context.Canceled) {
+		// Keep the unfinished rows visibly incomplete rather than guessing
+		st := *m.enrich
+		st.stopped = true
+		m.enrich = &st
+		m = m.applyVolumeView()
+		m.notice = fmt.Sprintf("loading details cancelled: %d of %d volumes complete (r reloads)", len(m.allVols)-len(st.pending), len(m.allVols))
+		return m, nil
+	}
+	m.enrich = nil
+	if msg.err != nil {
+		m.notice = msg.err.Error()
+	}
+	m = m.applyVolumeView()
+	m = m.refreshProjects()
+	if m.snap != nil {
+		m.diff = m.snap.Diff(m.allVols)
+	}
+	return m, nil
+}
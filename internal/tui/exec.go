@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"fmt"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/domain"
+)
+
+// execReadyMsg carries a prepared interactive command (or why there is none).
+type execReadyMsg struct {
+	container string
+	cmd       *exec.Cmd
+	err       error
+}
+
+// execDoneMsg is sent when the interactive session ends and the TUI resumes.
+type execDoneMsg struct {
+	container string
+	err       error
+}
+
+// prepareExec detects a shell in the background; running the detection
+// inline would freeze the UI for a few round-trips to the daemon.
+func (m model) prepareExec(c domain.Container) tea.Cmd {
+	ctx := m.ctx
+	return func() tea.Msg {
+		cmd, err := dockercli.ExecShellCommand(ctx, c.ID)
+		return execReadyMsg{container: c.Name, cmd: cmd, err: err}
+	}
+}
+
+// runExec suspends the TUI and hands the terminal to the shell.
+func (m model) runExec(msg execReadyMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = msg.err.Error()
+		return m, nil
+	}
+	name := msg.container
+	return m, tea.ExecProcess(msg.cmd, func(err error) tea.Msg {
+		return execDoneMsg{container: name, err: err}
+	})
+}
+
+func (m model) execDone(msg execDoneMsg) model {
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("exec in %s: %v", msg.container, msg.err)
+	} else {
+		m.notice = fmt.Sprintf("left %s", msg.container)
+	}
+	return m
+}
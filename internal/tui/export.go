@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// exportColumns is the header of a volume export: every table column plus
+// what the table has no room for.
+var exportColumns = []string{
+	"name", "size_bytes", "size", "attached", "project", "status",
+	"driver", "created_at", "last_used", "mountpoint", "labels",
+}
+
+// exportVolumes writes the rows currently shown to a CSV (or TSV when tsv
+// is set) file in the working directory.
+func (m model) exportVolumes(tsv bool) model {
+	ext := tern(tsv, "tsv", "csv")
+	file := fmt.Sprintf("dockwatch-volumes-%s.%s", time.Now().Format("20060102-150405"), ext)
+	if err := writeVolumes(file, m.vols, m.cfg.IsIgnored, tsv); err != nil {
+		m.notice = fmt.Sprintf("export failed: %v", err)
+		return m
+	}
+	m.notice = fmt.Sprintf("exported %d volume(s) to %s", len(m.vols), file)
+	return m
+}
+
+func writeVolumes(file string, vols []domain.Volume, ignored func(string) bool, tsv bool) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if tsv {
+		w.Comma = '\t'
+	}
+	w.Write(exportColumns)
+	for _, v := range vols {
+		status := v.State.Label()
+		if ignored(v.Name) {
+			status = "IGNORED"
+		}
+		created := ""
+		if !v.CreatedAt.IsZero() {
+			created = v.CreatedAt.Format(time.RFC3339)
+		}
+		w.Write([]string{
+			v.Name,
+			strconv.FormatInt(v.SizeBytes, 10),
+			v.SizeHuman(),
+			strings.Join(v.Attached, ","),
+			v.Project,
+			status,
+			v.Driver,
+			created,
+			formatLastUsed(v),
+			v.Mountpoint,
+			formatLabels(v.Labels),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// formatLastUsed is "in use", an RFC 3339 time, or empty when unknown.
+func formatLastUsed(v domain.Volume) string {
+	switch {
+	case v.InUse:
+		return "in use"
+	case v.LastUsed.IsZero():
+		return ""
+	}
+	return v.LastUsed.Format(time.RFC3339)
+}
+
+// formatLabels renders labels as sorted key=value pairs separated by ";".
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// finderRows is how many of the best matches the finder lists.
+const finderRows = 10
+
+// finder is the ctrl+f fuzzy search over the shown volume names.
+type finder struct {
+	query   string
+	cursor  int
+	matches []int // indexes into m.vols, best match first
+}
+
+// openFinder starts a search with every shown volume matching.
+func (m model) openFinder() model {
+	f := &finder{}
+	f.matches = m.fuzzyMatches("")
+	m.finder = f
+	return m
+}
+
+// updateFinder edits the query; ↑/↓ pick a match, Enter jumps to it and
+// Esc closes the finder where the cursor was.
+func (m model) updateFinder(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	f := *m.finder
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlF:
+		m.finder = nil
+		return m, nil
+	case tea.KeyEnter:
+		m.finder = nil
+		if f.cursor < len(f.matches) {
+			m.table.SetCursor(f.matches[f.cursor])
+			m = m.followVisual()
+		}
+		return m, nil
+	case tea.KeyUp, tea.KeyCtrlP:
+		f.cursor = max(f.cursor-1, 0)
+	case tea.KeyDown, tea.KeyCtrlN:
+		f.cursor = min(f.cursor+1, max(min(len(f.matches), finderRows)-1, 0))
+	case tea.KeyBackspace:
+		if r := []rune(f.query); len(r) > 0 {
+			f.query = string(r[:len(r)-1])
+		}
+		f.matches, f.cursor = m.fuzzyMatches(f.query), 0
+	case tea.KeyRunes, tea.KeySpace:
+		f.query += string(msg.Runes)
+		f.matches, f.cursor = m.fuzzyMatches(f.query), 0
+	}
+	m.finder = &f
+	return m, nil
+}
+
+// fuzzyMatches returns the rows whose name contains query's characters in
+// order, best first; ties keep table order.
+func (m model) fuzzyMatches(query string) []int {
+	type hit struct{ row, score int }
+	var hits []hit
+	for i, v := range m.vols {
+		if score, ok := fuzzyScore(v.Name, query); ok {
+			hits = append(hits, hit{i, score})
+		}
+	}
+	slices.SortStableFunc(hits, func(a, b hit) int { return cmp.Compare(b.score, a.score) })
+	rows := make([]int, len(hits))
+	for i, h := range hits {
+		rows[i] = h.row
+	}
+	return rows
+}
+
+// fuzzyScore matches query against name fzf-style: every query character
+// must appear in order, case-insensitively. Consecutive characters and
+// matches at the start of a word (after -, _, . or /) score higher, and
+// shorter names win among equals.
+func fuzzyScore(name, query string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, true
+	}
+	score, qi, prev := 0, 0, -2
+	runes := []rune(name)
+	for i, r := range runes {
+		if qi == len(q) {
+			break
+		}
+		if unicode.ToLower(r) != q[qi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 4
+		}
+		if i == 0 || strings.ContainsRune("-_./:", runes[i-1]) {
+			score += 3
+		}
+		prev = i
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score*100 - len(runes), true
+}
+
+func (m model) renderFinder() string {
+	f := m.finder
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Find volume: %s%s  %d/%d\n\n", f.query, glyphs.caret, len(f.matches), len(m.vols))
+	if len(f.matches) == 0 {
+		fmt.Fprintln(sb, dimStyle.Render("  no match"))
+	}
+	for i, row := range f.matches[:min(len(f.matches), finderRows)] {
+		line := m.vols[row].Name
+		if i == f.cursor {
+			line = selectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		fmt.Fprintln(sb, line)
+	}
+	fmt.Fprintf(sb, "\n[Type] Search  [↑/↓] Pick  [Enter] Jump  [Esc] Close")
+	return m.pane().Render(sb.String())
+}
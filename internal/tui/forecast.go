@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/history"
+)
+
+// forecastWindow is how much history the header's fill-up forecast is
+// fitted on, and forecastWarn how close a fill-up is flagged.
+const (
+	forecastWindow = 7 * day
+	forecastWarn   = 14.0 // days
+)
+
+// forecastMsg carries the disk forecast from the history database.
+type forecastMsg struct {
+	forecast history.Forecast
+	err      error
+}
+
+// loadForecast reads the forecast in the background. Without recorded
+// history there is nothing to show, so the database is never created here.
+func loadForecast() tea.Cmd {
+	return func() tea.Msg {
+		if _, err := os.Stat(history.Path()); err != nil {
+			return forecastMsg{err: history.ErrNotEnoughHistory}
+		}
+		db, err := history.Open(history.Path())
+		if err != nil {
+			return forecastMsg{err: err}
+		}
+		defer db.Close()
+		f, err := db.Forecast(context.Background(), forecastWindow, time.Now())
+		return forecastMsg{forecast: f, err: err}
+	}
+}
+
+func (m model) setForecast(msg forecastMsg) model {
+	switch {
+	case errors.Is(msg.err, history.ErrNotEnoughHistory):
+		m.forecast = nil
+	case msg.err != nil:
+		m.notice = msg.err.Error()
+	default:
+		m.forecast = &msg.forecast
+	}
+	return m
+}
+
+// renderForecast is the header's disk outlook, empty without a forecast.
+func (m model) renderForecast() string {
+	f := m.forecast
+	if f == nil {
+		return ""
+	}
+	if !f.Growing() {
+		return dimStyle.Render(fmt.Sprintf("  Disk: %s free, not growing", domain.HumanSize(f.Free)))
+	}
+	s := fmt.Sprintf("  Disk: %s free, full in ~%s", domain.HumanSize(f.Free), domain.HumanAge(time.Duration(f.DaysLeft()*float64(day))))
+	if f.DaysLeft() < forecastWarn {
+		return warnStyle.Render(s)
+	}
+	return dimStyle.Render(s)
+}
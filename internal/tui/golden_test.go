@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/muesli/termenv"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/domain"
+	"dockwatch/internal/provider/fake"
+)
+
+// Run with -update to rewrite testdata/ after an intended layout change.
+
+var goldenSizes = []tea.WindowSizeMsg{{Width: 60, Height: 20}, {Width: 80, Height: 24}, {Width: 140, Height: 45}, {Width: 200, Height: 50}}
+
+// goldenModel is a Volumes view with every volume's details in and nothing
+// running in the background. Ages are relative to now, so they render the
+// same on every run.
+func goldenModel(t *testing.T, size tea.WindowSizeMsg) model {
+	t.Helper()
+	t.Setenv("DOCKWATCH_CONFIG_DIR", t.TempDir())
+	t.Setenv("DOCKWATCH_STATE_DIR", t.TempDir())
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	m := newModel(cfg)
+	m.provider = fake.New()
+	m.allVols = []domain.Volume{
+		{Name: "pgdata", Driver: "local", SizeBytes: 3 << 30, Attached: []string{"db"}, Project: "shop", InUse: true, State: domain.InUseRunning,
+			CreatedAt: now.Add(-90 * day), Labels: map[string]string{"com.docker.compose.project": "shop"}},
+		{Name: "ci-cache", Driver: "local", SizeBytes: 200 << 20, State: domain.Orphaned, CreatedAt: now.Add(-40 * day), LastUsed: now.Add(-12 * day)},
+		{Name: "scratch", Driver: "local", SizeBytes: 10 << 20, State: domain.Orphaned, CreatedAt: now.Add(-3 * day)},
+		{Name: "nfs-media", Driver: "local", SizeBytes: -1, Attached: []string{"jellyfin"}, State: domain.InUseStopped, CreatedAt: now.Add(-400 * day)},
+	}
+	m = m.resize(size)
+	return m.applyVolumeView()
+}
+
+func TestGolden(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+
+	screens := []struct {
+		name  string
+		setup func(m model) model
+	}{
+		{"table", func(m model) model { return m }},
+		{"details", func(m model) model {
+			m.table.SetCursor(1)
+			m.active = paneDetails
+			return m
+		}},
+		{"plan", func(m model) model {
+			m.marked["ci-cache"], m.marked["scratch"] = true, true
+			m.table.SetRows(m.volumeRows())
+			m.active = panePlan
+			return m
+		}},
+		{"error", func(m model) model {
+			next, _ := m.setVolumes(volumesMsg{err: &domain.KindError{Kind: domain.ErrPermission,
+				Msg: "permission denied while trying to connect to the Docker daemon socket"}})
+			return next
+		}},
+		{"unreachable", func(m model) model {
+			m.health = &healthState{err: errors.New("agent unreachable: dial tcp 10.0.0.5:8080: connect: connection refused")}
+			return m
+		}},
+	}
+	for _, s := range screens {
+		for _, size := range goldenSizes {
+			t.Run(fmt.Sprintf("%s/%dx%d", s.name, size.Width, size.Height), func(t *testing.T) {
+				m := s.setup(goldenModel(t, size))
+				golden.RequireEqual(t, []byte(m.View()))
+			})
+		}
+	}
+}
+
+func TestGoldenPlain(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	setAccessibility(true, true, false)
+	t.Cleanup(func() { setAccessibility(false, false, false) })
+
+	size := tea.WindowSizeMsg{Width: 80, Height: 24}
+	m := goldenModel(t, size)
+	m.table.SetCursor(1)
+	m.marked["ci-cache"] = true
+	m.table.SetRows(m.volumeRows())
+	golden.RequireEqual(t, []byte(m.View()))
+}
+
+func TestGoldenASCII(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	setAccessibility(false, false, true)
+	t.Cleanup(func() { setAccessibility(false, false, false) })
+
+	m := goldenModel(t, tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.marked["ci-cache"], m.marked["scratch"] = true, true
+	m.table.SetRows(m.volumeRows())
+	m.active = panePlan
+	golden.RequireEqual(t, []byte(m.View()))
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"strings"
+
+	"dockwatch/internal/domain"
+)
+
+// graphNode is one resource in the dependency tree.
+type graphNode struct {
+	label    string
+	children []graphNode
+}
+
+// containerNode shows what a container depends on: its image, volumes,
+// bind mounts and networks.
+func (m model) containerNode(c domain.Container) graphNode {
+	n := graphNode{label: "container " + c.Name + " (" + c.State + ")"}
+	n.children = append(n.children, graphNode{label: "image " + c.Image})
+	for _, mt := range c.Mounts {
+		switch mt.Type {
+		case "volume":
+			name := mt.Name
+			if domain.IsAnonymousVolume(name) {
+				name = name[:12] + "… (anonymous)"
+			}
+			n.children = append(n.children, graphNode{label: "volume " + name + " → " + mt.Destination})
+		default:
+			n.children = append(n.children, graphNode{label: mt.Type + " " + mt.Source + " → " + mt.Destination})
+		}
+	}
+	for _, net := range c.Networks {
+		n.children = append(n.children, graphNode{label: "network " + net})
+	}
+	return n
+}
+
+// volumeNode lists the containers that would break if the volume went away.
+func (m model) volumeNode(v domain.Volume) graphNode {
+	n := graphNode{label: "volume " + v.Name}
+	seen := map[string]bool{}
+	for _, c := range m.containers {
+		for _, mt := range c.Mounts {
+			if mt.Type == "volume" && mt.Name == v.Name && !seen[c.Name] {
+				seen[c.Name] = true
+				n.children = append(n.children, m.containerNode(c))
+			}
+		}
+	}
+	// Containers not loaded yet: fall back to the names from the volume listing
+	for _, name := range v.Attached {
+		if !seen[name] {
+			n.children = append(n.children, graphNode{label: "container " + name})
+		}
+	}
+	return n
+}
+
+// imageNode lists the containers created from an image.
+func (m model) imageNode(img domain.Image) graphNode {
+	n := graphNode{label: "image " + img.Ref()}
+	for _, c := range m.containers {
+		if (c.ImageID != "" && strings.HasPrefix(c.ImageID, img.ID)) || c.Image == img.Ref() {
+			n.children = append(n.children, m.containerNode(c))
+		}
+	}
+	return n
+}
+
+// renderTree draws a node and its descendants with box-drawing guides.
+func renderTree(n graphNode) string {
+	sb := &strings.Builder{}
+	sb.WriteString(n.label + "\n")
+	writeChildren(sb, n.children, "")
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func writeChildren(sb *strings.Builder, children []graphNode, prefix string) {
+	for i, c := range children {
+		branch, next := glyphs.branch, glyphs.pipe
+		if i == len(children)-1 {
+			branch, next = glyphs.last, "    "
+		}
+		sb.WriteString(prefix + branch + c.label + "\n")
+		writeChildren(sb, c.children, prefix+next)
+	}
+}
+
+func (m model) renderGraph(root graphNode) string {
+	body := "Dependencies:\n" + renderTree(root)
+	if len(root.children) == 0 {
+		body += "\n  <nothing depends on this>"
+	}
+	return m.pane().Render(body + "\n\n[G] Close")
+}
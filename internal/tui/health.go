@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/doctor"
+	"dockwatch/internal/provider"
+)
+
+// healthState is the startup checklist shown while no provider is connected.
+type healthState struct {
+	err      error          // why connecting failed
+	report   *doctor.Report // nil until the first run finishes, and for agents
+	checking bool
+}
+
+// healthMsg carries a diagnostics run together with a fresh connect attempt.
+type healthMsg struct {
+	report *doctor.Report
+	prov   provider.Provider
+	err    error
+}
+
+// runHealth diagnoses the local Docker setup and tries to connect again.
+// Against a remote agent the daemon checks say nothing useful, so only the
+// connection is retried.
+func (m model) runHealth() tea.Cmd {
+	ctx := m.ctx
+	return func() tea.Msg {
+		var report *doctor.Report
+		if os.Getenv("DOCKWATCH_REMOTE") == "" {
+			r := doctor.Run(ctx)
+			report = &r
+		}
+		prov, err := getDockerProvider()
+		return healthMsg{report: report, prov: prov, err: err}
+	}
+}
+
+func (m model) onHealth(msg healthMsg) (tea.Model, tea.Cmd) {
+	if m.health == nil {
+		return m, nil
+	}
+	if msg.err == nil {
+		m = m.connected(msg.prov)
+		m.health = nil
+		return m, tea.Batch(m.loadUsage(), loadForecast(), waitRetry(m.retries), waitJobs(m.jobs), m.resumeTab())
+	}
+	m.health = &healthState{err: msg.err, report: msg.report}
+	return m, nil
+}
+
+// connected installs prov as the model's provider behind the orphan guard
+// and retry middlewares, and the dry-run and trace ones when requested.
+func (m model) connected(prov provider.Provider) model {
+	if d, ok := prov.(*dockercli.DockerProvider); ok {
+		d.SetLimits(dockercli.LimitsFrom(m.cfg))
+		d.SetHelper(dockercli.HelperFrom(m.cfg))
+	}
+	mws := []provider.Middleware{provider.GuardOrphans(m.orphanGuard())}
+	if provider.DryRunRequested() {
+		mws = append(mws, provider.WithDryRun(stateLogger("dry-run.log")))
+		m.dryRun = true
+	}
+	mws = append(mws, m.retryMiddleware())
+	if provider.TraceRequested() {
+		mws = append(mws, provider.Logging(stateLogger("trace.log")))
+	}
+	m.provider = provider.Chain(prov, mws...)
+	return m
+}
+
+// orphanGuard is the orphans section of the config.
+func (m model) orphanGuard() provider.OrphanGuard {
+	return provider.OrphanGuard{MinIdle: m.cfg.OrphanMinIdle(), Labels: m.cfg.Orphans.KeepLabels}
+}
+
+func (m model) updateHealth(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "r":
+		if !m.health.checking {
+			st := *m.health
+			st.checking = true
+			m.health = &st
+			return m, m.runHealth()
+		}
+	case "q":
+		return m.quit()
+	}
+	return m, nil
+}
+
+func (m model) viewHealth() string {
+	h := m.health
+	var b strings.Builder
+	b.WriteString(m.title("Dockwatch can't reach Docker") + "\n")
+	b.WriteString(dimStyle.Render(h.err.Error()) + "\n\n")
+
+	switch {
+	case h.report == nil && h.checking:
+		b.WriteString("Running diagnostics…\n")
+	case h.report == nil:
+		b.WriteString("Check that DOCKWATCH_REMOTE points at a running agent and DOCKWATCH_TOKEN is valid.\n")
+	default:
+		for _, c := range h.report.Checks {
+			b.WriteString(fmt.Sprintf("%s %-14s %s\n", checkMark(c.Status), c.Name, dimStyle.Render(c.Detail)))
+			if c.Hint != "" {
+				b.WriteString(fmt.Sprintf("  %-14s → %s\n", "", c.Hint))
+			}
+		}
+	}
+
+	help := "[R] Re-check  [Q] Quit"
+	if h.checking && h.report != nil {
+		help = "Re-checking…  [Q] Quit"
+	}
+	return b.String() + "\n" + m.pane().Render(help)
+}
+
+func checkMark(s doctor.Status) string {
+	switch s {
+	case doctor.OK:
+		return okStyle.Render(glyphs.ok)
+	case doctor.Warn:
+		return warnStyle.Render(glyphs.warn)
+	case doctor.Fail:
+		return failStyle.Render(glyphs.fail)
+	}
+	return dimStyle.Render(glyphs.skip)
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleIgnore adds or removes the volume under the cursor from the
+// persistent ignore list and saves the config immediately.
+func (m model) toggleIgnore() model {
+	idx := m.table.Cursor()
+	if idx < 0 || idx >= len(m.vols) {
+		return m
+	}
+	name := m.vols[idx].Name
+	if m.cfg.ToggleIgnore(name) {
+		m.notice = fmt.Sprintf("ignoring %s", name)
+	} else {
+		m.notice = fmt.Sprintf("no longer ignoring %s", name)
+	}
+	return m.saveIgnore()
+}
+
+// updateIgnore handles keys while the ignore pane is active. It reports
+// whether the key was consumed.
+func (m model) updateIgnore(msg tea.KeyMsg) (bool, model) {
+	switch msg.String() {
+	case "up":
+		if m.ignoreCursor > 0 {
+			m.ignoreCursor--
+		}
+	case "down":
+		if m.ignoreCursor < len(m.cfg.Ignore)-1 {
+			m.ignoreCursor++
+		}
+	case "x", "delete":
+		if m.ignoreCursor >= len(m.cfg.Ignore) {
+			return true, m
+		}
+		entry := m.cfg.Ignore[m.ignoreCursor]
+		m.cfg.ToggleIgnore(entry)
+		if m.ignoreCursor >= len(m.cfg.Ignore) && m.ignoreCursor > 0 {
+			m.ignoreCursor--
+		}
+		m.notice = fmt.Sprintf("no longer ignoring %s", entry)
+		return true, m.saveIgnore()
+	default:
+		return false, m
+	}
+	return true, m
+}
+
+// saveIgnore persists the config and refreshes row statuses.
+func (m model) saveIgnore() model {
+	if err := m.cfg.Save(); err != nil {
+		m.notice = fmt.Sprintf("failed to save config: %v", err)
+	}
+	m.table.SetRows(m.volumeRows())
+	return m
+}
+
+func (m model) renderIgnore() string {
+	lines := make([]string, 0, len(m.cfg.Ignore))
+	for i, entry := range m.cfg.Ignore {
+		cursor := "  "
+		if i == m.ignoreCursor {
+			cursor = "> "
+		}
+		lines = append(lines, cursor+entry)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "  <nothing ignored>")
+	}
+	body := "Ignore List:\n" + strings.Join(lines, "\n") + "\n\n[↑/↓] Move   [X] Remove   [Tab] Switch"
+	return m.pane().Render(body)
+}
@@ -0,0 +1,271 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/plan"
+)
+
+// imagesMsg carries the result of an asynchronous image listing.
+type imagesMsg struct {
+	images []domain.Image
+	err    error
+}
+
+func newImageTable() table.Model {
+	cols := []table.Column{
+		{Title: " ", Width: 1},
+		{Title: "Repository", Width: 16},
+		{Title: "Tag", Width: 11},
+		{Title: "Size", Width: 6},
+		{Title: "Unique", Width: 6},
+		{Title: "Status", Width: 8},
+		{Title: "Upstream", Width: 8},
+		{Title: "Vulns", Width: 6},
+	}
+	t := table.New(table.WithColumns(cols), table.WithFocused(true), table.WithStyles(tableStyles()))
+	t.KeyMap.LineUp.SetKeys("up")
+	t.KeyMap.LineDown.SetKeys("down")
+	return t
+}
+
+// loadImages lists images in the background.
+func (m model) loadImages() tea.Cmd {
+	if m.provider == nil {
+		return nil
+	}
+	prov, ctx := m.provider, m.ctx
+	return func() tea.Msg {
+		imgs, err := prov.ListImages(ctx)
+		return imagesMsg{images: imgs, err: err}
+	}
+}
+
+func (m model) setImages(msg imagesMsg) (model, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = msg.err.Error()
+		return m, nil
+	}
+	// Sort by repository so tags of one repository sit together
+	imgs := msg.images
+	sort.SliceStable(imgs, func(i, j int) bool {
+		if imgs[i].Repository != imgs[j].Repository {
+			return imgs[i].Repository < imgs[j].Repository
+		}
+		return imgs[i].Tag < imgs[j].Tag
+	})
+	m.images = imgs
+	m.itable.SetRows(m.imageRows())
+	// Images are also listed for the reclaimable estimate; only query
+	// registries once someone looks at them
+	if m.cfg.Images.CheckRegistry && m.resource == resImages {
+		return m.checkRegistry()
+	}
+	return m, nil
+}
+
+// imageRows derives display rows from the image list and marks; the
+// repository name is printed once per group.
+func (m model) imageRows() []table.Row {
+	rows := make([]table.Row, 0, len(m.images))
+	now, minAge := time.Now(), m.cfg.UnusedImageAge()
+	prev := ""
+	for _, img := range m.images {
+		repo := img.Repository
+		if repo == prev {
+			repo = "  " + glyphs.dot
+		}
+		prev = img.Repository
+		rows = append(rows, table.Row{
+			tern(m.imarked[img.Ref()], glyphs.mark, " "),
+			repo,
+			img.Tag,
+			shortBytes(img.SizeBytes),
+			tern(img.UniqueSize < 0, "?", shortBytes(img.UniqueSize)),
+			imageStatus(img, now, minAge),
+			m.upstreamStatus(img),
+			m.vulnStatus(img),
+		})
+	}
+	return rows
+}
+
+func (m model) updateImages(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case " ":
+		idx := m.itable.Cursor()
+		if idx >= 0 && idx < len(m.images) {
+			ref := m.images[idx].Ref()
+			m.imarked[ref] = !m.imarked[ref]
+			m.itable.SetRows(m.imageRows())
+		}
+		return m, nil
+	case "u":
+		return m.markStaleImages(), nil
+	case "p":
+		m.showImagePlan = !m.showImagePlan
+		return m, nil
+	case "P":
+		idx := m.itable.Cursor()
+		if idx < 0 || idx >= len(m.images) || m.images[idx].Dangling() {
+			return m, nil
+		}
+		return m.startPull(m.images[idx].Ref())
+	case "v":
+		return m.scanImage()
+	case "i":
+		m.showImageInfo = !m.showImageInfo
+		return m, nil
+	case "g":
+		m.showGraph = !m.showGraph
+		return m, m.loadContainers()
+	case "c":
+		m.notice = "checking registries… (ctrl+c cancels)"
+		return m.checkRegistry()
+	case "r":
+		return m, m.loadImages()
+	}
+
+	var cmd tea.Cmd
+	m.itable, cmd = m.itable.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewImages() string {
+	header := m.renderTabs() + "\n" + m.title("Docker Images")
+	var total int64
+	for _, img := range m.images {
+		total += img.SizeBytes
+	}
+	statusInfo := fmt.Sprintf("Images: %d  Total: %s", len(m.images), humanBytes(total))
+	if m.notice != "" {
+		statusInfo += "  — " + m.notice
+	}
+	header = header + "\n" + dimStyle.Render(statusInfo)
+
+	lower := m.pane().Render("[↑/↓] Move  [Space] Mark  [U] Mark unused  [C] Check upstream  [Shift+P] Pull  [V] Scan  [I] Details  [G] Graph  [P] Plan  [R] Refresh  [1-5] Views  [Q] Quit")
+	switch {
+	case m.pull != nil:
+		lower = m.renderPull()
+	case m.showImagePlan:
+		lower = m.renderImagePlan()
+	case m.showImageInfo:
+		lower = m.renderImageInfo()
+	case m.showGraph:
+		if idx := m.itable.Cursor(); idx >= 0 && idx < len(m.images) {
+			lower = m.renderGraph(m.imageNode(m.images[idx]))
+		}
+	}
+	return header + "\n" + m.pane().Render(m.itable.View()) + "\n" + lower
+}
+
+// selectedImage returns the image under the cursor in the Images view.
+func (m model) selectedImage() (domain.Image, bool) {
+	idx := m.itable.Cursor()
+	if idx < 0 || idx >= len(m.images) {
+		return domain.Image{}, false
+	}
+	return m.images[idx], true
+}
+
+// provenance are the OCI annotation labels shown in the details pane.
+var provenance = []struct{ label, title string }{
+	{"org.opencontainers.image.source", "Source"},
+	{"org.opencontainers.image.revision", "Revision"},
+	{"org.opencontainers.image.version", "Version"},
+}
+
+// renderImageInfo describes the image under the cursor: where it came
+// from, whether the registry has moved its tag on, and its scan.
+func (m model) renderImageInfo() string {
+	img, ok := m.selectedImage()
+	if !ok {
+		return m.pane().Render("Image Details:\n  <no image selected>")
+	}
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Image Details: %s\n", img.Ref())
+	fmt.Fprintf(sb, "ID: %s\n", strings.TrimPrefix(img.ID, "sha256:"))
+	fmt.Fprintf(sb, "Digest: %s\n", ifEmpty(shortDigest(img.Digest()), "<none, built locally or never pushed>"))
+	if u, ok := m.upstream[img.Ref()]; ok && u.err == nil && u.digest != "" {
+		line := "Registry: " + shortDigest(u.digest)
+		if m.stale(img) {
+			line = warnStyle.Render(line + " — the tag has moved on; this copy is stale")
+		}
+		sb.WriteString(line + "\n")
+	}
+	for _, p := range provenance {
+		if v := img.Labels[p.label]; v != "" {
+			fmt.Fprintf(sb, "%s: %s\n", p.title, v)
+		}
+	}
+	fmt.Fprintf(sb, "Size: %s (%s unique)\n", humanBytes(img.SizeBytes), tern(img.UniqueSize < 0, "?", humanBytes(img.UniqueSize)))
+	if !img.CreatedAt.IsZero() {
+		fmt.Fprintf(sb, "Created: %s (%d days ago)\n", img.CreatedAt.Format("2006-01-02"), int(time.Since(img.CreatedAt).Hours()/24))
+	}
+	fmt.Fprintf(sb, "Containers: %d\n", img.Containers)
+	m.vulnDetails(sb, img)
+	return m.pane().Render(sb.String() + "\n[V] Scan  [C] Check upstream  [I] Close")
+}
+
+// shortDigest abbreviates "sha256:<64 hex>" to its first 12 hex digits.
+func shortDigest(d string) string {
+	if algo, hex, ok := strings.Cut(d, ":"); ok && len(hex) > 12 {
+		return algo + ":" + hex[:12]
+	}
+	return d
+}
+
+// markStaleImages marks every dangling image and every image unused for the
+// configured number of days — the image counterpart of marking orphans.
+func (m model) markStaleImages() model {
+	now, minAge := time.Now(), m.cfg.UnusedImageAge()
+	n := 0
+	for _, img := range m.images {
+		if (img.Dangling() || img.Unused(now, minAge)) && !m.imarked[img.Ref()] {
+			m.imarked[img.Ref()] = true
+			n++
+		}
+	}
+	m.notice = fmt.Sprintf("marked %d dangling/unused image(s)", n)
+	m.itable.SetRows(m.imageRows())
+	return m
+}
+
+func imageStatus(img domain.Image, now time.Time, minAge time.Duration) string {
+	switch {
+	case img.Dangling():
+		return "DANGLING"
+	case img.Containers > 0:
+		return "IN USE"
+	case img.Unused(now, minAge):
+		return "UNUSED"
+	}
+	return ""
+}
+
+func (m model) renderImagePlan() string {
+	groups, total := plan.ImagePlan(m.images, m.imarked)
+	sb := &strings.Builder{}
+	sb.WriteString("Image Prune Plan:\n")
+	if len(groups) == 0 {
+		sb.WriteString("  <none selected>\n")
+	}
+	for _, g := range groups {
+		fmt.Fprintf(sb, "  %s — reclaim %s\n", g.Repository, humanBytes(g.Reclaim))
+		for _, it := range g.Items {
+			fmt.Fprintf(sb, "    %s %s (%s)\n", glyphs.check, ifEmpty(it.Image.Tag, it.Image.ID), humanBytes(it.Reclaim))
+			for _, w := range it.Warnings {
+				sb.WriteString(warnStyle.Render("      ! "+w) + "\n")
+			}
+		}
+	}
+	fmt.Fprintf(sb, "\nTotal space to reclaim: %s (unique layers only)", humanBytes(total))
+	return m.pane().Render(sb.String())
+}
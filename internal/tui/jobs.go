@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"dockwatch/internal/jobs"
+)
+
+// jobWorkers is how many background jobs run at once.
+const jobWorkers = 2
+
+// jobsMsg reports that the jobs changed, so the Jobs pane redraws.
+type jobsMsg struct{}
+
+// waitJobs delivers the next change of q.
+func waitJobs(q *jobs.Queue) tea.Cmd {
+	return func() tea.Msg {
+		<-q.Changed()
+		return jobsMsg{}
+	}
+}
+
+// enqueue runs fn as a background job and delivers the message it returns,
+// or cancelled when the job is cancelled before it starts.
+func (m model) enqueue(kind, title string, cancelled tea.Msg, fn func(ctx context.Context, progress jobs.Progress) (tea.Msg, error)) tea.Cmd {
+	result := make(chan tea.Msg, 1)
+	id := m.jobs.Add(kind, title, func(ctx context.Context, progress jobs.Progress) error {
+		msg, err := fn(ctx, progress)
+		result <- msg
+		return err
+	})
+	done := m.jobs.Done(id)
+	return func() tea.Msg {
+		select {
+		case msg := <-result:
+			return msg
+		case <-done:
+			// A job that ran has sent its result before finishing
+			select {
+			case msg := <-result:
+				return msg
+			default:
+				return cancelled
+			}
+		}
+	}
+}
+
+// updateJobs handles keys while the Jobs pane is active. It reports
+// whether the key was consumed.
+func (m model) updateJobs(msg tea.KeyMsg) (bool, model) {
+	list := m.jobs.Jobs()
+	switch msg.String() {
+	case "up":
+		m.jobCursor = max(m.jobCursor-1, 0)
+	case "down":
+		m.jobCursor = min(m.jobCursor+1, max(len(list)-1, 0))
+	case "x", "delete":
+		if m.jobCursor < len(list) {
+			j := list[m.jobCursor]
+			if m.jobs.Cancel(j.ID) {
+				m.notice = fmt.Sprintf("cancelling job %d (%s)…", j.ID, j.Title)
+			}
+		}
+	case "X":
+		m.jobs.Clear()
+		m.jobCursor = 0
+		m.notice = "cleared finished jobs"
+	default:
+		return false, m
+	}
+	return true, m
+}
+
+func (m model) renderJobs() string {
+	list := m.jobs.Jobs()
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Jobs: %d active, %d total\n", m.jobs.Active(), len(list))
+	if len(list) == 0 {
+		sb.WriteString("  <no jobs; prunes and data root scans run here>\n")
+	}
+	now := time.Now()
+	for i, j := range list {
+		cursor := "  "
+		if i == m.jobCursor {
+			cursor = "> "
+		}
+		fmt.Fprintf(sb, "%s#%d %-6s %-9s %s", cursor, j.ID, j.Kind, jobStyle(j.State).Render(j.State.String()), j.Title)
+		switch {
+		case j.State == jobs.Running && j.Total > 0:
+			fmt.Fprintf(sb, "  %d/%d (%.0f%%)", j.Done, j.Total, 100*j.Fraction())
+		case j.State == jobs.Running:
+			fmt.Fprintf(sb, "  %s", now.Sub(j.Started).Round(time.Second))
+		case j.State.Finished() && !j.Started.IsZero():
+			fmt.Fprintf(sb, "  in %s", j.Finished.Sub(j.Started).Round(time.Second))
+		}
+		sb.WriteString("\n")
+		switch {
+		case j.State == jobs.Failed:
+			fmt.Fprintf(sb, "      %s\n", failStyle.Render(firstLine(j.Err.Error())))
+		case j.State == jobs.Running && j.Detail != "":
+			fmt.Fprintf(sb, "      %s\n", dimStyle.Render(j.Detail))
+		}
+	}
+	sb.WriteString("\n[↑/↓] Move  [X] Cancel  [Shift+X] Clear finished  [Tab] Switch")
+	return m.pane().Render(sb.String())
+}
+
+// jobStyle colors a job state like the volume states: green when done,
+// red when failed.
+func jobStyle(s jobs.State) lipgloss.Style {
+	switch s {
+	case jobs.Done:
+		return okStyle
+	case jobs.Failed:
+		return failStyle
+	case jobs.Running:
+		return headerStyle
+	}
+	return dimStyle
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Until the first WindowSizeMsg the views keep their fixed layout: tables
+// of 20 rows and panes 80 columns wide.
+const (
+	defaultPaneWidth = 80
+	minPaneWidth     = 30
+
+	// splitMinDetails is the narrowest details pane worth showing beside
+	// the volume table; on narrower terminals the panes stack.
+	splitMinDetails = 40
+
+	// tableFrameLines are the table's border and column header.
+	tableFrameLines = 4
+	// chromeLines is everything around a table: the tab bar, the two
+	// header lines, its frame, and the help pane below it.
+	chromeLines = 3 + tableFrameLines + 7
+)
+
+// resize fits the tables to a new terminal size. The Volumes view refits
+// its table to the lower pane on every render.
+func (m model) resize(msg tea.WindowSizeMsg) model {
+	m.width, m.height = msg.Width, msg.Height
+	h := max(msg.Height-chromeLines, 3)
+	m.table.SetHeight(h)
+	m.ctable.SetHeight(h)
+	m.itable.SetHeight(h)
+	m.ptable.SetHeight(h)
+	m.ntable.SetHeight(h)
+	return m
+}
+
+// pane is the bordered box the lower panes are drawn in: 80 columns, or
+// the terminal width when that is narrower.
+func (m model) pane() lipgloss.Style {
+	w := defaultPaneWidth
+	if m.width > 0 {
+		// The border takes a column on each side
+		w = clamp(m.width-2, minPaneWidth, defaultPaneWidth)
+	}
+	return borderStyle.Width(w)
+}
+
+// fit clips a rendered view to the terminal, so a narrow window cuts long
+// lines off instead of wrapping them into the rows below.
+func (m model) fit(s string) string {
+	if m.width <= 0 || m.height <= 0 {
+		return s
+	}
+	return lipgloss.NewStyle().MaxWidth(m.width).MaxHeight(m.height).Render(s)
+}
+
+// splitWidth returns the width of the details pane beside a volume table
+// drawn tableWidth wide, border included, or 0 when it doesn't fit.
+func (m model) splitWidth(tableWidth int) int {
+	if m.width <= 0 || m.width-tableWidth-2 < splitMinDetails {
+		return 0
+	}
+	return m.width - tableWidth
+}
+
+// split reports whether the details pane sits beside the volume table.
+func (m model) split() bool {
+	return m.splitWidth(lipgloss.Width(m.renderTable())) > 0
+}
+
+// tableArea is the volume table, with the details of the row under the
+// cursor beside it on wide terminals. The details follow the cursor and
+// are clipped to the table's height.
+func (m model) tableArea() string {
+	t := m.renderTable()
+	w := m.splitWidth(lipgloss.Width(t))
+	body := m.detailsBody()
+	if w == 0 || body == "" {
+		return t
+	}
+	h := lipgloss.Height(t) - 2 // inside the border
+	// The border takes a column on each side, the padding another
+	lines := strings.Split(lipgloss.NewStyle().Width(w-4).Render(strings.TrimRight(body, "\n")), "\n")
+	lines = lines[:min(len(lines), h)]
+	side := borderStyle.Copy().Width(w - 2).Height(h).Render(strings.Join(lines, "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, t, side)
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/jobs"
+)
+
+// composeDoneMsg reports the outcome of `docker compose down` or `up`.
+type composeDoneMsg struct {
+	project string
+	action  string // "down" or "up"
+	err     error
+}
+
+// location is where compose recorded p on its containers: the directory it
+// ran in and the files it read.
+func (p project) location() (dir string, files []string) {
+	for _, c := range p.containers {
+		if d := c.ProjectDir(); d != "" {
+			return d, c.ProjectFiles()
+		}
+	}
+	return "", nil
+}
+
+// askDown asks before stopping and removing p's containers. Its volumes
+// stay; with the containers gone they show as orphaned, ready for T.
+func (m model) askDown() model {
+	p, ok := m.selectedProject()
+	if !ok {
+		return m
+	}
+	if len(p.containers) == 0 {
+		m.notice = fmt.Sprintf("%s has no containers to take down", p.name)
+		return m
+	}
+	m.confirmDown = true
+	return m
+}
+
+// composeProject runs `docker compose` action ("down" or "up") for p as a
+// background job.
+func (m model) composeProject(p project, action string) (model, tea.Cmd) {
+	if os.Getenv("DOCKWATCH_REMOTE") != "" {
+		m.notice = "compose runs through the docker CLI on this machine; not available against an agent"
+		return m, nil
+	}
+	dir, files := p.location()
+	args := []string{"down"}
+	if action == "up" {
+		args = []string{"up", "-d"}
+		if err := composeFilesReadable(dir, files); err != nil {
+			m.notice = fmt.Sprintf("can't bring %s up: %v", p.name, err)
+			return m, nil
+		}
+	}
+	if m.dryRun {
+		stateLogger("dry-run.log")("would run docker compose -p %s %s (in %s)", p.name, strings.Join(args, " "), ifEmpty(dir, "?"))
+		m.notice = fmt.Sprintf("would run compose %s for %s", action, p.name)
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("compose %s %s…", action, p.name)
+	return m, m.enqueue("compose", fmt.Sprintf("Compose %s %s", action, p.name), composeDoneMsg{project: p.name, action: action, err: context.Canceled},
+		func(ctx context.Context, progress jobs.Progress) (tea.Msg, error) {
+			progress(0, 1, "docker compose "+strings.Join(args, " "))
+			docker, err := dockercli.NewDockerProvider()
+			if err != nil {
+				return composeDoneMsg{project: p.name, action: action, err: err}, err
+			}
+			defer docker.Close()
+			err = docker.Compose(ctx, p.name, dir, files, args...)
+			return composeDoneMsg{project: p.name, action: action, err: err}, err
+		})
+}
+
+// composeFilesReadable checks that the project can be brought up from
+// here: compose recorded where it lives, and its files are still there.
+func composeFilesReadable(dir string, files []string) error {
+	if dir == "" && len(files) == 0 {
+		return errors.New("its containers don't record a compose file; run compose up from its directory")
+	}
+	for _, f := range files {
+		if _, err := os.Stat(f); err != nil {
+			return fmt.Errorf("compose file %s: %w", f, err)
+		}
+	}
+	return nil
+}
+
+func (m model) onComposeDone(msg composeDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("compose %s %s: %v", msg.action, msg.project, msg.err)
+	} else {
+		m.notice = fmt.Sprintf("%s is %s", msg.project, msg.action)
+	}
+	return m, tea.Batch(m.loadVolumes(), m.loadContainers(), m.loadNetworks())
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/domain"
+	"dockwatch/internal/livesize"
+)
+
+// liveState tracks the size of one volume while it changes, shown in the
+// details pane.
+type liveState struct {
+	volume  string
+	first   livesize.Sample // the size when tracking started
+	last    livesize.Sample
+	samples <-chan livesize.Sample
+	cancel  context.CancelFunc
+}
+
+// liveSizeMsg carries a new size of the tracked volume; ended is set once
+// the watch has stopped.
+type liveSizeMsg struct {
+	samples <-chan livesize.Sample
+	sample  livesize.Sample
+	ended   bool
+}
+
+// toggleLiveSize starts tracking the size of the volume under the cursor,
+// or stops tracking it. Only one volume is tracked at a time.
+func (m model) toggleLiveSize() (model, tea.Cmd) {
+	idx := m.table.Cursor()
+	if idx < 0 || idx >= len(m.vols) {
+		return m, nil
+	}
+	v := m.vols[idx]
+	if m.live != nil {
+		m.live.cancel()
+		stopped := m.live.volume
+		m.live = nil
+		if stopped == v.Name {
+			m.notice = "stopped tracking " + stopped
+			return m, nil
+		}
+	}
+	if os.Getenv("DOCKWATCH_REMOTE") != "" || !dockercli.IsLocalDaemon() || v.Mountpoint == "" {
+		m.notice = "live size tracking reads the mountpoint, so it needs the daemon on this machine"
+		return m, nil
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	samples := livesize.Watch(ctx, v.Mountpoint)
+	m.live = &liveState{volume: v.Name, samples: samples, cancel: cancel}
+	if !m.split() {
+		m.active = paneDetails
+	}
+	m.notice = "tracking the size of " + v.Name + " (L stops)"
+	return m, waitLiveSize(samples)
+}
+
+func waitLiveSize(samples <-chan livesize.Sample) tea.Cmd {
+	return func() tea.Msg {
+		s, ok := <-samples
+		return liveSizeMsg{samples: samples, sample: s, ended: !ok}
+	}
+}
+
+func (m model) onLiveSize(msg liveSizeMsg) (tea.Model, tea.Cmd) {
+	// Samples from a watch that has since been stopped or replaced
+	if m.live == nil || m.live.samples != msg.samples || msg.ended {
+		return m, nil
+	}
+	st := *m.live
+	m.live = &st
+	if err := msg.sample.Err; err != nil {
+		st.cancel()
+		m.live = nil
+		m.notice = fmt.Sprintf("stopped tracking %s: %v", st.volume, err)
+		return m, nil
+	}
+	if st.first.At.IsZero() {
+		st.first = msg.sample
+	}
+	st.last = msg.sample
+	return m, waitLiveSize(st.samples)
+}
+
+// liveSizeLine describes the tracked size of v for the details pane, or is
+// empty when v isn't tracked.
+func (m model) liveSizeLine(v domain.Volume) string {
+	st := m.live
+	if st == nil || st.volume != v.Name {
+		return ""
+	}
+	if st.last.At.IsZero() {
+		return "Live size: measuring…\n"
+	}
+	how := "watching for changes"
+	if !st.last.Live {
+		how = "re-measured every few seconds"
+	}
+	line := fmt.Sprintf("Live size: %s in %d files (%s)\n", domain.HumanSize(st.last.Bytes), st.last.Files, how)
+	delta := st.last.Bytes - st.first.Bytes
+	elapsed := st.last.At.Sub(st.first.At)
+	if delta == 0 {
+		return line + fmt.Sprintf("  unchanged since %s\n", st.first.At.Format("15:04:05"))
+	}
+	sign := tern(delta > 0, "+", "-")
+	change := fmt.Sprintf("  %s%s since %s", sign, domain.HumanSize(abs(delta)), st.first.At.Format("15:04:05"))
+	if elapsed >= time.Second {
+		change += fmt.Sprintf(", %s%s/s on average", sign, domain.HumanSize(int64(float64(abs(delta))/elapsed.Seconds())))
+	}
+	return line + tern(delta > 0, warnStyle, okStyle).Render(change) + "\n"
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
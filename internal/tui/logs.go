@@ -0,0 +1,240 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+
+	"dockwatch/internal/domain"
+)
+
+const (
+	defaultLogTail = 200
+	logPaneLines   = 15
+	logPollEvery   = 2 * time.Second
+)
+
+var matchStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("220"))
+
+// logView is the state of the log tail pane for one container.
+type logView struct {
+	container domain.Container
+	lines     []string
+	tail      int
+	follow    bool
+	offset    int // lines scrolled up from the bottom
+
+	query     string
+	searching bool // typing into the search prompt
+	err       error
+}
+
+// logsMsg carries freshly fetched log lines for a container.
+type logsMsg struct {
+	id    string
+	lines []string
+	err   error
+}
+
+// logTickMsg triggers a re-fetch while follow mode is on.
+type logTickMsg struct{ id string }
+
+func (m model) openLogs(c domain.Container) (tea.Model, tea.Cmd) {
+	m.logs = &logView{container: c, tail: defaultLogTail, follow: true}
+	return m, m.fetchLogs()
+}
+
+func (m model) fetchLogs() tea.Cmd {
+	if m.logs == nil || m.provider == nil {
+		return nil
+	}
+	prov, ctx := m.provider, m.ctx
+	id, tail := m.logs.container.ID, m.logs.tail
+	return func() tea.Msg {
+		lines, err := prov.ContainerLogs(ctx, id, tail)
+		return logsMsg{id: id, lines: lines, err: err}
+	}
+}
+
+// setLogs stores fetched lines and schedules the next poll in follow mode.
+func (m model) setLogs(msg logsMsg) (tea.Model, tea.Cmd) {
+	if m.logs == nil || m.logs.container.ID != msg.id {
+		return m, nil // pane was closed or switched meanwhile
+	}
+	lv := *m.logs
+	lv.lines, lv.err = msg.lines, msg.err
+	m.logs = &lv
+	if !lv.follow {
+		return m, nil
+	}
+	id := msg.id
+	return m, tea.Tick(logPollEvery, func(time.Time) tea.Msg { return logTickMsg{id: id} })
+}
+
+func (m model) onLogTick(msg logTickMsg) (tea.Model, tea.Cmd) {
+	if m.logs == nil || !m.logs.follow || m.logs.container.ID != msg.id {
+		return m, nil
+	}
+	return m, m.fetchLogs()
+}
+
+// updateLogs handles keys while the log pane is open.
+func (m model) updateLogs(msg tea.KeyMsg) (bool, model, tea.Cmd) {
+	lv := *m.logs
+	if lv.searching {
+		switch msg.Type {
+		case tea.KeyEnter:
+			lv.searching = false
+		case tea.KeyEsc:
+			lv.searching, lv.query = false, ""
+		case tea.KeyBackspace:
+			if lv.query != "" {
+				lv.query = lv.query[:len(lv.query)-1]
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			lv.query += string(msg.Runes)
+		}
+		m.logs = &lv
+		return true, m, nil
+	}
+
+	var cmd tea.Cmd
+	switch msg.String() {
+	case "esc":
+		m.logs = nil
+		return true, m, nil
+	case "/":
+		lv.searching = true
+	case "f":
+		lv.follow = !lv.follow
+		if lv.follow {
+			lv.offset = 0
+			m.logs = &lv
+			cmd = m.fetchLogs()
+		}
+	case "+":
+		lv.tail *= 2
+		m.logs = &lv
+		cmd = m.fetchLogs()
+	case "-":
+		if lv.tail > 25 {
+			lv.tail /= 2
+		}
+		m.logs = &lv
+		cmd = m.fetchLogs()
+	case "pgup", "k":
+		lv.follow = false
+		lv.offset = min(lv.offset+logPaneLines, max(len(lv.visible())-logPaneLines, 0))
+	case "pgdown", "j":
+		lv.offset = max(lv.offset-logPaneLines, 0)
+	default:
+		return false, m, nil
+	}
+	m.logs = &lv
+	return true, m, cmd
+}
+
+// visible returns the lines matching the search query (all lines if empty).
+func (lv logView) visible() []string {
+	if lv.query == "" {
+		return lv.lines
+	}
+	var out []string
+	for _, l := range lv.lines {
+		if containsFold(l, lv.query) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func (m model) renderLogs() string {
+	lv := m.logs
+	lines := lv.visible()
+	end := max(len(lines)-lv.offset, 0)
+	start := max(end-logPaneLines, 0)
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Logs: %s (last %d, follow %s)\n", lv.container.Name, lv.tail, tern(lv.follow, "on", "off"))
+	if lv.err != nil {
+		fmt.Fprintf(sb, "  error: %v\n", lv.err)
+	}
+	for _, l := range lines[start:end] {
+		// By display width, so a wide or multi-byte character isn't split
+		l = runewidth.Truncate(l, 76, "")
+		if lv.query != "" {
+			l = highlight(l, lv.query)
+		}
+		sb.WriteString(l + "\n")
+	}
+	if len(lines) == 0 {
+		sb.WriteString("  <no log lines>\n")
+	}
+	if lv.searching {
+		fmt.Fprintf(sb, "\n/%s%s", lv.query, glyphs.caret)
+	} else {
+		if lv.query != "" {
+			fmt.Fprintf(sb, "\nfilter: %q (%d of %d lines)", lv.query, len(lines), len(lv.lines))
+		}
+		sb.WriteString("\n[F] Follow  [/] Search  [+/-] Tail  [J/K] Scroll  [Esc] Close")
+	}
+	return m.pane().Render(sb.String())
+}
+
+// highlight marks case-insensitive occurrences of q in s. It compares rune
+// by rune on s itself: lowercasing can change a string's byte length, so
+// offsets into a lowered copy don't fit s.
+func highlight(s, q string) string {
+	if q == "" {
+		return s
+	}
+	var sb strings.Builder
+	plain := 0 // start of the text not yet written
+	for i := 0; i < len(s); {
+		if n := foldPrefix(s[i:], q); n > 0 {
+			sb.WriteString(s[plain:i])
+			sb.WriteString(matchStyle.Render(s[i : i+n]))
+			i += n
+			plain = i
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	sb.WriteString(s[plain:])
+	return sb.String()
+}
+
+// containsFold reports whether s contains q, ignoring case as highlight does.
+func containsFold(s, q string) bool {
+	for i := 0; i < len(s); {
+		if foldPrefix(s[i:], q) > 0 {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return q == ""
+}
+
+// foldPrefix returns the length in bytes of the prefix of s that equals q
+// under Unicode case folding, or 0 if s doesn't start with q.
+func foldPrefix(s, q string) int {
+	n := 0
+	for _, qr := range q {
+		if n >= len(s) {
+			return 0
+		}
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if r != qr && !strings.EqualFold(string(r), string(qr)) {
+			return 0
+		}
+		n += size
+	}
+	return n
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/domain"
+)
+
+// memPaneRows caps the memory-backed storage pane; the fullest mounts come first.
+const memPaneRows = 10
+
+// memState is the memory-backed storage pane of the Containers view.
+type memState struct {
+	mounts  []domain.MemMount
+	hostMem int64
+	loading bool
+}
+
+// memMountsMsg carries a finished /dev/shm and tmpfs scan.
+type memMountsMsg struct {
+	mounts  []domain.MemMount
+	hostMem int64
+}
+
+// toggleMem opens the memory-backed storage pane and scans, or closes it.
+// Usage is read with docker exec, which an agent doesn't offer.
+func (m model) toggleMem() (model, tea.Cmd) {
+	if m.mem != nil {
+		m.mem = nil
+		return m, nil
+	}
+	if os.Getenv("DOCKWATCH_REMOTE") != "" {
+		m.notice = "shm and tmpfs usage is not available through an agent"
+		return m, nil
+	}
+	m = m.closeBinds()
+	m.swarm = nil
+	return m.scanMem()
+}
+
+func (m model) scanMem() (model, tea.Cmd) {
+	m.mem = &memState{loading: true}
+	cs, ctx := m.containers, m.ctx
+	return m, func() tea.Msg {
+		return memMountsMsg{mounts: dockercli.MemMounts(ctx, cs), hostMem: dockercli.HostMemory(ctx)}
+	}
+}
+
+func (m model) setMem(msg memMountsMsg) model {
+	if m.mem == nil {
+		return m // closed while scanning
+	}
+	m.mem = &memState{mounts: msg.mounts, hostMem: msg.hostMem}
+	return m
+}
+
+func (m model) renderMem() string {
+	sb := &strings.Builder{}
+	sb.WriteString("Memory-backed Storage — /dev/shm and tmpfs count against RAM\n\n")
+	switch {
+	case m.mem.loading:
+		sb.WriteString("  measuring…\n")
+	case len(m.mem.mounts) == 0:
+		sb.WriteString("  <no running container has its own /dev/shm or a tmpfs mount>\n")
+	}
+	var used, size int64
+	for i, mm := range m.mem.mounts {
+		used += max(mm.UsedBytes, 0)
+		size += mm.SizeBytes
+		if i >= memPaneRows {
+			continue
+		}
+		usage := "?"
+		if mm.UsedBytes >= 0 {
+			usage = domain.HumanSize(mm.UsedBytes)
+		}
+		fmt.Fprintf(sb, "  %-18s %-22s %10s / %s\n", mm.Container, mm.Destination, usage, domain.HumanSize(mm.SizeBytes))
+	}
+	if n := len(m.mem.mounts) - memPaneRows; n > 0 {
+		fmt.Fprintf(sb, "  … %d more\n", n)
+	}
+	if !m.mem.loading && len(m.mem.mounts) > 0 {
+		fmt.Fprintf(sb, "\n  Total: %s used of %s allowed", humanBytes(used), humanBytes(size))
+		if m.mem.hostMem > 0 {
+			fmt.Fprintf(sb, " (host memory %s)", humanBytes(m.mem.hostMem))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n[M] Close  [R] Rescan")
+	return m.pane().Render(sb.String())
+}
@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quickMenu is the per-volume action menu opened with enter.
+type quickMenu struct {
+	cursor int
+	name   string // volume the menu was opened on
+}
+
+// quickAction is one menu entry; key triggers it directly.
+type quickAction struct {
+	key   string
+	label string
+	run   func(m model) (model, tea.Cmd)
+}
+
+// quickActions lists what can be done to the volume under the cursor.
+func (m model) quickActions(name string) []quickAction {
+	return []quickAction{
+		{"i", "Details", func(m model) (model, tea.Cmd) {
+			m.active = paneDetails
+			return m, nil
+		}},
+		{"m", tern(m.marked[name], "Unmark", "Mark for prune"), func(m model) (model, tea.Cmd) {
+			m.marked[name] = !m.marked[name]
+			m.table.SetRows(m.volumeRows())
+			return m, nil
+		}},
+		{"x", tern(m.cfg.IsIgnored(name), "Unprotect (remove from ignore list)", "Protect (add to ignore list)"), func(m model) (model, tea.Cmd) {
+			return m.toggleIgnore(), nil
+		}},
+		{"?", "Why orphan?", func(m model) (model, tea.Cmd) {
+			return m.explain()
+		}},
+		{"c", "Go to container", func(m model) (model, tea.Cmd) {
+			return m.gotoContainer(name)
+		}},
+		{"g", "Dependency graph", func(m model) (model, tea.Cmd) {
+			m.active = paneGraph
+			return m, m.loadContainers()
+		}},
+		{"b", "Back up now", func(m model) (model, tea.Cmd) {
+			return m.backupNow(name)
+		}},
+		{"l", tern(m.live != nil && m.live.volume == name, "Stop tracking size", "Track size live"), func(m model) (model, tea.Cmd) {
+			return m.toggleLiveSize()
+		}},
+		{"o", "Browse mountpoint", func(m model) (model, tea.Cmd) {
+			next, cmd := m.browseVolume()
+			return next.(model), cmd
+		}},
+		{"d", "Delete now", func(m model) (model, tea.Cmd) {
+			return m.askDelete(), nil
+		}},
+	}
+}
+
+// openMenu opens the quick-actions menu on the volume under the cursor.
+func (m model) openMenu() model {
+	idx := m.table.Cursor()
+	if idx < 0 || idx >= len(m.vols) {
+		return m
+	}
+	m.menu = &quickMenu{name: m.vols[idx].Name}
+	return m
+}
+
+// updateMenu handles every key while the menu is open.
+func (m model) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	actions := m.quickActions(m.menu.name)
+	menu := *m.menu
+	switch key := msg.String(); key {
+	case "esc", "q", "enter":
+		m.menu = nil
+		if key != "enter" {
+			return m, nil
+		}
+		return actions[menu.cursor].run(m)
+	case "up":
+		menu.cursor = max(menu.cursor-1, 0)
+	case "down":
+		menu.cursor = min(menu.cursor+1, len(actions)-1)
+	default:
+		for _, a := range actions {
+			if a.key == key {
+				m.menu = nil
+				return a.run(m)
+			}
+		}
+	}
+	m.menu = &menu
+	return m, nil
+}
+
+func (m model) renderMenu() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Actions: %s\n\n", m.menu.name)
+	for i, a := range m.quickActions(m.menu.name) {
+		line := fmt.Sprintf("[%s] %s", strings.ToUpper(a.key), a.label)
+		if i == m.menu.cursor {
+			line = selectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		fmt.Fprintln(sb, line)
+	}
+	fmt.Fprintf(sb, "\n[↑/↓] Move  [Enter] Run  [Esc] Close")
+	return m.pane().Render(sb.String())
+}
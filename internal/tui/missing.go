@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/compose"
+	"dockwatch/internal/dockercli"
+)
+
+// missingState is the Missing pane: volumes the compose files in
+// compose.dirs declare that the daemon doesn't have.
+type missingState struct {
+	refs   []compose.Ref // one per missing volume, by name
+	cursor int
+}
+
+// recreateDoneMsg reports the outcome of recreating a volume.
+type recreateDoneMsg struct {
+	name string
+	err  error
+}
+
+// openMissing rescans the compose files and lists the volumes they declare
+// that aren't there, e.g. after one was deleted by accident.
+func (m model) openMissing() model {
+	m.active = paneMissing
+	m = m.scanCompose()
+	st := &missingState{}
+	for name, refs := range m.compose {
+		if !m.volumeListed(name) {
+			ref := refs[0]
+			for _, r := range refs {
+				if r.External {
+					ref = r
+					break
+				}
+			}
+			st.refs = append(st.refs, ref)
+		}
+	}
+	sort.Slice(st.refs, func(i, j int) bool { return st.refs[i].Name < st.refs[j].Name })
+	if m.missing != nil {
+		st.cursor = min(m.missing.cursor, max(len(st.refs)-1, 0))
+	}
+	m.missing = st
+	return m
+}
+
+// updateMissing handles keys while the Missing pane is active. It reports
+// whether the key was consumed.
+func (m model) updateMissing(msg tea.KeyMsg) (bool, model, tea.Cmd) {
+	if m.missing == nil {
+		return false, m, nil
+	}
+	st := *m.missing
+	m.missing = &st
+	switch msg.String() {
+	case "up":
+		st.cursor = max(st.cursor-1, 0)
+	case "down":
+		st.cursor = min(st.cursor+1, max(len(st.refs)-1, 0))
+	case "M":
+		return true, m.openMissing(), nil
+	case "c":
+		if st.cursor >= len(st.refs) {
+			return true, m, nil
+		}
+		next, cmd := m.recreate(st.refs[st.cursor])
+		return true, next, cmd
+	default:
+		return false, m, nil
+	}
+	return true, m, nil
+}
+
+// recreate creates ref's volume empty, with the driver, options and labels
+// its compose file gives it, so the stack starts again. The data is gone
+// either way; restoring a backup into the new volume is a separate step.
+func (m model) recreate(ref compose.Ref) (model, tea.Cmd) {
+	if os.Getenv("DOCKWATCH_REMOTE") != "" {
+		m.notice = "recreating volumes runs the docker CLI; not available against an agent"
+		return m, nil
+	}
+	labels := ref.CreateLabels()
+	if m.dryRun {
+		stateLogger("dry-run.log")("would create volume %s (driver %s, opts %v, labels %v)", ref.Name, ifEmpty(ref.Driver, "local"), ref.DriverOpts, labels)
+		m.notice = "would recreate " + ref.Name
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("recreating %s…", ref.Name)
+	ctx := m.ctx
+	return m, func() tea.Msg {
+		docker, err := dockercli.NewDockerProvider()
+		if err != nil {
+			return recreateDoneMsg{name: ref.Name, err: err}
+		}
+		defer docker.Close()
+		return recreateDoneMsg{name: ref.Name, err: docker.CreateVolume(ctx, ref.Name, ref.Driver, ref.DriverOpts, labels)}
+	}
+}
+
+func (m model) onRecreateDone(msg recreateDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("recreate %s: %v", msg.name, msg.err)
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("recreated %s empty; restore a backup into it with B if there is one", msg.name)
+	if m.missing != nil {
+		st := *m.missing
+		st.refs = slices.DeleteFunc(slices.Clone(st.refs), func(r compose.Ref) bool { return r.Name == msg.name })
+		st.cursor = min(st.cursor, max(len(st.refs)-1, 0))
+		m.missing = &st
+	}
+	return m, m.loadVolumes()
+}
+
+func (m model) renderMissing() string {
+	sb := &strings.Builder{}
+	st := m.missing
+	switch {
+	case len(m.cfg.Compose.Dirs) == 0:
+		sb.WriteString("Missing Compose Volumes:\n  <set compose.dirs in the config to the directories holding your compose projects>\n")
+		return m.pane().Render(sb.String())
+	case st == nil:
+		sb.WriteString("Missing Compose Volumes:\n  <press M to look for volumes compose files declare that don't exist>\n")
+		return m.pane().Render(sb.String())
+	}
+	fmt.Fprintf(sb, "Missing Compose Volumes: %d declared but not on the daemon\n", len(st.refs))
+	if len(st.refs) == 0 {
+		sb.WriteString("  <every volume the compose files declare exists>\n")
+	}
+	for i, r := range st.refs {
+		cursor := "  "
+		if i == st.cursor {
+			cursor = "> "
+		}
+		kind := tern(r.External, warnStyle.Render("external"), "        ")
+		fmt.Fprintf(sb, "%s%s  %s  %s\n", cursor, kind, r.Name, dimStyle.Render(r.File))
+	}
+	sb.WriteString("\n[↑/↓] Move  [C] Recreate empty  [M] Rescan  [Tab] Switch")
+	return m.pane().Render(sb.String())
+}
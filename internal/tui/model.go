@@ -3,22 +3,31 @@ package tui
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"dockwatch/internal/compose"
+	"dockwatch/internal/config"
 	"dockwatch/internal/dockercli"
 	"dockwatch/internal/domain"
+	"dockwatch/internal/history"
+	"dockwatch/internal/jobs"
+	"dockwatch/internal/notes"
+	"dockwatch/internal/policy"
 	"dockwatch/internal/provider"
+	"dockwatch/internal/remote"
+	"dockwatch/internal/snapshot"
 )
 
 var (
-	titleStyle    = lipgloss.NewStyle().Bold(true)
-	headerStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
-	selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57"))
-	borderStyle   = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	titleStyle  = lipgloss.NewStyle().Bold(true)
+	borderStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
 )
 
 type pane int
@@ -27,151 +36,790 @@ const (
 	paneTable pane = iota
 	paneDetails
 	panePlan
+	paneIgnore
+	paneDiff
+	paneGraph
+	paneRoot    // data root breakdown, see root.go
+	paneWhy     // orphan explanation, see why.go
+	paneJobs    // background jobs, see jobs.go
+	paneBackups // archives to restore from, see backups.go
+	paneDedup   // duplicate content in marked volumes, see dedup.go
+	paneMissing // compose volumes that don't exist, see missing.go
+	paneCount   // number of panes, keep last
 )
 
 type model struct {
-	ready  bool
-	active pane
-
-	vols   []domain.Volume
-	table  table.Model
-	marked map[int]bool // row index -> marked
-
-	showDetails bool
+	ready    bool
+	active   pane
+	resource resource
+
+	allVols   []domain.Volume // every listed volume
+	vols      []domain.Volume // the rows shown: allVols filtered and sorted
+	volSort   volumeSort
+	ageFilter int // index into ageFilters
+
+	// Saved view in effect, nil for all volumes, and its parsed settings
+	view       *config.View
+	viewFilter domain.VolumeFilter
+	viewMinAge time.Duration
+
+	prompt *prompt // text input in the header, nil when closed
+
+	// Local notes and tags, and the tag the list is narrowed to
+	notes     *notes.Store
+	tagFilter string
+	table     vtable
+	marked    map[string]bool // volume name -> marked
+	visual    bool            // visual range selection in progress
+	click     lastClick       // for telling double clicks apart, see mouse.go
+	anchor    int             // row the visual selection started at
+
+	// Drill-down trail for Backspace, the project a drill-down narrowed
+	// the volumes to, and a container to select once listed; see nav.go
+	back             []location
+	projectFilter    string
+	pendingContainer string
+	pendingVolume    string // volume to select once listed, see session.go
+
+	menu          *quickMenu // per-volume quick actions, nil when closed
+	columns       *colPicker // column chooser, nil when closed
+	finder        *finder    // ctrl+f volume search, nil when closed
+	palette       *palette   // ctrl+p command search, nil when closed
+	confirmDelete string     // volume awaiting a y/N answer to delete it
+	deleteHolders []string   // stopped containers to remove along with it
+
+	// Persistent user configuration (ignore list)
+	cfg          *config.Config
+	ignoreCursor int
+	notice       string // one-line feedback shown under the header
+
+	// Policy this host follows, loaded at startup and on refresh; see delete.go
+	policy       *policy.Policy
+	policyErr    error
+	policyLoaded bool
+
+	// Prune plan options and the apply in flight
+	planExited bool        // include exited containers in the plan
+	apply      *applyState // prune in progress, nil when idle
+
+	// Quitting mid-apply: the question is open, or the answer was given
+	// and dockwatch quits once the apply reports, see shutdown.go
+	quitAsk     bool
+	quitPending bool
+
+	// Background jobs: prunes and scans, see jobs.go
+	jobs      *jobs.Queue
+	jobCursor int
+
+	// Last snapshot and what changed since (nil if none taken)
+	snap *snapshot.Snapshot
+	diff []snapshot.Change
+
+	// Containers view
+	containers        []domain.Container
+	ctable            table.Model
+	logs              *logView    // nil when the log pane is closed
+	showGraph         bool        // dependency tree in place of the help pane (Containers/Images)
+	showContainerInfo bool        // health and restarts of the selected container
+	binds             *bindState  // bind mount pane (Containers), nil when closed
+	mem               *memState   // shm/tmpfs pane (Containers), nil when closed
+	swarm             *swarmState // secrets and configs pane (Containers), nil when closed
+
+	stats        map[string]domain.ContainerStats // by container name
+	statsAt      time.Time                        // when stats was sampled
+	statsPolling bool
+	writeRates   map[string]float64 // block bytes/s by container name, see writers.go
+	showWriters  bool
+
+	// Images view
+	images        []domain.Image
+	itable        table.Model
+	imarked       map[string]bool // image ref -> marked
+	showImagePlan bool
+	upstream      map[string]upstream   // image ref -> registry lookup
+	pull          *pullState            // nil unless an image pull is running
+	vulns         map[string]vulnResult // image ID -> scanner findings
+	showImageInfo bool
+
+	// Projects view
+	networks        []domain.Network
+	ptable          table.Model
+	confirmTeardown bool
+	confirmDown     bool // compose down of the selected project awaits y/N
+
+	// Networks view
+	ntable          table.Model
+	nmarked         map[string]bool // network name -> marked
+	confirmNetworks []string        // networks awaiting a y/N answer to delete them
+
+	confirmTruncate *domain.Container // container awaiting a y/N answer to empty its log
+
+	// Volume details streaming in after a reload
+	enrich *enrichState
+
+	registryCancel context.CancelFunc // stops a running registry check
+
+	// Reports from the retry middleware, and the call being retried
+	retries  chan retryMsg
+	retrying *provider.RetryStatus
+
+	// Days-until-full outlook from the usage history, nil without one
+	forecast *history.Forecast
+
+	// Data root breakdown, nil until U is first pressed
+	root *rootState
+	why  *whyState // orphan explanation pane, nil until first opened
+
+	// Backups pane, nil until B is first pressed
+	backups *backupsState
+
+	// The volume whose size is tracked live, nil unless L started it
+	live *liveState
+
+	// Duplicate content report, nil until = is first pressed
+	dedup *dedupState
+
+	// Volumes the compose files in compose.dirs declare, see compose.go,
+	// and the Missing pane listing those that don't exist
+	compose compose.Index
+	missing *missingState
+
+	// Diagnostics shown instead of the views while the daemon is unreachable
+	health *healthState
+
+	// The setup wizard, shown over everything else while open
+	onboard *onboardState
+
+	// Terminal size, zero until the first WindowSizeMsg
+	width, height int
 
 	// Provider management
+	dryRun   bool // removals are logged to the state dir, never executed
 	provider provider.Provider
 	ctx      context.Context
 }
 
 func New() model {
-	// Start with Docker provider by default
+	cfg, cfgErr := config.Load()
+
+	setAccessibility(config.NoColorRequested(), config.PlainRequested(), cfg.ASCII())
+	applyTheme(cfg.Theme)
+	m := newModel(cfg)
+
+	dockercli.UseContext(cfg.Context)
+	endpoint, discovered := dockercli.DiscoverSocket()
+
+	// Without a daemon the TUI opens on the health check screen instead
 	dockerProv, err := getDockerProvider()
 	if err != nil {
-		// If Docker fails, create a model with error state
-		fmt.Printf("Failed to connect to Docker: %v\n", err)
-		fmt.Printf("Make sure Docker is running and accessible\n")
-		return model{
-			active:   paneTable,
-			vols:     []domain.Volume{},
-			table:    table.New(),
-			marked:   map[int]bool{},
-			provider: nil,
-			ctx:      context.Background(),
-		}
+		m.health = &healthState{err: err, checking: true}
+	} else {
+		m = m.connected(dockerProv)
+	}
+
+	snap, err := snapshot.Latest()
+	if err != nil {
+		m.notice = err.Error()
+	}
+	m.snap = snap
+	if m.notes, err = notes.Load(); err != nil {
+		m.notice = err.Error()
+	}
+	m = m.resumeSession()
+	if discovered {
+		m.notice = fmt.Sprintf("no default docker socket; using %s at %s", endpoint.Name, endpoint.Host)
+	}
+	if cfgErr != nil {
+		m.notice = cfgErr.Error() + "; running on defaults, changes to the config won't be saved"
+	} else if firstRun() {
+		m, _ = m.openOnboard()
 	}
+	return m
+}
 
-	// Build columns
-	cols := []table.Column{
-		{Title: "Name", Width: 28},
-		{Title: "Size", Width: 10},
-		{Title: "Attached", Width: 18},
-		{Title: "Project", Width: 14},
-		{Title: "Status", Width: 8},
+// newModel returns a model on cfg with nothing listed and no provider.
+func newModel(cfg *config.Config) model {
+	m := model{
+		active:  paneTable,
+		vols:    []domain.Volume{},
+		ctable:  newContainerTable(),
+		itable:  newImageTable(),
+		ptable:  newProjectTable(),
+		ntable:  newNetworkTable(),
+		imarked: map[string]bool{},
+		nmarked: map[string]bool{},
+		marked:  map[string]bool{},
+		cfg:     cfg,
+		ctx:     context.Background(),
+		retries: make(chan retryMsg, 16),
 	}
+	m.jobs = jobs.New(m.ctx, jobWorkers)
+	// Volumes are listed once the program starts, see Init
+	m.table = newVTable(tableColumns(m.shownColumns()))
+	return m
+}
 
-	// Load Docker data
-	vols, err := dockerProv.ListVolumes(context.Background())
-	if err != nil {
-		fmt.Printf("Failed to load volumes: %v\n", err)
-		vols = []domain.Volume{}
+// title renders a view title, flagging dry-run mode so nobody mistakes a
+// rehearsal for the real thing, and a call being retried.
+func (m model) title(s string) string {
+	if m.dryRun {
+		return titleStyle.Render(s) + " " + warnStyle.Render("[DRY RUN]") + m.renderRetrying()
+	}
+	return titleStyle.Render(s) + m.renderRetrying()
+}
+
+// removedVerb is "removed", or "would remove" in dry-run mode.
+func (m model) removedVerb() string {
+	return tern(m.dryRun, "would remove", "removed")
+}
+
+// volumesMsg carries a fresh volume listing.
+type volumesMsg struct {
+	vols []domain.Volume
+	err  error
+}
+
+// loadVolumes re-lists volumes in the background. Only the cheap ls data
+// comes back here; details stream in afterwards, see startEnrich.
+func (m model) loadVolumes() tea.Cmd {
+	if m.provider == nil {
+		return nil
+	}
+	prov, ctx := m.provider, m.ctx
+	return func() tea.Msg {
+		vols, err := prov.ListVolumesBasic(ctx)
+		return volumesMsg{vols: vols, err: err}
+	}
+}
+
+// setVolumes replaces the volume list and starts enriching it. Marks
+// survive by name; marks on volumes that disappeared are dropped.
+func (m model) setVolumes(msg volumesMsg) (model, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = msg.err.Error()
+		return m, nil
 	}
+	m.allVols = msg.vols
+	m.visual = false // row indexes just changed under the anchor
+	m = m.reconcileMarks()
+	m, cmd := m.startEnrich()
+	m = m.applyVolumeView()
+	m = m.refreshProjects()
+	return m, cmd
+}
 
-	rows := make([]table.Row, 0, len(vols))
-	for _, v := range vols {
-		attached := "<none>"
-		if len(v.Attached) > 0 {
-			attached = strings.Join(v.Attached, ",")
+// volumeRows derives display rows from the volume list, marks and ignore
+// list. Volumes still waiting for their details show placeholders instead
+// of guessed values.
+func (m model) volumeRows() []table.Row {
+	rows := make([]table.Row, 0, len(m.vols))
+	pending := m.enrich.pendingSet()
+	cols := m.shownColumns()
+	now := time.Now()
+	for i, v := range m.vols {
+		mark := tern(m.marked[v.Name], glyphs.mark, " ")
+		if m.inVisual(i) {
+			mark = tern(m.marked[v.Name], glyphs.mark, glyphs.dot)
 		}
-		status := "ACTIVE"
-		if v.Orphan {
-			status = "ORPHAN"
+		row := table.Row{mark}
+		for _, c := range cols {
+			if pending[v.Name] && !c.listed {
+				row = append(row, c.loading)
+			} else {
+				row = append(row, c.value(v, now))
+			}
 		}
-		rows = append(rows, table.Row{v.Name, v.SizeHuman(), attached, v.Project, status})
+		rows = append(rows, row)
 	}
+	return rows
+}
 
-	t := table.New(table.WithColumns(cols), table.WithRows(rows), table.WithFocused(true))
-	t.KeyMap.LineUp.SetKeys("up")
-	t.KeyMap.LineDown.SetKeys("down")
-
-	return model{
-		active:   paneTable,
-		vols:     vols,
-		table:    t,
-		marked:   map[int]bool{},
-		provider: dockerProv,
-		ctx:      context.Background(),
+// reconcileMarks drops marks for volumes no longer listed, saying so: a
+// mark silently vanishing is as surprising as one silently moving.
+func (m model) reconcileMarks() model {
+	live := make(map[string]bool, len(m.allVols))
+	for _, v := range m.allVols {
+		live[v.Name] = true
 	}
+	var dropped []string
+	for name := range m.marked {
+		if !live[name] {
+			dropped = append(dropped, name)
+			delete(m.marked, name)
+		}
+	}
+	switch len(dropped) {
+	case 0:
+	case 1:
+		m.notice = fmt.Sprintf("unmarked %s: volume no longer exists", dropped[0])
+	default:
+		m.notice = fmt.Sprintf("unmarked %d volumes that no longer exist", len(dropped))
+	}
+	return m
 }
 
-func (m model) Init() tea.Cmd { return nil }
+func (m model) Init() tea.Cmd {
+	var onboard tea.Cmd
+	if m.onboard != nil {
+		onboard = listContexts(m.ctx)
+	}
+	if m.health != nil {
+		return tea.Batch(m.runHealth(), onboard)
+	}
+	return tea.Batch(m.loadUsage(), loadForecast(), waitRetry(m.retries), waitJobs(m.jobs), m.resumeTab(), onboard)
+}
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case containersMsg:
+		return m.setContainers(msg), nil
+	case volumesMsg:
+		return m.setVolumes(msg)
+	case volumeBatchMsg:
+		return m.onVolumeBatch(msg)
+	case applyProgressMsg:
+		return m.onApplyProgress(msg)
+	case applyDoneMsg:
+		return m.onApplyDone(msg)
+	case deleteDoneMsg:
+		return m.onDeleteDone(msg)
+	case policyMsg:
+		return m.setPolicy(msg), nil
+	case truncateDoneMsg:
+		return m.onTruncateDone(msg)
+	case imagesMsg:
+		return m.setImages(msg)
+	case networksMsg:
+		return m.setNetworks(msg), nil
+	case networksDeletedMsg:
+		return m.onNetworksDeleted(msg)
+	case registryMsg:
+		return m.setRegistry(msg), nil
+	case pullProgressMsg:
+		return m.onPullProgress(msg)
+	case pullDoneMsg:
+		return m.onPullDone(msg)
+	case vulnMsg:
+		return m.setVulns(msg), nil
+	case logsMsg:
+		return m.setLogs(msg)
+	case logTickMsg:
+		return m.onLogTick(msg)
+	case statsMsg:
+		return m.setStats(msg)
+	case statsTickMsg:
+		return m.onStatsTick()
+	case execReadyMsg:
+		return m.runExec(msg)
+	case browseDoneMsg:
+		return m.browseDone(msg), nil
+	case healthMsg:
+		return m.onHealth(msg)
+	case contextsMsg:
+		return m.onContexts(msg), nil
+	case reconnectMsg:
+		return m.onReconnect(msg)
+	case retryMsg:
+		return m.onRetry(msg)
+	case forecastMsg:
+		return m.setForecast(msg), nil
+	case bindMountsMsg:
+		return m.setBinds(msg), nil
+	case memMountsMsg:
+		return m.setMem(msg), nil
+	case swarmObjectsMsg:
+		return m.setSwarm(msg), nil
+	case swarmPrunedMsg:
+		return m.onSwarmPruned(msg)
+	case whyMsg:
+		return m.setWhy(msg), nil
+	case rootUsageMsg:
+		return m.setRoot(msg), nil
+	case backupsMsg:
+		return m.setBackups(msg), nil
+	case backupDoneMsg:
+		return m.onBackupDone(msg)
+	case restoreDoneMsg:
+		return m.onRestoreDone(msg)
+	case dedupMsg:
+		return m.setDedup(msg), nil
+	case liveSizeMsg:
+		return m.onLiveSize(msg)
+	case recreateDoneMsg:
+		return m.onRecreateDone(msg)
+	case composeDoneMsg:
+		return m.onComposeDone(msg)
+	case jobsMsg:
+		return m, waitJobs(m.jobs)
+	case resumeMsg:
+		return m.switchResource(m.resource)
+	case tea.WindowSizeMsg:
+		return m.resize(msg), nil
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+	case execDoneMsg:
+		return m.execDone(msg), m.loadContainers()
 	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			if next, ok := m.cancelRunning(); ok {
+				return next, nil
+			}
+			return m.quit()
+		}
+		if m.onboard != nil {
+			return m.updateOnboard(msg)
+		}
+		if m.health != nil {
+			return m.updateHealth(msg)
+		}
+		if m.quitAsk {
+			return m.updateQuit(msg)
+		}
+		if m.menu != nil {
+			return m.updateMenu(msg)
+		}
+		if m.columns != nil {
+			return m.updateColumns(msg)
+		}
+		if m.finder != nil {
+			return m.updateFinder(msg)
+		}
+		if m.palette != nil {
+			return m.updatePalette(msg)
+		}
+		if m.prompt != nil {
+			return m.updatePrompt(msg)
+		}
+		if handled, next := m.updateApply(msg); handled {
+			return next, nil
+		}
+		if m.confirmDelete != "" {
+			return m.updateDelete(msg)
+		}
+		// Text input in the log search prompt swallows every key
+		searching := m.logs != nil && m.logs.searching
+		if !searching {
+			if i, ok := tabKey(msg.String()); ok {
+				return m.openTab(resourceTabs[i].res)
+			}
+			switch msg.String() {
+			case "[":
+				return m.cycleTab(-1)
+			case "]":
+				return m.cycleTab(1)
+			case "backspace":
+				return m.goBack()
+			case "ctrl+p":
+				return m.openPalette(), nil
+			case "q":
+				if m.resource != resVolumes {
+					return m.quit()
+				}
+			}
+		}
+		switch m.resource {
+		case resContainers:
+			return m.updateContainers(msg)
+		case resImages:
+			return m.updateImages(msg)
+		case resProjects:
+			return m.updateProjects(msg)
+		case resNetworks:
+			return m.updateNetworks(msg)
+		}
+		if m.active == paneIgnore {
+			if handled, next := m.updateIgnore(msg); handled {
+				return next, nil
+			}
+		}
+		if m.active == paneJobs {
+			if handled, next := m.updateJobs(msg); handled {
+				return next, nil
+			}
+		}
+		if m.active == paneBackups {
+			if handled, next, cmd := m.updateBackups(msg); handled {
+				return next, cmd
+			}
+		}
+		if m.active == paneMissing {
+			if handled, next, cmd := m.updateMissing(msg); handled {
+				return next, cmd
+			}
+		}
+		if handled, next := m.updateVisual(msg); handled {
+			return next, nil
+		}
+		if n, ok := viewKey(msg.String()); ok {
+			return m.selectView(n), nil
+		}
 		switch msg.String() {
 		case "q", "esc":
-			if m.provider != nil {
-				m.provider.Close()
-			}
-			return m, tea.Quit
+			return m.quit()
 		case "tab":
-			m.active = (m.active + 1) % 3
+			m.active = (m.active + 1) % paneCount
 		case "enter":
-			m.showDetails = !m.showDetails
+			return m.openMenu(), nil
 		case "p":
 			m.active = panePlan
+			m = m.scanCompose()
+		case "a", "c", "e":
+			if m.active == panePlan {
+				return m.updatePlan(msg)
+			}
+			if msg.String() == "e" {
+				m = m.exportVolumes(false)
+			}
+		case "E":
+			m = m.exportVolumes(true)
+		case "C":
+			m = m.openColumns()
+		case "ctrl+f":
+			m = m.openFinder()
 		case " ":
-			idx := m.table.Cursor()
-			m.marked[idx] = !m.marked[idx]
+			if idx := m.table.Cursor(); idx >= 0 && idx < len(m.vols) {
+				name := m.vols[idx].Name
+				m.marked[name] = !m.marked[name]
+				m.table.SetRows(m.volumeRows())
+			}
+		case "V":
+			m = m.startVisual()
+		case "t":
+			m = m.cycleSort()
+		case "n":
+			m = m.editNote()
+		case "#":
+			m = m.editTags()
+		case "f":
+			m = m.filterTag()
+		case "w":
+			name := ""
+			if m.view != nil {
+				name = m.view.Name
+			}
+			m = m.ask("Save view as", name, model.saveView)
+		case "A":
+			m = m.cycleAgeFilter()
+		case "U":
+			return m.scanRoot()
+		case "J":
+			m.active = paneJobs
+		case "b":
+			return m.openBackups()
+		case "=":
+			return m.findDuplicates()
+		case "L":
+			return m.toggleLiveSize()
+		case "M":
+			m = m.openMissing()
+		case "?":
+			return m.explain()
+		case "r":
+			return m, m.loadUsage()
+		case "d":
+			m = m.askDelete()
+		case "y", "Y":
+			m = m.copyVolume(msg.String() == "Y")
+		case "o":
+			return m.browseVolume()
+		case "O":
+			m = m.openFileManager()
+		case "x":
+			m = m.toggleIgnore()
+		case "s":
+			m = m.takeSnapshot()
+		case "g":
+			if m.active == paneGraph {
+				m.active = paneTable
+				return m, nil
+			}
+			m.active = paneGraph
+			return m, m.loadContainers()
 		}
 	}
 
 	var cmd tea.Cmd
 	m.table, cmd = m.table.Update(msg)
+	if m.visual {
+		// The highlighted range follows the cursor
+		m.table.SetRows(m.volumeRows())
+	}
 	return m, cmd
 }
 
+func (m model) quit() (tea.Model, tea.Cmd) {
+	if m.apply != nil {
+		// Leaving now would lose track of what the apply removed
+		m.quitAsk = true
+		return m, nil
+	}
+	// Best effort: a session that didn't save only costs the resume
+	_ = m.saveSession()
+	m.jobs.CancelAll()
+	if m.provider != nil {
+		m.provider.Close()
+	}
+	return m, tea.Quit
+}
+
 func (m model) View() string {
-	header := titleStyle.Render("Docker Volumes — Real Data")
+	if ascii {
+		return m.fit(asciiReplacer.Replace(m.render()))
+	}
+	return m.fit(m.render())
+}
 
-	// Add status info
-	statusInfo := fmt.Sprintf("Volumes: %d", len(m.vols))
-	header = header + "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(statusInfo)
+func (m model) render() string {
+	if m.onboard != nil {
+		return m.renderOnboard()
+	}
+	if m.health != nil {
+		return m.viewHealth()
+	}
+	if m.quitAsk {
+		return m.renderTabs() + "\n" + m.renderQuit()
+	}
+	if m.palette != nil {
+		return m.renderTabs() + "\n" + m.renderPalette()
+	}
+	switch m.resource {
+	case resContainers:
+		return m.viewContainers()
+	case resImages:
+		return m.viewImages()
+	case resProjects:
+		return m.viewProjects()
+	case resNetworks:
+		return m.viewNetworks()
+	}
 
-	// Top table with markers
-	rendered := m.renderTable()
+	header, lower := m.volumeHeader(), m.lowerPane()
+	m = m.fitTable(header, lower)
+	return header + "\n" + m.tableArea() + "\n" + lower
+}
+
+// volumeHeader is the title and status lines above the volume table.
+func (m model) volumeHeader() string {
+	header := m.renderTabs() + "\n" + m.title("Docker Volumes — Real Data") + "  " + headerStyle.Render(m.reclaimable().String()) + m.renderForecast()
 
-	// Details / Plan panes
+	// Add status info
+	orphans, ignored := 0, 0
+	pending := m.enrich.pendingSet()
+	for _, v := range m.allVols {
+		switch {
+		case pending[v.Name]:
+		case m.cfg.IsIgnored(v.Name):
+			ignored++
+		case v.Orphan():
+			orphans++
+		}
+	}
+	shown := fmt.Sprint(len(m.allVols))
+	if len(m.vols) != len(m.allVols) {
+		shown = fmt.Sprintf("%d of %d", len(m.vols), len(m.allVols))
+	}
+	if age := max(ageFilters[m.ageFilter], m.viewMinAge); age > 0 {
+		shown += fmt.Sprintf(" (older than %s)", domain.HumanAge(age))
+	}
+	statusInfo := fmt.Sprintf("Volumes: %s  Orphans: %d  Ignored: %d", shown, orphans, ignored)
+	statusInfo += m.viewLabel()
+	if m.tagFilter != "" {
+		statusInfo += "  Tag: " + m.tagFilter
+	}
+	if m.projectFilter != "" {
+		statusInfo += "  Project: " + m.projectFilter
+	}
+	if n := m.jobs.Active(); n > 0 {
+		statusInfo += fmt.Sprintf("  Jobs: %d", n)
+	}
+	statusInfo += "  " + m.table.Position()
+	if m.confirmDelete != "" {
+		statusInfo += m.deletePrompt()
+	}
+	if m.prompt != nil {
+		statusInfo += m.prompt.render()
+	}
+	if m.visual {
+		lo, hi := m.visualRange()
+		statusInfo += fmt.Sprintf("  VISUAL %d row(s): V/Space mark, Esc cancel", hi-lo+1)
+	}
+	if len(pending) > 0 {
+		state := tern(m.enrich.stopped, "Details stopped", "Loading details")
+		statusInfo += fmt.Sprintf("  %s: %d/%d", state, len(m.allVols)-len(pending), len(m.allVols))
+	}
+	if m.notice != "" {
+		statusInfo += "  — " + m.notice
+	}
+	header = header + "\n" + dimStyle.Render(statusInfo)
+	return header
+}
+
+// lowerPane is the pane below the volume table: an open overlay, the
+// active pane, or the key help.
+func (m model) lowerPane() string {
 	lower := ""
-	switch m.active {
-	case paneDetails:
+	switch {
+	case m.menu != nil:
+		lower = m.renderMenu()
+	case m.columns != nil:
+		lower = m.renderColumns()
+	case m.finder != nil:
+		lower = m.renderFinder()
+	case m.active == paneDetails && !m.split():
 		lower = m.renderDetails()
-	case panePlan:
+	case m.active == panePlan:
 		lower = m.renderPlan()
+	case m.active == paneIgnore:
+		lower = m.renderIgnore()
+	case m.active == paneDiff:
+		lower = m.renderDiff()
+	case m.active == paneRoot:
+		lower = m.renderRoot()
+	case m.active == paneWhy:
+		lower = m.renderWhy()
+	case m.active == paneJobs:
+		lower = m.renderJobs()
+	case m.active == paneBackups:
+		lower = m.renderBackups()
+	case m.active == paneDedup:
+		lower = m.renderDedup()
+	case m.active == paneMissing:
+		lower = m.renderMissing()
+	case m.active == paneGraph:
+		if idx := m.table.Cursor(); idx >= 0 && idx < len(m.vols) {
+			lower = m.renderGraph(m.volumeNode(m.vols[idx]))
+		}
 	default:
-		lower = helpText()
+		lower = m.helpText()
 	}
+	return lower
+}
 
-	return header + "\n" + rendered + "\n" + lower
+// fitTable gives the table whatever height header and lower leave.
+func (m model) fitTable(header, lower string) model {
+	if m.height > 0 {
+		m.table.SetHeight(max(m.height-lipgloss.Height(header)-lipgloss.Height(lower)-tableFrameLines, 3))
+	}
+	return m
 }
 
+// renderTable draws the volume table at its natural width; marks are a
+// column of their own, so rendering never touches row data.
 func (m model) renderTable() string {
-	// decorate selected row if marked
-	rows := m.table.Rows()
-	for i := range rows {
-		mark := " "
-		if m.marked[i] {
-			mark = "✓"
-		}
-		// prepend checkbox to name
-		rows[i][0] = fmt.Sprintf("[%s] %s", mark, rows[i][0])
-	}
-	// render inside border
-	return borderStyle.Width(80).Render(m.table.View())
+	return borderStyle.Copy().UnsetWidth().Render(m.table.StyledView(m.volumeCellStyle()))
 }
 
 func (m model) renderDetails() string {
+	body := m.detailsBody()
+	if body == "" {
+		return ""
+	}
+	return m.pane().Render(body)
+}
+
+// detailsBody describes the volume under the cursor, or is empty when
+// there is none.
+func (m model) detailsBody() string {
 	idx := m.table.Cursor()
 	if idx < 0 || idx >= len(m.vols) {
 		return ""
@@ -183,36 +831,30 @@ func (m model) renderDetails() string {
 	}
 	sb := &strings.Builder{}
 	fmt.Fprintf(sb, "Details: %s (%s)\n", v.Name, v.SizeHuman())
-	fmt.Fprintf(sb, "Driver: %s\n", v.Driver)
+	if v.SizeStale {
+		fmt.Fprintf(sb, "Size: cached, may lag writes by attached containers\n")
+	}
+	sb.WriteString(m.liveSizeLine(v))
+	fmt.Fprintf(sb, "Driver: %s%s\n", v.Driver, tern(v.PluginDriver(), " (plugin-managed, not in system df)", ""))
 	fmt.Fprintf(sb, "Project: %s\n", ifEmpty(v.Project, "<none>"))
-	fmt.Fprintf(sb, "Status: %s\n", tern(v.Orphan, "ORPHAN", "ACTIVE"))
+	fmt.Fprintf(sb, "Status: %s\n", stateStyle(v.State).Render(v.State.Label()))
 	fmt.Fprintf(sb, "Attached: %s\n", attached)
-	fmt.Fprintf(sb, "\nReal Docker volume data\n")
-
-	return borderStyle.Width(80).Render(sb.String())
-}
-
-func (m model) renderPlan() string {
-	total := int64(0)
-	lines := make([]string, 0)
-	for i, v := range m.vols {
-		if m.marked[i] {
-			lines = append(lines, fmt.Sprintf("  ✓ %s (%s)", v.Name, v.SizeHuman()))
-			if v.SizeBytes > 0 {
-				total += v.SizeBytes
-			}
-		}
+	if len(v.Tags) > 0 {
+		fmt.Fprintf(sb, "Tags: %s\n", strings.Join(v.Tags, ", "))
 	}
-	if len(lines) == 0 {
-		lines = append(lines, "  <none selected>")
+	if v.Note != "" {
+		fmt.Fprintf(sb, "Note: %s\n", v.Note)
 	}
-	human := humanBytes(total)
-	body := "Prune Plan:\n" + strings.Join(lines, "\n") + "\n\nTotal space to reclaim: " + human + "\n\n[A] Apply prune   [C] Cancel   [Q] Quit"
-	return borderStyle.Width(80).Render(body)
+	fmt.Fprintf(sb, "\nReal Docker volume data\n")
+	return sb.String()
 }
 
-func helpText() string {
-	return borderStyle.Width(80).Render("[↑/↓] Move  [Space] Mark  [Enter] Details  [P] Plan  [Tab] Switch  [Q] Quit")
+func (m model) helpText() string {
+	return m.pane().Render("[↑/↓/PgUp/PgDn] Move  [Ctrl+F] Find  [Space] Mark  [V] Range  [D] Delete\n" +
+		"[E] Export  [T] Sort  [A] Age  [W] Save view  [Alt+1-9] Views  [C] Columns\n" +
+		"[N] Note  [#] Tags  [F] Filter by tag  [U] Data root  [?] Why orphan  [J] Jobs\n" +
+		"[X] Ignore  [S] Snapshot  [G] Graph  [B] Backups  [Enter] Actions  [P] Plan\n" +
+		"[Y] Copy  [O] Open  [R] Reload  [Tab] Switch  [Ctrl+P] Commands  [Q] Quit")
 }
 
 func humanBytes(b int64) string {
@@ -249,11 +891,35 @@ func tern[T any](cond bool, a, b T) T {
 	return b
 }
 
-// getDockerProvider creates a Docker provider, returns error if Docker is not available
+// getDockerProvider creates a Docker provider, returns error if Docker is not available.
+// With $DOCKWATCH_REMOTE set it talks to that agent instead.
 func getDockerProvider() (provider.Provider, error) {
+	if client, err := remote.FromEnv(); client != nil || err != nil {
+		if err != nil {
+			return nil, fmt.Errorf("failed to create remote provider: %w", err)
+		}
+		return client, nil
+	}
 	dockerProv, err := dockercli.NewDockerProvider()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker provider: %w", err)
 	}
 	return dockerProv, nil
 }
+
+// stateLogger appends lines to the named log in the state directory, e.g.
+// dry-run removals; stderr belongs to the TUI.
+func stateLogger(name string) func(format string, args ...any) {
+	path := filepath.Join(config.StateDir(), name)
+	return func(format string, args ...any) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		fmt.Fprintf(f, "%s "+format+"\n", append([]any{time.Now().Format(time.RFC3339)}, args...)...)
+	}
+}
@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// tableHeadLines are the lines above the first table row: the top
+	// border and the column header.
+	tableHeadLines = 2
+
+	// doubleClick is how soon a second click on the same row counts as a
+	// double click.
+	doubleClick = 400 * time.Millisecond
+)
+
+// lastClick is the previous left click on a volume row.
+type lastClick struct {
+	row int
+	at  time.Time
+}
+
+// updateMouse handles mouse events: a click on the tab bar switches
+// views, and in the Volumes view a click selects a row, a double click
+// opens its details, the wheel scrolls, and a click on the pane below the
+// table switches to the next pane like Tab. Overlays and prompts take the
+// keyboard only.
+func (m model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.onboard != nil || m.health != nil || m.overlayOpen() {
+		return m, nil
+	}
+	if msg.Y == 0 && msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress {
+		if r, ok := tabAt(msg.X); ok {
+			return m.openTab(r)
+		}
+		return m, nil
+	}
+	if m.resource != resVolumes {
+		return m, nil
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.table.SetCursor(m.table.Cursor() - 1)
+		return m.followVisual(), nil
+	case tea.MouseButtonWheelDown:
+		m.table.SetCursor(m.table.Cursor() + 1)
+		return m.followVisual(), nil
+	case tea.MouseButtonLeft:
+	default:
+		return m, nil
+	}
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+
+	header, lower := m.volumeHeader(), m.lowerPane()
+	laid := m.fitTable(header, lower)
+	top := lipgloss.Height(header)
+	table := laid.renderTable()
+	bottom := top + lipgloss.Height(table)
+	switch {
+	case msg.Y >= bottom:
+		m.active = (m.active + 1) % paneCount
+		return m, nil
+	case msg.Y < top, msg.X >= lipgloss.Width(table):
+		// Above the table, or on the details beside it
+		return m, nil
+	}
+	row, ok := laid.table.RowAt(msg.Y - top - tableHeadLines)
+	if !ok {
+		return m, nil
+	}
+	now := time.Now()
+	double := m.click.row == row && now.Sub(m.click.at) < doubleClick
+	m.click = lastClick{row: row, at: now}
+	m.table = laid.table
+	m.table.SetCursor(row)
+	m = m.followVisual()
+	if double {
+		m.click = lastClick{}
+		m.active = paneDetails
+	}
+	return m, nil
+}
+
+// overlayOpen reports whether a prompt or menu is waiting for keys.
+func (m model) overlayOpen() bool {
+	return m.menu != nil || m.columns != nil || m.finder != nil || m.palette != nil || m.prompt != nil || m.confirmDelete != ""
+}
+
+// followVisual redraws the rows when a visual range follows the cursor.
+func (m model) followVisual() model {
+	if m.visual {
+		m.table.SetRows(m.volumeRows())
+	}
+	return m
+}
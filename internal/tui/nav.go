@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/domain"
+)
+
+// maxBack caps the back-stack; the oldest locations fall off first.
+const maxBack = 50
+
+// location is a place a drill-down left, so Backspace can return to it.
+type location struct {
+	resource resource
+	item     string // project, volume or container under the cursor
+	project  string // compose project the Volumes view was narrowed to
+	logs     bool   // the container's log pane was open
+}
+
+// here is the current location.
+func (m model) here() location {
+	l := location{resource: m.resource, project: m.projectFilter}
+	switch m.resource {
+	case resVolumes:
+		if idx := m.table.Cursor(); idx >= 0 && idx < len(m.vols) {
+			l.item = m.vols[idx].Name
+		}
+	case resContainers:
+		if c, ok := m.selectedContainer(); ok {
+			l.item = c.Name
+		}
+		l.logs = m.logs != nil
+	case resProjects:
+		if p, ok := m.selectedProject(); ok {
+			l.item = p.name
+		}
+	case resNetworks:
+		if n, ok := m.selectedNetwork(); ok {
+			l.item = n.Name
+		}
+	}
+	return l
+}
+
+// push records the current location before a drill-down leaves it.
+func (m model) push() model {
+	m.back = append(m.back, m.here())
+	if len(m.back) > maxBack {
+		m.back = slices.Clone(m.back[len(m.back)-maxBack:])
+	}
+	return m
+}
+
+// goBack returns to the last location on the back-stack that differs from
+// the current one, e.g. skipping a log pane already closed with Esc.
+func (m model) goBack() (model, tea.Cmd) {
+	here := m.here()
+	for len(m.back) > 0 {
+		l := m.back[len(m.back)-1]
+		m.back = m.back[:len(m.back)-1]
+		if l != here {
+			return m.restore(l)
+		}
+	}
+	m.notice = "nothing to go back to"
+	return m, nil
+}
+
+// restore shows l again with the cursor on its item.
+func (m model) restore(l location) (model, tea.Cmd) {
+	m.resource = l.resource
+	if !l.logs {
+		m.logs = nil
+	}
+	var cmd tea.Cmd
+	if l.project != m.projectFilter {
+		m.projectFilter = l.project
+		m = m.applyVolumeView()
+	}
+	switch l.resource {
+	case resVolumes:
+		if i := slices.IndexFunc(m.vols, func(v domain.Volume) bool { return v.Name == l.item }); i >= 0 {
+			m.table.SetCursor(i)
+		}
+	case resContainers:
+		m = m.selectContainer(l.item)
+		var statsCmd tea.Cmd
+		m, statsCmd = m.startStats()
+		cmd = tea.Batch(m.loadContainers(), statsCmd)
+	case resProjects:
+		for i, p := range m.projects() {
+			if p.name == l.item {
+				m.ptable.SetCursor(i)
+			}
+		}
+	case resNetworks:
+		if i := slices.IndexFunc(m.networks, func(n domain.Network) bool { return n.Name == l.item }); i >= 0 {
+			m.ntable.SetCursor(i)
+		}
+	}
+	return m, cmd
+}
+
+// selectContainer puts the container cursor on name, now if it is listed
+// and otherwise once the next listing arrives.
+func (m model) selectContainer(name string) model {
+	m.pendingContainer = name
+	for i, c := range m.containers {
+		if c.Name == name {
+			m.ctable.SetCursor(i)
+			m.pendingContainer = ""
+		}
+	}
+	return m
+}
+
+// breadcrumb shows the trail of drill-downs that led here, or nothing
+// when there is none.
+func (m model) breadcrumb() string {
+	if len(m.back) == 0 {
+		return ""
+	}
+	crumbs := make([]string, 0, len(m.back)+1)
+	for _, l := range append(slices.Clone(m.back), m.here()) {
+		crumbs = append(crumbs, l.String())
+	}
+	return strings.Join(crumbs, glyphs.crumbSep) + "  [Backspace] Back"
+}
+
+func (l location) String() string {
+	s := resourceTabs[slices.IndexFunc(resourceTabs, func(t resourceTab) bool { return t.res == l.resource })].name
+	if l.item != "" {
+		s += ": " + l.item
+	}
+	if l.logs {
+		s += " (logs)"
+	}
+	return s
+}
+
+// gotoContainer drills down from a volume to the first container using
+// it in the Containers view.
+func (m model) gotoContainer(volume string) (model, tea.Cmd) {
+	i := slices.IndexFunc(m.vols, func(v domain.Volume) bool { return v.Name == volume })
+	if i < 0 {
+		return m, nil
+	}
+	for _, name := range m.vols[i].Attached {
+		if strings.HasPrefix(name, "service:") {
+			continue // swarm services have no container to show
+		}
+		m = m.push()
+		m, cmd := m.switchResource(resContainers)
+		return m.selectContainer(name), cmd
+	}
+	m.notice = volume + " is not used by any container"
+	return m, nil
+}
@@ -0,0 +1,307 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"dockwatch/internal/domain"
+)
+
+// networksDeletedMsg reports the outcome of removing networks: the ones
+// removed, and why the others weren't.
+type networksDeletedMsg struct {
+	removed []string
+	err     error
+}
+
+func newNetworkTable() table.Model {
+	cols := []table.Column{
+		{Title: " ", Width: 1},
+		{Title: "Name", Width: 17},
+		{Title: "Driver", Width: 7},
+		{Title: "Subnet", Width: 16},
+		{Title: "Conn", Width: 4},
+		{Title: "Project", Width: 11},
+		{Title: "Status", Width: 6},
+	}
+	t := table.New(table.WithColumns(cols), table.WithFocused(true), table.WithStyles(tableStyles()))
+	t.KeyMap.LineUp.SetKeys("up")
+	t.KeyMap.LineDown.SetKeys("down")
+	return t
+}
+
+// networkRows lists networks in the order the daemon returned them.
+func (m model) networkRows() []table.Row {
+	rows := make([]table.Row, 0, len(m.networks))
+	running := m.runningProjects()
+	for _, n := range m.networks {
+		subnet := "-"
+		if len(n.IPAM) > 0 {
+			subnet = n.IPAM[0].Subnet
+			if len(n.IPAM) > 1 {
+				subnet += fmt.Sprintf(" +%d", len(n.IPAM)-1)
+			}
+		}
+		rows = append(rows, table.Row{
+			tern(m.nmarked[n.Name], glyphs.mark, " "),
+			n.Name,
+			n.Driver,
+			subnet,
+			fmt.Sprint(len(n.Containers)),
+			ifEmpty(n.Project(), "-"),
+			tern(networkUnused(n, running), "UNUSED", ""),
+		})
+	}
+	return rows
+}
+
+func (m model) selectedNetwork() (domain.Network, bool) {
+	idx := m.ntable.Cursor()
+	if idx < 0 || idx >= len(m.networks) {
+		return domain.Network{}, false
+	}
+	return m.networks[idx], true
+}
+
+// runningProjects are the compose projects with a running container.
+func (m model) runningProjects() map[string]bool {
+	running := map[string]bool{}
+	for _, c := range m.containers {
+		if p := c.Project(); p != "" && c.Running() {
+			running[p] = true
+		}
+	}
+	return running
+}
+
+// networkUnused reports whether nothing is connected to n and nothing is
+// about to be: the daemon's own networks and the default network of a
+// compose project that is up are never unused.
+func networkUnused(n domain.Network, running map[string]bool) bool {
+	switch {
+	case len(n.Containers) > 0, n.Predefined():
+		return false
+	case n.ComposeDefault() && running[n.Project()]:
+		return false
+	}
+	return true
+}
+
+// markUnusedNetworks marks every unused network, the network counterpart
+// of marking orphans.
+func (m model) markUnusedNetworks() model {
+	running := m.runningProjects()
+	n := 0
+	for _, net := range m.networks {
+		if networkUnused(net, running) && !m.nmarked[net.Name] {
+			m.nmarked[net.Name] = true
+			n++
+		}
+	}
+	m.notice = fmt.Sprintf("marked %d unused network(s)", n)
+	m.ntable.SetRows(m.networkRows())
+	return m
+}
+
+// networkBlocker explains why n can't be removed, or returns "".
+func networkBlocker(n domain.Network) string {
+	switch {
+	case n.Predefined():
+		return "it is one of Docker's predefined networks"
+	case len(n.Containers) > 0:
+		return fmt.Sprintf("%s connected to it; disconnect or remove %s first",
+			strings.Join(n.Containers, ", "), tern(len(n.Containers) == 1, "it", "them"))
+	}
+	return ""
+}
+
+// askDeleteNetworks asks for confirmation to remove the marked networks,
+// or the selected one when none is marked, unless something blocks one.
+func (m model) askDeleteNetworks() model {
+	if m.provider == nil {
+		return m
+	}
+	var targets []domain.Network
+	for _, n := range m.networks {
+		if m.nmarked[n.Name] {
+			targets = append(targets, n)
+		}
+	}
+	if len(targets) == 0 {
+		n, ok := m.selectedNetwork()
+		if !ok {
+			return m
+		}
+		targets = append(targets, n)
+	}
+	names := make([]string, len(targets))
+	for i, n := range targets {
+		if why := networkBlocker(n); why != "" {
+			m.notice = fmt.Sprintf("can't delete network %s: %s", n.Name, why)
+			return m
+		}
+		names[i] = n.Name
+	}
+	m.confirmNetworks, m.notice = names, ""
+	return m
+}
+
+func (m model) updateNetworks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirmNetworks != nil {
+		names := m.confirmNetworks
+		m.confirmNetworks = nil
+		if msg.String() != "y" {
+			m.notice = "delete cancelled"
+			return m, nil
+		}
+		if m.dryRun {
+			log := stateLogger("dry-run.log")
+			for _, name := range names {
+				log("would remove network %s", name)
+			}
+			m.notice = fmt.Sprintf("would delete %d network(s)", len(names))
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("deleting %d network(s)…", len(names))
+		prov, ctx := m.provider, m.ctx
+		return m, func() tea.Msg {
+			var done networksDeletedMsg
+			var errs []error
+			for _, name := range names {
+				if err := prov.RemoveNetwork(ctx, name); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", name, err))
+					continue
+				}
+				done.removed = append(done.removed, name)
+			}
+			done.err = errors.Join(errs...)
+			return done
+		}
+	}
+	switch msg.String() {
+	case " ":
+		if n, ok := m.selectedNetwork(); ok {
+			m.nmarked[n.Name] = !m.nmarked[n.Name]
+			m.ntable.SetRows(m.networkRows())
+		}
+		return m, nil
+	case "u":
+		return m.markUnusedNetworks(), nil
+	case "d":
+		return m.askDeleteNetworks(), nil
+	case "r":
+		return m, tea.Batch(m.loadContainers(), m.loadNetworks())
+	}
+
+	var cmd tea.Cmd
+	m.ntable, cmd = m.ntable.Update(msg)
+	return m, cmd
+}
+
+func (m model) onNetworksDeleted(msg networksDeletedMsg) (tea.Model, tea.Cmd) {
+	for _, name := range msg.removed {
+		delete(m.nmarked, name)
+	}
+	m.notice = fmt.Sprintf("%s %d network(s)", m.removedVerb(), len(msg.removed))
+	switch {
+	case errors.Is(msg.err, domain.ErrNetworkInUse):
+		// The listing was stale; the refresh shows who connected since
+		m.notice += fmt.Sprintf("; containers connected since the last refresh, disconnect them first (%v)", msg.err)
+	case msg.err != nil:
+		m.notice += fmt.Sprintf("; failed: %v", msg.err)
+	}
+	return m, tea.Batch(m.loadNetworks(), m.loadContainers())
+}
+
+func (m model) viewNetworks() string {
+	header := m.renderTabs() + "\n" + m.title("Docker Networks")
+	running, unused, marked := m.runningProjects(), 0, 0
+	for _, n := range m.networks {
+		if networkUnused(n, running) {
+			unused++
+		}
+		if m.nmarked[n.Name] {
+			marked++
+		}
+	}
+	statusInfo := fmt.Sprintf("Networks: %d  Unused: %d  Marked: %d", len(m.networks), unused, marked)
+	if m.notice != "" {
+		statusInfo += "  — " + m.notice
+	}
+	header = header + "\n" + dimStyle.Render(statusInfo)
+
+	lower := m.pane().Render("<no network selected>")
+	if n, ok := m.selectedNetwork(); ok {
+		lower = m.renderNetwork(n)
+	}
+	if m.height > 0 {
+		m.ntable.SetHeight(max(m.height-lipgloss.Height(header)-lipgloss.Height(lower)-tableFrameLines, 3))
+	}
+	return header + "\n" + m.pane().Render(m.ntable.View()) + "\n" + lower
+}
+
+// renderNetwork is the details pane: addressing, reachability and who is
+// connected.
+func (m model) renderNetwork(n domain.Network) string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Network %s (%s)\n", n.Name, shortID(n.ID))
+	fmt.Fprintf(sb, "Driver: %s, scope %s", n.Driver, n.Scope)
+	if n.Attachable {
+		sb.WriteString(", attachable")
+	}
+	if n.IPv6 {
+		sb.WriteString(", IPv6")
+	}
+	sb.WriteString("\n")
+	if n.Internal {
+		sb.WriteString("Internal: containers can't reach anything outside it\n")
+	} else {
+		sb.WriteString("External: containers can reach outside networks\n")
+	}
+	if len(n.IPAM) == 0 {
+		fmt.Fprintf(sb, "IPAM (%s): <no address pools>\n", ifEmpty(n.IPAMDriver, "default"))
+	}
+	for _, c := range n.IPAM {
+		line := fmt.Sprintf("IPAM (%s): subnet %s", ifEmpty(n.IPAMDriver, "default"), ifEmpty(c.Subnet, "?"))
+		if c.Gateway != "" {
+			line += ", gateway " + c.Gateway
+		}
+		if c.IPRange != "" {
+			line += ", range " + c.IPRange
+		}
+		sb.WriteString(line + "\n")
+	}
+	if p := n.Project(); p != "" {
+		fmt.Fprintf(sb, "Project: %s%s\n", p, tern(n.ComposeDefault(), " (its default network)", ""))
+	}
+	if len(n.Containers) == 0 {
+		sb.WriteString("Connected: <none>\n")
+	} else {
+		states := make([]string, len(n.Containers))
+		for i, name := range n.Containers {
+			states[i] = name
+			if j := slices.IndexFunc(m.containers, func(c domain.Container) bool { return c.Name == name }); j >= 0 {
+				states[i] += " (" + m.containers[j].State + ")"
+			}
+		}
+		fmt.Fprintf(sb, "Connected: %s\n", strings.Join(states, ", "))
+	}
+
+	if m.confirmNetworks != nil {
+		fmt.Fprintf(sb, "\nDelete network(s) %s? [y/N]", strings.Join(m.confirmNetworks, ", "))
+	} else {
+		sb.WriteString("\n[Space] Mark  [U] Mark unused  [D] Delete  [R] Refresh  [1-5] Views  [Q] Quit")
+	}
+	return m.pane().Render(sb.String())
+}
+
+// shortID abbreviates a full object ID the way docker ls does.
+func shortID(id string) string {
+	return id[:min(len(id), 12)]
+}
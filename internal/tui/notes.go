@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"dockwatch/internal/domain"
+)
+
+// selectedVolume returns the volume under the cursor, if any.
+func (m model) selectedVolume() (domain.Volume, bool) {
+	idx := m.table.Cursor()
+	if idx < 0 || idx >= len(m.vols) {
+		return domain.Volume{}, false
+	}
+	return m.vols[idx], true
+}
+
+// editNote prompts for the selected volume's local note.
+func (m model) editNote() model {
+	v, ok := m.selectedVolume()
+	if !ok || m.notes == nil {
+		return m
+	}
+	return m.ask("Note for "+v.Name, v.Note, func(m model, note string) model {
+		m.notes.SetNote(v.Name, note)
+		return m.saveNotes(fmt.Sprintf("note on %s saved", v.Name))
+	})
+}
+
+// editTags prompts for the selected volume's local tags, comma-separated.
+func (m model) editTags() model {
+	v, ok := m.selectedVolume()
+	if !ok || m.notes == nil {
+		return m
+	}
+	return m.ask("Tags for "+v.Name+" (comma-separated)", strings.Join(v.Tags, ", "), func(m model, tags string) model {
+		m.notes.SetTags(v.Name, strings.Split(tags, ","))
+		return m.saveNotes(fmt.Sprintf("tags on %s saved", v.Name))
+	})
+}
+
+// filterTag prompts for a tag to show only volumes carrying it; an empty
+// answer shows every volume again.
+func (m model) filterTag() model {
+	return m.ask("Show tag", m.tagFilter, func(m model, tag string) model {
+		m.tagFilter = strings.TrimSpace(tag)
+		m.visual = false
+		m = m.applyVolumeView()
+		m.notice = tern(m.tagFilter == "", "tag filter cleared", fmt.Sprintf("showing volumes tagged %q", m.tagFilter))
+		return m
+	})
+}
+
+func (m model) saveNotes(done string) model {
+	if err := m.notes.Save(); err != nil {
+		m.notice = fmt.Sprintf("failed to save notes: %v", err)
+		return m
+	}
+	m = m.applyVolumeView()
+	m.notice = done
+	return m
+}
@@ -0,0 +1,318 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/provider"
+)
+
+// onboardStep is a page of the setup wizard.
+type onboardStep int
+
+const (
+	stepContext onboardStep = iota
+	stepTheme
+	stepProtect
+	stepReview
+)
+
+// onboardState is the setup wizard shown on first launch, when there is no
+// config file yet, and from the palette afterwards.
+type onboardState struct {
+	step     onboardStep
+	loading  bool // listing the docker contexts
+	contexts []dockercli.Context
+	sockets  []dockercli.Endpoint
+	err      error // why the contexts couldn't be listed
+	cursor   int   // in the contexts or themes
+
+	context string // the picked context; "" leaves config.context alone
+	theme   string
+	protect string // the ignore list being edited, comma-separated
+	invalid string // why protect doesn't parse
+}
+
+// contextsMsg carries the docker contexts for the wizard.
+type contextsMsg struct {
+	contexts []dockercli.Context
+	err      error
+}
+
+// reconnectMsg carries a provider for a newly picked context.
+type reconnectMsg struct {
+	prov provider.Provider
+	err  error
+}
+
+// firstRun reports whether no config file has been written yet.
+func firstRun() bool {
+	_, err := os.Stat(config.Path())
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// openOnboard opens the wizard on its first page, prefilled from the config.
+func (m model) openOnboard() (model, tea.Cmd) {
+	m.onboard = &onboardState{
+		loading: true,
+		sockets: dockercli.FindSockets(),
+		theme:   ifEmpty(m.cfg.Theme, config.Themes[0]),
+		protect: strings.Join(m.cfg.Ignore, ", "),
+	}
+	return m, listContexts(m.ctx)
+}
+
+func listContexts(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		contexts, err := dockercli.ListContexts(ctx)
+		return contextsMsg{contexts: contexts, err: err}
+	}
+}
+
+func (m model) onContexts(msg contextsMsg) model {
+	if m.onboard == nil {
+		return m
+	}
+	st := *m.onboard
+	st.loading = false
+	st.contexts, st.err = msg.contexts, msg.err
+	for i, c := range st.contexts {
+		if c.Current {
+			st.cursor = i
+		}
+	}
+	m.onboard = &st
+	return m
+}
+
+// updateOnboard handles keys while the wizard is open: Enter moves on, Esc
+// goes back, and on the first page skips the wizard.
+func (m model) updateOnboard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	st := *m.onboard
+	m.onboard = &st
+	if st.step == stepProtect {
+		switch msg.Type {
+		case tea.KeyBackspace:
+			if r := []rune(st.protect); len(r) > 0 {
+				st.protect = string(r[:len(r)-1])
+			}
+			st.invalid = ""
+			return m, nil
+		case tea.KeyRunes, tea.KeySpace:
+			st.protect += string(msg.Runes)
+			st.invalid = ""
+			return m, nil
+		}
+	}
+	switch msg.String() {
+	case "up":
+		st.cursor = max(st.cursor-1, 0)
+	case "down":
+		st.cursor = min(st.cursor+1, max(st.options()-1, 0))
+	case "esc":
+		if st.step == stepContext {
+			return m.finishOnboard(false)
+		}
+		st.step--
+		st.cursor = st.pick()
+	case "enter":
+		switch st.step {
+		case stepContext:
+			if st.loading {
+				return m, nil
+			}
+			if st.cursor < len(st.contexts) {
+				st.context = st.contexts[st.cursor].Name
+			}
+		case stepTheme:
+			st.theme = config.Themes[st.cursor]
+		case stepProtect:
+			if _, err := parsePatterns(st.protect); err != nil {
+				st.invalid = err.Error()
+				return m, nil
+			}
+		case stepReview:
+			return m.finishOnboard(true)
+		}
+		st.step++
+		st.cursor = st.pick()
+	}
+	return m, nil
+}
+
+// options is how many choices the current page lists.
+func (st *onboardState) options() int {
+	switch st.step {
+	case stepContext:
+		return len(st.contexts)
+	case stepTheme:
+		return len(config.Themes)
+	}
+	return 0
+}
+
+// pick is the cursor position of the current page's choice.
+func (st *onboardState) pick() int {
+	switch st.step {
+	case stepContext:
+		for i, c := range st.contexts {
+			if c.Name == st.context || st.context == "" && c.Current {
+				return i
+			}
+		}
+	case stepTheme:
+		return max(slices.Index(config.Themes, st.theme), 0)
+	}
+	return 0
+}
+
+// parsePatterns splits a comma-separated list of names and globs.
+func parsePatterns(s string) ([]string, error) {
+	var patterns []string
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// finishOnboard closes the wizard and writes the config, applying the
+// answers when save is set. Skipping writes it unchanged, so the wizard
+// doesn't come back on the next launch.
+func (m model) finishOnboard(save bool) (tea.Model, tea.Cmd) {
+	st := m.onboard
+	m.onboard = nil
+	if !save {
+		if err := m.cfg.Save(); err != nil {
+			m.notice = fmt.Sprintf("failed to save config: %v", err)
+			return m, nil
+		}
+		m.notice = "setup skipped; run it again from the palette (Ctrl+P)"
+		return m, nil
+	}
+	m.cfg.Theme = st.theme
+	m.cfg.Ignore, _ = parsePatterns(st.protect)
+	switched := false
+	if st.context != "" {
+		m.cfg.Context = st.context
+		current := slices.IndexFunc(st.contexts, func(c dockercli.Context) bool { return c.Current })
+		switched = (current < 0 || st.contexts[current].Name != st.context) && dockercli.SwitchContext(st.context)
+	}
+	if err := m.cfg.Save(); err != nil {
+		m.notice = fmt.Sprintf("failed to save config: %v", err)
+		return m, nil
+	}
+	applyTheme(m.cfg.Theme)
+	m.notice = "saved " + config.Path()
+	m.table.SetRows(m.volumeRows())
+	if !switched {
+		return m, nil
+	}
+	m.notice += "; connecting to " + st.context + "…"
+	if m.health != nil {
+		h := *m.health
+		h.checking = true
+		m.health = &h
+		return m, m.runHealth()
+	}
+	return m, func() tea.Msg {
+		prov, err := getDockerProvider()
+		return reconnectMsg{prov: prov, err: err}
+	}
+}
+
+func (m model) onReconnect(msg reconnectMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.health = &healthState{err: msg.err, checking: true}
+		return m, m.runHealth()
+	}
+	m = m.connected(msg.prov)
+	return m, m.loadUsage()
+}
+
+func (m model) renderOnboard() string {
+	st := m.onboard
+	var b strings.Builder
+	b.WriteString(m.title(fmt.Sprintf("Set up dockwatch (%d/4)", st.step+1)) + "\n\n")
+	choice := func(i int, line string) {
+		if i == st.cursor {
+			line = selectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+	help := "[↑/↓] Move  [Enter] Next  [Esc] Back"
+	switch st.step {
+	case stepContext:
+		help = "[↑/↓] Move  [Enter] Next  [Esc] Skip setup"
+		b.WriteString("Which Docker should dockwatch use?\n")
+		switch {
+		case st.loading:
+			b.WriteString("  looking for docker contexts…\n")
+		case st.err != nil:
+			fmt.Fprintf(&b, "  %v\n", st.err)
+		case len(st.contexts) == 0:
+			b.WriteString("  <no docker contexts>\n")
+		}
+		for i, c := range st.contexts {
+			line := fmt.Sprintf("%-16s %s", c.Name, c.Host)
+			if c.Current {
+				line += "  (current)"
+			}
+			choice(i, line)
+		}
+		if len(st.sockets) > 0 {
+			b.WriteString("\nSockets found on this machine:\n")
+			for _, e := range st.sockets {
+				fmt.Fprintf(&b, "  %-16s %s\n", e.Name, dimStyle.Render(e.Host))
+			}
+		}
+		for _, env := range []string{"DOCKER_HOST", "DOCKER_CONTEXT"} {
+			if v := os.Getenv(env); v != "" {
+				fmt.Fprintf(&b, "\n%s\n", warnStyle.Render(fmt.Sprintf("%s=%s picks the daemon while it is set; the context applies without it", env, v)))
+				break
+			}
+		}
+	case stepTheme:
+		b.WriteString("Which colors suit your terminal?\n")
+		for i, name := range config.Themes {
+			t := themes[name]
+			sample := func(c lipgloss.Color, s string) string { return lipgloss.NewStyle().Foreground(c).Render(s) }
+			choice(i, fmt.Sprintf("%-6s %s %s %s %s", name, sample(t.running, "running"), sample(t.stopped, "stopped"), sample(t.orphaned, "orphaned"), sample(t.protected, "protected")))
+		}
+	case stepProtect:
+		help = "[Enter] Next  [Esc] Back"
+		b.WriteString("Which volumes must never be deleted? Names or globs, comma-separated (e.g. prod-*, pgdata).\n")
+		b.WriteString("They are left out of orphan counts and plans; x toggles one later.\n\n")
+		fmt.Fprintf(&b, "  %s%s\n", st.protect, glyphs.caret)
+		if st.invalid != "" {
+			fmt.Fprintf(&b, "  %s\n", failStyle.Render(st.invalid))
+		}
+	case stepReview:
+		help = "[Enter] Save  [Esc] Back"
+		fmt.Fprintf(&b, "This is written to %s:\n\n", config.Path())
+		fmt.Fprintf(&b, "  context  %s\n", ifEmpty(st.context, dimStyle.Render("(unchanged)")))
+		fmt.Fprintf(&b, "  theme    %s\n", st.theme)
+		patterns, _ := parsePatterns(st.protect)
+		fmt.Fprintf(&b, "  ignore   %s\n", ifEmpty(strings.Join(patterns, ", "), dimStyle.Render("(none)")))
+	}
+	return b.String() + "\n" + m.pane().Render(help)
+}
@@ -0,0 +1,238 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteRows is how many commands the palette lists at once.
+const paletteRows = 12
+
+// anyView marks commands the palette offers in every view.
+const anyView resource = -1
+
+// command is one palette entry. Most replay their key, so the palette and
+// the keymap can't drift apart; the rest have no key and run directly.
+type command struct {
+	res  resource
+	name string
+	key  string
+	run  func(m model) (tea.Model, tea.Cmd)
+}
+
+// commands lists every action the palette knows, by view.
+var commands = []command{
+	{res: anyView, name: "Go to Volumes", key: "1"},
+	{res: anyView, name: "Go to Containers", key: "2"},
+	{res: anyView, name: "Go to Images", key: "3"},
+	{res: anyView, name: "Go to Projects", key: "4"},
+	{res: anyView, name: "Go back", key: "backspace"},
+	{res: anyView, name: "Run the setup wizard", run: func(m model) (tea.Model, tea.Cmd) { return m.openOnboard() }},
+	{res: anyView, name: "Quit", run: model.quit},
+
+	{res: resVolumes, name: "Refresh volumes", key: "r"},
+	{res: resVolumes, name: "Find volume", key: "ctrl+f"},
+	{res: resVolumes, name: "Volume actions", key: "enter"},
+	{res: resVolumes, name: "Mark all orphans", run: func(m model) (tea.Model, tea.Cmd) { return m.markOrphans(), nil }},
+	{res: resVolumes, name: "Mark or unmark volume", key: " "},
+	{res: resVolumes, name: "Start visual range", key: "V"},
+	{res: resVolumes, name: "Show prune plan", key: "p"},
+	{res: resVolumes, name: "Delete volume", key: "d"},
+	{res: resVolumes, name: "Export CSV", key: "e"},
+	{res: resVolumes, name: "Export TSV", key: "E"},
+	{res: resVolumes, name: "Cycle sort order", key: "t"},
+	{res: resVolumes, name: "Cycle age filter", key: "A"},
+	{res: resVolumes, name: "Save view", key: "w"},
+	{res: resVolumes, name: "Pick columns", key: "C"},
+	{res: resVolumes, name: "Edit note", key: "n"},
+	{res: resVolumes, name: "Edit tags", key: "#"},
+	{res: resVolumes, name: "Filter by tag", key: "f"},
+	{res: resVolumes, name: "Why orphan?", key: "?"},
+	{res: resVolumes, name: "Dependency graph", key: "g"},
+	{res: resVolumes, name: "Data root usage", key: "U"},
+	{res: resVolumes, name: "Show jobs", key: "J"},
+	{res: resVolumes, name: "Browse backups", key: "b"},
+	{res: resVolumes, name: "Find duplicate content in marked volumes", key: "="},
+	{res: resVolumes, name: "Track volume size live", key: "L"},
+	{res: resVolumes, name: "Recreate missing compose volumes", key: "M"},
+	{res: resVolumes, name: "Copy volume name", key: "y"},
+	{res: resVolumes, name: "Copy mountpoint", key: "Y"},
+	{res: resVolumes, name: "Open mountpoint", key: "o"},
+	{res: resVolumes, name: "Open in file manager", key: "O"},
+	{res: resVolumes, name: "Ignore or unignore volume", key: "x"},
+	{res: resVolumes, name: "Take snapshot", key: "s"},
+
+	{res: resContainers, name: "Refresh containers", key: "r"},
+	{res: resContainers, name: "Container logs", key: "l"},
+	{res: resContainers, name: "Inspect health and restarts", key: "i"},
+	{res: resContainers, name: "Truncate logs", key: "t"},
+	{res: resContainers, name: "Open shell", key: "e"},
+	{res: resContainers, name: "Dependency graph", key: "g"},
+	{res: resContainers, name: "Bind mounts", key: "b"},
+	{res: resContainers, name: "Memory-backed storage", key: "m"},
+	{res: resContainers, name: "Swarm secrets and configs", key: "s"},
+	{res: resContainers, name: "Top writers", key: "w"},
+
+	{res: resImages, name: "Refresh images", key: "r"},
+	{res: resImages, name: "Mark or unmark image", key: " "},
+	{res: resImages, name: "Mark unused images", key: "u"},
+	{res: resImages, name: "Show image prune plan", key: "p"},
+	{res: resImages, name: "Pull image", key: "P"},
+	{res: resImages, name: "Check registries", key: "c"},
+	{res: resImages, name: "Scan image for vulnerabilities", key: "v"},
+	{res: resImages, name: "Show image details", key: "i"},
+	{res: resImages, name: "Dependency graph", key: "g"},
+
+	{res: resProjects, name: "Refresh projects", key: "r"},
+	{res: resProjects, name: "Project volumes", key: "enter"},
+	{res: resProjects, name: "Tear down orphaned resources", key: "t"},
+	{res: resProjects, name: "Compose down project", key: "d"},
+	{res: resProjects, name: "Compose up project", key: "u"},
+
+	{res: resNetworks, name: "Refresh networks", key: "r"},
+	{res: resNetworks, name: "Mark or unmark network", key: " "},
+	{res: resNetworks, name: "Mark unused networks", key: "u"},
+	{res: resNetworks, name: "Delete marked or selected networks", key: "d"},
+}
+
+// palette is the ctrl+p command search.
+type palette struct {
+	query   string
+	cursor  int
+	matches []command
+}
+
+// openPalette lists every command of the current view.
+func (m model) openPalette() model {
+	m.palette = &palette{matches: m.paletteMatches("")}
+	return m
+}
+
+// paletteMatches returns the commands of the current view whose name
+// fuzzy-matches query, best first.
+func (m model) paletteMatches(query string) []command {
+	type hit struct {
+		cmd   command
+		score int
+	}
+	var hits []hit
+	for _, c := range commands {
+		if c.res != anyView && c.res != m.resource {
+			continue
+		}
+		if score, ok := fuzzyScore(c.name, query); ok {
+			hits = append(hits, hit{c, score})
+		}
+	}
+	if query != "" {
+		slices.SortStableFunc(hits, func(a, b hit) int { return cmp.Compare(b.score, a.score) })
+	}
+	cmds := make([]command, len(hits))
+	for i, h := range hits {
+		cmds[i] = h.cmd
+	}
+	return cmds
+}
+
+// updatePalette edits the query; ↑/↓ pick a command, Enter runs it.
+func (m model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := *m.palette
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlP:
+		m.palette = nil
+		return m, nil
+	case tea.KeyEnter:
+		m.palette = nil
+		if p.cursor >= len(p.matches) {
+			return m, nil
+		}
+		c := p.matches[p.cursor]
+		if c.run != nil {
+			return c.run(m)
+		}
+		return m.Update(keyMsg(c.key))
+	case tea.KeyUp:
+		p.cursor = max(p.cursor-1, 0)
+	case tea.KeyDown:
+		p.cursor = min(p.cursor+1, max(min(len(p.matches), paletteRows)-1, 0))
+	case tea.KeyBackspace:
+		if r := []rune(p.query); len(r) > 0 {
+			p.query = string(r[:len(r)-1])
+		}
+		p.matches, p.cursor = m.paletteMatches(p.query), 0
+	case tea.KeyRunes, tea.KeySpace:
+		p.query += string(msg.Runes)
+		p.matches, p.cursor = m.paletteMatches(p.query), 0
+	}
+	m.palette = &p
+	return m, nil
+}
+
+// keyMsg builds the key press a command replays.
+func keyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	case "ctrl+f":
+		return tea.KeyMsg{Type: tea.KeyCtrlF}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// markOrphans marks every shown orphan that isn't ignored for pruning.
+func (m model) markOrphans() model {
+	n := 0
+	for _, v := range m.vols {
+		if v.Orphan() && !m.cfg.IsIgnored(v.Name) && !m.marked[v.Name] {
+			m.marked[v.Name] = true
+			n++
+		}
+	}
+	m.table.SetRows(m.volumeRows())
+	m.notice = fmt.Sprintf("marked %d orphan volume(s)", n)
+	return m
+}
+
+func (m model) renderPalette() string {
+	p := m.palette
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Command: %s%s\n\n", p.query, glyphs.caret)
+	if len(p.matches) == 0 {
+		fmt.Fprintln(sb, dimStyle.Render("  no matching command"))
+	}
+	for i, c := range p.matches[:min(len(p.matches), paletteRows)] {
+		line := c.name
+		if c.key != "" {
+			line += "  " + dimStyle.Render(keyLabel(c.key))
+		}
+		if i == p.cursor {
+			line = selectedStyle.Render("> " + c.name)
+		} else {
+			line = "  " + line
+		}
+		fmt.Fprintln(sb, line)
+	}
+	fmt.Fprintf(sb, "\n[Type] Search  [↑/↓] Pick  [Enter] Run  [Esc] Close")
+	return m.pane().Render(sb.String())
+}
+
+// keyLabel spells a key the way the help lines do.
+func keyLabel(key string) string {
+	switch key {
+	case " ":
+		return "[Space]"
+	case "enter", "backspace":
+		return "[" + strings.ToUpper(key[:1]) + key[1:] + "]"
+	case "ctrl+f":
+		return "[Ctrl+F]"
+	}
+	return "[" + key + "]"
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// glyphSet is the non-ASCII characters the TUI draws with.
+type glyphSet struct {
+	mark  string // a marked row, in the one-column mark column
+	check string // a list item that is done or holds
+	dot   string
+	caret string
+	// Health check results
+	ok, warn, fail, skip string
+	tabSep, crumbSep     string
+	// Tree guides for a child, the last child, and what continues below
+	branch, last, pipe string
+}
+
+var (
+	unicodeGlyphs = glyphSet{
+		mark: "✓", check: "✓", dot: "·", caret: "█",
+		ok: "✓", warn: "!", fail: "✗", skip: "-",
+		tabSep: " │ ", crumbSep: " › ",
+		branch: "├── ", last: "└── ", pipe: "│   ",
+	}
+	asciiGlyphs = glyphSet{
+		mark: "x", check: "[x]", dot: ".", caret: "_",
+		ok: "[x]", warn: "[!]", fail: "[ ]", skip: "[-]",
+		tabSep: " | ", crumbSep: " > ",
+		branch: "|-- ", last: "`-- ", pipe: "|   ",
+	}
+	// Spelled out rather than drawn, for screen readers
+	plainGlyphs = glyphSet{
+		mark: "x", check: "x", dot: ".", caret: "_",
+		ok: "ok  ", warn: "warn", fail: "FAIL", skip: "skip",
+		tabSep: " | ", crumbSep: " > ",
+		branch: "|-- ", last: "`-- ", pipe: "|   ",
+	}
+)
+
+// asciiReplacer swaps what's left of Unicode in a rendered frame, from
+// borders, truncation and help text, for ASCII of the same width.
+var asciiReplacer = strings.NewReplacer(
+	"╭", "+", "╮", "+", "╰", "+", "╯", "+", "─", "-", "│", "|", "├", "|", "└", "`",
+	"…", ".", "—", "-", "↑", "^", "↓", "v", "→", ">", "›", ">", "≥", ">",
+	"✓", "x", "✗", "x", "·", ".", "█", "#",
+)
+
+// glyphs are the characters in use.
+var glyphs = unicodeGlyphs
+
+// noColor and plain are the accessibility modes. Without color the
+// selection is marked with text; plain output also drops box drawing and
+// spells statuses out, for screen readers and dumb terminals. ascii keeps
+// the layout but draws it in ASCII, for consoles without Unicode.
+var noColor, plain, ascii bool
+
+// setAccessibility switches the modes and the shared styles to match.
+// Tables pick up the change when they are created.
+func setAccessibility(colorless, plainText, asciiOnly bool) {
+	noColor, plain, ascii = colorless || plainText, plainText, asciiOnly
+	if noColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	glyphs = unicodeGlyphs
+	borderStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	switch {
+	case plain:
+		glyphs = plainGlyphs
+		borderStyle = lipgloss.NewStyle().Padding(0, 1)
+	case ascii:
+		glyphs = asciiGlyphs
+		borderStyle = lipgloss.NewStyle().Border(lipgloss.Border{
+			Top: "-", Bottom: "-", Left: "|", Right: "|",
+			TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+",
+		}).Padding(0, 1)
+	}
+}
+
+// tableStyles are the bubbles table styles. Without color the selected
+// row starts with ">" in place of its first cell's padding.
+func tableStyles() table.Styles {
+	s := table.DefaultStyles()
+	if noColor {
+		s.Selected = lipgloss.NewStyle().Transform(func(row string) string {
+			if rest, ok := strings.CutPrefix(row, " "); ok {
+				return ">" + rest
+			}
+			return row
+		})
+	}
+	return s
+}
+
+// selectedTab is the active tab's label: highlighted, or bracketed when
+// there is no color to highlight it with.
+func selectedTab(label string) string {
+	if noColor {
+		return "[" + label + "]"
+	}
+	return selectedStyle.Render(" " + label + " ")
+}
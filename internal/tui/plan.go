@@ -0,0 +1,216 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/audit"
+	"dockwatch/internal/domain"
+	"dockwatch/internal/plan"
+)
+
+// pruneSet is everything the unified prune plan would remove.
+type pruneSet struct {
+	containers []domain.Container
+	volumes    []domain.Volume
+	images     []domain.Image
+	networks   []domain.Network
+}
+
+// applyDoneMsg reports the outcome of applying a prune plan.
+type applyDoneMsg struct {
+	removed   int
+	freed     int64
+	errs      []error
+	gone      []string // labels of the steps that succeeded
+	teardown  bool     // from a project teardown rather than the prune plan
+	cancelled bool
+	remaining int      // steps never started because of the cancel
+	untouched []string // their labels
+}
+
+// pruneSet collects marked volumes (minus ignored ones), exited containers
+// when enabled along with the dangling images they leave behind, and marked
+// images.
+func (m model) pruneSet() pruneSet {
+	var ps pruneSet
+	for _, v := range m.allVols {
+		if m.marked[v.Name] && !m.cfg.IsIgnored(v.Name) {
+			ps.volumes = append(ps.volumes, v)
+		}
+	}
+	if m.planExited {
+		for _, c := range m.containers {
+			if c.Exited() {
+				ps.containers = append(ps.containers, c)
+			}
+		}
+	}
+	marks := m.planImageMarks()
+	for _, img := range m.images {
+		if marks[img.Ref()] {
+			ps.images = append(ps.images, img)
+		}
+	}
+	return ps
+}
+
+// planImageMarks is the images the prune plan removes, by ref: the marked
+// ones and, with exited containers included, the dangling images no other
+// container uses, which removing those containers would leave behind.
+func (m model) planImageMarks() map[string]bool {
+	if !m.planExited {
+		return m.imarked
+	}
+	marks := map[string]bool{}
+	for ref, on := range m.imarked {
+		marks[ref] = on
+	}
+	for _, img := range m.images {
+		if img.Dangling() && !marks[img.Ref()] && m.leftByExited(img) {
+			marks[img.Ref()] = true
+		}
+	}
+	return marks
+}
+
+// leftByExited reports whether img is used by exited containers only.
+func (m model) leftByExited(img domain.Image) bool {
+	exited := false
+	for _, c := range m.containers {
+		if (c.ImageID == "" || !strings.HasPrefix(c.ImageID, img.ID)) && c.Image != img.Ref() {
+			continue
+		}
+		if !c.Exited() {
+			return false
+		}
+		exited = true
+	}
+	return exited
+}
+
+func (m model) updatePlan(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "e":
+		m.planExited = !m.planExited
+		if m.planExited {
+			return m, m.loadContainers()
+		}
+	case "c":
+		m.marked = map[string]bool{}
+		m.imarked = map[string]bool{}
+		m.planExited = false
+		m.table.SetRows(m.volumeRows())
+		m.itable.SetRows(m.imageRows())
+		m.active = paneTable
+	case "a":
+		if m.apply != nil || m.provider == nil {
+			return m, nil
+		}
+		ps := m.pruneSet()
+		if len(ps.containers)+len(ps.volumes)+len(ps.images) == 0 {
+			m.notice = "nothing to apply"
+			return m, nil
+		}
+		m.notice = "applying prune plan…"
+		_, imgReclaim := plan.ImagePlan(m.images, m.planImageMarks())
+		return m.startApply(ps, imgReclaim, false)
+	}
+	return m, nil
+}
+
+func (m model) onApplyDone(msg applyDoneMsg) (tea.Model, tea.Cmd) {
+	if m.apply != nil {
+		m.apply.cancel()
+		m.apply = nil
+	}
+	// Nothing left to ask about
+	m.quitAsk = false
+	if !msg.teardown {
+		m.marked = map[string]bool{}
+		m.imarked = map[string]bool{}
+		m.planExited = false
+		m.active = paneTable
+	}
+	m.notice = fmt.Sprintf("%s %d object(s), ~%s %s", m.removedVerb(), msg.removed, humanBytes(msg.freed), tern(m.dryRun, "reclaimable", "freed"))
+	if len(msg.errs) > 0 {
+		m.notice += fmt.Sprintf(", %d failed: %v", len(msg.errs), msg.errs[0])
+	}
+	if msg.cancelled {
+		m.notice = fmt.Sprintf("cancelled, %d left untouched — %s", msg.remaining, m.notice)
+	}
+	if err := audit.Append(m.auditRecord(msg)); err != nil {
+		m.notice += "; " + err.Error()
+	}
+	if m.quitPending {
+		return m.quit()
+	}
+	return m, tea.Batch(m.loadVolumes(), m.loadContainers(), m.loadImages(), m.loadNetworks())
+}
+
+func (m model) renderPlan() string {
+	ps := m.pruneSet()
+	total := int64(0)
+	sb := &strings.Builder{}
+	sb.WriteString("Prune Plan:\n")
+
+	sb.WriteString(" Volumes:\n")
+	for _, v := range ps.volumes {
+		fmt.Fprintf(sb, "  %s %s (%s)\n", glyphs.check, v.Name, v.SizeHuman())
+		if warning := m.composeWarning(v.Name); warning != "" {
+			fmt.Fprintf(sb, "    %s\n", warnStyle.Render("! "+warning))
+		}
+		total += max(v.SizeBytes, 0)
+	}
+	if len(ps.volumes) == 0 {
+		sb.WriteString("  <none selected>\n")
+	}
+
+	if m.planExited {
+		sb.WriteString(" Exited containers (with anonymous volumes):\n")
+		now := time.Now()
+		for _, c := range ps.containers {
+			age := "?"
+			if !c.FinishedAt.IsZero() {
+				age = domain.HumanAge(now.Sub(c.FinishedAt)) + " ago"
+			}
+			anon := ""
+			if n := len(c.AnonymousVolumes()); n > 0 {
+				anon = fmt.Sprintf(", +%d anon vol", n)
+			}
+			fmt.Fprintf(sb, "  %s %s (exit %d, %s, %s%s)\n", glyphs.check, c.Name, c.ExitCode, age, humanSize(c.SizeRw), anon)
+			total += max(c.SizeRw, 0)
+		}
+		if len(ps.containers) == 0 {
+			sb.WriteString("  <no exited containers>\n")
+		}
+		header := false
+		for _, img := range ps.images {
+			if m.imarked[img.Ref()] {
+				continue
+			}
+			if !header {
+				sb.WriteString(" Dangling images they leave behind:\n")
+				header = true
+			}
+			fmt.Fprintf(sb, "  %s %s (%s)\n", glyphs.check, shortID(img.ID), humanBytes(img.SizeBytes))
+		}
+	}
+
+	if len(ps.images) > 0 {
+		_, imgReclaim := plan.ImagePlan(m.images, m.planImageMarks())
+		fmt.Fprintf(sb, " Images: %d, %s reclaimable (see Images view)\n", len(ps.images), humanBytes(imgReclaim))
+		total += imgReclaim
+	}
+
+	fmt.Fprintf(sb, "\nTotal space to reclaim: %s\n\n", humanBytes(total))
+	if m.apply != nil {
+		sb.WriteString(m.renderApply())
+	} else {
+		sb.WriteString("[A] Apply prune   [E] " + tern(m.planExited, "Exclude", "Include") + " exited containers   [C] Cancel   [Q] Quit")
+	}
+	return m.pane().Render(sb.String())
+}
@@ -0,0 +1,302 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/domain"
+)
+
+// project groups the resources of one compose project.
+type project struct {
+	name       string
+	containers []domain.Container
+	volumes    []domain.Volume
+	networks   []domain.Network
+}
+
+// networksMsg carries the result of an asynchronous network listing.
+type networksMsg struct {
+	networks []domain.Network
+	err      error
+}
+
+func newProjectTable() table.Model {
+	cols := []table.Column{
+		{Title: "Project", Width: 24},
+		{Title: "Containers", Width: 12},
+		{Title: "Volumes", Width: 8},
+		{Title: "Networks", Width: 8},
+		{Title: "Footprint", Width: 12},
+	}
+	t := table.New(table.WithColumns(cols), table.WithFocused(true), table.WithStyles(tableStyles()))
+	t.KeyMap.LineUp.SetKeys("up")
+	t.KeyMap.LineDown.SetKeys("down")
+	return t
+}
+
+func (m model) loadNetworks() tea.Cmd {
+	if m.provider == nil {
+		return nil
+	}
+	prov, ctx := m.provider, m.ctx
+	return func() tea.Msg {
+		ns, err := prov.ListNetworks(ctx)
+		return networksMsg{networks: ns, err: err}
+	}
+}
+
+func (m model) setNetworks(msg networksMsg) model {
+	if msg.err != nil {
+		m.notice = msg.err.Error()
+		return m
+	}
+	m.networks = msg.networks
+	m.ntable.SetRows(m.networkRows())
+	return m.refreshProjects()
+}
+
+// projects groups volumes, containers and networks by compose project label.
+func (m model) projects() []project {
+	byName := map[string]*project{}
+	get := func(name string) *project {
+		p, ok := byName[name]
+		if !ok {
+			p = &project{name: name}
+			byName[name] = p
+		}
+		return p
+	}
+	for _, c := range m.containers {
+		if name := c.Project(); name != "" {
+			get(name).containers = append(get(name).containers, c)
+		}
+	}
+	for _, v := range m.allVols {
+		if v.Project != "" {
+			get(v.Project).volumes = append(get(v.Project).volumes, v)
+		}
+	}
+	for _, n := range m.networks {
+		if name := n.Project(); name != "" {
+			get(name).networks = append(get(name).networks, n)
+		}
+	}
+
+	out := make([]project, 0, len(byName))
+	for _, p := range byName {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+func (p project) running() int {
+	n := 0
+	for _, c := range p.containers {
+		if c.Running() {
+			n++
+		}
+	}
+	return n
+}
+
+// footprint sums known volume sizes and container writable layers.
+func (p project) footprint() int64 {
+	total := int64(0)
+	for _, v := range p.volumes {
+		total += max(v.SizeBytes, 0)
+	}
+	for _, c := range p.containers {
+		total += max(c.SizeRw, 0)
+	}
+	return total
+}
+
+// orphaned returns what a teardown would remove: exited and never started
+// containers, volumes used by nothing but those containers, and networks
+// nobody is attached to. Paused and restarting containers are kept, since
+// docker rm refuses them. Ignored volumes are kept, and so is the default
+// network while any of the project's containers is.
+func (p project) orphaned(ignored func(string) bool) pruneSet {
+	var ps pruneSet
+	removed := map[string]bool{}
+	for _, c := range p.containers {
+		if c.Exited() || c.State == "created" {
+			ps.containers = append(ps.containers, c)
+			removed[c.Name] = true
+		}
+	}
+	for _, v := range p.volumes {
+		if ignored(v.Name) {
+			continue
+		}
+		free := true
+		for _, name := range v.Attached {
+			if !removed[name] {
+				free = false
+			}
+		}
+		if free {
+			ps.volumes = append(ps.volumes, v)
+		}
+	}
+	kept := len(ps.containers) < len(p.containers)
+	for _, n := range p.networks {
+		if n.Predefined() || (kept && n.ComposeDefault()) {
+			continue
+		}
+		free := true
+		for _, name := range n.Containers {
+			if !removed[name] {
+				free = false
+			}
+		}
+		if free {
+			ps.networks = append(ps.networks, n)
+		}
+	}
+	return ps
+}
+
+func (m model) refreshProjects() model {
+	projects := m.projects()
+	rows := make([]table.Row, 0, len(projects))
+	for _, p := range projects {
+		rows = append(rows, table.Row{
+			p.name,
+			fmt.Sprintf("%d/%d up", p.running(), len(p.containers)),
+			fmt.Sprint(len(p.volumes)),
+			fmt.Sprint(len(p.networks)),
+			humanBytes(p.footprint()),
+		})
+	}
+	m.ptable.SetRows(rows)
+	return m
+}
+
+func (m model) selectedProject() (project, bool) {
+	projects := m.projects()
+	idx := m.ptable.Cursor()
+	if idx < 0 || idx >= len(projects) {
+		return project{}, false
+	}
+	return projects[idx], true
+}
+
+func (m model) updateProjects(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirmTeardown {
+		m.confirmTeardown = false
+		p, ok := m.selectedProject()
+		if msg.String() != "y" || !ok || m.apply != nil || m.provider == nil {
+			m.notice = "teardown cancelled"
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("tearing down orphaned resources of %s…", p.name)
+		return m.startApply(p.orphaned(m.cfg.IsIgnored), 0, true)
+	}
+	if m.confirmDown {
+		m.confirmDown = false
+		p, ok := m.selectedProject()
+		if msg.String() != "y" || !ok {
+			m.notice = "compose down cancelled"
+			return m, nil
+		}
+		return m.composeProject(p, "down")
+	}
+
+	switch msg.String() {
+	case "enter":
+		if p, ok := m.selectedProject(); ok {
+			m = m.push()
+			m.resource, m.projectFilter = resVolumes, p.name
+			m = m.applyVolumeView()
+			m.table.SetCursor(0)
+		}
+		return m, nil
+	case "t":
+		if p, ok := m.selectedProject(); ok {
+			ps := p.orphaned(m.cfg.IsIgnored)
+			if len(ps.containers)+len(ps.volumes)+len(ps.networks) == 0 {
+				m.notice = fmt.Sprintf("%s has no orphaned resources", p.name)
+				return m, nil
+			}
+			m.confirmTeardown = true
+		}
+		return m, nil
+	case "d":
+		return m.askDown(), nil
+	case "u":
+		if p, ok := m.selectedProject(); ok {
+			return m.composeProject(p, "up")
+		}
+		return m, nil
+	case "r":
+		return m, tea.Batch(m.loadVolumes(), m.loadContainers(), m.loadNetworks())
+	}
+
+	var cmd tea.Cmd
+	m.ptable, cmd = m.ptable.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewProjects() string {
+	header := m.renderTabs() + "\n" + m.title("Compose Projects")
+	statusInfo := fmt.Sprintf("Projects: %d", len(m.ptable.Rows()))
+	if m.notice != "" {
+		statusInfo += "  — " + m.notice
+	}
+	header = header + "\n" + dimStyle.Render(statusInfo)
+
+	lower := m.pane().Render("<no project selected>")
+	if p, ok := m.selectedProject(); ok {
+		lower = m.renderProject(p)
+	}
+	return header + "\n" + m.pane().Render(m.ptable.View()) + "\n" + lower
+}
+
+func (m model) renderProject(p project) string {
+	orphans := p.orphaned(m.cfg.IsIgnored)
+	isOrphan := map[string]bool{}
+	for _, c := range orphans.containers {
+		isOrphan["c/"+c.Name] = true
+	}
+	for _, v := range orphans.volumes {
+		isOrphan["v/"+v.Name] = true
+	}
+	for _, n := range orphans.networks {
+		isOrphan["n/"+n.Name] = true
+	}
+	mark := func(key string) string { return tern(isOrphan[key], "  (orphaned)", "") }
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "Project %s — footprint %s\n", p.name, humanBytes(p.footprint()))
+	sb.WriteString(" Containers:\n")
+	for _, c := range p.containers {
+		fmt.Fprintf(sb, "  %s (%s)%s\n", c.Name, c.State, mark("c/"+c.Name))
+	}
+	sb.WriteString(" Volumes:\n")
+	for _, v := range p.volumes {
+		fmt.Fprintf(sb, "  %s (%s)%s\n", v.Name, v.SizeHuman(), mark("v/"+v.Name))
+	}
+	sb.WriteString(" Networks:\n")
+	for _, n := range p.networks {
+		fmt.Fprintf(sb, "  %s%s\n", n.Name, mark("n/"+n.Name))
+	}
+
+	if m.apply != nil {
+		sb.WriteString("\n" + m.renderApply())
+	} else if m.confirmTeardown {
+		fmt.Fprintf(sb, "\nRemove %d container(s), %d volume(s), %d network(s)? [y/N]",
+			len(orphans.containers), len(orphans.volumes), len(orphans.networks))
+	} else if m.confirmDown {
+		fmt.Fprintf(sb, "\nStop and remove the %d container(s) of %s with compose down? Volumes are kept. [y/N]", len(p.containers), p.name)
+	} else {
+		sb.WriteString("\n[D] Compose down  [U] Compose up  [T] Tear down orphaned  [R] Refresh  [1-5] Views  [Q] Quit")
+	}
+	return m.pane().Render(sb.String())
+}
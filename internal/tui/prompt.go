@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prompt is a one-line text input shown in the Volumes header, e.g. the
+// name of a view to save.
+type prompt struct {
+	label string
+	value string
+	enter string                                       // what Enter does, e.g. "saves"
+	done  func(m model, value string) (model, tea.Cmd) // called on Enter
+}
+
+// ask opens a prompt prefilled with value.
+func (m model) ask(label, value string, done func(model, string) model) model {
+	return m.askThen(label, value, "saves", func(m model, value string) (model, tea.Cmd) {
+		return done(m, value), nil
+	})
+}
+
+// askThen opens a prompt whose answer starts work in the background.
+func (m model) askThen(label, value, enter string, done func(model, string) (model, tea.Cmd)) model {
+	m.prompt = &prompt{label: label, value: value, enter: enter, done: done}
+	return m
+}
+
+// updatePrompt edits the open prompt; Enter submits it, Esc cancels.
+func (m model) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := *m.prompt
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.prompt = nil
+		return p.done(m, p.value)
+	case tea.KeyEsc:
+		m.prompt = nil
+		return m, nil
+	case tea.KeyBackspace:
+		if r := []rune(p.value); len(r) > 0 {
+			p.value = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		p.value += string(msg.Runes)
+	}
+	m.prompt = &p
+	return m, nil
+}
+
+func (p *prompt) render() string {
+	return fmt.Sprintf("  %s: %s%s (Enter %s, Esc cancels)", p.label, p.value, glyphs.caret, p.enter)
+}
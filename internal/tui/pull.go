@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/domain"
+)
+
+// pullState tracks the image pull in progress, if any.
+type pullState struct {
+	ref      string
+	progress domain.PullProgress
+	bar      progress.Model
+	events   chan tea.Msg
+	cancel   context.CancelFunc
+}
+
+// pullProgressMsg is one progress update from the running pull.
+type pullProgressMsg struct{ progress domain.PullProgress }
+
+// pullDoneMsg ends a pull.
+type pullDoneMsg struct {
+	ref string
+	err error
+}
+
+// startPull pulls ref in a goroutine and streams its progress back through
+// a channel that waitPull drains one message at a time.
+func (m model) startPull(ref string) (model, tea.Cmd) {
+	if m.pull != nil {
+		m.notice = fmt.Sprintf("already pulling %s", m.pull.ref)
+		return m, nil
+	}
+	if m.provider == nil {
+		return m, nil
+	}
+
+	events := make(chan tea.Msg, 64)
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.pull = &pullState{ref: ref, bar: progress.New(progress.WithDefaultGradient(), progress.WithWidth(60)), events: events, cancel: cancel}
+	prov := m.provider
+	go func() {
+		err := prov.PullImage(ctx, ref, func(p domain.PullProgress) {
+			// Drop intermediate updates rather than stall the pull on a slow UI
+			select {
+			case events <- pullProgressMsg{progress: p}:
+			default:
+			}
+		})
+		events <- pullDoneMsg{ref: ref, err: err}
+	}()
+	return m, waitPull(events)
+}
+
+func waitPull(events chan tea.Msg) tea.Cmd {
+	return func() tea.Msg { return <-events }
+}
+
+func (m model) onPullProgress(msg pullProgressMsg) (tea.Model, tea.Cmd) {
+	if m.pull == nil {
+		return m, nil
+	}
+	ps := *m.pull
+	ps.progress = msg.progress
+	m.pull = &ps
+	return m, waitPull(ps.events)
+}
+
+func (m model) onPullDone(msg pullDoneMsg) (tea.Model, tea.Cmd) {
+	if m.pull != nil {
+		m.pull.cancel()
+	}
+	m.pull = nil
+	if errors.Is(msg.err, context.Canceled) {
+		m.notice = fmt.Sprintf("pull of %s cancelled; downloaded layers stay cached", msg.ref)
+		return m, m.loadImages()
+	}
+	if msg.err != nil {
+		m.notice = msg.err.Error()
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("pulled %s", msg.ref)
+	return m, m.loadImages()
+}
+
+func (m model) renderPull() string {
+	ps := m.pull
+	p := ps.progress
+	detail := fmt.Sprintf("%d/%d layers", p.LayersDone, p.Layers)
+	if p.Total > 0 {
+		detail = fmt.Sprintf("%s / %s, %s", humanBytes(p.Current), humanBytes(p.Total), detail)
+	}
+	body := fmt.Sprintf("Pulling %s\n%s\n%s\n%s", ps.ref, ps.bar.ViewAs(p.Fraction()), detail, dimStyle.Render(p.Status))
+	return m.pane().Render(body)
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reclaimable is what a cleanup of the obvious leftovers would free.
+type reclaimable struct {
+	bytes      int64
+	volumes    int // orphan volumes, ignored ones excluded
+	images     int // dangling images no container uses
+	containers int // exited containers
+}
+
+// reclaimable totals the latest listings. Volumes still waiting for their
+// details are left out until their orphan status is known, and images count
+// only the bytes they don't share with other images.
+func (m model) reclaimable() reclaimable {
+	var r reclaimable
+	pending := m.enrich.pendingSet()
+	for _, v := range m.allVols {
+		if v.Orphan() && !pending[v.Name] && !m.cfg.IsIgnored(v.Name) {
+			r.volumes++
+			r.bytes += max(v.SizeBytes, 0)
+		}
+	}
+	for _, img := range m.images {
+		if img.Dangling() && img.Containers == 0 {
+			r.images++
+			r.bytes += max(tern(img.UniqueSize >= 0, img.UniqueSize, img.SizeBytes), 0)
+		}
+	}
+	for _, c := range m.containers {
+		if c.Exited() {
+			r.containers++
+			r.bytes += max(c.SizeRw, 0)
+		}
+	}
+	return r
+}
+
+func (r reclaimable) String() string {
+	return fmt.Sprintf("Reclaimable: %s (%d orphan volumes, %d dangling images, %d exited containers)",
+		humanBytes(r.bytes), r.volumes, r.images, r.containers)
+}
+
+// loadUsage refreshes every listing the reclaimable estimate is based on,
+// and the policy that protects some of it.
+func (m model) loadUsage() tea.Cmd {
+	return tea.Batch(m.loadVolumes(), m.loadContainers(), m.loadImages(), m.loadPolicy())
+}
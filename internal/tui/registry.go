@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/registry"
+)
+
+// registryWorkers bounds concurrent registry requests.
+const registryWorkers = 4
+
+// upstream is what the registry said about one image tag.
+type upstream struct {
+	digest string
+	err    error // registry.ErrNotFound for local-only tags
+}
+
+// registryMsg carries registry lookups for a batch of tags. A cancelled
+// check carries only the lookups that finished.
+type registryMsg struct {
+	results   map[string]upstream
+	total     int
+	cancelled bool
+}
+
+// checkRegistry looks up every tagged image in its registry in the background.
+func (m model) checkRegistry() (model, tea.Cmd) {
+	var refs []string
+	for _, img := range m.images {
+		if !img.Dangling() {
+			refs = append(refs, img.Ref())
+		}
+	}
+	if len(refs) == 0 {
+		return m, nil
+	}
+	if m.registryCancel != nil {
+		m.registryCancel()
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.registryCancel = cancel
+	return m, func() tea.Msg {
+		results := lookupAll(ctx, refs)
+		return registryMsg{results: results, total: len(refs), cancelled: ctx.Err() != nil}
+	}
+}
+
+func lookupAll(ctx context.Context, refs []string) map[string]upstream {
+	client := registry.NewClient()
+	results := make(map[string]upstream, len(refs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+	for i := 0; i < registryWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ref := range jobs {
+				d, err := client.Digest(ctx, ref)
+				if ctx.Err() != nil {
+					// Unchecked, not failed
+					continue
+				}
+				mu.Lock()
+				results[ref] = upstream{digest: d, err: err}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for _, ref := range refs {
+		select {
+		case jobs <- ref:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func (m model) setRegistry(msg registryMsg) model {
+	if m.upstream == nil {
+		m.upstream = map[string]upstream{}
+	}
+	for ref, u := range msg.results {
+		m.upstream[ref] = u
+	}
+	if m.registryCancel != nil {
+		m.registryCancel()
+		m.registryCancel = nil
+	}
+	m.notice = "registry check finished"
+	if n := m.staleImages(); n > 0 {
+		m.notice += fmt.Sprintf("; %d tag(s) behind their registry", n)
+	}
+	if msg.cancelled {
+		m.notice = fmt.Sprintf("registry check cancelled: %d of %d tags checked", len(msg.results), msg.total)
+	}
+	m.itable.SetRows(m.imageRows())
+	return m
+}
+
+// upstreamStatus renders the Upstream column for an image.
+func (m model) upstreamStatus(img domain.Image) string {
+	if img.Dangling() {
+		return "local"
+	}
+	u, ok := m.upstream[img.Ref()]
+	switch {
+	case !ok:
+		return ""
+	case m.stale(img):
+		return "stale"
+	case u.err == nil:
+		return "pullable"
+	case errors.Is(u.err, registry.ErrNotFound):
+		return "local"
+	default:
+		return "error"
+	}
+}
+
+// stale reports whether the registry has moved img's tag on: the digest it
+// now serves differs from the one the image was pulled as. Images built
+// locally have no digest to compare and are never stale.
+func (m model) stale(img domain.Image) bool {
+	u, ok := m.upstream[img.Ref()]
+	local := img.Digest()
+	return ok && u.err == nil && u.digest != "" && local != "" && u.digest != local
+}
+
+func (m model) staleImages() int {
+	n := 0
+	for _, img := range m.images {
+		if m.stale(img) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/provider"
+)
+
+// retryMsg relays a retry of a provider call from whichever command made it.
+type retryMsg provider.RetryStatus
+
+// retryMiddleware retries transient daemon errors and reports each retry
+// on m.retries, dropping reports while the UI is behind.
+func (m model) retryMiddleware() provider.Middleware {
+	ch := m.retries
+	return provider.Retry(provider.DefaultRetry, func(st provider.RetryStatus) {
+		select {
+		case ch <- retryMsg(st):
+		default:
+		}
+	})
+}
+
+// waitRetry delivers the next retry report; it is re-armed after each.
+func waitRetry(ch chan retryMsg) tea.Cmd {
+	if ch == nil {
+		return nil
+	}
+	return func() tea.Msg { return <-ch }
+}
+
+func (m model) onRetry(msg retryMsg) (tea.Model, tea.Cmd) {
+	if msg.Done {
+		m.retrying = nil
+	} else {
+		st := provider.RetryStatus(msg)
+		m.retrying = &st
+	}
+	return m, waitRetry(m.retries)
+}
+
+// renderRetrying is the title's note while a call is being retried.
+func (m model) renderRetrying() string {
+	if m.retrying == nil {
+		return ""
+	}
+	r := m.retrying
+	return " " + warnStyle.Render(fmt.Sprintf("[retrying %s %d/%d…]", r.Op, r.Attempt, r.Of))
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/doctor"
+	"dockwatch/internal/domain"
+	"dockwatch/internal/jobs"
+)
+
+// dfRows are the `docker system df` rows, in its own order.
+var dfRows = []string{"Images", "Containers", "Local Volumes", "Build Cache"}
+
+// rootState is the data root breakdown shown in paneRoot.
+type rootState struct {
+	usage      domain.RootUsage
+	err        error
+	plugins    []domain.Plugin
+	pluginsErr error
+	loading    bool
+}
+
+// rootUsageMsg carries a finished data root scan.
+type rootUsageMsg struct {
+	usage      domain.RootUsage
+	err        error
+	plugins    []domain.Plugin
+	pluginsErr error
+}
+
+// scanRoot opens the data root pane and measures it. Walking overlay2 on a
+// big host takes a while, hence a pane of its own rather than the header.
+func (m model) scanRoot() (model, tea.Cmd) {
+	m.active = paneRoot
+	if os.Getenv("DOCKWATCH_REMOTE") != "" {
+		m.root = &rootState{err: doctor.ErrRemoteRoot}
+		return m, nil
+	}
+	m.root = &rootState{loading: true}
+	return m, m.enqueue("scan", "Measure Docker data root", rootUsageMsg{err: context.Canceled},
+		func(ctx context.Context, _ jobs.Progress) (tea.Msg, error) {
+			du, err := doctor.DataRoot(ctx)
+			if du.Root == "" {
+				return rootUsageMsg{err: err}, err
+			}
+			usage, err := dockercli.RootBreakdown(ctx, du.Root)
+			plugins, pluginsErr := dockercli.Plugins(ctx, du.Root)
+			return rootUsageMsg{usage: usage, err: err, plugins: plugins, pluginsErr: pluginsErr}, err
+		})
+}
+
+func (m model) setRoot(msg rootUsageMsg) model {
+	m.root = &rootState{usage: msg.usage, err: msg.err, plugins: msg.plugins, pluginsErr: msg.pluginsErr}
+	return m
+}
+
+func (m model) renderRoot() string {
+	sb := &strings.Builder{}
+	st := m.root
+	switch {
+	case st == nil:
+		sb.WriteString("Data Root:\n  <press U to measure Docker's data root>\n")
+		return m.pane().Render(sb.String())
+	case st.loading:
+		sb.WriteString("Data Root:\n  measuring…\n")
+		return m.pane().Render(sb.String())
+	case errors.Is(st.err, domain.ErrPermission):
+		fmt.Fprintf(sb, "Data Root:\n  %v\n  Run dockwatch as root (e.g. with sudo) to see the breakdown.\n", st.err)
+		m.writePlugins(sb)
+		return m.pane().Render(sb.String() + "\n[U] Rescan")
+	case st.err != nil:
+		fmt.Fprintf(sb, "Data Root:\n  %v\n", st.err)
+		return m.pane().Render(sb.String() + "\n[U] Rescan")
+	}
+
+	fmt.Fprintf(sb, "Data Root: %s (measured on disk)\n", st.usage.Root)
+	var onDisk int64
+	skipped := 0
+	for _, d := range st.usage.Dirs {
+		onDisk += max(d.SizeBytes, 0)
+		skipped += d.Skipped
+		size := domain.HumanSize(d.SizeBytes)
+		if d.Skipped > 0 {
+			size = "≥" + size
+		}
+		fmt.Fprintf(sb, "  %10s  %-15s %s\n", size, d.Name, d.Subsystem)
+	}
+	fmt.Fprintf(sb, "  %10s  total\n", domain.HumanSize(onDisk))
+	if skipped > 0 {
+		fmt.Fprintf(sb, "  (%d unreadable entries skipped)\n", skipped)
+	}
+
+	if st.usage.Reported != nil {
+		var reported int64
+		var parts []string
+		for _, row := range dfRows {
+			if size, ok := st.usage.Reported[row]; ok {
+				reported += size
+				parts = append(parts, fmt.Sprintf("%s %s", row, humanBytes(size)))
+			}
+		}
+		fmt.Fprintf(sb, "\ndocker system df: %s — %s\n", strings.Join(parts, ", "), humanBytes(reported))
+		switch gap := onDisk - reported; {
+		case gap > 0:
+			fmt.Fprintf(sb, "Unaccounted: %s on disk that system df doesn't count\n(container logs, layers left by failed pulls or builds, containerd leftovers)\n", humanBytes(gap))
+		case gap < 0:
+			fmt.Fprintf(sb, "system df reports %s more than is on disk (layers shared between images count once per image)\n", humanBytes(-gap))
+		}
+	}
+	m.writePlugins(sb)
+	return m.pane().Render(sb.String() + "\n[U] Rescan")
+}
+
+// writePlugins lists the installed plugins with their size and the volumes
+// each provides. Neither shows up in system df, and prunes leave both be.
+func (m model) writePlugins(sb *strings.Builder) {
+	st := m.root
+	sb.WriteString("\nPlugins:\n")
+	switch {
+	case st.pluginsErr != nil:
+		fmt.Fprintf(sb, "  %v\n", st.pluginsErr)
+	case len(st.plugins) == 0:
+		sb.WriteString("  <none installed>\n")
+	}
+	driven := map[string]bool{}
+	for _, p := range st.plugins {
+		var vols []string
+		for _, v := range m.allVols {
+			if p.Drives(v) {
+				vols = append(vols, v.Name)
+				driven[v.Name] = true
+			}
+		}
+		line := fmt.Sprintf("  %10s  %s, %s", domain.HumanSize(p.SizeBytes), p.Name, tern(p.Enabled, "enabled", "disabled"))
+		switch {
+		case len(vols) > 0:
+			line += fmt.Sprintf(", drives %d volume(s): %s", len(vols), strings.Join(vols, ", "))
+		case p.VolumeDriver():
+			line += ", volume driver with no volumes"
+		}
+		sb.WriteString(line + "\n")
+	}
+	// Legacy plugins run outside the daemon and aren't listed
+	var others []string
+	for _, v := range m.allVols {
+		if v.PluginDriver() && !driven[v.Name] {
+			others = append(others, fmt.Sprintf("%s (%s)", v.Name, v.Driver))
+		}
+	}
+	if len(others) > 0 {
+		fmt.Fprintf(sb, "  Volumes of drivers not installed as plugins: %s\n", strings.Join(others, ", "))
+	}
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/session"
+)
+
+// resumeMsg loads the tab a resumed session opened on.
+type resumeMsg struct{}
+
+// sessionState is what the next launch against this context resumes.
+func (m model) sessionState() session.State {
+	s := session.State{
+		Tab:           resourceTabs[m.tabIndex()].name,
+		Sort:          m.volSort.configName(),
+		OlderThanDays: int(ageFilters[m.ageFilter] / day),
+		Tag:           m.tagFilter,
+		Project:       m.projectFilter,
+		Cursor:        m.pendingVolume,
+		Marked:        slices.Sorted(maps.Keys(m.marked)),
+		MarkedImages:  slices.Sorted(maps.Keys(m.imarked)),
+	}
+	if m.view != nil {
+		s.View = m.view.Name
+	}
+	if idx := m.table.Cursor(); idx >= 0 && idx < len(m.vols) {
+		s.Cursor = m.vols[idx].Name
+	}
+	return s
+}
+
+// saveSession remembers the session for the next launch. Quitting from
+// the health screen keeps the previous one: nothing was listed to save.
+func (m model) saveSession() error {
+	if m.health != nil {
+		return nil
+	}
+	return session.Save(engineHost(), m.sessionState())
+}
+
+// resumeSession restores the session saved for this context, if any. The
+// cursor and marks apply once the volumes are listed; marks on volumes
+// gone since are dropped then, with a notice.
+func (m model) resumeSession() model {
+	s, ok, err := session.Load(engineHost())
+	if err != nil {
+		m.notice = err.Error()
+		return m
+	}
+	if !ok {
+		return m
+	}
+	if i := slices.IndexFunc(m.cfg.Views, func(v config.View) bool { return v.Name == s.View }); s.View != "" && i >= 0 {
+		m = m.selectView(i + 1)
+	}
+	m.volSort = parseSort(s.Sort)
+	m.ageFilter = max(slices.Index(ageFilters, time.Duration(s.OlderThanDays)*day), 0)
+	m.tagFilter, m.projectFilter = s.Tag, s.Project
+	m.pendingVolume = s.Cursor
+	for _, name := range s.Marked {
+		m.marked[name] = true
+	}
+	for _, ref := range s.MarkedImages {
+		m.imarked[ref] = true
+	}
+	if i := slices.IndexFunc(resourceTabs, func(t resourceTab) bool { return t.name == s.Tab }); i >= 0 {
+		m.resource = resourceTabs[i].res
+	}
+	m.notice = fmt.Sprintf("resumed the session of %s", s.Saved.Local().Format(time.DateTime))
+	if n := len(s.Marked) + len(s.MarkedImages); n > 0 {
+		m.notice += fmt.Sprintf(", %d mark(s)", n)
+	}
+	return m
+}
+
+// resumeTab loads the tab a resumed session opened on, unless that is the
+// Volumes tab, which always loads.
+func (m model) resumeTab() tea.Cmd {
+	if m.resource == resVolumes {
+		return nil
+	}
+	return func() tea.Msg { return resumeMsg{} }
+}
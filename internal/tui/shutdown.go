@@ -0,0 +1,48 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/audit"
+)
+
+// auditRecord is the audit log entry for a finished apply.
+func (m model) auditRecord(msg applyDoneMsg) audit.Record {
+	r := audit.Record{
+		Action:    tern(msg.teardown, "teardown", "prune"),
+		Removed:   msg.gone,
+		Untouched: msg.untouched,
+		Freed:     msg.freed,
+		Cancelled: msg.cancelled,
+		DryRun:    m.dryRun,
+		Quit:      m.quitPending,
+	}
+	for _, err := range msg.errs {
+		r.Failed = append(r.Failed, err.Error())
+	}
+	return r
+}
+
+// updateQuit answers the question quitting mid-apply asks: wait for the
+// removals, or cancel the ones not started. Either way dockwatch quits once
+// the apply has reported and its audit record is written.
+func (m model) updateQuit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "w":
+		m.quitAsk, m.quitPending = false, true
+		m.notice = "quitting once the apply finishes…"
+	case "c":
+		m.quitAsk, m.quitPending = false, true
+		m.apply.cancel()
+		m.notice = "cancelling the remaining removals, then quitting…"
+	case "esc", "n":
+		m.quitAsk, m.quitPending = false, false
+		m.notice = ""
+	}
+	return m, nil
+}
+
+func (m model) renderQuit() string {
+	return m.pane().Render("Quit while removals are running?\n\n" + m.applyProgress() +
+		"\n\n[W] Wait for them, then quit  [C] Cancel the rest, then quit  [Esc] Keep going")
+}
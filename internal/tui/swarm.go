@@ -0,0 +1,190 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/dockercli"
+	"dockwatch/internal/domain"
+)
+
+// swarmPaneRows caps the secrets and configs pane; unreferenced ones come
+// first.
+const swarmPaneRows = 10
+
+// swarmState is the secrets and configs pane of the Containers view.
+type swarmState struct {
+	objects      []domain.SwarmObject
+	loading      bool
+	err          error
+	confirmPrune bool
+}
+
+// swarmObjectsMsg carries the listed secrets and configs.
+type swarmObjectsMsg struct {
+	objects []domain.SwarmObject
+	err     error
+}
+
+// swarmPrunedMsg reports how a prune of unreferenced secrets and configs
+// went.
+type swarmPrunedMsg struct {
+	removed int
+	err     error
+}
+
+// toggleSwarm opens the secrets and configs pane and lists them, or closes
+// it. They are read with the docker CLI, which an agent doesn't offer.
+func (m model) toggleSwarm() (model, tea.Cmd) {
+	if m.swarm != nil {
+		m.swarm = nil
+		return m, nil
+	}
+	if os.Getenv("DOCKWATCH_REMOTE") != "" {
+		m.notice = "swarm secrets and configs are not available through an agent"
+		return m, nil
+	}
+	m = m.closeBinds()
+	m.mem = nil
+	return m.loadSwarm()
+}
+
+func (m model) loadSwarm() (model, tea.Cmd) {
+	m.swarm = &swarmState{loading: true}
+	ctx := m.ctx
+	return m, func() tea.Msg {
+		objs, err := dockercli.SwarmObjects(ctx)
+		return swarmObjectsMsg{objects: objs, err: err}
+	}
+}
+
+func (m model) setSwarm(msg swarmObjectsMsg) model {
+	if m.swarm == nil {
+		return m // closed while listing
+	}
+	m.swarm = &swarmState{objects: msg.objects, err: msg.err}
+	return m
+}
+
+// unreferencedSwarm returns the secrets and configs no service uses.
+func (m model) unreferencedSwarm() []domain.SwarmObject {
+	var out []domain.SwarmObject
+	for _, o := range m.swarm.objects {
+		if o.Unreferenced() {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// updateSwarm handles the keys of the open pane, reporting whether it took
+// the key.
+func (m model) updateSwarm(msg tea.KeyMsg) (bool, tea.Model, tea.Cmd) {
+	st := m.swarm
+	if st.confirmPrune {
+		m.swarm = &swarmState{objects: st.objects}
+		if msg.String() != "y" {
+			m.notice = "prune cancelled"
+			return true, m, nil
+		}
+		objs := m.unreferencedSwarm()
+		if m.dryRun {
+			log := stateLogger("dry-run.log")
+			for _, o := range objs {
+				log("would remove %s %s", o.Kind, o.Name)
+			}
+			m.notice = fmt.Sprintf("would remove %d secret(s) and config(s)", len(objs))
+			return true, m, nil
+		}
+		m.notice = fmt.Sprintf("removing %d secret(s) and config(s)…", len(objs))
+		ctx := m.ctx
+		return true, m, func() tea.Msg {
+			var done swarmPrunedMsg
+			var errs []error
+			for _, o := range objs {
+				if err := dockercli.RemoveSwarmObject(ctx, o); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				done.removed++
+			}
+			done.err = errors.Join(errs...)
+			return done
+		}
+	}
+	switch msg.String() {
+	case "p":
+		if st.loading || st.err != nil {
+			return true, m, nil
+		}
+		if len(m.unreferencedSwarm()) == 0 {
+			m.notice = "every secret and config is referenced by a service"
+			return true, m, nil
+		}
+		m.swarm = &swarmState{objects: st.objects, confirmPrune: true}
+		return true, m, nil
+	case "r":
+		m, cmd := m.loadSwarm()
+		return true, m, tea.Batch(m.loadContainers(), cmd)
+	}
+	return false, m, nil
+}
+
+func (m model) onSwarmPruned(msg swarmPrunedMsg) (tea.Model, tea.Cmd) {
+	m.notice = fmt.Sprintf("%s %d secret(s) and config(s)", m.removedVerb(), msg.removed)
+	if msg.err != nil {
+		m.notice += fmt.Sprintf("; failed: %v", msg.err)
+	}
+	if m.swarm == nil {
+		return m, nil
+	}
+	return m.loadSwarm()
+}
+
+func (m model) renderSwarm() string {
+	st := m.swarm
+	sb := &strings.Builder{}
+	sb.WriteString("Secrets and Configs — swarm objects and the services using them\n\n")
+	switch {
+	case st.loading:
+		sb.WriteString("  listing…\n")
+	case errors.Is(st.err, dockercli.ErrNotSwarmManager):
+		sb.WriteString("  <not in swarm mode, or this node is not a manager>\n")
+	case st.err != nil:
+		fmt.Fprintf(sb, "  %s\n", failStyle.Render(st.err.Error()))
+	case len(st.objects) == 0:
+		sb.WriteString("  <the swarm has no secrets or configs>\n")
+	}
+	// Unreferenced first: they are what a prune removes
+	unused := m.unreferencedSwarm()
+	rows := unused
+	for _, o := range st.objects {
+		if !o.Unreferenced() {
+			rows = append(rows, o)
+		}
+	}
+	for i, o := range rows {
+		if i >= swarmPaneRows {
+			fmt.Fprintf(sb, "  … %d more\n", len(rows)-swarmPaneRows)
+			break
+		}
+		users := warnStyle.Render("unreferenced")
+		if !o.Unreferenced() {
+			users = strings.Join(o.Services, ", ")
+		}
+		fmt.Fprintf(sb, "  %-6s %-24s %s  %s\n", o.Kind, o.Name, o.CreatedAt.Local().Format("2006-01-02 15:04"), users)
+	}
+	if len(unused) > 0 {
+		fmt.Fprintf(sb, "\n  %d of %d unreferenced by any service\n", len(unused), len(st.objects))
+	}
+	if st.confirmPrune {
+		fmt.Fprintf(sb, "\nRemove %d unreferenced secret(s) and config(s)? [y/N]", len(unused))
+	} else {
+		sb.WriteString("\n[S] Close  [P] Prune unreferenced  [R] Refresh")
+	}
+	return m.pane().Render(sb.String())
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// resourceTab is a view in the tab bar.
+type resourceTab struct {
+	res  resource
+	name string
+}
+
+// resourceTabs are the views in the tab bar, in order; the digit keys
+// pick them by position.
+var resourceTabs = []resourceTab{
+	{resVolumes, "Volumes"},
+	{resContainers, "Containers"},
+	{resImages, "Images"},
+	{resProjects, "Projects"},
+	{resNetworks, "Networks"},
+}
+
+// tabKey returns which tab a digit key selects, 0-based.
+func tabKey(key string) (int, bool) {
+	if len(key) != 1 || key[0] < '1' || int(key[0]-'1') >= len(resourceTabs) {
+		return 0, false
+	}
+	return int(key[0] - '1'), true
+}
+
+// tabIndex is the position of the current view in the tab bar.
+func (m model) tabIndex() int {
+	for i, t := range resourceTabs {
+		if t.res == m.resource {
+			return i
+		}
+	}
+	return 0
+}
+
+// cycleTab moves delta tabs along the bar, wrapping at either end.
+func (m model) cycleTab(delta int) (model, tea.Cmd) {
+	n := len(resourceTabs)
+	return m.openTab(resourceTabs[((m.tabIndex()+delta)%n+n)%n].res)
+}
+
+// openTab switches views from the tab bar. That starts a new trail, so it
+// drops the back-stack and any drill-down narrowing.
+func (m model) openTab(r resource) (model, tea.Cmd) {
+	m.back = nil
+	if m.projectFilter != "" {
+		m.projectFilter = ""
+		m = m.applyVolumeView()
+	}
+	return m.switchResource(r)
+}
+
+// switchResource shows the view of r and refreshes what it lists.
+func (m model) switchResource(r resource) (model, tea.Cmd) {
+	m.resource = r
+	switch r {
+	case resContainers:
+		var statsCmd tea.Cmd
+		m, statsCmd = m.startStats()
+		return m, tea.Batch(m.loadContainers(), statsCmd)
+	case resImages:
+		return m, m.loadImages()
+	case resProjects, resNetworks:
+		return m, tea.Batch(m.loadContainers(), m.loadNetworks())
+	}
+	return m, nil
+}
+
+// renderTabs is the tab bar at the top of every view, the current one
+// highlighted.
+func (m model) renderTabs() string {
+	tabs := make([]string, len(resourceTabs))
+	for i, t := range resourceTabs {
+		label := fmt.Sprintf("%d %s", i+1, t.name)
+		if t.res == m.resource {
+			tabs[i] = selectedTab(label)
+		} else {
+			tabs[i] = dimStyle.Render(" " + label + " ")
+		}
+	}
+	hint := "[/] Switch"
+	if crumbs := m.breadcrumb(); crumbs != "" {
+		hint = crumbs
+	}
+	return strings.Join(tabs, dimStyle.Render(glyphs.tabSep)) + dimStyle.Render("   "+hint)
+}
+
+// tabAt returns the tab drawn at column x of the tab bar.
+func tabAt(x int) (resource, bool) {
+	left := 0
+	for i, t := range resourceTabs {
+		w := runewidth.StringWidth(fmt.Sprintf(" %d %s ", i+1, t.name))
+		if x >= left && x < left+w {
+			return t.res, true
+		}
+		left += w + lipgloss.Width(glyphs.tabSep)
+	}
+	return 0, false
+}
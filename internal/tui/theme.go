@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme is the set of colors the TUI draws with.
+type theme struct {
+	header, selectedFg, selectedBg, dim lipgloss.Color
+	ok, warn, fail, huge                lipgloss.Color
+	// Volume rows, by state
+	running, stopped, orphaned, protected lipgloss.Color
+}
+
+// themes are the config.Themes, by name.
+var themes = map[string]theme{
+	"dark": {
+		header: "212", selectedFg: "229", selectedBg: "57", dim: "240",
+		ok: "42", warn: "214", fail: "196", huge: "203",
+		running: "42", stopped: "37", orphaned: "220", protected: "39",
+	},
+	// Darker foregrounds that stay readable on a white background
+	"light": {
+		header: "162", selectedFg: "231", selectedBg: "57", dim: "243",
+		ok: "28", warn: "166", fail: "160", huge: "160",
+		running: "28", stopped: "30", orphaned: "136", protected: "25",
+	},
+}
+
+// activeTheme is the theme in use; the styles below are drawn from it.
+var activeTheme theme
+
+var (
+	headerStyle   lipgloss.Style
+	selectedStyle lipgloss.Style
+	dimStyle      lipgloss.Style
+	okStyle       lipgloss.Style
+	warnStyle     lipgloss.Style
+	failStyle     lipgloss.Style
+	// hugeStyle marks the size of volumes past colors.hugeVolume.
+	hugeStyle lipgloss.Style
+)
+
+func init() {
+	applyTheme("")
+}
+
+// applyTheme switches the shared styles to the named theme, or to dark when
+// the name is empty or unknown.
+func applyTheme(name string) {
+	t, ok := themes[name]
+	if !ok {
+		t = themes["dark"]
+	}
+	activeTheme = t
+	headerStyle = lipgloss.NewStyle().Bold(true).Foreground(t.header)
+	selectedStyle = lipgloss.NewStyle().Foreground(t.selectedFg).Background(t.selectedBg)
+	dimStyle = lipgloss.NewStyle().Foreground(t.dim)
+	okStyle = lipgloss.NewStyle().Foreground(t.ok)
+	warnStyle = lipgloss.NewStyle().Foreground(t.warn)
+	failStyle = lipgloss.NewStyle().Foreground(t.fail)
+	hugeStyle = lipgloss.NewStyle().Bold(true).Foreground(t.huge)
+}
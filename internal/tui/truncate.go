@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/domain"
+)
+
+// truncateDoneMsg reports how many bytes emptying a container's log freed.
+type truncateDoneMsg struct {
+	name  string
+	freed int64
+	err   error
+}
+
+// askTruncate asks for confirmation to empty the log of c. Only json-file
+// logs live in a file that can be truncated.
+func (m model) askTruncate(c domain.Container) model {
+	if m.provider == nil {
+		return m
+	}
+	if c.LogDriver != "" && c.LogDriver != "json-file" {
+		m.notice = fmt.Sprintf("%s logs to %s; only json-file logs can be truncated", c.Name, c.LogDriver)
+		return m
+	}
+	m.confirmTruncate = &c
+	return m
+}
+
+// updateTruncate handles the y/N answer to the truncate prompt.
+func (m model) updateTruncate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := *m.confirmTruncate
+	m.confirmTruncate = nil
+	if msg.String() != "y" {
+		m.notice = "truncate cancelled"
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("truncating logs of %s…", c.Name)
+	prov, ctx := m.provider, m.ctx
+	return m, func() tea.Msg {
+		freed, err := prov.TruncateLogs(ctx, c.ID)
+		return truncateDoneMsg{name: c.Name, freed: freed, err: err}
+	}
+}
+
+func (m model) onTruncateDone(msg truncateDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.notice = fmt.Sprintf("truncate %s: %v", msg.name, msg.err)
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("%s logs of %s, %s %s", tern(m.dryRun, "would truncate", "truncated"),
+		msg.name, humanBytes(msg.freed), tern(m.dryRun, "reclaimable", "freed"))
+	return m, m.loadContainers()
+}
+
+// truncatePrompt is the confirmation line shown while a truncate is pending.
+func (m model) truncatePrompt() string {
+	c := m.confirmTruncate
+	size := "unknown size"
+	if c.LogSize >= 0 {
+		size = humanBytes(c.LogSize)
+	}
+	return fmt.Sprintf("Truncate the logs of %s (%s)? The container keeps running. [y/N]", c.Name, size)
+}
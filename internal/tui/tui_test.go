@@ -0,0 +1,1101 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+
+	"dockwatch/internal/audit"
+	"dockwatch/internal/backup"
+	"dockwatch/internal/config"
+	"dockwatch/internal/domain"
+	"dockwatch/internal/provider/fake"
+)
+
+// daemon returns a fake with two orphans and a volume a running container
+// mounts, listed in that order.
+func daemon() *fake.Provider {
+	p := fake.New(
+		domain.Volume{Name: "ci-cache", Driver: "local", SizeBytes: 200 << 20},
+		domain.Volume{Name: "scratch", Driver: "local", SizeBytes: 10 << 20},
+		domain.Volume{Name: "pgdata", Driver: "local", SizeBytes: 3 << 30},
+	)
+	p.Containers = []domain.Container{{ID: "c1", Name: "db", Image: "postgres", State: "running",
+		Mounts: []domain.Mount{{Type: "volume", Name: "pgdata", Destination: "/var/lib/postgresql/data"}}}}
+	return p
+}
+
+// start runs the TUI against p with an empty config and state directory.
+func start(t *testing.T, p *fake.Provider) *teatest.TestModel {
+	t.Helper()
+	return startWith(t, p, nil)
+}
+
+// startWith is start with the config adjusted by setup first.
+func startWith(t *testing.T, p *fake.Provider, setup func(*config.Config)) *teatest.TestModel {
+	t.Helper()
+	t.Setenv("DOCKWATCH_CONFIG_DIR", t.TempDir())
+	t.Setenv("DOCKWATCH_STATE_DIR", t.TempDir())
+	t.Setenv("DOCKWATCH_DRY_RUN", "")
+	t.Setenv("DOCKWATCH_TRACE", "")
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if setup != nil {
+		setup(cfg)
+	}
+	tm := teatest.NewTestModel(t, newModel(cfg).connected(p), teatest.WithInitialTermSize(140, 40))
+	// lipgloss styles are shared, so a program must be gone before the next starts
+	t.Cleanup(func() {
+		tm.Quit()
+		tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+	})
+	return tm
+}
+
+// waitFor blocks until the screen shows want. Output is consumed as it is
+// read, so each call only sees what was drawn since the previous one.
+func waitFor(t *testing.T, tm *teatest.TestModel, want string) {
+	t.Helper()
+	teatest.WaitFor(t, tm.Output(), func(b []byte) bool {
+		return bytes.Contains(b, []byte(want))
+	}, teatest.WithDuration(5*time.Second), teatest.WithCheckInterval(10*time.Millisecond))
+}
+
+func press(tm *teatest.TestModel, keys ...string) {
+	for _, k := range keys {
+		switch k {
+		case "down":
+			tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+		case "up":
+			tm.Send(tea.KeyMsg{Type: tea.KeyUp})
+		case "esc":
+			tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+		case "enter":
+			tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+		default:
+			tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		}
+	}
+}
+
+func finalModel(t *testing.T, tm *teatest.TestModel) model {
+	t.Helper()
+	if err := tm.Quit(); err != nil {
+		t.Fatal(err)
+	}
+	return tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+}
+
+func removed(p *fake.Provider, op string) []string {
+	var args []string
+	for _, c := range p.Calls(op) {
+		args = append(args, c.Arg)
+	}
+	return args
+}
+
+func TestMarkPlanApply(t *testing.T) {
+	p := daemon()
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, " ", "down", " ")
+	press(tm, "p")
+	waitFor(t, tm, "Prune Plan")
+	press(tm, "a")
+	waitFor(t, tm, "removed 2 object(s)")
+
+	if got := removed(p, "RemoveVolume"); !slices.Equal(got, []string{"ci-cache", "scratch"}) {
+		t.Errorf("removed %v, want ci-cache and scratch", got)
+	}
+	m := finalModel(t, tm)
+	if len(m.marked) != 0 {
+		t.Errorf("marks left after apply: %v", m.marked)
+	}
+	if len(m.allVols) != 1 || m.allVols[0].Name != "pgdata" {
+		t.Errorf("listed %v after apply, want only pgdata", m.allVols)
+	}
+}
+
+func TestPlanExitedTakesTheirDanglingImages(t *testing.T) {
+	p := daemon()
+	p.Containers = append(p.Containers,
+		domain.Container{ID: "c2", Name: "old-build", Image: "5f1e2d3c4b5a", ImageID: "5f1e2d3c4b5a6978", State: "exited"})
+	p.Images = []domain.Image{
+		{ID: "5f1e2d3c4b5a", Repository: "<none>", Tag: "<none>", SizeBytes: 80 << 20},
+		{ID: "0a9b8c7d6e5f", Repository: "<none>", Tag: "<none>", SizeBytes: 5 << 20},
+	}
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "p", "e")
+	waitFor(t, tm, "Dangling images they leave behind")
+	press(tm, "a")
+	waitFor(t, tm, "removed 2 object(s)")
+
+	if got := removed(p, "RemoveContainer"); !slices.Equal(got, []string{"c2"}) {
+		t.Errorf("removed containers %v, want c2", got)
+	}
+	if got := removed(p, "RemoveImage"); !slices.Equal(got, []string{"5f1e2d3c4b5a"}) {
+		t.Errorf("removed images %v, want only the one old-build used", got)
+	}
+}
+
+func TestBrokenConfigIsNotOverwritten(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKWATCH_CONFIG_DIR", dir)
+	t.Setenv("DOCKWATCH_STATE_DIR", t.TempDir())
+	t.Setenv("DOCKWATCH_DRY_RUN", "")
+	broken := "ignore: [keep-me\nviews:\n"
+	if err := os.WriteFile(config.Path(), []byte(broken), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load()
+	if err == nil {
+		t.Fatal("a malformed config loaded")
+	}
+	tm := teatest.NewTestModel(t, newModel(cfg).connected(daemon()), teatest.WithInitialTermSize(140, 40))
+	t.Cleanup(func() {
+		tm.Quit()
+		tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+	})
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "x")
+	waitFor(t, tm, "failed to save config")
+	if data, _ := os.ReadFile(config.Path()); string(data) != broken {
+		t.Errorf("config rewritten to %q", data)
+	}
+}
+
+func TestPlanSkipsIgnored(t *testing.T) {
+	p := daemon()
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, " ", "x", "down", " ", "p")
+	waitFor(t, tm, "Prune Plan")
+	press(tm, "a")
+	waitFor(t, tm, "removed 1 object(s)")
+
+	if got := removed(p, "RemoveVolume"); !slices.Equal(got, []string{"scratch"}) {
+		t.Errorf("removed %v, want only scratch", got)
+	}
+}
+
+func TestClearPlan(t *testing.T) {
+	p := daemon()
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, " ", "p", "c", "p", "a")
+	waitFor(t, tm, "nothing to apply")
+
+	if calls := p.Calls("RemoveVolume"); len(calls) != 0 {
+		t.Errorf("cleared plan still removed %v", calls)
+	}
+}
+
+func TestApplyReportsFailures(t *testing.T) {
+	p := daemon()
+	p.FailNext("RemoveVolume", errors.New("boom"))
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, " ", "down", " ", "p", "a")
+	waitFor(t, tm, "1 failed: volume ci-cache: boom")
+
+	m := finalModel(t, tm)
+	if len(m.allVols) != 2 {
+		t.Errorf("listed %d volumes after a partial apply, want ci-cache and pgdata", len(m.allVols))
+	}
+}
+
+func TestDeleteRefusedByPolicy(t *testing.T) {
+	p := daemon()
+	tm := startWith(t, p, func(*config.Config) {
+		rules := "version: 1\nrules:\n  - name: keep-ci\n    match:\n      name: \"ci-*\"\n    action: protect\n"
+		if err := os.WriteFile(config.PolicyPath(), []byte(rules), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	})
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "d")
+	waitFor(t, tm, `not deleting ci-cache: policy rule "keep-ci" protects it`)
+	if calls := p.Calls("RemoveVolume"); len(calls) != 0 {
+		t.Errorf("removed %v despite the policy", calls)
+	}
+}
+
+func TestDeleteHeldByStoppedContainer(t *testing.T) {
+	p := daemon()
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	// A container grabs the volume after it was listed as an orphan
+	p.Do(func(p *fake.Provider) {
+		p.Containers = append(p.Containers, domain.Container{ID: "c2", Name: "migrate", State: "exited",
+			Mounts: []domain.Mount{{Type: "volume", Name: "ci-cache"}}})
+	})
+	press(tm, "d")
+	waitFor(t, tm, "Delete volume ci-cache now?")
+	press(tm, "y")
+	waitFor(t, tm, "held by stopped migrate")
+	press(tm, "y")
+	waitFor(t, tm, "removed ci-cache")
+
+	if got := removed(p, "RemoveContainer"); !slices.Equal(got, []string{"migrate"}) {
+		t.Errorf("removed containers %v, want migrate", got)
+	}
+	if got := removed(p, "RemoveVolume"); !slices.Equal(got, []string{"ci-cache", "ci-cache"}) {
+		t.Errorf("volume removals %v, want a refused one and a retry", got)
+	}
+}
+
+func TestDeleteHeldByRunningContainer(t *testing.T) {
+	p := daemon()
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	p.Do(func(p *fake.Provider) {
+		p.Containers = append(p.Containers, domain.Container{ID: "c2", Name: "runner", State: "running",
+			Mounts: []domain.Mount{{Type: "volume", Name: "ci-cache"}}})
+	})
+	press(tm, "d", "y")
+	waitFor(t, tm, "in use by running runner")
+
+	if calls := p.Calls("RemoveContainer"); len(calls) != 0 {
+		t.Errorf("removed a running container: %v", calls)
+	}
+}
+
+func TestDeleteRefusesAttached(t *testing.T) {
+	p := daemon()
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "down", "down", "d")
+	waitFor(t, tm, "not deleting pgdata")
+	press(tm, "y")
+
+	m := finalModel(t, tm)
+	if m.confirmDelete != "" || len(p.Calls("RemoveVolume")) != 0 {
+		t.Error("an attached volume got past the delete guard")
+	}
+}
+
+func TestListingRetriesTransientErrors(t *testing.T) {
+	p := daemon()
+	p.FailNext("ListVolumesBasic", io.EOF, io.EOF)
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	if n := len(p.Calls("ListVolumesBasic")); n != 3 {
+		t.Errorf("listed %d times, want 2 failures and a success", n)
+	}
+}
+
+func TestListingShowsPermanentErrors(t *testing.T) {
+	p := daemon()
+	p.Fail("ListVolumesBasic", &domain.KindError{Kind: domain.ErrPermission, Msg: "permission denied while trying to connect to the docker daemon"})
+	tm := start(t, p)
+	waitFor(t, tm, "permission denied")
+
+	if n := len(p.Calls("ListVolumesBasic")); n != 1 {
+		t.Errorf("listed %d times, want no retries for a permission error", n)
+	}
+}
+
+func TestQuitClosesProvider(t *testing.T) {
+	p := daemon()
+	p.Latency = 20 * time.Millisecond
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "q")
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+	if !p.Closed() {
+		t.Error("quitting left the provider open")
+	}
+}
+
+func TestTruncateLogs(t *testing.T) {
+	p := daemon()
+	p.Containers[0].LogDriver, p.Containers[0].LogSize = "json-file", 5<<20
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "2", "t")
+	waitFor(t, tm, "Truncate the logs of db (5.00 MB)?")
+	press(tm, "y")
+	waitFor(t, tm, "truncated logs of db, 5.00 MB freed")
+
+	if got := removed(p, "TruncateLogs"); !slices.Equal(got, []string{"c1"}) {
+		t.Errorf("truncated %v, want c1", got)
+	}
+}
+
+func TestLogSearchFoldsCase(t *testing.T) {
+	// "İ" lowercases to three bytes, which used to push match offsets past
+	// the end of the line
+	line := "İİİ Error: İstanbul unreachable"
+	got := highlight(line, "error")
+	if !strings.HasPrefix(got, "İİİ ") || !strings.Contains(got, "Error") || !strings.HasSuffix(got, ": İstanbul unreachable") {
+		t.Errorf("highlight changed the text: %q", got)
+	}
+	if !containsFold(line, "ERROR") || containsFold(line, "warning") {
+		t.Error("containsFold disagrees with a case-insensitive search")
+	}
+}
+
+func TestExplainOrphan(t *testing.T) {
+	p := daemon()
+	p.Containers = append(p.Containers, domain.Container{ID: "c2", Name: "old-job", State: "exited"})
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "?")
+	waitFor(t, tm, "no usage history recorded")
+
+	m := finalModel(t, tm)
+	if why := m.renderWhy(); !strings.Contains(why, "checked 2 container(s), 1 running, 1 stopped") {
+		t.Errorf("explanation misses the container check:\n%s", why)
+	}
+}
+
+func TestColumnPickerSavesOrder(t *testing.T) {
+	tm := start(t, daemon())
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "C", "J", " ", "enter")
+	waitFor(t, tm, "saved 7 column(s) to config")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Size", "Age", "Last Used", "Attached", "Project", "Tags", "Status"}
+	if !slices.Equal(cfg.Columns, want) {
+		t.Errorf("saved columns %v, want %v", cfg.Columns, want)
+	}
+}
+
+func TestFinderJumpsToMatch(t *testing.T) {
+	tm := start(t, daemon())
+	waitFor(t, tm, "Orphans: 2")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlF})
+	press(tm, "p", "g")
+	waitFor(t, tm, "1/3")
+	press(tm, "enter")
+
+	m := finalModel(t, tm)
+	if got := m.vols[m.table.Cursor()].Name; got != "pgdata" {
+		t.Errorf("cursor on %s, want pgdata", got)
+	}
+}
+
+func TestMouseSelectsRow(t *testing.T) {
+	tm := start(t, daemon())
+	waitFor(t, tm, "Orphans: 2")
+
+	// The tab bar, two header lines, the table's top border and column
+	// header, then the rows: line 6 is the second one
+	click := tea.MouseMsg{X: 10, Y: 6, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress}
+	tm.Send(click)
+	tm.Send(click)
+
+	m := finalModel(t, tm)
+	if got := m.vols[m.table.Cursor()].Name; got != "scratch" {
+		t.Errorf("cursor on %s, want scratch", got)
+	}
+	if m.active != paneDetails {
+		t.Errorf("double click left pane %d active, want details", m.active)
+	}
+}
+
+func TestTabBar(t *testing.T) {
+	tm := start(t, daemon())
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "[")
+	waitFor(t, tm, "Docker Networks")
+	press(tm, "]", "]")
+	waitFor(t, tm, "Containers: 1")
+	// " 1 Volumes " and " 2 Containers " with a separator after each
+	tm.Send(tea.MouseMsg{X: 33, Y: 0, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	waitFor(t, tm, "Docker Images")
+
+	if m := finalModel(t, tm); m.resource != resImages {
+		t.Errorf("showing resource %d, want images", m.resource)
+	}
+}
+
+func TestDrillDownAndBack(t *testing.T) {
+	p := daemon()
+	p.Volumes[2].Project = "shop"
+	p.Containers[0].Labels = map[string]string{"com.docker.compose.project": "shop"}
+	tm := start(t, p)
+	// Wide enough for the whole trail next to the tabs
+	tm.Send(tea.WindowSizeMsg{Width: 180, Height: 40})
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "4")
+	waitFor(t, tm, "shop")
+	press(tm, "enter")
+	waitFor(t, tm, "Project: shop")
+	press(tm, "enter", "c")
+	waitFor(t, tm, "Projects: shop › Volumes: pgdata › Containers: db")
+	press(tm, "l")
+	waitFor(t, tm, "Containers: db (logs)")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyBackspace})
+	tm.Send(tea.KeyMsg{Type: tea.KeyBackspace})
+	waitFor(t, tm, "Projects: shop › Volumes: pgdata")
+	tm.Send(tea.KeyMsg{Type: tea.KeyBackspace})
+	waitFor(t, tm, "Compose Projects")
+
+	m := finalModel(t, tm)
+	if m.resource != resProjects || len(m.back) != 0 || m.projectFilter != "" {
+		t.Errorf("back in resource %d with %d location(s) left and filter %q, want projects, none and none",
+			m.resource, len(m.back), m.projectFilter)
+	}
+}
+
+func TestPaletteRunsCommands(t *testing.T) {
+	tm := start(t, daemon())
+	waitFor(t, tm, "Orphans: 2")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlP})
+	press(tm, "m", "a", "r", "k", " ", "o", "r", "p")
+	waitFor(t, tm, "> Mark all orphans")
+	press(tm, "enter")
+	waitFor(t, tm, "marked 2 orphan volume(s)")
+
+	// Commands with a key replay it
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlP})
+	press(tm, "c", "o", "l", "u", "m", "n", "s", "enter")
+	waitFor(t, tm, "[Shift+↑/↓] Reorder")
+
+	m := finalModel(t, tm)
+	if !m.marked["ci-cache"] || !m.marked["scratch"] || m.marked["pgdata"] {
+		t.Errorf("marked %v, want the two orphans", m.marked)
+	}
+}
+
+func TestApplyRunsAsJob(t *testing.T) {
+	p := daemon()
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, " ", "p", "a")
+	waitFor(t, tm, "removed 1")
+	press(tm, "J")
+	waitFor(t, tm, "#1 prune  done")
+}
+
+func TestRemovalsRunSideBySideWithinAKind(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := map[string]int{}, map[string]int{}
+	step := func(kind string) applyStep {
+		return applyStep{kind: kind, label: kind, run: func(context.Context) error {
+			mu.Lock()
+			running[kind]++
+			peak[kind] = max(peak[kind], running[kind])
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			running[kind]--
+			mu.Unlock()
+			return nil
+		}}
+	}
+	steps := []applyStep{step("container"), step("volume"), step("volume"), step("volume"), step("volume"), step("image")}
+
+	events := make(chan tea.Msg, len(steps)+1)
+	err := runApply(context.Background(), steps, 10, 0, 3, newPauseGate(), 0, false, events, func(int, int, string) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peak["volume"] != 3 {
+		t.Errorf("at most %d volume removals ran at once, want 3", peak["volume"])
+	}
+	if len(running) != 3 || peak["container"] != 1 {
+		t.Errorf("kinds overlapped or went missing: %v", peak)
+	}
+	var done []int
+	for range steps {
+		done = append(done, (<-events).(applyProgressMsg).done)
+	}
+	if !slices.IsSorted(done) {
+		t.Errorf("progress went %v, want in order", done)
+	}
+	if res := (<-events).(applyDoneMsg); res.removed != len(steps) {
+		t.Errorf("removed %d, want %d", res.removed, len(steps))
+	}
+}
+
+func TestQuitWaitsForApply(t *testing.T) {
+	p := daemon()
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	p.Do(func(p *fake.Provider) { p.Latency = 100 * time.Millisecond })
+	press(tm, " ", "down", " ", "p", "a", "q")
+	waitFor(t, tm, "Quit while removals are running?")
+	press(tm, "w")
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+
+	if got := removed(p, "RemoveVolume"); len(got) != 2 {
+		t.Errorf("removed %v before quitting, want both orphans", got)
+	}
+	data, err := os.ReadFile(audit.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec audit.Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.Removed) != 2 || rec.Cancelled || !rec.Quit {
+		t.Errorf("audit record %+v, want both removed on quit", rec)
+	}
+}
+
+func TestSessionResumes(t *testing.T) {
+	p := daemon()
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "down", " ", "t", "q")
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newModel(cfg).resumeSession()
+	if !m.marked["scratch"] || len(m.marked) != 1 {
+		t.Errorf("resumed marks %v, want scratch", m.marked)
+	}
+	if m.volSort != sortSize || m.pendingVolume != "scratch" {
+		t.Errorf("resumed sort %s with the cursor on %q, want size on scratch", m.volSort, m.pendingVolume)
+	}
+
+	m, _ = m.setVolumes(volumesMsg{vols: p.Volumes})
+	if got := m.vols[m.table.Cursor()].Name; got != "scratch" {
+		t.Errorf("cursor on %s once listed, want scratch", got)
+	}
+}
+
+func TestBackupsPane(t *testing.T) {
+	dir := t.TempDir()
+	taken := time.Date(2026, 1, 15, 9, 30, 0, 0, time.Local)
+	for _, name := range []string{backup.FileName("pgdata", taken), backup.FileName("pgdata", taken.AddDate(0, 0, 1)), backup.FileName("gone", taken)} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("archive"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p := daemon()
+	tm := startWith(t, p, func(cfg *config.Config) { cfg.Backup.Destination = dir })
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "b")
+	waitFor(t, tm, "3 archive(s) of 2 volume(s)")
+	// pgdata is running; restoring it in place would pull data from under
+	// the database
+	press(tm, "down", "i")
+	waitFor(t, tm, "a running container uses it")
+
+	press(tm, "down", "n")
+	waitFor(t, tm, "Restore "+backup.FileName("pgdata", taken)+" to new volume: pgdata-restored")
+	press(tm, "esc")
+	press(tm, "up", "up", "i", "x", "enter")
+	waitFor(t, tm, "restore cancelled")
+
+	m := finalModel(t, tm)
+	if m.backups.cursor != 0 || m.backups.archives[0].Volume != "gone" {
+		t.Errorf("cursor on %+v, want the backup of gone", m.backups.archives[m.backups.cursor])
+	}
+}
+
+func TestBackupFromMenu(t *testing.T) {
+	dir := t.TempDir()
+	p := daemon()
+	tm := startWith(t, p, func(cfg *config.Config) {
+		cfg.Backup.Destination = dir
+		t.Setenv("DOCKWATCH_DRY_RUN", "1")
+	})
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "enter")
+	waitFor(t, tm, "Back up now")
+	press(tm, "b")
+	waitFor(t, tm, "would back up")
+
+	m := finalModel(t, tm)
+	if !strings.Contains(m.notice, dir) {
+		t.Errorf("notice %q, want the archive in %s", m.notice, dir)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+	t.Setenv("DOCKWATCH_REMOTE", "")
+	p := daemon()
+	for i, body := range []string{"go build cache", "go build cache", "pg"} {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "data"), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		p.Volumes[i].Mountpoint = dir
+	}
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "=")
+	waitFor(t, tm, "mark two or more volumes")
+	press(tm, " ", "down", " ", "down", " ", "=")
+	waitFor(t, tm, "identical  ci-cache, scratch — 14 B each, keeping one frees 14 B")
+}
+
+func TestComposeWarnings(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte("volumes:\n  scratch:\n    external: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tm := startWith(t, daemon(), func(cfg *config.Config) { cfg.Compose.Dirs = []string{dir} })
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "down", " ", "p")
+	waitFor(t, tm, "! declared external in")
+	press(tm, "c", "d")
+	waitFor(t, tm, "scratch is declared external in")
+	press(tm, "n")
+	waitFor(t, tm, "delete cancelled")
+}
+
+func TestRecreateMissing(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte("name: shop\nvolumes:\n  pgdata:\n    name: pgdata\n  uploads:\n    labels: {tier: web}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tm := startWith(t, daemon(), func(cfg *config.Config) {
+		cfg.Compose.Dirs = []string{dir}
+		t.Setenv("DOCKWATCH_DRY_RUN", "1")
+	})
+	waitFor(t, tm, "Orphans: 2")
+
+	// pgdata exists; only uploads is missing
+	press(tm, "M")
+	waitFor(t, tm, "1 declared but not on the daemon")
+	press(tm, "c")
+	waitFor(t, tm, "would recreate shop_uploads")
+
+	log, err := os.ReadFile(filepath.Join(config.StateDir(), "dry-run.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "would create volume shop_uploads (driver local, opts map[], labels map[com.docker.compose.project:shop com.docker.compose.volume:uploads tier:web])") {
+		t.Errorf("dry-run log:\n%s", log)
+	}
+}
+
+func TestComposeLifecycle(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "compose.yaml")
+	p := daemon()
+	p.Containers[0].Labels = map[string]string{
+		"com.docker.compose.project":              "shop",
+		"com.docker.compose.project.working_dir":  dir,
+		"com.docker.compose.project.config_files": file,
+	}
+	tm := startWith(t, p, func(*config.Config) { t.Setenv("DOCKWATCH_DRY_RUN", "1") })
+	waitFor(t, tm, "Orphans: 2")
+	press(tm, "4")
+	waitFor(t, tm, "Project shop")
+
+	// The compose file is gone: up can't work from here
+	press(tm, "u")
+	waitFor(t, tm, "can't bring shop up: compose file "+file)
+	press(tm, "d")
+	waitFor(t, tm, "Stop and remove the 1 container(s) of shop")
+	press(tm, "y")
+	waitFor(t, tm, "would run compose down for shop")
+
+	if err := os.WriteFile(file, []byte("services: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	press(tm, "u")
+	waitFor(t, tm, "would run compose up for shop")
+	log, err := os.ReadFile(filepath.Join(config.StateDir(), "dry-run.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "would run docker compose -p shop up -d (in "+dir+")") {
+		t.Errorf("dry-run log:\n%s", log)
+	}
+}
+
+func TestSetupWizard(t *testing.T) {
+	t.Setenv("PATH", t.TempDir()) // no docker CLI to list contexts with
+	t.Cleanup(func() { applyTheme("") })
+	tm := start(t, daemon())
+	waitFor(t, tm, "Orphans: 2")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlP})
+	press(tm, "s", "e", "t", "u", "p", "enter")
+	waitFor(t, tm, "failed to list docker contexts")
+	press(tm, "enter")
+	waitFor(t, tm, "Set up dockwatch (2/4)")
+	press(tm, "down", "enter")
+	waitFor(t, tm, "Set up dockwatch (3/4)")
+
+	press(tm, "p", "r", "o", "d", "-", "*", ",", " ", "[", "enter")
+	waitFor(t, tm, "syntax error in pattern")
+	tm.Send(tea.KeyMsg{Type: tea.KeyBackspace})
+	press(tm, "enter")
+	waitFor(t, tm, "ignore   prod-*")
+	press(tm, "enter")
+	waitFor(t, tm, "saved "+config.Path())
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Theme != "light" || !slices.Equal(cfg.Ignore, []string{"prod-*"}) || cfg.Context != "" {
+		t.Errorf("saved theme %q, ignore %v, context %q", cfg.Theme, cfg.Ignore, cfg.Context)
+	}
+}
+
+func TestScanImage(t *testing.T) {
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	trivy := `#!/bin/sh
+echo '{"Results": [{"Vulnerabilities": [
+  {"VulnerabilityID": "CVE-2024-1", "PkgName": "openssl", "Severity": "CRITICAL", "FixedVersion": "3.0.14"},
+  {"VulnerabilityID": "CVE-2024-2", "PkgName": "zlib", "Severity": "HIGH"}]}]}'
+`
+	if err := os.WriteFile(filepath.Join(bin, "trivy"), []byte(trivy), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	p := daemon()
+	p.Images = []domain.Image{{ID: "sha256:aaa", Repository: "nginx", Tag: "1.19", SizeBytes: 130 << 20, UniqueSize: -1}}
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "3")
+	waitFor(t, tm, "nginx")
+	press(tm, "v")
+	waitFor(t, tm, "1C 1H")
+
+	m := finalModel(t, tm)
+	r := m.vulns["sha256:aaa"]
+	if r.err != nil || r.summary.Scanner != "trivy" || r.summary.Counts["critical"] != 1 {
+		t.Errorf("scan %+v", r)
+	}
+	if !m.showImageInfo {
+		t.Error("scanning didn't open the details pane")
+	}
+}
+
+func TestSwarmPrune(t *testing.T) {
+	t.Setenv("DOCKWATCH_REMOTE", "")
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	docker := `#!/bin/sh
+case "$1 $2" in
+"info --format") echo "active true" ;;
+"secret ls") echo s1; echo s2 ;;
+"secret inspect") echo '[{"ID": "s1", "CreatedAt": "2024-05-01T10:00:00Z", "Spec": {"Name": "db_password"}},
+  {"ID": "s2", "CreatedAt": "2024-05-02T10:00:00Z", "Spec": {"Name": "old_token"}}]' ;;
+"config ls") echo c1 ;;
+"config inspect") echo '[{"ID": "c1", "CreatedAt": "2024-05-03T10:00:00Z", "Spec": {"Name": "nginx_conf"}}]' ;;
+"service ls") echo w1 ;;
+"service inspect") echo '[{"Spec": {"Name": "web", "TaskTemplate": {"ContainerSpec": {
+  "Secrets": [{"SecretID": "s1"}], "Configs": [{"ConfigID": "c1"}]}}}}]' ;;
+"secret rm" | "config rm") echo "$@" >> "$0.log" ;;
+*) exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(bin, "docker"), []byte(docker), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tm := start(t, daemon())
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "2", "s")
+	waitFor(t, tm, "1 of 3 unreferenced by any service")
+	press(tm, "p")
+	waitFor(t, tm, "Remove 1 unreferenced secret(s) and config(s)?")
+	press(tm, "y")
+	waitFor(t, tm, "removed 1 secret(s) and config(s)")
+	finalModel(t, tm)
+
+	got, err := os.ReadFile(filepath.Join(bin, "docker.log"))
+	if err != nil || string(got) != "secret rm s2\n" {
+		t.Errorf("removed %q (%v), want only secret s2", got, err)
+	}
+}
+
+func TestPluginUsage(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+	t.Setenv("DOCKWATCH_REMOTE", "")
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "plugins", "p1", "rootfs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "plugins", "p1", "rootfs", "sshfs"), make([]byte, 3<<20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	docker := `#!/bin/sh
+case "$1 $2" in
+"info --format") echo "` + root + `" ;;
+"plugin ls") echo p1 ;;
+"plugin inspect") echo '[{"Id": "p1", "Name": "vieux/sshfs:latest", "Enabled": true,
+  "Config": {"Interface": {"Types": ["docker.volumedriver/1.0"]}}}]' ;;
+*) exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(bin, "docker"), []byte(docker), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	p := daemon()
+	p.Volumes = append(p.Volumes,
+		domain.Volume{Name: "share", Driver: "vieux/sshfs", SizeBytes: -1},
+		domain.Volume{Name: "nfs-data", Driver: "netapp", SizeBytes: -1})
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 4")
+
+	press(tm, "U")
+	waitFor(t, tm, "vieux/sshfs:latest, enabled, drives 1 volume(s): share")
+
+	m := finalModel(t, tm)
+	if ps := m.root.plugins; len(ps) != 1 || ps[0].SizeBytes != 3<<20 {
+		t.Errorf("plugins %+v, want vieux/sshfs measured at 3 MiB", ps)
+	}
+	if out := m.renderRoot(); !strings.Contains(out, "Volumes of drivers not installed as plugins: nfs-data (netapp)") {
+		t.Errorf("data root pane doesn't name the legacy plugin volume:\n%s", out)
+	}
+}
+
+func TestStaleTag(t *testing.T) {
+	p := daemon()
+	p.Images = []domain.Image{
+		{ID: "aaa", Repository: "nginx", Tag: "latest", UniqueSize: -1, RepoDigests: []string{"nginx@sha256:1111111111111111"}},
+		{ID: "bbb", Repository: "redis", Tag: "7", UniqueSize: -1, RepoDigests: []string{"docker.io/library/redis@sha256:2222222222222222"}},
+		{ID: "ccc", Repository: "myapp", Tag: "dev", UniqueSize: -1},
+	}
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "3")
+	waitFor(t, tm, "myapp")
+	tm.Send(registryMsg{total: 3, results: map[string]upstream{
+		"nginx:latest": {digest: "sha256:9999999999999999"},
+		"redis:7":      {digest: "sha256:2222222222222222"},
+		"myapp:dev":    {digest: "sha256:3333333333333333"},
+	}})
+	waitFor(t, tm, "1 tag(s) behind their registry")
+	press(tm, "down", "i")
+	waitFor(t, tm, "this copy is stale")
+
+	m := finalModel(t, tm)
+	var got []string
+	for _, img := range m.images {
+		got = append(got, m.upstreamStatus(img))
+	}
+	// Sorted by repository: myapp, nginx, redis
+	if !slices.Equal(got, []string{"pullable", "stale", "pullable"}) {
+		t.Errorf("upstream %v", got)
+	}
+}
+
+func TestCrashLoop(t *testing.T) {
+	p := daemon()
+	p.Containers = append(p.Containers,
+		domain.Container{ID: "c2", Name: "worker", Image: "app", State: "running", RestartCount: 7, RestartPolicy: "always",
+			StartedAt: time.Now().Add(-time.Minute), Health: "unhealthy", HealthOutput: "curl: (7) connection refused\n"},
+		domain.Container{ID: "c3", Name: "cron", Image: "app", State: "running", RestartCount: 7, StartedAt: time.Now().Add(-time.Hour)},
+	)
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "2")
+	waitFor(t, tm, "Crash-looping: 1  Unhealthy: 1")
+	press(tm, "down", "i")
+	waitFor(t, tm, "connection refused")
+
+	m := finalModel(t, tm)
+	rows := m.ctable.Rows()
+	if rows[1][2] != "LOOPING" || rows[1][3] != "unhealthy" || rows[2][2] != "running" {
+		t.Errorf("rows %v", rows)
+	}
+}
+
+func TestTopWriters(t *testing.T) {
+	p := daemon()
+	p.Containers = append(p.Containers,
+		domain.Container{ID: "c2", Name: "logger", Image: "app", State: "running"},
+		domain.Container{ID: "c3", Name: "idle", Image: "app", State: "running"},
+	)
+	p.Stats = []domain.ContainerStats{{Name: "db", BlockWrite: 1 << 20}, {Name: "logger"}, {Name: "idle", BlockWrite: 5 << 20}}
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "2", "w")
+	waitFor(t, tm, "Top Writers")
+	// logger writes four times as much as db, and idle nothing
+	tm.Send(statsMsg{stats: []domain.ContainerStats{{Name: "db", BlockWrite: 2 << 20}, {Name: "logger", BlockWrite: 4 << 20}, {Name: "idle", BlockWrite: 5 << 20}}})
+	waitFor(t, tm, "→ volume pgdata (3.0 GB)")
+
+	m := finalModel(t, tm)
+	var got []string
+	for _, w := range m.topWriters() {
+		got = append(got, w.container.Name)
+	}
+	if !slices.Equal(got, []string{"logger", "db"}) {
+		t.Errorf("top writers %v", got)
+	}
+}
+
+func TestLiveSize(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+	t.Setenv("DOCKWATCH_REMOTE", "")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a"), make([]byte, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := daemon()
+	p.Volumes[0].Mountpoint = dir
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "L")
+	waitFor(t, tm, "Live size: 100 B in 1 files")
+	if err := os.WriteFile(filepath.Join(dir, "b"), make([]byte, 2048), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, tm, "+2.0 KB since")
+
+	press(tm, "L")
+	waitFor(t, tm, "stopped tracking ci-cache")
+	if m := finalModel(t, tm); m.live != nil {
+		t.Errorf("still tracking %s", m.live.volume)
+	}
+}
+
+func TestNetworkDetailsAndDelete(t *testing.T) {
+	p := daemon()
+	p.Networks = []domain.Network{
+		{ID: "n1", Name: "backend", Driver: "bridge", Scope: "local", Internal: true, Containers: []string{"db"},
+			IPAMDriver: "default", IPAM: []domain.IPAMConfig{{Subnet: "172.20.0.0/16", Gateway: "172.20.0.1"}}},
+		{ID: "n2", Name: "spare", Driver: "bridge", Scope: "local"},
+	}
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "5")
+	waitFor(t, tm, "subnet 172.20.0.0/16, gateway 172.20.0.1")
+	press(tm, "d")
+	waitFor(t, tm, "can't delete network backend: db connected to it")
+	press(tm, "down", "d")
+	waitFor(t, tm, "Delete network(s) spare?")
+	press(tm, "y")
+	waitFor(t, tm, "Networks: 1")
+
+	finalModel(t, tm)
+	if len(p.Networks) != 1 || p.Networks[0].Name != "backend" {
+		t.Errorf("networks left: %v", p.Networks)
+	}
+}
+
+func TestMarkUnusedNetworks(t *testing.T) {
+	p := daemon()
+	p.Containers[0].Labels = map[string]string{"com.docker.compose.project": "shop"}
+	compose := func(project, network string) map[string]string {
+		return map[string]string{"com.docker.compose.project": project, "com.docker.compose.network": network}
+	}
+	p.Networks = []domain.Network{
+		{ID: "n1", Name: "bridge", Driver: "bridge"},
+		{ID: "n2", Name: "host", Driver: "host"},
+		{ID: "n3", Name: "shop_default", Driver: "bridge", Labels: compose("shop", "default")},
+		{ID: "n4", Name: "old_default", Driver: "bridge", Labels: compose("old", "default")},
+		{ID: "n5", Name: "shop_backend", Driver: "bridge", Labels: compose("shop", "backend")},
+	}
+	tm := start(t, p)
+	waitFor(t, tm, "Orphans: 2")
+
+	press(tm, "5")
+	waitFor(t, tm, "Unused: 2")
+	press(tm, "u")
+	waitFor(t, tm, "marked 2 unused network(s)")
+	press(tm, "d")
+	waitFor(t, tm, "Delete network(s) old_default, shop_backend?")
+
+	m := finalModel(t, tm)
+	if !m.nmarked["old_default"] || !m.nmarked["shop_backend"] || len(m.nmarked) != 2 {
+		t.Errorf("marked %v, want old_default and shop_backend", m.nmarked)
+	}
+	var shop project
+	for _, pr := range m.projects() {
+		if pr.name == "shop" {
+			shop = pr
+		}
+	}
+	if ps := shop.orphaned(m.cfg.IsIgnored); len(ps.networks) != 1 || ps.networks[0].Name != "shop_backend" {
+		t.Errorf("teardown of shop removes networks %v, want only shop_backend", ps.networks)
+	}
+}
+
+func TestTeardownKeepsPausedContainers(t *testing.T) {
+	p := project{
+		name: "shop",
+		containers: []domain.Container{
+			{Name: "web", State: "exited"},
+			{Name: "init", State: "created"},
+			{Name: "db", State: "paused"},
+			{Name: "worker", State: "restarting"},
+		},
+		volumes: []domain.Volume{
+			{Name: "shop_static", Attached: []string{"web", "init"}},
+			{Name: "shop_db", Attached: []string{"db"}},
+		},
+		networks: []domain.Network{{ID: "n1", Name: "shop_default", Driver: "bridge",
+			Labels: map[string]string{"com.docker.compose.project": "shop", "com.docker.compose.network": "default"}}},
+	}
+	ps := p.orphaned(func(string) bool { return false })
+	var names []string
+	for _, c := range ps.containers {
+		names = append(names, c.Name)
+	}
+	if !slices.Equal(names, []string{"web", "init"}) {
+		t.Errorf("teardown removes containers %v, want web and init", names)
+	}
+	if len(ps.volumes) != 1 || ps.volumes[0].Name != "shop_static" {
+		t.Errorf("teardown removes volumes %v, want only shop_static", ps.volumes)
+	}
+	if len(ps.networks) != 0 {
+		t.Errorf("teardown removes %v while db and worker are still attached", ps.networks)
+	}
+}
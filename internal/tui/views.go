@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"dockwatch/internal/config"
+	"dockwatch/internal/domain"
+)
+
+// viewKey returns which saved view an alt+digit key selects: 1-9 pick a
+// view, 0 clears it.
+func viewKey(key string) (int, bool) {
+	digit, ok := strings.CutPrefix(key, "alt+")
+	if !ok || len(digit) != 1 || digit[0] < '0' || digit[0] > '9' {
+		return 0, false
+	}
+	n, _ := strconv.Atoi(digit)
+	return n, true
+}
+
+// selectView switches to the n-th saved view (1-based), or back to every
+// volume for 0.
+func (m model) selectView(n int) model {
+	if n > len(m.cfg.Views) {
+		m.notice = fmt.Sprintf("no view %d; W saves the current one", n)
+		return m
+	}
+	m.view, m.viewFilter, m.viewMinAge = nil, nil, 0
+	m.tagFilter = ""
+	m.ageFilter = 0
+	m.visual = false
+	if n == 0 {
+		m.volSort = sortDaemon
+		m = m.applyVolumeView()
+		m.notice = "showing all volumes"
+		return m
+	}
+
+	v := m.cfg.Views[n-1]
+	// LoadFile validated the view, so parse errors can't happen here
+	m.viewFilter, _ = domain.ParseVolumeFilter(v.Filter)
+	if v.OlderThan != "" {
+		m.viewMinAge, _ = domain.ParseAge(v.OlderThan)
+	}
+	m.view = &v
+	m.volSort = parseSort(v.Sort)
+	m = m.applyVolumeView()
+	m.notice = fmt.Sprintf("view %q", v.Name)
+	return m
+}
+
+// saveView stores the current filter, sort and columns under name,
+// replacing a view with the same name.
+func (m model) saveView(name string) model {
+	if name = strings.TrimSpace(name); name == "" {
+		return m
+	}
+	v := config.View{Name: name, Sort: m.volSort.configName()}
+	if m.view != nil {
+		v.Filter, v.OlderThan, v.Columns = m.view.Filter, m.view.OlderThan, m.view.Columns
+	}
+	if m.tagFilter != "" {
+		v.Filter = append(slices.Clone(v.Filter), "tag="+m.tagFilter)
+	}
+	if age := ageFilters[m.ageFilter]; age > m.viewMinAge {
+		v.OlderThan = domain.HumanAge(age)
+	}
+	m.cfg.SetView(v)
+	if err := m.cfg.Save(); err != nil {
+		m.notice = fmt.Sprintf("failed to save view: %v", err)
+		return m
+	}
+	m.view = &v
+	m.notice = fmt.Sprintf("saved view %q", name)
+	return m
+}
+
+// viewLabel names the active view for the header.
+func (m model) viewLabel() string {
+	if m.view == nil {
+		return ""
+	}
+	return "  View: " + m.view.Name
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startVisual anchors a visual selection at the cursor row.
+func (m model) startVisual() model {
+	idx := m.table.Cursor()
+	if idx < 0 {
+		return m
+	}
+	m.visual, m.anchor = true, idx
+	m.table.SetRows(m.volumeRows())
+	return m
+}
+
+// visualRange returns the inclusive row range between anchor and cursor.
+func (m model) visualRange() (int, int) {
+	lo, hi := m.anchor, m.table.Cursor()
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	return clamp(lo, 0, len(m.vols)-1), clamp(hi, 0, len(m.vols)-1)
+}
+
+// inVisual reports whether row i is inside the active visual selection.
+func (m model) inVisual(i int) bool {
+	if !m.visual {
+		return false
+	}
+	lo, hi := m.visualRange()
+	return i >= lo && i <= hi
+}
+
+// updateVisual handles keys while a visual selection is active: V or space
+// marks the range (or unmarks it if every row is already marked), esc
+// abandons it. Anything else, navigation included, is not consumed.
+func (m model) updateVisual(msg tea.KeyMsg) (bool, model) {
+	if !m.visual {
+		return false, m
+	}
+	switch msg.String() {
+	case "V", " ":
+		lo, hi := m.visualRange()
+		all := true
+		for i := lo; i <= hi; i++ {
+			all = all && m.marked[m.vols[i].Name]
+		}
+		for i := lo; i <= hi; i++ {
+			m.marked[m.vols[i].Name] = !all
+		}
+		m.notice = fmt.Sprintf("%s %d volume(s)", tern(all, "unmarked", "marked"), hi-lo+1)
+	case "esc":
+	default:
+		return false, m
+	}
+	m.visual = false
+	m.table.SetRows(m.volumeRows())
+	return true, m
+}
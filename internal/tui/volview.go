@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// volumeSort is the order of the Volumes view.
+type volumeSort int
+
+const (
+	sortDaemon volumeSort = iota // as `docker volume ls` returned them
+	sortSize                     // largest first
+	sortAge                      // oldest first
+	sortIdle                     // longest unused first
+	sortName
+	sortCount
+)
+
+func (s volumeSort) String() string {
+	return [...]string{"daemon order", "size", "age", "last used", "name"}[s]
+}
+
+// configName is the sort as written in a saved view, see config.ViewSorts.
+func (s volumeSort) configName() string {
+	return [...]string{"", "size", "age", "lastUsed", "name"}[s]
+}
+
+func parseSort(name string) volumeSort {
+	for s := sortDaemon; s < sortCount; s++ {
+		if s.configName() == name {
+			return s
+		}
+	}
+	return sortDaemon
+}
+
+// ageFilters are the minimum ages A cycles through; 0 shows every volume.
+var ageFilters = []time.Duration{0, 7 * day, 30 * day, 90 * day, 365 * day}
+
+const day = 24 * time.Hour
+
+// applyVolumeView derives the shown rows (m.vols) from the full listing,
+// the saved view and the age filter, keeping the cursor on the same volume where it is still shown.
+// Volumes of unknown age never pass an age filter and sort last by age.
+func (m model) applyVolumeView() model {
+	var selected string
+	if idx := m.table.Cursor(); idx >= 0 && idx < len(m.vols) {
+		selected = m.vols[idx].Name
+	}
+	if selected == "" {
+		selected = m.pendingVolume
+	}
+
+	if m.notes != nil {
+		m.notes.Annotate(m.allVols)
+	}
+	now := time.Now()
+	minAge := max(ageFilters[m.ageFilter], m.viewMinAge)
+	vols := make([]domain.Volume, 0, len(m.allVols))
+	for _, v := range m.allVols {
+		if minAge > 0 && (v.CreatedAt.IsZero() || now.Sub(v.CreatedAt) < minAge) {
+			continue
+		}
+		if !m.viewFilter.Match(v) || m.tagFilter != "" && !slices.Contains(v.Tags, m.tagFilter) ||
+			m.projectFilter != "" && v.Project != m.projectFilter {
+			continue
+		}
+		vols = append(vols, v)
+	}
+	switch m.volSort {
+	case sortSize:
+		slices.SortStableFunc(vols, func(a, b domain.Volume) int { return cmp.Compare(b.SizeBytes, a.SizeBytes) })
+	case sortAge:
+		slices.SortStableFunc(vols, func(a, b domain.Volume) int { return earliestFirst(a.CreatedAt, b.CreatedAt) })
+	case sortIdle:
+		slices.SortStableFunc(vols, func(a, b domain.Volume) int { return earliestFirst(a.IdleSince(), b.IdleSince()) })
+	case sortName:
+		slices.SortStableFunc(vols, func(a, b domain.Volume) int { return cmp.Compare(a.Name, b.Name) })
+	}
+	m.vols = vols
+
+	m.table.SetColumns(tableColumns(m.shownColumns()))
+	m.table.SetRows(m.volumeRows())
+	if i := slices.IndexFunc(m.vols, func(v domain.Volume) bool { return v.Name == selected }); i >= 0 {
+		m.table.SetCursor(i)
+		m.pendingVolume = ""
+	}
+	return m
+}
+
+// earliestFirst orders times ascending with unknown (zero) times last.
+func earliestFirst(a, b time.Time) int {
+	if a.IsZero() != b.IsZero() {
+		return tern(a.IsZero(), 1, -1)
+	}
+	return a.Compare(b)
+}
+
+// cycleSort switches to the next volume order.
+func (m model) cycleSort() model {
+	m.volSort = (m.volSort + 1) % sortCount
+	m.visual = false
+	m = m.applyVolumeView()
+	m.notice = "sorted by " + m.volSort.String()
+	return m
+}
+
+// cycleAgeFilter raises the minimum age of shown volumes, wrapping back to
+// showing everything.
+func (m model) cycleAgeFilter() model {
+	m.ageFilter = (m.ageFilter + 1) % len(ageFilters)
+	m.visual = false
+	m = m.applyVolumeView()
+	if m.ageFilter == 0 {
+		m.notice = "showing volumes of any age"
+	} else {
+		m.notice = fmt.Sprintf("showing volumes older than %s", domain.HumanAge(ageFilters[m.ageFilter]))
+	}
+	return m
+}
@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// vtable is a virtualized table: it owns the full row set and cursor but
+// only hands the visible window of rows to the underlying bubbles table,
+// so rendering and navigation cost the same for 50 rows or 5,000.
+type vtable struct {
+	inner  table.Model
+	cols   []table.Column
+	rows   []table.Row
+	cursor int
+	offset int
+}
+
+func newVTable(cols []table.Column) vtable {
+	t := table.New(table.WithColumns(cols), table.WithFocused(true), table.WithStyles(tableStyles()))
+	// Navigation is ours; the inner table only ever sees one window
+	t.KeyMap = table.KeyMap{}
+	return vtable{inner: t, cols: cols}
+}
+
+// SetColumns replaces the columns, dropping rows that no longer fit them;
+// callers set fresh rows right after.
+func (t *vtable) SetColumns(cols []table.Column) {
+	t.rows = nil
+	t.inner.SetRows(nil)
+	t.inner.SetColumns(cols)
+	t.cols = cols
+}
+
+// Rows returns every row, not just the visible ones.
+func (t vtable) Rows() []table.Row { return t.rows }
+
+// SetRows replaces the rows, keeping the cursor in range.
+func (t *vtable) SetRows(rows []table.Row) {
+	t.rows = rows
+	t.SetCursor(t.cursor)
+}
+
+// Cursor returns the index of the selected row in the full row set.
+func (t vtable) Cursor() int {
+	if len(t.rows) == 0 {
+		return -1
+	}
+	return t.cursor
+}
+
+// SetCursor selects row n, scrolling the window just enough to show it.
+func (t *vtable) SetCursor(n int) {
+	h := t.inner.Height()
+	t.cursor = clamp(n, 0, len(t.rows)-1)
+	switch {
+	case t.cursor < t.offset:
+		t.offset = t.cursor
+	case t.cursor >= t.offset+h:
+		t.offset = t.cursor - h + 1
+	}
+	t.offset = clamp(t.offset, 0, len(t.rows)-h)
+
+	end := min(t.offset+h, len(t.rows))
+	t.inner.SetRows(t.rows[t.offset:end])
+	t.inner.SetCursor(t.cursor - t.offset)
+}
+
+// SetHeight sets how many rows are visible, keeping the cursor in view.
+func (t *vtable) SetHeight(h int) {
+	t.inner.SetHeight(h)
+	t.SetCursor(t.cursor)
+}
+
+// Update handles navigation keys: arrows, page up/down, home/end.
+func (t vtable) Update(msg tea.Msg) (vtable, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return t, nil
+	}
+	h := t.inner.Height()
+	switch key.String() {
+	case "up":
+		t.SetCursor(t.cursor - 1)
+	case "down":
+		t.SetCursor(t.cursor + 1)
+	case "pgup":
+		t.SetCursor(t.cursor - h)
+	case "pgdown":
+		t.SetCursor(t.cursor + h)
+	case "home":
+		t.SetCursor(0)
+	case "end":
+		t.SetCursor(len(t.rows) - 1)
+	}
+	return t, nil
+}
+
+// RowAt returns the row drawn on the n-th line of the visible window, if
+// any.
+func (t vtable) RowAt(n int) (int, bool) {
+	row := t.offset + n
+	if n < 0 || n >= t.inner.Height() || row >= len(t.rows) {
+		return 0, false
+	}
+	return row, true
+}
+
+// Position is the "row X of Y" indicator.
+func (t vtable) Position() string {
+	if len(t.rows) == 0 {
+		return "no rows"
+	}
+	return fmt.Sprintf("row %d of %d", t.cursor+1, len(t.rows))
+}
+
+func (t vtable) View() string { return t.inner.View() }
+
+// cellStyler returns the style for a cell given its index in the full row
+// set and its column, and whether it has one.
+type cellStyler func(row, col int) (lipgloss.Style, bool)
+
+// StyledView is View with cells of unselected rows rendered in the styles
+// style returns; the selected row keeps the table's own highlight. Rows
+// without a styled cell are left exactly as the table drew them.
+func (t vtable) StyledView(style cellStyler) string {
+	lines := strings.Split(t.inner.View(), "\n")
+	cols := t.cols
+	// Line 0 is the header, then one line per row of the window
+	for i := 1; i < len(lines); i++ {
+		row := t.offset + i - 1
+		if row >= len(t.rows) || row == t.cursor {
+			continue
+		}
+		cells := make([]string, len(cols))
+		styled := false
+		for c, col := range cols {
+			value := ""
+			if c < len(t.rows[row]) {
+				value = runewidth.Truncate(t.rows[row][c], col.Width, "…")
+			}
+			if st, ok := style(row, c); ok {
+				value, styled = st.Render(value), true
+			}
+			// Same layout as the bubbles table: fixed width, one cell of padding
+			cells[c] = cellPadding.Render(lipgloss.NewStyle().Width(col.Width).MaxWidth(col.Width).Inline(true).Render(value))
+		}
+		if styled {
+			lines[i] = lipgloss.JoinHorizontal(lipgloss.Left, cells...)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var cellPadding = lipgloss.NewStyle().Padding(0, 1)
+
+// clamp bounds v to [low, high], preferring low when the range is empty.
+func clamp(v, low, high int) int {
+	return max(low, min(v, high))
+}
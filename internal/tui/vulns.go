@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/jobs"
+	"dockwatch/internal/vuln"
+)
+
+// vulnResult is the scan of one image, by image ID.
+type vulnResult struct {
+	summary  vuln.Summary
+	scanning bool
+	err      error
+}
+
+// vulnMsg carries a finished scan.
+type vulnMsg struct {
+	id      string
+	summary vuln.Summary
+	err     error
+}
+
+// scanImage runs trivy or grype against the selected image as a background
+// job and opens the details pane to show what it finds.
+func (m model) scanImage() (model, tea.Cmd) {
+	img, ok := m.selectedImage()
+	if !ok {
+		return m, nil
+	}
+	m.showImageInfo = true
+	if os.Getenv("DOCKWATCH_REMOTE") != "" {
+		m.notice = "scanners run against the docker daemon on this machine; not available against an agent"
+		return m, nil
+	}
+	if r, ok := m.vulns[img.ID]; ok && r.scanning {
+		return m, nil
+	}
+	scanner, err := vuln.Find(m.cfg.Images.Scanner)
+	if err != nil {
+		m.notice = err.Error()
+		return m, nil
+	}
+	if m.vulns == nil {
+		m.vulns = map[string]vulnResult{}
+	}
+	m.vulns[img.ID] = vulnResult{scanning: true}
+	m.itable.SetRows(m.imageRows())
+	ref := img.Ref()
+	return m, m.enqueue("scan", fmt.Sprintf("Scan %s with %s", ref, scanner), vulnMsg{id: img.ID, err: context.Canceled},
+		func(ctx context.Context, progress jobs.Progress) (tea.Msg, error) {
+			progress(0, 1, scanner+" "+ref)
+			s, err := vuln.Scan(ctx, scanner, ref)
+			return vulnMsg{id: img.ID, summary: s, err: err}, err
+		})
+}
+
+func (m model) setVulns(msg vulnMsg) model {
+	if m.vulns == nil {
+		m.vulns = map[string]vulnResult{}
+	}
+	m.vulns[msg.id] = vulnResult{summary: msg.summary, err: msg.err}
+	m.itable.SetRows(m.imageRows())
+	return m
+}
+
+// vulnStatus renders the Vulns column for an image.
+func (m model) vulnStatus(img domain.Image) string {
+	r, ok := m.vulns[img.ID]
+	switch {
+	case !ok:
+		return ""
+	case r.scanning:
+		return "…"
+	case r.err != nil:
+		return "err"
+	}
+	return r.summary.Short()
+}
+
+// vulnDetails writes the image's scan to sb for the details pane.
+func (m model) vulnDetails(sb *strings.Builder, img domain.Image) {
+	r, ok := m.vulns[img.ID]
+	switch {
+	case !ok:
+		sb.WriteString("Vulnerabilities: <not scanned, press v>\n")
+	case r.scanning:
+		sb.WriteString("Vulnerabilities: scanning…\n")
+	case r.err != nil:
+		fmt.Fprintf(sb, "Vulnerabilities: %s\n", failStyle.Render(r.err.Error()))
+	default:
+		s := r.summary
+		fmt.Fprintf(sb, "Vulnerabilities (%s): %d critical, %d high, %d medium, %d low\n",
+			s.Scanner, s.Counts[vuln.Critical], s.Counts[vuln.High], s.Counts[vuln.Medium], s.Counts[vuln.Low])
+		for _, f := range s.Top {
+			line := fmt.Sprintf("  %-8s %-20s %s", strings.ToUpper(f.Severity), f.ID, f.Package)
+			if f.Fixed != "" {
+				line += ", fixed in " + f.Fixed
+			}
+			sb.WriteString(tern(f.Severity == vuln.Critical, failStyle, warnStyle).Render(line) + "\n")
+		}
+		if img.Containers == 0 && s.Counts[vuln.Critical] > 0 {
+			sb.WriteString("No container uses it; deleting it is the easy fix\n")
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dockwatch/internal/domain"
+	"dockwatch/internal/history"
+)
+
+// whyState is the explanation pane for one volume: the evidence behind its
+// ORPHAN flag, so it can be trusted before deleting.
+type whyState struct {
+	volume   string
+	loading  bool
+	lastSeen []string  // containers history last saw the volume attached to
+	seenAt   time.Time // when that sample was taken
+	noDB     bool      // no usage history is being recorded
+	err      error
+}
+
+// whyMsg carries what the history database knows about a volume.
+type whyMsg struct {
+	volume   string
+	lastSeen []string
+	seenAt   time.Time
+	noDB     bool
+	err      error
+}
+
+// explain opens the pane on the volume under the cursor and refreshes the
+// containers the reasoning is based on.
+func (m model) explain() (model, tea.Cmd) {
+	idx := m.table.Cursor()
+	if idx < 0 || idx >= len(m.vols) {
+		return m, nil
+	}
+	name := m.vols[idx].Name
+	m.why = &whyState{volume: name, loading: true}
+	m.active = paneWhy
+	return m, tea.Batch(m.loadContainers(), lastAttached(name))
+}
+
+// lastAttached looks the volume up in the history database without
+// creating one.
+func lastAttached(name string) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := os.Stat(history.Path()); err != nil {
+			return whyMsg{volume: name, noDB: true}
+		}
+		db, err := history.Open(history.Path())
+		if err != nil {
+			return whyMsg{volume: name, err: err}
+		}
+		defer db.Close()
+		names, at, err := db.LastAttached(context.Background(), name)
+		return whyMsg{volume: name, lastSeen: names, seenAt: at, err: err}
+	}
+}
+
+func (m model) setWhy(msg whyMsg) model {
+	if m.why == nil || m.why.volume != msg.volume {
+		return m
+	}
+	m.why = &whyState{volume: msg.volume, lastSeen: msg.lastSeen, seenAt: msg.seenAt, noDB: msg.noDB, err: msg.err}
+	return m
+}
+
+// renderWhy lists each check: ✓ supports the flag, ! argues against
+// deleting, · is neutral.
+func (m model) renderWhy() string {
+	if m.why == nil {
+		return m.pane().Render("Why orphan? — press ? on a volume")
+	}
+	var v domain.Volume
+	found := false
+	for _, vol := range m.allVols {
+		if vol.Name == m.why.volume {
+			v, found = vol, true
+		}
+	}
+	if !found {
+		return m.pane().Render(fmt.Sprintf("%s no longer exists", m.why.volume))
+	}
+
+	sb := &strings.Builder{}
+	line := func(mark, format string, args ...any) {
+		fmt.Fprintf(sb, "  %s %s\n", mark, fmt.Sprintf(format, args...))
+	}
+	fmt.Fprintf(sb, "Why is %s %s?\n\n", v.Name, tern(v.Orphan(), "ORPHAN", "not an orphan"))
+
+	running := 0
+	for _, c := range m.containers {
+		if c.Running() {
+			running++
+		}
+	}
+	if len(v.Attached) > 0 {
+		line("!", "mounted by %s", strings.Join(v.Attached, ", "))
+	} else {
+		line(glyphs.check, "no container mounts it — checked %d container(s), %d running, %d stopped",
+			len(m.containers), running, len(m.containers)-running)
+	}
+
+	now := time.Now()
+	switch {
+	case v.InUse:
+		line("!", "in use by a running container right now")
+	case !v.LastUsed.IsZero():
+		line(glyphs.check, "last used %s ago (%s)", domain.HumanAge(now.Sub(v.LastUsed)), v.LastUsed.Format("2006-01-02 15:04"))
+	default:
+		line(glyphs.dot, "no record of when a container last used it")
+	}
+
+	switch w := m.why; {
+	case w.loading:
+		line(glyphs.dot, "checking usage history…")
+	case w.err != nil:
+		line(glyphs.dot, "usage history unreadable: %v", w.err)
+	case w.noDB:
+		line(glyphs.dot, "no usage history recorded (see `dockwatch history record`)")
+	case len(w.lastSeen) == 0:
+		line(glyphs.check, "usage history never saw it attached to a container")
+	default:
+		line(glyphs.check, "usage history last saw it attached to %s on %s", strings.Join(w.lastSeen, ", "), w.seenAt.Format("2006-01-02"))
+	}
+
+	if v.Project == "" {
+		line(glyphs.dot, "not part of a compose project")
+	} else {
+		left, up := 0, 0
+		for _, c := range m.containers {
+			if c.Project() == v.Project {
+				left++
+				if c.Running() {
+					up++
+				}
+			}
+		}
+		switch {
+		case left == 0:
+			line(glyphs.check, "compose project %s has no containers left", v.Project)
+		case up == 0:
+			line("!", "compose project %s still has %d stopped container(s); `compose up` may want it back", v.Project, left)
+		default:
+			line("!", "compose project %s is running (%d of %d container(s)); a service may mount it on its next start", v.Project, up, left)
+		}
+	}
+
+	// Mounts were covered above; only the ignore list and policy are left
+	unmounted := v
+	unmounted.Attached = nil
+	if reason := m.protection(unmounted); reason != "" {
+		line("!", "protected: %s", reason)
+	}
+	if reason, keep := m.orphanGuard().Keeps(v, now); keep && len(v.Attached) == 0 {
+		line("!", "not flagged orphan because %s (orphans in config.yaml)", reason)
+	}
+	sb.WriteString("\n[?] Explain the volume under the cursor  [Tab] Next pane")
+	return m.pane().Render(sb.String())
+}
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"dockwatch/internal/domain"
+)
+
+// writersPaneRows caps the top writers pane; the heaviest writers come first.
+const writersPaneRows = 8
+
+// writer is a running container ranked by how fast it writes to disk.
+type writer struct {
+	container domain.Container
+	rate      float64 // block bytes written per second between the last two samples
+}
+
+// writeRates derives per-container write throughput from two cumulative
+// stats samples dt apart. Containers missing from prev, or whose counters
+// went backwards because they restarted, are left out until next sample.
+func writeRates(prev, cur map[string]domain.ContainerStats, dt time.Duration) map[string]float64 {
+	rates := map[string]float64{}
+	if dt <= 0 {
+		return rates
+	}
+	for name, st := range cur {
+		p, ok := prev[name]
+		if !ok || st.BlockWrite < p.BlockWrite {
+			continue
+		}
+		rates[name] = float64(st.BlockWrite-p.BlockWrite) / dt.Seconds()
+	}
+	return rates
+}
+
+// topWriters ranks the running containers that wrote anything since the
+// previous sample, heaviest first.
+func (m model) topWriters() []writer {
+	var ws []writer
+	for _, c := range m.containers {
+		if r := m.writeRates[c.Name]; r > 0 && c.Running() {
+			ws = append(ws, writer{container: c, rate: r})
+		}
+	}
+	sort.SliceStable(ws, func(i, j int) bool { return ws[i].rate > ws[j].rate })
+	return ws
+}
+
+// writeTargets is where a container's writes can land: its volumes and bind
+// mounts, and its writable layer. tmpfs mounts are RAM and left out.
+func writeTargets(c domain.Container) (volumes, binds []string) {
+	for _, mt := range c.Mounts {
+		switch mt.Type {
+		case "volume":
+			volumes = append(volumes, mt.Name)
+		case "bind":
+			binds = append(binds, mt.Source)
+		}
+	}
+	return volumes, binds
+}
+
+func (m model) renderWriters() string {
+	sb := &strings.Builder{}
+	sb.WriteString("Top Writers — block writes per second, from the last two stats samples\n\n")
+	ws := m.topWriters()
+	switch {
+	case m.statsAt.IsZero() || m.writeRates == nil:
+		sb.WriteString("  measuring…\n")
+	case len(ws) == 0:
+		sb.WriteString("  <no running container wrote to disk since the last sample>\n")
+	}
+	var total float64
+	for i, w := range ws {
+		total += w.rate
+		if i >= writersPaneRows {
+			continue
+		}
+		vols, binds := writeTargets(w.container)
+		var into string
+		switch {
+		case len(vols) == 1 && len(binds) == 0:
+			// blkio can't tell mounts apart, but with one volume that's
+			// almost certainly where the data goes
+			into = "→ volume " + vols[0]
+			if v, ok := m.volumeByName(vols[0]); ok {
+				into += " (" + v.SizeHuman() + ")"
+			}
+		case len(vols)+len(binds) == 0:
+			into = "→ writable layer"
+		default:
+			targets := append(prefixed("volume ", vols), prefixed("bind ", binds)...)
+			into = "→ one of " + strings.Join(targets, ", ")
+		}
+		fmt.Fprintf(sb, "  %-20s %10s/s  %s\n", w.container.Name, domain.HumanSize(int64(w.rate)), into)
+	}
+	if len(ws) > writersPaneRows {
+		fmt.Fprintf(sb, "  … and %d more\n", len(ws)-writersPaneRows)
+	}
+	if len(ws) > 0 {
+		fmt.Fprintf(sb, "\nTotal: %s/s across %d container(s)\n", domain.HumanSize(int64(total)), len(ws))
+	}
+	return m.pane().Render(sb.String() + "\n[W] Close  [L] Logs of selected")
+}
+
+func (m model) volumeByName(name string) (domain.Volume, bool) {
+	for _, v := range m.allVols {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return domain.Volume{}, false
+}
+
+func prefixed(prefix string, names []string) []string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = prefix + n
+	}
+	return out
+}
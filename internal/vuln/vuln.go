@@ -0,0 +1,221 @@
+// Package vuln summarizes the known vulnerabilities of an image by running
+// trivy or grype, whichever is installed, so old images carrying critical
+// CVEs can be deleted first.
+package vuln
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Scanners are the supported scanners, in the order they are looked for.
+var Scanners = []string{"trivy", "grype"}
+
+// ErrNoScanner is returned when neither scanner is installed.
+var ErrNoScanner = errors.New("neither trivy nor grype is installed")
+
+// Severities from worst to least; scanners' own names are mapped onto them.
+const (
+	Critical = "critical"
+	High     = "high"
+	Medium   = "medium"
+	Low      = "low"
+	Unknown  = "unknown"
+)
+
+var severities = []string{Critical, High, Medium, Low, Unknown}
+
+// topFindings is how many of the worst findings a Summary keeps.
+const topFindings = 5
+
+// Finding is one vulnerability in one package.
+type Finding struct {
+	ID       string // e.g. CVE-2024-1234
+	Severity string
+	Package  string
+	Fixed    string // the version fixing it, "" when there is none
+}
+
+// Summary is what a scan found in an image.
+type Summary struct {
+	Scanner string
+	Counts  map[string]int // distinct vulnerability IDs, by severity
+	Top     []Finding      // the worst few, critical first
+}
+
+// Short renders the critical and high counts, e.g. "2C 7H", or "ok" when
+// there are none.
+func (s Summary) Short() string {
+	c, h := s.Counts[Critical], s.Counts[High]
+	if c == 0 && h == 0 {
+		return "ok"
+	}
+	return fmt.Sprintf("%dC %dH", c, h)
+}
+
+// Find returns the scanner to use: want if it is installed, or with want
+// empty the first of Scanners that is.
+func Find(want string) (string, error) {
+	candidates := Scanners
+	if want != "" {
+		candidates = []string{want}
+	}
+	for _, name := range candidates {
+		if _, err := exec.LookPath(name); err == nil {
+			return name, nil
+		}
+	}
+	if want != "" {
+		return "", fmt.Errorf("%s is not installed", want)
+	}
+	return "", ErrNoScanner
+}
+
+// Scan runs scanner against the image ref in the local daemon.
+func Scan(ctx context.Context, scanner, ref string) (Summary, error) {
+	var args []string
+	var parse func([]byte) ([]Finding, error)
+	switch scanner {
+	case "trivy":
+		args, parse = []string{"image", "--quiet", "--format", "json", "--scanners", "vuln", ref}, parseTrivy
+	case "grype":
+		args, parse = []string{"docker:" + ref, "--output", "json", "--quiet"}, parseGrype
+	default:
+		return Summary{}, fmt.Errorf("unknown scanner %q", scanner)
+	}
+	cmd := exec.CommandContext(ctx, scanner, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := lastLine(stderr.String()); msg != "" {
+			return Summary{}, fmt.Errorf("%s: %s", scanner, msg)
+		}
+		return Summary{}, fmt.Errorf("%s: %w", scanner, err)
+	}
+	findings, err := parse(out)
+	if err != nil {
+		return Summary{}, fmt.Errorf("%s: unreadable report: %w", scanner, err)
+	}
+	return summarize(scanner, findings), nil
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// parseTrivy reads `trivy image --format json`.
+func parseTrivy(data []byte) ([]Finding, error) {
+	var report struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				VulnerabilityID string
+				PkgName         string
+				Severity        string
+				FixedVersion    string
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, r := range report.Results {
+		for _, v := range r.Vulnerabilities {
+			findings = append(findings, Finding{ID: v.VulnerabilityID, Severity: severity(v.Severity), Package: v.PkgName, Fixed: v.FixedVersion})
+		}
+	}
+	return findings, nil
+}
+
+// parseGrype reads `grype --output json`.
+func parseGrype(data []byte) ([]Finding, error) {
+	var report struct {
+		Matches []struct {
+			Vulnerability struct {
+				ID       string `json:"id"`
+				Severity string `json:"severity"`
+				Fix      struct {
+					Versions []string `json:"versions"`
+				} `json:"fix"`
+			} `json:"vulnerability"`
+			Artifact struct {
+				Name string `json:"name"`
+			} `json:"artifact"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, m := range report.Matches {
+		f := Finding{ID: m.Vulnerability.ID, Severity: severity(m.Vulnerability.Severity), Package: m.Artifact.Name}
+		if len(m.Vulnerability.Fix.Versions) > 0 {
+			f.Fixed = m.Vulnerability.Fix.Versions[0]
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+// severity maps a scanner's severity name onto ours; grype's Negligible
+// counts as low.
+func severity(s string) string {
+	switch s = strings.ToLower(s); s {
+	case Critical, High, Medium, Low:
+		return s
+	case "negligible":
+		return Low
+	}
+	return Unknown
+}
+
+func rank(severity string) int {
+	for i, s := range severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(severities)
+}
+
+// summarize counts each vulnerability ID once, at the worst severity it
+// was reported with, and keeps the worst findings.
+func summarize(scanner string, findings []Finding) Summary {
+	worst := map[string]string{}
+	for _, f := range findings {
+		if s, ok := worst[f.ID]; !ok || rank(f.Severity) < rank(s) {
+			worst[f.ID] = f.Severity
+		}
+	}
+	s := Summary{Scanner: scanner, Counts: map[string]int{}}
+	for _, sev := range worst {
+		s.Counts[sev]++
+	}
+	findings = slices.Clone(findings)
+	sort.SliceStable(findings, func(i, j int) bool {
+		if ri, rj := rank(findings[i].Severity), rank(findings[j].Severity); ri != rj {
+			return ri < rj
+		}
+		return findings[i].ID < findings[j].ID
+	})
+	seen := map[string]bool{}
+	for _, f := range findings {
+		if len(s.Top) == topFindings || rank(f.Severity) > rank(High) {
+			break
+		}
+		if !seen[f.ID] {
+			seen[f.ID] = true
+			s.Top = append(s.Top, f)
+		}
+	}
+	return s
+}
@@ -0,0 +1,47 @@
+package vuln
+
+import "testing"
+
+func TestParseTrivy(t *testing.T) {
+	report := `{"Results": [
+		{"Target": "debian", "Vulnerabilities": [
+			{"VulnerabilityID": "CVE-1", "PkgName": "openssl", "Severity": "CRITICAL", "FixedVersion": "3.0.2"},
+			{"VulnerabilityID": "CVE-2", "PkgName": "zlib", "Severity": "HIGH"},
+			{"VulnerabilityID": "CVE-3", "PkgName": "tar", "Severity": "LOW"}
+		]},
+		{"Target": "app", "Vulnerabilities": [
+			{"VulnerabilityID": "CVE-1", "PkgName": "libssl", "Severity": "HIGH"}
+		]},
+		{"Target": "clean"}
+	]}`
+	findings, err := parseTrivy([]byte(report))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := summarize("trivy", findings)
+	// CVE-1 counts once, at its worst
+	if s.Counts[Critical] != 1 || s.Counts[High] != 1 || s.Counts[Low] != 1 || s.Short() != "1C 1H" {
+		t.Errorf("counts %v", s.Counts)
+	}
+	if len(s.Top) != 2 || s.Top[0] != (Finding{ID: "CVE-1", Severity: Critical, Package: "openssl", Fixed: "3.0.2"}) || s.Top[1].ID != "CVE-2" {
+		t.Errorf("top %+v", s.Top)
+	}
+}
+
+func TestParseGrype(t *testing.T) {
+	report := `{"matches": [
+		{"vulnerability": {"id": "GHSA-1", "severity": "Negligible"}, "artifact": {"name": "bash"}},
+		{"vulnerability": {"id": "CVE-9", "severity": "Medium", "fix": {"versions": ["1.2"]}}, "artifact": {"name": "curl"}}
+	]}`
+	findings, err := parseGrype([]byte(report))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := summarize("grype", findings)
+	if s.Counts[Low] != 1 || s.Counts[Medium] != 1 || s.Short() != "ok" || len(s.Top) != 0 {
+		t.Errorf("summary %+v", s)
+	}
+	if findings[1].Fixed != "1.2" {
+		t.Errorf("findings %+v", findings)
+	}
+}